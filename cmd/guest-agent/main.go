@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/agent"
+	"github.com/mdlayher/vsock"
+)
+
+// The guest agent runs the commands of the runner inside a VM and copies
+// files in and out of it. Build it statically, e.g. CGO_ENABLED=0 go build
+// ./cmd/guest-agent, and start it at boot in the rootfs.
+func main() {
+	port := flag.Uint("port", 1234, "Vsock port to listen on, the -vsock-port of the runner")
+	flag.Parse()
+
+	listener, err := vsock.Listen(uint32(*port), nil)
+	if err != nil {
+		log.Fatalf("Failed to listen on vsock port %d: %v", *port, err)
+	}
+	log.Printf("Guest agent listening on vsock port %d, protocol versions %v", *port, agent.Versions)
+
+	log.Fatal(agent.Serve(listener))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
@@ -11,9 +12,11 @@ import (
 	"time"
 
 	"github.com/bookpanda/firecracker-runner-node/internal/config"
+	"github.com/bookpanda/firecracker-runner-node/internal/exporter"
 	"github.com/bookpanda/firecracker-runner-node/internal/filesystem"
 	"github.com/bookpanda/firecracker-runner-node/internal/network"
 	"github.com/bookpanda/firecracker-runner-node/internal/node"
+	"github.com/bookpanda/firecracker-runner-node/internal/run"
 	"github.com/bookpanda/firecracker-runner-node/internal/vm"
 	filesystemProto "github.com/bookpanda/firecracker-runner-node/proto/filesystem/v1"
 	networkProto "github.com/bookpanda/firecracker-runner-node/proto/network/v1"
@@ -33,13 +36,37 @@ func main() {
 	vmCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	vmManager := vm.NewManager(conf, vmCtx)
+	runs, err := run.NewManager(conf.RunsDir)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to start a run: %v", err))
+	}
+
+	vmManager, err := vm.NewManager(conf, vmCtx, runs)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create VM manager: %v", err))
+	}
 	vmSvc := vm.NewService(vmManager, logger.Named("vmSvc"))
 
-	networkSvc := network.NewService(logger.Named("networkSvc"))
-	filesystemSvc := filesystem.NewService(logger.Named("filesystemSvc"))
+	networkManager, err := network.NewDefaultManager(network.Options{
+		StateDir:  conf.NetworkStateDir,
+		Bridge:    conf.Bridge,
+		TapPrefix: conf.TapPrefix,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to set up network manager: %v", err))
+	}
+	// Cleanup replaces the VM manager, so look it up on every call
+	networkManager.SetTapResolver(func(ip string) (string, error) {
+		vm, err := vmSvc.Manager().GetVM(ip)
+		if err != nil {
+			return "", err
+		}
+		return vm.TapName, nil
+	})
+	networkSvc := network.NewService(networkManager, logger.Named("networkSvc"))
+	filesystemSvc := filesystem.NewService(conf, runs, logger.Named("filesystemSvc"))
 
-	nodeManager := node.NewManager(conf)
+	nodeManager := node.NewManager(conf, runs)
 	nodeSvc := node.NewService(nodeManager, logger.Named("nodeSvc"))
 
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%v", conf.Port))
@@ -47,7 +74,16 @@ func main() {
 		panic(fmt.Sprintf("Failed to listen: %v", err))
 	}
 
-	grpcServer := grpc.NewServer()
+	metrics := exporter.New(exporter.Sources{
+		VMs:           exporter.VMs(vmSvc),
+		Taps:          exporter.Taps(networkManager),
+		TraceSessions: exporter.TraceSessions(vmSvc, nodeSvc),
+	})
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(metrics.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(metrics.StreamInterceptor()),
+	)
 	grpc_health_v1.RegisterHealthServer(grpcServer, health.NewServer())
 	vmProto.RegisterVmServiceServer(grpcServer, vmSvc)
 	networkProto.RegisterNetworkServiceServer(grpcServer, networkSvc)
@@ -63,15 +99,31 @@ func main() {
 		}
 	}()
 
-	wait := gracefulShutdown(context.Background(), 2*time.Second, logger, map[string]operation{
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	metricsServer := &http.Server{Addr: conf.MetricsAddr, Handler: mux}
+	if conf.MetricsAddr != "" {
+		go func() {
+			logger.Sugar().Infof("Serving metrics at %v/metrics", conf.MetricsAddr)
+
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Fatal("Failed to serve metrics", zap.Error(err))
+			}
+		}()
+	}
+
+	wait := gracefulShutdown(context.Background(), conf.ShutdownTimeout, logger, map[string]operation{
 		"vm-manager": func(ctx context.Context) error {
 			cancel() // cancel vmCtx to stop syscall tracking and other VM operations
-			return vmManager.StopAllVMs()
+			return vmSvc.Manager().StopAllVMs()
 		},
 		"server": func(ctx context.Context) error {
 			grpcServer.GracefulStop()
 			return nil
 		},
+		"metrics-server": func(ctx context.Context) error {
+			return metricsServer.Shutdown(ctx)
+		},
 	})
 
 	<-wait
@@ -0,0 +1,545 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	agentProto "github.com/bookpanda/firecracker-runner-node/proto/agent/v1"
+)
+
+func TestMain(m *testing.M) {
+	// every test goes through a few heartbeats
+	heartbeatInterval = 50 * time.Millisecond
+	os.Exit(m.Run())
+}
+
+// listen serves handler on a unix socket and returns a function that
+// connects to it.
+func listen(t *testing.T, handler func(net.Conn)) func() (net.Conn, *bufio.Reader) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go handler(conn)
+		}
+	}()
+
+	return func() (net.Conn, *bufio.Reader) {
+		conn, err := net.Dial("unix", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn, bufio.NewReader(conn)
+	}
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	conn, r := listen(t, ServeConn)()
+	client, err := NewClient(conn, r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// wait collects the output of the command of client by stream.
+func wait(t *testing.T, client *Client) (stdout, stderr string, result job.Result) {
+	t.Helper()
+
+	var out, errOut strings.Builder
+	result, err := client.Wait(func(stream job.Stream, data []byte) {
+		if stream == job.Stderr {
+			errOut.Write(data)
+		} else {
+			out.Write(data)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out.String(), errOut.String(), result
+}
+
+func TestExec(t *testing.T) {
+	client := newTestClient(t)
+	if client.Version() != 3 {
+		t.Fatalf("negotiated version %d, want 3", client.Version())
+	}
+
+	dir := t.TempDir()
+	err := client.Start(job.Spec{
+		Argv: []string{"sh", "-c", "echo $GREETING; pwd; echo oops >&2; exit 3"},
+		Env:  []string{"GREETING=hello"},
+		Dir:  dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Pid() <= 0 {
+		t.Fatalf("got PID %d", client.Pid())
+	}
+
+	stdout, stderr, result := wait(t, client)
+	if want := "hello\n" + dir + "\n"; stdout != want {
+		t.Fatalf("got stdout %q, want %q", stdout, want)
+	}
+	if stderr != "oops\n" {
+		t.Fatalf("got stderr %q, want %q", stderr, "oops\n")
+	}
+	if result.ExitCode != 3 || result.Killed || result.Error != "" || result.Duration <= 0 {
+		t.Fatalf("got %+v, want exit code 3", result)
+	}
+}
+
+func TestExecOutlivesHeartbeats(t *testing.T) {
+	client := newTestClient(t)
+	if err := client.Start(job.Spec{Argv: []string{"sleep", "0.5"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, result := wait(t, client); result.ExitCode != 0 {
+		t.Fatalf("got %+v, want exit code 0", result)
+	}
+}
+
+func TestSignal(t *testing.T) {
+	client := newTestClient(t)
+	if err := client.Start(job.Spec{Argv: []string{"sleep", "60"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, result := wait(t, client)
+	if !result.Killed || result.ExitCode != -1 || result.Signal != "terminated" {
+		t.Fatalf("got %+v, want a command killed by SIGTERM", result)
+	}
+}
+
+func TestStdin(t *testing.T) {
+	client := newTestClient(t)
+	if err := client.Start(job.Spec{Argv: []string{"cat"}, Stdin: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Write([]byte("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CloseStdin(); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _, result := wait(t, client)
+	if stdout != "hello\n" || result.ExitCode != 0 {
+		t.Fatalf("got %q and %+v, want stdin echoed", stdout, result)
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	client := newTestClient(t)
+	if err := client.Start(job.Spec{Argv: []string{"stty", "size"}, TTY: true, Rows: 24, Cols: 80}); err != nil {
+		t.Fatal(err)
+	}
+	// a terminal ends lines with \r\n
+	if stdout, _, result := wait(t, client); stdout != "24 80\r\n" || result.ExitCode != 0 {
+		t.Fatalf("got %q and %+v, want the initial size", stdout, result)
+	}
+
+	client = newTestClient(t)
+	err := client.Start(job.Spec{Argv: []string{"sh", "-c", "read line; stty size"}, TTY: true, Rows: 24, Cols: 80})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Resize(40, 120); err != nil {
+		t.Fatal(err)
+	}
+	// the agent handles the frames in order
+	if err := client.Write([]byte("go\n")); err != nil {
+		t.Fatal(err)
+	}
+	// the terminal echoes the line
+	if stdout, _, result := wait(t, client); stdout != "go\r\n40 120\r\n" || result.ExitCode != 0 {
+		t.Fatalf("got %q and %+v, want the size after the resize", stdout, result)
+	}
+}
+
+func TestStdinNeedsVersion2(t *testing.T) {
+	// an agent that only speaks version 1
+	conn, r := listen(t, func(conn net.Conn) {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		r.ReadString('\n')
+		fmt.Fprintf(conn, "VERSION 1\n")
+		io.Copy(io.Discard, r)
+	})()
+	client, err := NewClient(conn, r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if client.Version() != 1 {
+		t.Fatalf("negotiated version %d, want 1", client.Version())
+	}
+	if err := client.Start(job.Spec{Argv: []string{"cat"}, Stdin: true}); err == nil {
+		t.Fatal("started a command with stdin on a version 1 agent")
+	}
+}
+
+func putFile(t *testing.T, f File, content []byte) (*Upload, error) {
+	t.Helper()
+
+	upload, err := newTestClient(t).PutFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := upload.Write(content[upload.Offset:]); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+func TestPutFile(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), ChunkSize/4)
+	sum := sha256.Sum256(content)
+	path := filepath.Join(t.TempDir(), "dir", "file")
+	f := File{Path: path, Mode: 0o750, Size: int64(len(content)), SHA256: sum[:]}
+
+	// the first upload is cut off halfway
+	client := newTestClient(t)
+	upload, err := client.PutFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upload.Offset != 0 {
+		t.Fatalf("first upload starts at %d", upload.Offset)
+	}
+	if err := upload.Write(content[:len(content)/2]); err != nil {
+		t.Fatal(err)
+	}
+	upload.Abort()
+
+	// the agent notices the hang up in its own time, until then a new upload
+	// may find less of the file
+	var resumed *Upload
+	for range 50 {
+		resumed, err = newTestClient(t).PutFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resumed.Offset == int64(len(content)/2) {
+			break
+		}
+		resumed.Abort()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if resumed.Offset != int64(len(content)/2) {
+		t.Fatalf("second upload starts at %d, want %d", resumed.Offset, len(content)/2)
+	}
+	if err := resumed.Write(content[resumed.Offset:]); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := resumed.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Size != f.Size || stored.Mode != 0o750 || !bytes.Equal(stored.SHA256, f.SHA256) {
+		t.Fatalf("got %+v, want %+v", stored, f)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatal("stored content differs")
+	}
+	st, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Mode().Perm() != 0o750 {
+		t.Fatalf("got mode %v, want 0750", st.Mode().Perm())
+	}
+	if parts, _ := filepath.Glob(path + ".part-*"); len(parts) != 0 {
+		t.Fatalf("left partial files %v", parts)
+	}
+}
+
+func TestPutFileChecksumMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	sum := sha256.Sum256([]byte("something else"))
+	upload, err := putFile(t, File{Path: path, Mode: 0o644, Size: 5, SHA256: sum[:]}, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := upload.Close(); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("got %v, want a checksum mismatch", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 0 {
+		t.Fatalf("left %d files behind", len(entries))
+	}
+}
+
+func TestPutFileTooLarge(t *testing.T) {
+	content := []byte("hello")
+	sum := sha256.Sum256(content)
+	upload, err := putFile(t, File{Path: filepath.Join(t.TempDir(), "file"), Mode: 0o644, Size: 2, SHA256: sum[:]}, content)
+	if err == nil {
+		_, err = upload.Close()
+	}
+	if err == nil || !strings.Contains(err.Error(), "larger") {
+		t.Fatalf("got %v, want the file to be too large", err)
+	}
+}
+
+// getFile downloads path from offset.
+func getFile(t *testing.T, path string, offset int64) (File, []byte, error) {
+	t.Helper()
+
+	download, err := newTestClient(t).GetFile(path, offset)
+	if err != nil {
+		return File{}, nil, err
+	}
+	var content []byte
+	for {
+		at, data, err := download.Next()
+		if errors.Is(err, io.EOF) {
+			return download.File, content, nil
+		}
+		if err != nil {
+			return File{}, nil, err
+		}
+		if at != offset+int64(len(content)) {
+			t.Fatalf("got chunk at %d, want %d", at, offset+int64(len(content)))
+		}
+		content = append(content, data...)
+	}
+}
+
+func TestGetFile(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), ChunkSize/4)
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, content, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+
+	f, got, err := getFile(t, path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatal("downloaded content differs")
+	}
+	if f.Size != int64(len(content)) || f.Mode != 0o640 || !bytes.Equal(f.SHA256, sum[:]) {
+		t.Fatalf("got %+v", f)
+	}
+
+	// a download that resumes further in
+	_, got, err = getFile(t, path, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content[1000:]) {
+		t.Fatal("resumed content differs")
+	}
+}
+
+func TestGetFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		path   string
+		offset int64
+	}{
+		{filepath.Join(dir, "missing"), 0},
+		{dir, 0},
+		{path, 6},
+	} {
+		if _, _, err := getFile(t, tt.path, tt.offset); err == nil {
+			t.Errorf("downloaded %s from %d", tt.path, tt.offset)
+		}
+	}
+}
+
+func TestGetFileFinalCheck(t *testing.T) {
+	content := []byte("hello")
+	sum := sha256.Sum256([]byte("other"))
+
+	for _, tt := range []struct {
+		name    string
+		file    File
+		wantErr string
+	}{
+		{name: "short", file: File{Path: "/file", Size: 10, SHA256: sum[:]}, wantErr: "ended after 5 of 10 bytes"},
+		{name: "changed", file: File{Path: "/file", Size: 5, SHA256: sum[:]}, wantErr: "does not match"},
+	} {
+		runner, agent := net.Pipe()
+		go newFrameConn(agent, bufio.NewReader(agent)).write(&agentProto.Frame{Body: &agentProto.Frame_Chunk{Chunk: &agentProto.Chunk{Data: content, Eof: true}}})
+		client := &Client{conn: newFrameConn(runner, bufio.NewReader(runner)), version: 3}
+		download := &Download{c: client, File: tt.file, hash: sha256.New()}
+
+		// the last chunk comes with the error
+		at, data, err := download.Next()
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+		if at != 0 || !bytes.Equal(data, content) {
+			t.Errorf("%s: got %q at %d, want the last chunk", tt.name, data, at)
+		}
+		if _, _, err := download.Next(); !errors.Is(err, io.EOF) {
+			t.Errorf("%s: got %v after the last chunk, want io.EOF", tt.name, err)
+		}
+		agent.Close()
+	}
+}
+
+func TestStartFailure(t *testing.T) {
+	client := newTestClient(t)
+	if err := client.Start(job.Spec{Argv: []string{"/does/not/exist"}}); err == nil {
+		t.Fatal("started a missing binary")
+	}
+}
+
+func TestServeLineProtocol(t *testing.T) {
+	conn, r := listen(t, ServeConn)()
+	fmt.Fprintf(conn, "echo hi; exit 2\n")
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "hi\nerror: exit status 2\n"; string(out) != want {
+		t.Fatalf("got %q, want %q", out, want)
+	}
+}
+
+func TestNewClientDetectsLegacyAgent(t *testing.T) {
+	// like an agent that runs HELLO as a command
+	dial := listen(t, func(conn net.Conn) {
+		defer conn.Close()
+		bufio.NewReader(conn).ReadString('\n')
+		fmt.Fprintf(conn, "sh: HELLO: not found\nerror: exit status 127\n")
+	})
+	conn, r := dial()
+	if _, err := NewClient(conn, r, nil); !errors.Is(err, ErrLegacyAgent) {
+		t.Fatalf("got %v, want ErrLegacyAgent", err)
+	}
+}
+
+func TestServeRejectsUnknownVersions(t *testing.T) {
+	conn, r := listen(t, ServeConn)()
+	fmt.Fprintf(conn, "HELLO 99 100\n")
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(line, "ERROR ") {
+		t.Fatalf("got %q, want an ERROR", line)
+	}
+}
+
+func TestPickVersion(t *testing.T) {
+	for _, tt := range []struct {
+		offered []int
+		want    int
+	}{
+		{[]int{1}, 1},
+		{[]int{1, 2}, 2},
+		{[]int{2, 3}, 3},
+		{[]int{3, 4}, 3},
+		{[]int{4, 5}, 0},
+		{nil, 0},
+	} {
+		if got := pickVersion(tt.offered); got != tt.want {
+			t.Errorf("pickVersion(%v) = %d, want %d", tt.offered, got, tt.want)
+		}
+	}
+}
+
+// silentAgent negotiates and then never sends anything.
+func silentAgent(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	r.ReadString('\n')
+	fmt.Fprintf(conn, "VERSION 1\n")
+	io.Copy(io.Discard, r)
+}
+
+func TestClientGivesUpOnSilentAgent(t *testing.T) {
+	conn, r := listen(t, silentAgent)()
+	client, err := NewClient(conn, r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	err = client.Start(job.Spec{Argv: []string{"true"}})
+	if err == nil || !strings.Contains(err.Error(), ErrNoHeartbeat.Error()) {
+		t.Fatalf("got %v, want %v", err, ErrNoHeartbeat)
+	}
+}
+
+func TestClientWaitsForSuspendedAgent(t *testing.T) {
+	conn, r := listen(t, silentAgent)()
+	var suspended atomic.Bool
+	suspended.Store(true)
+	client, err := NewClient(conn, r, suspended.Load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	started := make(chan error, 1)
+	go func() {
+		err := client.Start(job.Spec{Argv: []string{"true"}})
+		started <- err
+	}()
+	select {
+	case err := <-started:
+		t.Fatalf("gave up on a suspended agent: %v", err)
+	case <-time.After(10 * heartbeatInterval):
+	}
+
+	suspended.Store(false)
+	select {
+	case err := <-started:
+		if err == nil {
+			t.Fatal("a silent agent started the command")
+		}
+	case <-time.After(10 * heartbeatInterval):
+		t.Fatal("kept waiting for the agent once it was resumed")
+	}
+}
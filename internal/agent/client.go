@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	agentProto "github.com/bookpanda/firecracker-runner-node/proto/agent/v1"
+)
+
+// Client is the runner side of a connection to the guest agent. It runs a
+// single command, which it controls as a job.Handle.
+type Client struct {
+	conn    *frameConn
+	version int
+	pid     int
+	start   time.Time
+}
+
+// NewClient negotiates the protocol version on conn, which is connected to
+// the agent. r reads conn and may hold data already. suspended, which may be
+// nil, reports whether the guest is paused and so cannot send heartbeats.
+//
+// It returns ErrLegacyAgent if the agent only speaks the line protocol.
+func NewClient(conn net.Conn, r *bufio.Reader, suspended func() bool) (*Client, error) {
+	conn.SetDeadline(time.Now().Add(helloTimeout))
+	if _, err := io.WriteString(conn, hello(Versions)); err != nil {
+		return nil, fmt.Errorf("failed to send HELLO: %v", err)
+	}
+	line, err := r.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		return nil, ErrLegacyAgent
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read VERSION: %v", err)
+	}
+	if reason, ok := strings.CutPrefix(line, "ERROR "); ok {
+		return nil, fmt.Errorf("guest agent refused the connection: %s", strings.TrimSpace(reason))
+	}
+	versions, ok := parseVersions(line, "VERSION")
+	if !ok {
+		return nil, ErrLegacyAgent
+	}
+	if len(versions) != 1 || pickVersion(versions) == 0 {
+		return nil, fmt.Errorf("guest agent picked unsupported protocol version %q", strings.TrimSpace(line))
+	}
+	conn.SetDeadline(time.Time{})
+
+	c := &Client{conn: newFrameConn(conn, r), version: versions[0]}
+	go c.conn.keepAlive(suspended)
+	return c, nil
+}
+
+// Version returns the negotiated protocol version.
+func (c *Client) Version() int {
+	return c.version
+}
+
+// Start runs spec, whose Dir and Env apply in the guest.
+func (c *Client) Start(spec job.Spec) error {
+	if len(spec.Argv) == 0 {
+		return fmt.Errorf("empty command")
+	}
+	if (spec.TTY || spec.Stdin) && c.version < 2 {
+		return fmt.Errorf("guest agent speaks protocol version %d, stdin and terminals need version 2", c.version)
+	}
+	c.start = time.Now()
+	exec := &agentProto.Exec{
+		Argv:  spec.Argv,
+		Env:   spec.Env,
+		Cwd:   spec.Dir,
+		Tty:   spec.TTY,
+		Stdin: spec.Stdin,
+	}
+	if spec.TTY {
+		exec.Size = &agentProto.TerminalSize{Rows: uint32(spec.Rows), Cols: uint32(spec.Cols)}
+	}
+	if err := c.conn.write(&agentProto.Frame{Body: &agentProto.Frame_Exec{Exec: exec}}); err != nil {
+		return fmt.Errorf("failed to send command: %v", err)
+	}
+
+	frame, err := c.conn.read()
+	if err != nil {
+		return fmt.Errorf("failed to read the start of the command: %v", err)
+	}
+	switch body := frame.Body.(type) {
+	case *agentProto.Frame_Started:
+		c.pid = int(body.Started.Pid)
+		return nil
+	case *agentProto.Frame_Exit:
+		return fmt.Errorf("failed to start command: %s", body.Exit.Error)
+	}
+	return fmt.Errorf("unexpected frame %T before the command started", frame.Body)
+}
+
+// Pid returns the PID of the command in the guest.
+func (c *Client) Pid() int {
+	return c.pid
+}
+
+func (c *Client) Write(data []byte) error {
+	return c.conn.write(&agentProto.Frame{Body: &agentProto.Frame_Stdin{Stdin: &agentProto.Stdin{Data: data}}})
+}
+
+func (c *Client) CloseStdin() error {
+	return c.conn.write(&agentProto.Frame{Body: &agentProto.Frame_Stdin{Stdin: &agentProto.Stdin{Eof: true}}})
+}
+
+func (c *Client) Resize(rows, cols uint16) error {
+	size := &agentProto.TerminalSize{Rows: uint32(rows), Cols: uint32(cols)}
+	return c.conn.write(&agentProto.Frame{Body: &agentProto.Frame_Resize{Resize: &agentProto.Resize{Size: size}}})
+}
+
+// Signal sends sig to the command and its children.
+func (c *Client) Signal(sig syscall.Signal) error {
+	return c.conn.write(&agentProto.Frame{Body: &agentProto.Frame_Signal{Signal: &agentProto.Signal{Signal: int32(sig)}}})
+}
+
+// Wait calls output with the output of the command until it exits, then
+// hangs up. The error is set if the connection was lost before.
+func (c *Client) Wait(output func(stream job.Stream, data []byte)) (job.Result, error) {
+	defer c.Close()
+	for {
+		frame, err := c.conn.read()
+		if err != nil {
+			return job.Result{}, fmt.Errorf("lost connection to the guest agent: %v", err)
+		}
+		switch body := frame.Body.(type) {
+		case *agentProto.Frame_Output:
+			output(job.Stream(body.Output.Stream), body.Output.Data)
+		case *agentProto.Frame_Exit:
+			return job.Result{
+				ExitCode: int(body.Exit.ExitCode),
+				Duration: time.Since(c.start),
+				Killed:   body.Exit.Signal != "",
+				Signal:   body.Exit.Signal,
+				Error:    body.Exit.Error,
+			}, nil
+		default:
+			return job.Result{}, fmt.Errorf("unexpected frame %T while the command runs", frame.Body)
+		}
+	}
+}
+
+// Close hangs up, which kills the command if it still runs.
+func (c *Client) Close() error {
+	return c.conn.close()
+}
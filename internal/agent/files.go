@@ -0,0 +1,371 @@
+package agent
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	agentProto "github.com/bookpanda/firecracker-runner-node/proto/agent/v1"
+)
+
+// ChunkSize is the most file content a frame carries.
+const ChunkSize = 256 << 10
+
+// File describes a file in the guest.
+type File struct {
+	Path string
+	// Mode holds the permission bits
+	Mode   os.FileMode
+	Size   int64
+	SHA256 []byte
+}
+
+// Upload copies a file into the guest.
+type Upload struct {
+	c *Client
+	// Offset is where the content continues from. The agent keeps what it
+	// received of a file until the file is complete, so a new upload of the
+	// same file resumes an earlier one that was cut off.
+	Offset int64
+}
+
+// PutFile starts copying f, with the checksum of its whole content, into the
+// guest.
+func (c *Client) PutFile(f File) (*Upload, error) {
+	if c.version < 3 {
+		return nil, fmt.Errorf("guest agent speaks protocol version %d, copying files needs version 3", c.version)
+	}
+	put := &agentProto.PutFile{Path: f.Path, Mode: uint32(f.Mode.Perm()), Size: f.Size, Sha256: f.SHA256}
+	if err := c.conn.write(&agentProto.Frame{Body: &agentProto.Frame_PutFile{PutFile: put}}); err != nil {
+		return nil, fmt.Errorf("failed to send file: %v", err)
+	}
+
+	frame, err := c.conn.read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the start of the upload: %v", err)
+	}
+	switch body := frame.Body.(type) {
+	case *agentProto.Frame_FileInfo:
+		return &Upload{c: c, Offset: body.FileInfo.Offset}, nil
+	case *agentProto.Frame_Error:
+		return nil, fmt.Errorf("guest agent refused the file: %s", body.Error.Message)
+	}
+	return nil, fmt.Errorf("unexpected frame %T before the upload started", frame.Body)
+}
+
+// Write sends data, the content of the file at Offset, and moves Offset past
+// it.
+func (u *Upload) Write(data []byte) error {
+	for len(data) > 0 {
+		n := min(len(data), ChunkSize)
+		chunk := &agentProto.Chunk{Offset: u.Offset, Data: data[:n]}
+		if err := u.c.conn.write(&agentProto.Frame{Body: &agentProto.Frame_Chunk{Chunk: chunk}}); err != nil {
+			return u.c.failure(fmt.Errorf("failed to send chunk: %v", err))
+		}
+		u.Offset += int64(n)
+		data = data[n:]
+	}
+	return nil
+}
+
+// Close ends the content and returns the file once the agent checked it and
+// moved it into place, then hangs up.
+func (u *Upload) Close() (File, error) {
+	defer u.c.Close()
+	chunk := &agentProto.Chunk{Offset: u.Offset, Eof: true}
+	if err := u.c.conn.write(&agentProto.Frame{Body: &agentProto.Frame_Chunk{Chunk: chunk}}); err != nil {
+		return File{}, u.c.failure(fmt.Errorf("failed to send the end of the file: %v", err))
+	}
+
+	frame, err := u.c.conn.read()
+	if err != nil {
+		return File{}, fmt.Errorf("lost connection to the guest agent: %v", err)
+	}
+	switch body := frame.Body.(type) {
+	case *agentProto.Frame_FileInfo:
+		return fileFromProto(body.FileInfo), nil
+	case *agentProto.Frame_Error:
+		return File{}, fmt.Errorf("guest agent failed to store the file: %s", body.Error.Message)
+	}
+	return File{}, fmt.Errorf("unexpected frame %T at the end of the upload", frame.Body)
+}
+
+// Abort hangs up. The agent keeps what it received for the next upload of
+// the file.
+func (u *Upload) Abort() error {
+	return u.c.Close()
+}
+
+// Download copies a file out of the guest.
+type Download struct {
+	c *Client
+	// File describes the whole file, even if the download started further in
+	File   File
+	offset int64
+	// hash is only set for downloads of the whole file, whose checksum can
+	// be checked
+	hash hash.Hash
+	done bool
+}
+
+// GetFile starts copying the file at path out of the guest from offset.
+func (c *Client) GetFile(path string, offset int64) (*Download, error) {
+	if c.version < 3 {
+		return nil, fmt.Errorf("guest agent speaks protocol version %d, copying files needs version 3", c.version)
+	}
+	get := &agentProto.GetFile{Path: path, Offset: offset}
+	if err := c.conn.write(&agentProto.Frame{Body: &agentProto.Frame_GetFile{GetFile: get}}); err != nil {
+		return nil, fmt.Errorf("failed to request file: %v", err)
+	}
+
+	frame, err := c.conn.read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the start of the download: %v", err)
+	}
+	switch body := frame.Body.(type) {
+	case *agentProto.Frame_FileInfo:
+		d := &Download{c: c, File: fileFromProto(body.FileInfo), offset: offset}
+		if offset == 0 {
+			d.hash = sha256.New()
+		}
+		return d, nil
+	case *agentProto.Frame_Error:
+		return nil, fmt.Errorf("guest agent refused to send the file: %s", body.Error.Message)
+	}
+	return nil, fmt.Errorf("unexpected frame %T before the download started", frame.Body)
+}
+
+// Next returns the next part of the content and its offset in the file, and
+// io.EOF after the last one, when it hangs up. If the size or checksum of the
+// whole file is wrong, the last part comes with the error, and the file that
+// was downloaded is not the one File describes.
+func (d *Download) Next() (int64, []byte, error) {
+	if d.done {
+		return 0, nil, io.EOF
+	}
+	frame, err := d.c.conn.read()
+	if err != nil {
+		return 0, nil, fmt.Errorf("lost connection to the guest agent: %v", err)
+	}
+	switch body := frame.Body.(type) {
+	case *agentProto.Frame_Chunk:
+		chunk := body.Chunk
+		if chunk.Offset != d.offset {
+			return 0, nil, fmt.Errorf("got chunk at offset %d, want %d", chunk.Offset, d.offset)
+		}
+		d.offset += int64(len(chunk.Data))
+		if d.hash != nil {
+			d.hash.Write(chunk.Data)
+		}
+		if chunk.Eof {
+			d.done = true
+			d.c.Close()
+			if err := d.check(); err != nil {
+				return chunk.Offset, chunk.Data, err
+			}
+		}
+		return chunk.Offset, chunk.Data, nil
+	case *agentProto.Frame_Error:
+		return 0, nil, fmt.Errorf("guest agent failed to send the file: %s", body.Error.Message)
+	}
+	return 0, nil, fmt.Errorf("unexpected frame %T while the file is sent", frame.Body)
+}
+
+// check checks the size of the downloaded file and, if it was downloaded
+// whole, its checksum.
+func (d *Download) check() error {
+	if d.offset != d.File.Size {
+		return fmt.Errorf("file ended after %d of %d bytes", d.offset, d.File.Size)
+	}
+	if d.hash != nil && !bytes.Equal(d.hash.Sum(nil), d.File.SHA256) {
+		return fmt.Errorf("checksum of %s does not match, it changed while it was sent", d.File.Path)
+	}
+	return nil
+}
+
+// Close hangs up.
+func (d *Download) Close() error {
+	return d.c.Close()
+}
+
+// failure returns the error the agent sent before it hung up, or err.
+func (c *Client) failure(err error) error {
+	if frame, readErr := c.conn.read(); readErr == nil && frame.GetError() != nil {
+		return errors.New(frame.GetError().Message)
+	}
+	return err
+}
+
+func fileFromProto(info *agentProto.FileInfo) File {
+	return File{Path: info.Path, Mode: os.FileMode(info.Mode).Perm(), Size: info.Size, SHA256: info.Sha256}
+}
+
+// servePut stores the file the runner sends, first under a partial name
+// that a later upload of the same file resumes.
+func servePut(c *frameConn, put *agentProto.PutFile) {
+	if err := receiveFile(c, put); err != nil {
+		c.write(errorFrame(err))
+	}
+}
+
+func receiveFile(c *frameConn, put *agentProto.PutFile) error {
+	if !filepath.IsAbs(put.Path) {
+		return fmt.Errorf("path %q is not absolute", put.Path)
+	}
+	if len(put.Sha256) != sha256.Size {
+		return fmt.Errorf("checksum of %d bytes is not a SHA-256", len(put.Sha256))
+	}
+	if put.Size < 0 {
+		return fmt.Errorf("negative size %d", put.Size)
+	}
+	if err := os.MkdirAll(filepath.Dir(put.Path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	// naming the partial file after the checksum resumes only uploads of the
+	// same content
+	partial := fmt.Sprintf("%s.part-%x", put.Path, put.Sha256[:8])
+	f, err := os.OpenFile(partial, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	if offset > put.Size {
+		if err := f.Truncate(0); err != nil {
+			return fmt.Errorf("failed to truncate file: %v", err)
+		}
+		offset, _ = f.Seek(0, io.SeekStart)
+	}
+	if err := c.write(&agentProto.Frame{Body: &agentProto.Frame_FileInfo{FileInfo: &agentProto.FileInfo{Path: put.Path, Offset: offset}}}); err != nil {
+		return err
+	}
+
+	for eof := false; !eof; {
+		frame, err := c.read()
+		if err != nil {
+			return fmt.Errorf("failed to read chunk: %v", err)
+		}
+		chunk := frame.GetChunk()
+		if chunk == nil {
+			return fmt.Errorf("unexpected frame %T while the file is sent", frame.Body)
+		}
+		if chunk.Offset != offset {
+			return fmt.Errorf("got chunk at offset %d, want %d", chunk.Offset, offset)
+		}
+		if offset+int64(len(chunk.Data)) > put.Size {
+			return fmt.Errorf("file is larger than %d bytes", put.Size)
+		}
+		n, err := f.Write(chunk.Data)
+		offset += int64(n)
+		if err != nil {
+			return fmt.Errorf("failed to write file: %v", err)
+		}
+		eof = chunk.Eof
+	}
+	if offset != put.Size {
+		return fmt.Errorf("file ended after %d of %d bytes", offset, put.Size)
+	}
+
+	sum, err := checksum(f)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(sum, put.Sha256) {
+		// the content is wrong, so there is nothing to resume
+		os.Remove(partial)
+		return fmt.Errorf("checksum of %s does not match", put.Path)
+	}
+	mode := os.FileMode(put.Mode).Perm()
+	if err := f.Chmod(mode); err != nil {
+		return fmt.Errorf("failed to set permissions: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	if err := os.Rename(partial, put.Path); err != nil {
+		return fmt.Errorf("failed to move file into place: %v", err)
+	}
+
+	info := &agentProto.FileInfo{Path: put.Path, Mode: uint32(mode), Size: put.Size, Sha256: sum}
+	return c.write(&agentProto.Frame{Body: &agentProto.Frame_FileInfo{FileInfo: info}})
+}
+
+// serveGet sends the file the runner asks for.
+func serveGet(c *frameConn, get *agentProto.GetFile) {
+	if err := sendFile(c, get); err != nil {
+		c.write(errorFrame(err))
+	}
+}
+
+func sendFile(c *frameConn, get *agentProto.GetFile) error {
+	f, err := os.Open(get.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	if !st.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", get.Path)
+	}
+	if get.Offset < 0 || get.Offset > st.Size() {
+		return fmt.Errorf("offset %d is outside of the %d bytes of the file", get.Offset, st.Size())
+	}
+
+	sum, err := checksum(f)
+	if err != nil {
+		return err
+	}
+	info := &agentProto.FileInfo{Path: get.Path, Mode: uint32(st.Mode().Perm()), Size: st.Size(), Sha256: sum}
+	if err := c.write(&agentProto.Frame{Body: &agentProto.Frame_FileInfo{FileInfo: info}}); err != nil {
+		return err
+	}
+
+	offset, err := f.Seek(get.Offset, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %v", err)
+	}
+	buf := make([]byte, ChunkSize)
+	for {
+		n, err := io.ReadFull(f, buf)
+		eof := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !eof {
+			return fmt.Errorf("failed to read file: %v", err)
+		}
+		chunk := &agentProto.Chunk{Offset: offset, Data: buf[:n], Eof: eof}
+		if err := c.write(&agentProto.Frame{Body: &agentProto.Frame_Chunk{Chunk: chunk}}); err != nil {
+			return err
+		}
+		offset += int64(n)
+		if eof {
+			return nil
+		}
+	}
+}
+
+// checksum returns the SHA-256 of the content of f.
+func checksum(f *os.File) ([]byte, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+	return h.Sum(nil), nil
+}
+
+func errorFrame(err error) *agentProto.Frame {
+	return &agentProto.Frame{Body: &agentProto.Frame_Error{Error: &agentProto.Error{Message: err.Error()}}}
+}
@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	agentProto "github.com/bookpanda/firecracker-runner-node/proto/agent/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Versions are the protocol versions this package speaks, oldest first. See
+// proto/agent.proto for the protocol.
+var Versions = []int{1, 2, 3}
+
+// heartbeatInterval is how long either side may stay silent before it sends
+// a heartbeat. After three missed heartbeats the other side is given up on.
+// Tests shorten it.
+var heartbeatInterval = 5 * time.Second
+
+const (
+	helloTimeout = 10 * time.Second
+	maxFrameSize = 1 << 20
+)
+
+var (
+	// ErrLegacyAgent is returned by NewClient if the agent does not answer
+	// HELLO with VERSION, as agents that only speak the line protocol do. The
+	// connection is then unusable, and the agent ran the HELLO line as a
+	// command, so callers should remember the answer rather than probe again.
+	ErrLegacyAgent = errors.New("guest agent only speaks the line protocol")
+	// ErrNoHeartbeat is returned when the other side went silent for too long.
+	ErrNoHeartbeat = errors.New("no heartbeat from the other side")
+)
+
+// hello is the line of the runner offering versions.
+func hello(versions []int) string {
+	fields := make([]string, 0, len(versions))
+	for _, v := range versions {
+		fields = append(fields, strconv.Itoa(v))
+	}
+	return "HELLO " + strings.Join(fields, " ") + "\n"
+}
+
+// parseVersions returns the versions of a line starting with keyword, HELLO
+// or VERSION, and false if line is not one.
+func parseVersions(line, keyword string) ([]int, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSuffix(line, "\n"), keyword+" ")
+	if !ok {
+		return nil, false
+	}
+	var versions []int
+	for _, field := range strings.Fields(rest) {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, false
+		}
+		versions = append(versions, v)
+	}
+	return versions, len(versions) > 0
+}
+
+// pickVersion returns the newest version in offered that this package
+// speaks, or 0 if there is none.
+func pickVersion(offered []int) int {
+	picked := 0
+	for _, v := range offered {
+		for _, known := range Versions {
+			if v == known && v > picked {
+				picked = v
+			}
+		}
+	}
+	return picked
+}
+
+// frameConn sends and receives frames on a connection after HELLO.
+type frameConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+
+	writeMu   sync.Mutex
+	lastRead  atomic.Int64 // unix nanos
+	lastWrite atomic.Int64
+	silent    atomic.Bool // set once the other side missed its heartbeats
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newFrameConn(conn net.Conn, r *bufio.Reader) *frameConn {
+	c := &frameConn{conn: conn, r: r, done: make(chan struct{})}
+	now := time.Now().UnixNano()
+	c.lastRead.Store(now)
+	c.lastWrite.Store(now)
+	return c
+}
+
+func (c *frameConn) write(frame *agentProto.Frame) error {
+	data, err := proto.Marshal(frame)
+	if err != nil {
+		return fmt.Errorf("failed to encode frame: %v", err)
+	}
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := c.conn.Write(buf); err != nil {
+		return c.err(err)
+	}
+	c.lastWrite.Store(time.Now().UnixNano())
+	return nil
+}
+
+// read returns the next frame that is not a heartbeat.
+func (c *frameConn) read() (*agentProto.Frame, error) {
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(c.r, size[:]); err != nil {
+			return nil, c.err(err)
+		}
+		n := binary.BigEndian.Uint32(size[:])
+		if n > maxFrameSize {
+			return nil, fmt.Errorf("frame of %d bytes is larger than %d", n, maxFrameSize)
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, c.err(err)
+		}
+		c.lastRead.Store(time.Now().UnixNano())
+
+		frame := &agentProto.Frame{}
+		if err := proto.Unmarshal(data, frame); err != nil {
+			return nil, fmt.Errorf("failed to decode frame: %v", err)
+		}
+		if frame.GetHeartbeat() == nil {
+			return frame, nil
+		}
+	}
+}
+
+// err replaces the error of a connection closed for missing heartbeats.
+func (c *frameConn) err(err error) error {
+	if c.silent.Load() {
+		return ErrNoHeartbeat
+	}
+	return err
+}
+
+// keepAlive sends a heartbeat whenever nothing was sent for an interval, and
+// closes the connection once nothing was received for three, until the
+// connection is closed. While suspended, which may be nil, reports true the
+// other side is not expected to send anything.
+func (c *frameConn) keepAlive(suspended func() bool) {
+	interval, timeout := heartbeatInterval, 3*heartbeatInterval
+	ticker := time.NewTicker(interval / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case now := <-ticker.C:
+			if suspended != nil && suspended() {
+				c.lastRead.Store(now.UnixNano())
+			}
+			if now.Sub(time.Unix(0, c.lastRead.Load())) > timeout {
+				c.silent.Store(true)
+				c.close()
+				return
+			}
+			if now.Sub(time.Unix(0, c.lastWrite.Load())) >= interval {
+				c.write(&agentProto.Frame{Body: &agentProto.Frame_Heartbeat{Heartbeat: &agentProto.Heartbeat{}}})
+			}
+		}
+	}
+}
+
+func (c *frameConn) close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	agentProto "github.com/bookpanda/firecracker-runner-node/proto/agent/v1"
+)
+
+// Serve serves the connections l accepts until l fails.
+func Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go ServeConn(conn)
+	}
+}
+
+// ServeConn runs the command conn sends, or copies the file it asks for, and
+// closes it. A runner that does not start with HELLO is served the line
+// protocol: the first line is run with sh -c, its stdout and stderr are
+// written back merged and, if it fails, a last "error: <err>" line.
+func ServeConn(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(helloTimeout))
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	offered, ok := parseVersions(line, "HELLO")
+	if !ok {
+		serveLine(conn, line[:len(line)-1])
+		return
+	}
+	version := pickVersion(offered)
+	if version == 0 {
+		fmt.Fprintf(conn, "ERROR no common protocol version, this agent speaks %v\n", Versions)
+		return
+	}
+	if _, err := fmt.Fprintf(conn, "VERSION %d\n", version); err != nil {
+		return
+	}
+
+	c := newFrameConn(conn, r)
+	defer c.close()
+	go c.keepAlive(nil)
+
+	frame, err := c.read()
+	if err != nil {
+		log.Printf("failed to read command: %v", err)
+		return
+	}
+	switch body := frame.Body.(type) {
+	case *agentProto.Frame_Exec:
+		serveExec(c, body.Exec)
+	case *agentProto.Frame_PutFile:
+		servePut(c, body.PutFile)
+	case *agentProto.Frame_GetFile:
+		serveGet(c, body.GetFile)
+	default:
+		log.Printf("unexpected frame %T before the command", frame.Body)
+	}
+}
+
+// serveExec runs e, forwarding its output and what the runner sends for it,
+// and kills it if the runner goes away.
+func serveExec(c *frameConn, e *agentProto.Exec) {
+	p, err := job.Start(job.Spec{
+		Argv:  e.Argv,
+		Env:   e.Env,
+		Dir:   e.Cwd,
+		TTY:   e.Tty,
+		Rows:  uint16(e.GetSize().GetRows()),
+		Cols:  uint16(e.GetSize().GetCols()),
+		Stdin: e.Stdin,
+	})
+	if err != nil {
+		c.write(exitFrame(&agentProto.Exit{ExitCode: -1, Error: err.Error()}))
+		return
+	}
+	c.write(&agentProto.Frame{Body: &agentProto.Frame_Started{Started: &agentProto.Started{Pid: int64(p.Pid())}}})
+
+	go func() {
+		for {
+			frame, err := c.read()
+			if err != nil {
+				// the runner hung up or stopped sending heartbeats
+				p.Kill()
+				return
+			}
+			switch body := frame.Body.(type) {
+			case *agentProto.Frame_Signal:
+				p.Signal(syscall.Signal(body.Signal.Signal))
+			case *agentProto.Frame_Stdin:
+				if len(body.Stdin.Data) > 0 {
+					p.Write(body.Stdin.Data)
+				}
+				if body.Stdin.Eof {
+					p.CloseStdin()
+				}
+			case *agentProto.Frame_Resize:
+				p.Resize(uint16(body.Resize.GetSize().GetRows()), uint16(body.Resize.GetSize().GetCols()))
+			}
+		}
+	}()
+
+	result, _ := p.Wait(func(stream job.Stream, data []byte) {
+		c.write(&agentProto.Frame{Body: &agentProto.Frame_Output{Output: &agentProto.Output{Stream: agentProto.Stream(stream), Data: data}}})
+	})
+	c.write(exitFrame(&agentProto.Exit{ExitCode: int32(result.ExitCode), Signal: result.Signal, Error: result.Error}))
+}
+
+func exitFrame(exit *agentProto.Exit) *agentProto.Frame {
+	return &agentProto.Frame{Body: &agentProto.Frame_Exit{Exit: exit}}
+}
+
+// serveLine runs command for a runner that speaks the line protocol.
+func serveLine(conn net.Conn, command string) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdout = conn
+	cmd.Stderr = conn
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(conn, "error: %v\n", err)
+	}
+}
@@ -1,19 +1,218 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
 )
 
+// EnvPrefix starts the environment variable of every flag, e.g. RUNNER_PORT
+// for -port or RUNNER_RUNS_DIR for -runs-dir.
+const EnvPrefix = "RUNNER_"
+
+// maxLinkName is the longest name of a network interface, IFNAMSIZ - 1.
+const maxLinkName = 15
+
+// Config is the configuration of a runner. Several runners can share a host
+// as long as they differ in port, directories, bridge and tap prefix.
+//
+// It is read from the defaults, then a YAML file whose keys are the flag
+// names, then RUNNER_* environment variables and last the flags.
 type Config struct {
-	Port int
+	Port        int    `yaml:"port"`
+	Subnet      string `yaml:"subnet"`
+	RunsDir     string `yaml:"runs-dir"`
+	MetricsAddr string `yaml:"metrics-addr"`
+
+	// SocketDir holds the API and vsock sockets of the VMs
+	SocketDir       string `yaml:"socket-dir"`
+	SnapshotsDir    string `yaml:"snapshots-dir"`
+	NetworkStateDir string `yaml:"network-state-dir"`
+
+	Bridge    string `yaml:"bridge"`
+	TapPrefix string `yaml:"tap-prefix"`
+
+	FirecrackerBin string `yaml:"firecracker-bin"`
+	// VsockPort is the guest port the agent listens on
+	VsockPort int `yaml:"vsock-port"`
+	// VMStopTimeout is how long a VM may take to shut down before its
+	// firecracker process is killed
+	VMStopTimeout time.Duration `yaml:"vm-stop-timeout"`
+	// ShutdownTimeout is how long the runner may take to stop after a
+	// signal before it exits anyway
+	ShutdownTimeout time.Duration `yaml:"shutdown-timeout"`
 }
 
-func ParseFlags() *Config {
-	cfg := &Config{}
+// Default returns the documented defaults.
+func Default() *Config {
+	return &Config{
+		Port:            50051,
+		Subnet:          "192.168.100.0/24",
+		RunsDir:         "./runs",
+		SocketDir:       os.TempDir(),
+		SnapshotsDir:    "./vm-snapshots",
+		NetworkStateDir: "./vm-network",
+		Bridge:          "br0",
+		TapPrefix:       "tap",
+		FirecrackerBin:  "firecracker",
+		VsockPort:       1234,
+		VMStopTimeout:   2 * time.Second,
+		ShutdownTimeout: 2 * time.Second,
+	}
+}
 
-	flag.IntVar(&cfg.Port, "port", 50051, "Port to listen on")
+// flags binds the flags of fs to c, with the current values of c as their
+// defaults.
+func (c *Config) flags(fs *flag.FlagSet) {
+	fs.IntVar(&c.Port, "port", c.Port, "Port to listen on")
+	fs.StringVar(&c.Subnet, "subnet", c.Subnet, "Subnet to allocate guest IPs from; its first address is the gateway")
+	fs.StringVar(&c.RunsDir, "runs-dir", c.RunsDir, "Directory to keep the artifacts of every run in")
+	fs.StringVar(&c.MetricsAddr, "metrics-addr", c.MetricsAddr, "Address to serve Prometheus metrics on at /metrics, e.g. :9090; disabled if empty")
+	fs.StringVar(&c.SocketDir, "socket-dir", c.SocketDir, "Directory for the API and vsock sockets of the VMs")
+	fs.StringVar(&c.SnapshotsDir, "snapshots-dir", c.SnapshotsDir, "Directory to keep VM snapshots in")
+	fs.StringVar(&c.NetworkStateDir, "network-state-dir", c.NetworkStateDir, "Directory for the network state and IP leases")
+	fs.StringVar(&c.Bridge, "bridge", c.Bridge, "Name of the bridge the taps of the VMs are attached to")
+	fs.StringVar(&c.TapPrefix, "tap-prefix", c.TapPrefix, "Prefix of the tap names, followed by the slot of the VM")
+	fs.StringVar(&c.FirecrackerBin, "firecracker-bin", c.FirecrackerBin, "Firecracker binary to run")
+	fs.IntVar(&c.VsockPort, "vsock-port", c.VsockPort, "Vsock port of the guest agent")
+	fs.DurationVar(&c.VMStopTimeout, "vm-stop-timeout", c.VMStopTimeout, "How long a VM may take to shut down before it is killed")
+	fs.DurationVar(&c.ShutdownTimeout, "shutdown-timeout", c.ShutdownTimeout, "How long the runner may take to shut down after a signal")
+}
 
-	flag.Parse()
+// envName returns the environment variable of the flag with the given name.
+func envName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
 
+// ParseFlags loads the configuration from the command line, see Load, and
+// exits if it is invalid.
+func ParseFlags() *Config {
+	cfg, err := Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 	return cfg
 }
+
+// Load reads the file named by -config or RUNNER_CONFIG, if any, over the
+// defaults, then the RUNNER_* environment variables, then the flags in args,
+// and validates the result.
+func Load(args []string) (*Config, error) {
+	// the first pass only finds the file, and fails on unknown flags
+	// before it is read
+	first := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	Default().flags(first)
+	path := first.String("config", os.Getenv(envName("config")), "YAML file to read the configuration from; "+EnvPrefix+"* environment variables and flags override it")
+	if err := first.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if *path != "" {
+		if err := cfg.loadFile(*path); err != nil {
+			return nil, err
+		}
+	}
+
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	cfg.flags(fs)
+	fs.String("config", "", "")
+
+	var envErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(envName(f.Name))
+		if !ok || f.Name == "config" || envErr != nil {
+			return
+		}
+		if err := fs.Set(f.Name, value); err != nil {
+			envErr = fmt.Errorf("invalid %s: %v", envName(f.Name), err)
+		}
+	})
+	if envErr != nil {
+		return nil, envErr
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %v", err)
+	}
+	return cfg, nil
+}
+
+// loadFile reads the YAML file at path into c. Keys that are not flag names
+// are rejected.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	return nil
+}
+
+// Validate checks that every setting is usable.
+func (c *Config) Validate() error {
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("port %d is out of range", c.Port)
+	}
+	if _, _, err := net.ParseCIDR(c.Subnet); err != nil {
+		return fmt.Errorf("invalid subnet %q", c.Subnet)
+	}
+	if c.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddr); err != nil {
+			return fmt.Errorf("invalid metrics address %q: %v", c.MetricsAddr, err)
+		}
+	}
+
+	for name, dir := range map[string]string{
+		"runs-dir":          c.RunsDir,
+		"socket-dir":        c.SocketDir,
+		"snapshots-dir":     c.SnapshotsDir,
+		"network-state-dir": c.NetworkStateDir,
+	} {
+		if dir == "" {
+			return fmt.Errorf("%s is empty", name)
+		}
+	}
+
+	if c.Bridge == "" || len(c.Bridge) > maxLinkName {
+		return fmt.Errorf("bridge name %q must be 1 to %d characters", c.Bridge, maxLinkName)
+	}
+	// leaves room for slots up to 9999
+	if c.TapPrefix == "" || len(c.TapPrefix) > maxLinkName-4 {
+		return fmt.Errorf("tap prefix %q must be 1 to %d characters", c.TapPrefix, maxLinkName-4)
+	}
+	if strings.HasPrefix(c.Bridge, c.TapPrefix) {
+		return fmt.Errorf("bridge %s starts with the tap prefix %s", c.Bridge, c.TapPrefix)
+	}
+
+	if c.FirecrackerBin == "" {
+		return fmt.Errorf("firecracker-bin is empty")
+	}
+	if c.VsockPort <= 0 || uint64(c.VsockPort) > math.MaxUint32 {
+		return fmt.Errorf("vsock port %d is out of range", c.VsockPort)
+	}
+	if c.VMStopTimeout <= 0 {
+		return fmt.Errorf("vm-stop-timeout must be positive")
+	}
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("shutdown-timeout must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "runner.yaml")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Fatalf("loaded %+v without a file, env or flags, want the defaults %+v", cfg, Default())
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+port: 6000
+bridge: br1
+tap-prefix: fc1tap
+runs-dir: /srv/runner1/runs
+vm-stop-timeout: 5s
+`)
+	t.Setenv("RUNNER_CONFIG", path)
+	t.Setenv("RUNNER_PORT", "7000")
+	t.Setenv("RUNNER_SOCKET_DIR", "/run/runner1")
+
+	cfg, err := Load([]string{"-port=8000", "-vsock-port", "5000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// flags beat env vars, which beat the file, which beats the defaults
+	want := Default()
+	want.Port = 8000
+	want.SocketDir = "/run/runner1"
+	want.Bridge = "br1"
+	want.TapPrefix = "fc1tap"
+	want.RunsDir = "/srv/runner1/runs"
+	want.VMStopTimeout = 5 * time.Second
+	want.VsockPort = 5000
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("loaded %+v, want %+v", cfg, want)
+	}
+
+	// -config beats RUNNER_CONFIG
+	other := writeConfig(t, "bridge: br2\n")
+	cfg, err = Load([]string{"-config", other})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Bridge != "br2" || cfg.TapPrefix != "tap" || cfg.Port != 7000 {
+		t.Fatalf("loaded bridge %s, tap prefix %s, port %d from -config", cfg.Bridge, cfg.TapPrefix, cfg.Port)
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	for name, tc := range map[string]struct {
+		file string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		"unknown key":       {file: "brigde: br1\n", want: "brigde"},
+		"unknown flag":      {args: []string{"-brigde=br1"}, want: "brigde"},
+		"bad env var":       {env: map[string]string{"RUNNER_PORT": "http"}, want: "RUNNER_PORT"},
+		"port":              {args: []string{"-port=70000"}, want: "port"},
+		"subnet":            {args: []string{"-subnet=192.168.100.0"}, want: "subnet"},
+		"long bridge":       {args: []string{"-bridge=bridge-of-runner-1"}, want: "bridge"},
+		"bridge is a tap":   {args: []string{"-bridge=tapbr"}, want: "tap prefix"},
+		"empty dir":         {file: "snapshots-dir: \"\"\n", want: "snapshots-dir"},
+		"stop timeout":      {args: []string{"-vm-stop-timeout=0s"}, want: "vm-stop-timeout"},
+		"metrics address":   {args: []string{"-metrics-addr=9090"}, want: "metrics address"},
+		"missing file":      {args: []string{"-config=/nonexistent/runner.yaml"}, want: "config file"},
+		"bad duration file": {file: "shutdown-timeout: soon\n", want: "config file"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			args := tc.args
+			if tc.file != "" {
+				args = append([]string{"-config", writeConfig(t, tc.file)}, args...)
+			}
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := Load(args)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("got error %v, want one about %q", err, tc.want)
+			}
+		})
+	}
+}
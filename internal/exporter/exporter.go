@@ -0,0 +1,211 @@
+package exporter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/hypervisor"
+	"github.com/bookpanda/firecracker-runner-node/internal/network"
+	"github.com/bookpanda/firecracker-runner-node/internal/node"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
+	"github.com/bookpanda/firecracker-runner-node/internal/vm"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Exporter serves the metrics of the runner and its VMs to Prometheus.
+type Exporter struct {
+	registry  *prometheus.Registry
+	requests  *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+}
+
+// New exports the metrics of sources, of the gRPC requests passed through
+// the interceptors of the Exporter, and of the runner process itself.
+func New(sources Sources) *Exporter {
+	e := &Exporter{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "runner_grpc_requests_total",
+			Help: "Number of gRPC requests handled, by method and status code.",
+		}, []string{"method", "code"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "runner_grpc_request_duration_seconds",
+			Help:    "Time taken to handle gRPC requests, by method.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 10), // 1ms to about 4m
+		}, []string{"method"}),
+	}
+	e.registry.MustRegister(
+		e.requests,
+		e.durations,
+		collector{sources: sources},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return e
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (e *Exporter) Handler() http.Handler {
+	return promhttp.HandlerFor(e.registry, promhttp.HandlerOpts{})
+}
+
+// VM is what is exported about a VM.
+type VM struct {
+	IP    string
+	State string
+	PID   int // 0 if the VM has no firecracker process
+	// Firecracker holds the counters of the firecracker metrics file summed
+	// since the VM started, and the latest value of every timing
+	Firecracker map[string]uint64
+}
+
+// Sources are read on every scrape. A nil source is not exported.
+type Sources struct {
+	VMs func() []VM
+	// Taps returns the number of taps the runner created
+	Taps func() int
+	// TraceSessions returns the running tracing sessions by target, "vm" or
+	// "node"
+	TraceSessions func() map[string]int
+}
+
+var (
+	vmsDesc = prometheus.NewDesc("runner_vms",
+		"Number of VMs by state.", []string{"state"}, nil)
+	tapsDesc = prometheus.NewDesc("runner_taps",
+		"Number of taps created by the runner.", nil, nil)
+	traceSessionsDesc = prometheus.NewDesc("runner_trace_sessions",
+		"Number of running syscall tracing sessions by target.", []string{"target"}, nil)
+
+	vmCPUDesc = prometheus.NewDesc("runner_vm_cpu_seconds_total",
+		"User and system CPU time of the firecracker process of a VM.", []string{"ip"}, nil)
+	vmRSSDesc = prometheus.NewDesc("runner_vm_resident_memory_bytes",
+		"Resident memory of the firecracker process of a VM.", []string{"ip"}, nil)
+	vmThreadsDesc = prometheus.NewDesc("runner_vm_threads",
+		"Threads of the firecracker process of a VM.", []string{"ip"}, nil)
+	vmReadDesc = prometheus.NewDesc("runner_vm_io_read_bytes_total",
+		"Bytes the firecracker process of a VM read from storage.", []string{"ip"}, nil)
+	vmWriteDesc = prometheus.NewDesc("runner_vm_io_write_bytes_total",
+		"Bytes the firecracker process of a VM wrote to storage.", []string{"ip"}, nil)
+)
+
+// vmStates are exported even when no VM is in them, so that a state
+// going to zero shows as such.
+var vmStates = []vm.State{vm.StateCreating, vm.StateRunning, vm.StatePaused, vm.StateStopping, vm.StateStopped, vm.StateFailed}
+
+// collector exports the state of the runner at scrape time.
+type collector struct {
+	sources Sources
+}
+
+// Describe sends nothing: the names of the firecracker metrics are only
+// known once a VM has flushed them, so the collector is unchecked.
+func (c collector) Describe(chan<- *prometheus.Desc) {}
+
+func (c collector) Collect(ch chan<- prometheus.Metric) {
+	if c.sources.VMs != nil {
+		c.collectVMs(ch, c.sources.VMs())
+	}
+	if c.sources.Taps != nil {
+		ch <- prometheus.MustNewConstMetric(tapsDesc, prometheus.GaugeValue, float64(c.sources.Taps()))
+	}
+	if c.sources.TraceSessions != nil {
+		for target, n := range c.sources.TraceSessions() {
+			ch <- prometheus.MustNewConstMetric(traceSessionsDesc, prometheus.GaugeValue, float64(n), target)
+		}
+	}
+}
+
+func (c collector) collectVMs(ch chan<- prometheus.Metric, vms []VM) {
+	byState := make(map[string]int)
+	for _, state := range vmStates {
+		byState[string(state)] = 0
+	}
+	for _, v := range vms {
+		byState[v.State]++
+	}
+	for state, n := range byState {
+		ch <- prometheus.MustNewConstMetric(vmsDesc, prometheus.GaugeValue, float64(n), state)
+	}
+
+	for _, v := range vms {
+		if v.PID > 0 {
+			// the process may be gone by now
+			if stat, err := readProcStat(v.PID); err == nil {
+				ch <- prometheus.MustNewConstMetric(vmCPUDesc, prometheus.CounterValue, stat.CPUSeconds, v.IP)
+				ch <- prometheus.MustNewConstMetric(vmRSSDesc, prometheus.GaugeValue, float64(stat.RSSBytes), v.IP)
+				ch <- prometheus.MustNewConstMetric(vmThreadsDesc, prometheus.GaugeValue, float64(stat.Threads), v.IP)
+				if stat.HasIO {
+					ch <- prometheus.MustNewConstMetric(vmReadDesc, prometheus.CounterValue, float64(stat.ReadBytes), v.IP)
+					ch <- prometheus.MustNewConstMetric(vmWriteDesc, prometheus.CounterValue, float64(stat.WriteBytes), v.IP)
+				}
+			}
+		}
+
+		for name, value := range v.Firecracker {
+			valueType, metricName := prometheus.CounterValue, firecrackerMetricName(name)+"_total"
+			if hypervisor.IsTiming(name) {
+				valueType, metricName = prometheus.GaugeValue, firecrackerMetricName(name)
+			}
+			desc := prometheus.NewDesc(metricName, "Firecracker metric "+name+" of a VM.", []string{"ip"}, nil)
+			ch <- prometheus.MustNewConstMetric(desc, valueType, float64(value), v.IP)
+		}
+	}
+}
+
+// firecrackerMetricName turns a firecracker metric such as
+// "net_eth0.rx_bytes_count" into "firecracker_net_eth0_rx_bytes_count".
+func firecrackerMetricName(name string) string {
+	return "firecracker_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// VMs exports the VMs of the manager of svc.
+func VMs(svc vm.Service) func() []VM {
+	return func() []VM {
+		vms := svc.Manager().ListVMs()
+		exported := make([]VM, 0, len(vms))
+		for _, v := range vms {
+			status := v.Status()
+			pid := status.PID
+			if status.State != vm.StateRunning && status.State != vm.StatePaused {
+				pid = 0
+			}
+			exported = append(exported, VM{IP: v.IP, State: string(status.State), PID: pid, Firecracker: v.MetricsTotals()})
+		}
+		return exported
+	}
+}
+
+// Taps counts the taps n created.
+func Taps(n *network.Manager) func() int {
+	return func() int {
+		return len(n.Taps())
+	}
+}
+
+// TraceSessions counts the running tracing sessions of VMs and of the node.
+func TraceSessions(vms vm.Service, n node.Service) func() map[string]int {
+	return func() map[string]int {
+		return map[string]int{
+			"vm":   running(vms.Manager().ListTraceSessions()),
+			"node": running(n.Manager().ListTraceSessions()),
+		}
+	}
+}
+
+func running(sessions []tracer.Session) int {
+	n := 0
+	for _, session := range sessions {
+		if session.StoppedAt.IsZero() {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,184 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/config"
+	"github.com/bookpanda/firecracker-runner-node/internal/node"
+	"github.com/bookpanda/firecracker-runner-node/internal/run"
+	"github.com/bookpanda/firecracker-runner-node/internal/vm"
+	nodeProto "github.com/bookpanda/firecracker-runner-node/proto/node/v1"
+	vmProto "github.com/bookpanda/firecracker-runner-node/proto/vm/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func scrape(t *testing.T, e *Exporter) string {
+	t.Helper()
+
+	server := httptest.NewServer(e.Handler())
+	defer server.Close()
+
+	res, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestExporterSources(t *testing.T) {
+	e := New(Sources{
+		VMs: func() []VM {
+			return []VM{
+				{IP: "192.168.100.2", State: "running", PID: os.Getpid(), Firecracker: map[string]uint64{
+					"net_eth0.rx_bytes_count": 1500,
+					"latencies_us.pause_vm":   85,
+				}},
+				{IP: "192.168.100.3", State: "running"},
+				{IP: "192.168.100.4", State: "failed"},
+			}
+		},
+		Taps:          func() int { return 3 },
+		TraceSessions: func() map[string]int { return map[string]int{"vm": 2, "node": 0} },
+	})
+	body := scrape(t, e)
+
+	for _, want := range []string{
+		`runner_vms{state="running"} 2`,
+		`runner_vms{state="failed"} 1`,
+		`runner_vms{state="paused"} 0`,
+		`runner_taps 3`,
+		`runner_trace_sessions{target="vm"} 2`,
+		`runner_trace_sessions{target="node"} 0`,
+		`firecracker_net_eth0_rx_bytes_count_total{ip="192.168.100.2"} 1500`,
+		`firecracker_latencies_us_pause_vm{ip="192.168.100.2"} 85`,
+		`runner_vm_threads{ip="192.168.100.2"}`,
+		`runner_vm_cpu_seconds_total{ip="192.168.100.2"}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics are missing %q", want)
+		}
+	}
+	// without a process there are no process stats
+	if strings.Contains(body, `runner_vm_threads{ip="192.168.100.3"}`) {
+		t.Error("exported process stats of a VM without a PID")
+	}
+}
+
+func TestExporterCountsRPCs(t *testing.T) {
+	e := New(Sources{})
+
+	unary := e.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/vm.v1.VmService/GetVm"}
+	for _, err := range []error{nil, nil, status.Error(codes.NotFound, "vm not found")} {
+		unary(context.Background(), nil, info, func(context.Context, any) (any, error) { return nil, err })
+	}
+
+	stream := e.StreamInterceptor()
+	streamInfo := &grpc.StreamServerInfo{FullMethod: "/vm.v1.VmService/WatchSyscalls", IsServerStream: true}
+	stream(nil, nil, streamInfo, func(any, grpc.ServerStream) error { return errors.New("send failed") })
+
+	body := scrape(t, e)
+	for _, want := range []string{
+		`runner_grpc_requests_total{code="OK",method="/vm.v1.VmService/GetVm"} 2`,
+		`runner_grpc_requests_total{code="NotFound",method="/vm.v1.VmService/GetVm"} 1`,
+		`runner_grpc_requests_total{code="Unknown",method="/vm.v1.VmService/WatchSyscalls"} 1`,
+		`runner_grpc_request_duration_seconds_count{method="/vm.v1.VmService/GetVm"} 3`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics are missing %q", want)
+		}
+	}
+}
+
+func TestParseProcStat(t *testing.T) {
+	// a command name with spaces and parentheses
+	line := "4242 (fire (cracker)) S 1 4242 4242 0 -1 4194560 1200 0 0 0 250 150 0 0 20 0 7 0 100 123456789 3000 " +
+		"18446744073709551615 1 1 0 0 0 0 0 0 0 0 0 0 17 3 0 0 0 0 0\n"
+	stat, err := parseProcStat([]byte(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.CPUSeconds != 4 || stat.Threads != 7 || stat.RSSBytes != 3000*int64(os.Getpagesize()) {
+		t.Fatalf("parsed %+v", stat)
+	}
+
+	read, written, ok := parseProcIO([]byte("rchar: 10\nwchar: 20\nread_bytes: 4096\nwrite_bytes: 8192\n"))
+	if !ok || read != 4096 || written != 8192 {
+		t.Fatalf("parsed io read %d, written %d, ok %v", read, written, ok)
+	}
+
+	if _, err := parseProcStat([]byte("4242 (firecracker) S 1")); err == nil {
+		t.Fatal("parsed a truncated stat")
+	}
+}
+
+// Cleanup swaps the managers of the services while scrapes read them; run
+// with -race.
+func TestExporterScrapesDuringCleanup(t *testing.T) {
+	// vm Cleanup kills leftover firecrackers through sudo pkill
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "sudo"), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	runs, err := run.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.NetworkStateDir = t.TempDir()
+	cfg.SnapshotsDir = t.TempDir()
+	vmManager, err := vm.NewManager(cfg, context.Background(), runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vmSvc := vm.NewService(vmManager, zap.NewNop())
+	nodeSvc := node.NewService(node.NewManager(cfg, runs), zap.NewNop())
+
+	e := New(Sources{
+		VMs:           VMs(vmSvc),
+		TraceSessions: TraceSessions(vmSvc, nodeSvc),
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			if _, err := vmSvc.Cleanup(context.Background(), &vmProto.CleanupVmRequest{}); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := nodeSvc.Cleanup(context.Background(), &nodeProto.CleanupNodeRequest{}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			if body := scrape(t, e); !strings.Contains(body, `runner_trace_sessions{target="vm"} 0`) {
+				t.Fatalf("metrics are missing the trace sessions:\n%s", body)
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package exporter
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryInterceptor counts and times unary RPCs.
+func (e *Exporter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+		e.observe(info.FullMethod, start, err)
+		return res, err
+	}
+}
+
+// StreamInterceptor counts and times streaming RPCs, from their start until
+// the handler returns.
+func (e *Exporter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		e.observe(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func (e *Exporter) observe(method string, start time.Time, err error) {
+	e.requests.WithLabelValues(method, status.Code(err).String()).Inc()
+	e.durations.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,95 @@
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// clockTicks is USER_HZ, the unit of the CPU times in /proc/<pid>/stat. It
+// is 100 on every architecture Linux supports.
+const clockTicks = 100
+
+// procStat is what the host knows about a process.
+type procStat struct {
+	CPUSeconds float64
+	RSSBytes   int64
+	Threads    int64
+
+	HasIO      bool // /proc/<pid>/io is only readable by the owner or root
+	ReadBytes  uint64
+	WriteBytes uint64
+}
+
+// readProcStat reads the stats of the process pid from /proc.
+func readProcStat(pid int) (procStat, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return procStat{}, err
+	}
+	stat, err := parseProcStat(data)
+	if err != nil {
+		return procStat{}, fmt.Errorf("failed to parse /proc/%d/stat: %v", pid, err)
+	}
+
+	if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/io", pid)); err == nil {
+		stat.ReadBytes, stat.WriteBytes, stat.HasIO = parseProcIO(data)
+	}
+	return stat, nil
+}
+
+// parseProcStat parses the fields of /proc/<pid>/stat described in proc(5).
+func parseProcStat(data []byte) (procStat, error) {
+	// the command name may contain spaces and parentheses
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return procStat{}, fmt.Errorf("no command name")
+	}
+	// fields[0] is the state, the third field
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("only %d fields", len(fields)+2)
+	}
+
+	var values [4]int64
+	for i, field := range []int{14, 15, 20, 24} {
+		value, err := strconv.ParseInt(fields[field-3], 10, 64)
+		if err != nil {
+			return procStat{}, fmt.Errorf("invalid field %d: %v", field, err)
+		}
+		values[i] = value
+	}
+	utime, stime, threads, rss := values[0], values[1], values[2], values[3]
+
+	return procStat{
+		CPUSeconds: float64(utime+stime) / clockTicks,
+		RSSBytes:   rss * int64(os.Getpagesize()),
+		Threads:    threads,
+	}, nil
+}
+
+// parseProcIO returns the bytes read from and written to storage in
+// /proc/<pid>/io.
+func parseProcIO(data []byte) (read, written uint64, ok bool) {
+	var found int
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		name, value, _ := strings.Cut(scanner.Text(), ":")
+		n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		switch name {
+		case "read_bytes":
+			read = n
+			found++
+		case "write_bytes":
+			written = n
+			found++
+		}
+	}
+	return read, written, found == 2
+}
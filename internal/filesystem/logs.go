@@ -0,0 +1,217 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// logChunkSize is the most bytes of a log sent at once.
+const logChunkSize = 64 * 1024
+
+// LogKind is a kind of log file the runner writes. The values match the
+// LogKind proto enum.
+type LogKind int
+
+const (
+	LogFirecracker  LogKind = iota + 1 // vm-logs/vm-<ip>.log
+	LogMetrics                         // vm-logs/vm-<ip>-metrics
+	LogStdout                          // vm-logs/vm-<ip>.stdout
+	LogStderr                          // vm-logs/vm-<ip>.stderr
+	LogTest                            // vm-test/vm-<ip>-<job ID>.log
+	LogSyscalls                        // vm-syscalls/<session>/vm-<ip>.log
+	LogNode                            // node-logs/node-server-<job ID>.log and node-client-<job ID>.log
+	LogNodeSyscalls                    // node-logs/<session>/node-syscalls-<pid>.log
+)
+
+var logKinds = []LogKind{
+	LogFirecracker, LogMetrics, LogStdout, LogStderr, LogTest, LogSyscalls, LogNode, LogNodeSyscalls,
+}
+
+func (k LogKind) String() string {
+	switch k {
+	case LogFirecracker:
+		return "firecracker"
+	case LogMetrics:
+		return "metrics"
+	case LogStdout:
+		return "stdout"
+	case LogStderr:
+		return "stderr"
+	case LogTest:
+		return "test"
+	case LogSyscalls:
+		return "syscalls"
+	case LogNode:
+		return "node"
+	case LogNodeSyscalls:
+		return "node syscalls"
+	}
+	return fmt.Sprintf("LogKind(%d)", int(k))
+}
+
+// patterns returns the globs of the files of kind k for the VM with the
+// given IP, relative to the directory of a run, or nil if k is
+// unknown.
+func (k LogKind) patterns(ip string) []string {
+	switch k {
+	case LogFirecracker:
+		return []string{fmt.Sprintf("vm-logs/vm-%s.log", ip)}
+	case LogMetrics:
+		return []string{fmt.Sprintf("vm-logs/vm-%s-metrics", ip)}
+	case LogStdout:
+		return []string{fmt.Sprintf("vm-logs/vm-%s.stdout", ip)}
+	case LogStderr:
+		return []string{fmt.Sprintf("vm-logs/vm-%s.stderr", ip)}
+	case LogTest:
+		// runs of earlier versions have a single vm-<ip>.log
+		return []string{fmt.Sprintf("vm-test/vm-%s.log", ip), fmt.Sprintf("vm-test/vm-%s-job-*.log", ip)}
+	case LogSyscalls:
+		return []string{fmt.Sprintf("vm-syscalls/*/vm-%s.log", ip)}
+	case LogNode:
+		return []string{"node-logs/node-server*.log", "node-logs/node-client*.log"}
+	case LogNodeSyscalls:
+		return []string{"node-logs/*/node-syscalls-*.log"}
+	}
+	return nil
+}
+
+func (k LogKind) vm() bool {
+	return k != LogNode && k != LogNodeSyscalls
+}
+
+// LogFile is a log file at Path, relative to the directory it was found in.
+type LogFile struct {
+	Kind LogKind
+	Path string
+}
+
+// FindLogs lists the log files of the given kinds under dir, or of every
+// kind if kinds is empty. VM logs are those of the VM with the given IP;
+// without one only node logs can be listed. Files that do not exist, such as
+// the test log of a VM that never ran a test, are skipped.
+func FindLogs(dir, ip string, kinds []LogKind) ([]LogFile, error) {
+	if ip != "" && net.ParseIP(ip) == nil {
+		return nil, fmt.Errorf("invalid IP %q", ip)
+	}
+	if len(kinds) == 0 {
+		kinds = logKinds
+		if ip == "" {
+			kinds = []LogKind{LogNode, LogNodeSyscalls}
+		}
+	}
+
+	var files []LogFile
+	for _, kind := range kinds {
+		if kind.patterns(ip) == nil {
+			return nil, fmt.Errorf("unknown log kind %v", kind)
+		}
+		if kind.vm() && ip == "" {
+			return nil, fmt.Errorf("an IP is required for %s logs", kind)
+		}
+
+		for _, pattern := range kind.patterns(ip) {
+			matches, err := filepath.Glob(filepath.Join(dir, pattern))
+			if err != nil {
+				return nil, fmt.Errorf("failed to find %s logs: %v", kind, err)
+			}
+			for _, match := range matches {
+				path, err := filepath.Rel(dir, match)
+				if err != nil {
+					return nil, fmt.Errorf("failed to find %s logs: %v", kind, err)
+				}
+				files = append(files, LogFile{Kind: kind, Path: path})
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// LogChunk is a part of a log file starting at Offset.
+type LogChunk struct {
+	Offset int64
+	Data   []byte
+	EOF    bool // the last chunk of the file
+}
+
+// ReadLog sends the file at path in chunks of up to logChunkSize bytes,
+// starting at offset, or at the start of its last tail lines if tail is
+// positive. It stops at the size the file had when it was opened, and sends
+// one empty chunk if there is nothing to read. The data of a chunk is only
+// valid until send returns.
+func ReadLog(path string, offset int64, tail int, send func(LogChunk) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %v", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat log file: %v", err)
+	}
+	size := info.Size()
+
+	if tail > 0 {
+		offset, err = tailOffset(f, size, tail)
+		if err != nil {
+			return fmt.Errorf("failed to find the last %d lines: %v", tail, err)
+		}
+	}
+	offset = min(max(offset, 0), size)
+
+	buf := make([]byte, logChunkSize)
+	for {
+		n, err := f.ReadAt(buf[:min(int64(len(buf)), size-offset)], offset)
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read log file: %v", err)
+		}
+
+		// the file may have been truncated since it was opened
+		chunk := LogChunk{Offset: offset, Data: buf[:n], EOF: err == io.EOF || offset+int64(n) >= size}
+		if err := send(chunk); err != nil {
+			return err
+		}
+		if chunk.EOF {
+			return nil
+		}
+		offset += int64(n)
+	}
+}
+
+// tailOffset returns the offset of the start of the last lines lines of f.
+func tailOffset(f *os.File, size int64, lines int) (int64, error) {
+	end := size
+	if end > 0 {
+		// a final newline ends the last line rather than starting another
+		var last [1]byte
+		if _, err := f.ReadAt(last[:], end-1); err != nil {
+			return 0, err
+		}
+		if last[0] == '\n' {
+			end--
+		}
+	}
+
+	buf := make([]byte, logChunkSize)
+	for end > 0 {
+		n := min(int64(len(buf)), end)
+		if _, err := f.ReadAt(buf[:n], end-n); err != nil {
+			return 0, err
+		}
+		for i := n - 1; i >= 0; i-- {
+			if buf[i] != '\n' {
+				continue
+			}
+			lines--
+			if lines == 0 {
+				return end - n + i + 1, nil
+			}
+		}
+		end -= n
+	}
+	return 0, nil
+}
@@ -0,0 +1,140 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeLogs(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindLogs(t *testing.T) {
+	dir := t.TempDir()
+	writeLogs(t, dir, map[string]string{
+		"vm-logs/vm-192.168.100.2.log":                 "",
+		"vm-logs/vm-192.168.100.2.stdout":              "",
+		"vm-logs/vm-192.168.100.3.log":                 "",
+		"vm-test/vm-192.168.100.2.log":                 "",
+		"vm-test/vm-192.168.100.2-job-1.log":           "",
+		"vm-test/vm-192.168.100.23-job-2.log":          "",
+		"vm-syscalls/trace-1/vm-192.168.100.2.log":     "",
+		"vm-syscalls/trace-2/vm-192.168.100.2.log":     "",
+		"node-logs/node-server.log":                    "",
+		"node-logs/node-client-job-3.log":              "",
+		"node-logs/trace-3/node-syscalls-1234.log":     "",
+		"node-logs/trace-3/node-syscalls-1234.log.bak": "",
+	})
+
+	files, err := FindLogs(dir, "192.168.100.2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LogFile{
+		{Kind: LogFirecracker, Path: "vm-logs/vm-192.168.100.2.log"},
+		{Kind: LogStdout, Path: "vm-logs/vm-192.168.100.2.stdout"},
+		{Kind: LogTest, Path: "vm-test/vm-192.168.100.2.log"},
+		{Kind: LogTest, Path: "vm-test/vm-192.168.100.2-job-1.log"},
+		{Kind: LogSyscalls, Path: "vm-syscalls/trace-1/vm-192.168.100.2.log"},
+		{Kind: LogSyscalls, Path: "vm-syscalls/trace-2/vm-192.168.100.2.log"},
+		{Kind: LogNode, Path: "node-logs/node-server.log"},
+		{Kind: LogNode, Path: "node-logs/node-client-job-3.log"},
+		{Kind: LogNodeSyscalls, Path: "node-logs/trace-3/node-syscalls-1234.log"},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("got %+v, want %+v", files, want)
+	}
+
+	files, err = FindLogs(dir, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 || files[0].Kind != LogNode || files[1].Kind != LogNode || files[2].Kind != LogNodeSyscalls {
+		t.Fatalf("got %+v, want the node logs only", files)
+	}
+
+	if _, err := FindLogs(dir, "", []LogKind{LogStdout}); err == nil {
+		t.Fatal("found VM logs without an IP")
+	}
+	if _, err := FindLogs(dir, "../../etc", nil); err == nil {
+		t.Fatal("found logs of an invalid IP")
+	}
+}
+
+func readLog(t *testing.T, path string, offset int64, tail int) string {
+	t.Helper()
+	var b strings.Builder
+	err := ReadLog(path, offset, tail, func(chunk LogChunk) error {
+		b.Write(chunk.Data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestReadLog(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("x", logChunkSize) + "\nlast\n"
+	writeLogs(t, dir, map[string]string{
+		"lines.log": "one\ntwo\nthree\n",
+		"long.log":  long,
+		"empty.log": "",
+	})
+	lines := filepath.Join(dir, "lines.log")
+
+	for _, tc := range []struct {
+		offset int64
+		tail   int
+		want   string
+	}{
+		{0, 0, "one\ntwo\nthree\n"},
+		{4, 0, "two\nthree\n"},
+		{100, 0, ""},
+		{0, 2, "two\nthree\n"},
+		{0, 5, "one\ntwo\nthree\n"},
+	} {
+		if got := readLog(t, lines, tc.offset, tc.tail); got != tc.want {
+			t.Errorf("offset %d tail %d: got %q, want %q", tc.offset, tc.tail, got, tc.want)
+		}
+	}
+
+	var chunks []LogChunk
+	err := ReadLog(filepath.Join(dir, "long.log"), 0, 0, func(chunk LogChunk) error {
+		chunks = append(chunks, LogChunk{Offset: chunk.Offset, Data: append([]byte(nil), chunk.Data...), EOF: chunk.EOF})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 2 || chunks[0].EOF || !chunks[1].EOF || string(chunks[0].Data)+string(chunks[1].Data) != long {
+		t.Fatalf("got %d chunks, want the file in two", len(chunks))
+	}
+	if got := readLog(t, filepath.Join(dir, "long.log"), 0, 1); got != "last\n" {
+		t.Fatalf("got tail %q, want %q", got, "last\n")
+	}
+
+	chunks = nil
+	if err := ReadLog(filepath.Join(dir, "empty.log"), 0, 0, func(chunk LogChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 1 || !chunks[0].EOF || len(chunks[0].Data) != 0 {
+		t.Fatalf("got %+v, want one empty final chunk", chunks)
+	}
+}
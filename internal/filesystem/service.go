@@ -3,9 +3,13 @@ package filesystem
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
+	"github.com/bookpanda/firecracker-runner-node/internal/config"
+	"github.com/bookpanda/firecracker-runner-node/internal/run"
 	proto "github.com/bookpanda/firecracker-runner-node/proto/filesystem/v1"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
 type Service interface {
@@ -14,33 +18,155 @@ type Service interface {
 
 type serviceImpl struct {
 	proto.UnimplementedFileSystemServiceServer
-	log *zap.Logger
+	config *config.Config
+	runs   *run.Manager
+	log    *zap.Logger
 }
 
-func NewService(log *zap.Logger) Service {
+func NewService(cfg *config.Config, runs *run.Manager, log *zap.Logger) Service {
 	return &serviceImpl{
-		log: log,
+		config: cfg,
+		runs:   runs,
+		log:    log,
 	}
 }
 
 func (s *serviceImpl) Cleanup(ctx context.Context, req *proto.CleanupFileSystemRequest) (*proto.CleanupFileSystemResponse, error) {
-	logDirs := []string{"./vm-test", "./vm-logs", "./vm-syscalls", "./node-logs"}
-	for _, logDir := range logDirs {
-		if err := GetEmptyLogDir(logDir); err != nil {
-			return nil, fmt.Errorf("failed to create log directory: %v", err)
-		}
-	}
-
 	cleanFiles := []string{"vm-", "vsock-"}
 	for _, cleanFile := range cleanFiles {
-		if err := CleanFilesInDir("/tmp", cleanFile); err != nil {
+		if err := CleanFilesInDir(s.config.SocketDir, cleanFile); err != nil {
 			return nil, fmt.Errorf("failed to clean up %s files: %v", cleanFile, err)
 		}
 	}
 
-	return &proto.CleanupFileSystemResponse{}, nil
+	// a fresh run instead of wiping the logs of the last one
+	manifest, err := s.runs.Start(req.RunId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CleanupFileSystemResponse{Run: runToProto(manifest, true)}, nil
+}
+
+func (s *serviceImpl) GetLogs(req *proto.GetLogsFileSystemRequest, stream grpc.ServerStreamingServer[proto.GetLogsFileSystemResponse]) error {
+	if req.Offset > 0 && req.Tail > 0 {
+		return fmt.Errorf("offset and tail cannot be combined")
+	}
+
+	kinds := make([]LogKind, 0, len(req.Kinds))
+	for _, kind := range req.Kinds {
+		kinds = append(kinds, LogKind(kind))
+	}
+	manifest, err := s.runs.Manifest(req.RunId)
+	if err != nil {
+		return err
+	}
+	dir := s.runs.Path(manifest.ID)
+	files, err := FindLogs(dir, req.Ip, kinds)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		err := ReadLog(filepath.Join(dir, file.Path), req.Offset, int(req.Tail), func(chunk LogChunk) error {
+			return stream.Send(&proto.GetLogsFileSystemResponse{
+				Path:   file.Path,
+				Kind:   proto.LogKind(file.Kind),
+				Offset: chunk.Offset,
+				Data:   chunk.Data,
+				Eof:    chunk.EOF,
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to send %s: %v", file.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) StartRun(_ context.Context, req *proto.StartRunFileSystemRequest) (*proto.StartRunFileSystemResponse, error) {
+	manifest, err := s.runs.Start(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.StartRunFileSystemResponse{Run: runToProto(manifest, true)}, nil
+}
+
+func (s *serviceImpl) ListRuns(_ context.Context, req *proto.ListRunsFileSystemRequest) (*proto.ListRunsFileSystemResponse, error) {
+	manifests, err := s.runs.List()
+	if err != nil {
+		return nil, err
+	}
+	current := s.runs.Current().ID
+
+	res := &proto.ListRunsFileSystemResponse{Runs: make([]*proto.Run, 0, len(manifests))}
+	for _, manifest := range manifests {
+		res.Runs = append(res.Runs, runToProto(manifest, manifest.ID == current))
+	}
+
+	return res, nil
+}
+
+func (s *serviceImpl) ExportRun(req *proto.ExportRunFileSystemRequest, stream grpc.ServerStreamingServer[proto.ExportRunFileSystemResponse]) error {
+	return s.runs.Export(req.Id, &chunkWriter{send: func(data []byte) error {
+		return stream.Send(&proto.ExportRunFileSystemResponse{Data: data})
+	}})
+}
+
+// chunkWriter sends what is written to it in chunks of up to logChunkSize
+// bytes.
+type chunkWriter struct {
+	send func([]byte) error
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	for written := 0; written < len(p); {
+		n := min(len(p)-written, logChunkSize)
+		if err := w.send(p[written : written+n]); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return len(p), nil
+}
+
+func runToProto(manifest run.Manifest, current bool) *proto.Run {
+	res := &proto.Run{
+		Id:        manifest.ID,
+		StartedAt: manifest.StartedAt.UnixMilli(),
+		Vms:       make([]*proto.RunVm, 0, len(manifest.VMs)),
+		Commands:  make([]*proto.RunCommand, 0, len(manifest.Commands)),
+		Current:   current,
+	}
+	for _, vm := range manifest.VMs {
+		res.Vms = append(res.Vms, &proto.RunVm{
+			Ip:         vm.IP,
+			Kernel:     imageToProto(vm.Kernel),
+			Rootfs:     imageToProto(vm.Rootfs),
+			VcpuCount:  vm.VcpuCount,
+			MemSizeMib: vm.MemSizeMib,
+			KernelArgs: vm.KernelArgs,
+			SnapshotId: vm.Snapshot,
+			CreatedAt:  vm.CreatedAt.UnixMilli(),
+		})
+	}
+	for _, cmd := range manifest.Commands {
+		res.Commands = append(res.Commands, &proto.RunCommand{
+			Target:    cmd.Target,
+			Command:   cmd.Command,
+			Server:    cmd.Server,
+			StartedAt: cmd.StartedAt.UnixMilli(),
+		})
+	}
+	return res
 }
 
-func (s *serviceImpl) GetLogs(ctx context.Context, req *proto.GetLogsFileSystemRequest) (*proto.GetLogsFileSystemResponse, error) {
-	return &proto.GetLogsFileSystemResponse{}, nil
+func imageToProto(image run.Image) *proto.Image {
+	res := &proto.Image{Path: image.Path, Size: image.Size}
+	if !image.ModTime.IsZero() {
+		res.ModTime = image.ModTime.UnixMilli()
+	}
+	return res
 }
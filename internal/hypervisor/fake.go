@@ -0,0 +1,373 @@
+package hypervisor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const fakeMemSize = 2 * fakePageSize
+const fakePageSize = 4096
+
+// VsockHandler serves a guest-side vsock connection after the CONNECT
+// handshake has been answered.
+type VsockHandler func(port uint32, conn net.Conn)
+
+// CommandHandler returns a VsockHandler that reads one command line per
+// connection, like the guest agent, and closes the connection once run returns.
+func CommandHandler(run func(command string, out io.Writer) error) VsockHandler {
+	return func(port uint32, conn net.Conn) {
+		defer conn.Close()
+
+		command, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		if err := run(command[:len(command)-1], conn); err != nil {
+			fmt.Fprintf(conn, "error: %v\n", err)
+		}
+	}
+}
+
+// EchoCommandHandler writes each command back as its output.
+var EchoCommandHandler = CommandHandler(func(command string, out io.Writer) error {
+	_, err := fmt.Fprintf(out, "%s\n", command)
+	return err
+})
+
+// Fake is an in-process hypervisor for tests. Its machines need neither KVM
+// nor root and emulate the firecracker vsock CONNECT handshake on the host
+// side unix socket.
+type Fake struct {
+	mu       sync.Mutex
+	machines []*FakeMachine
+	handler  VsockHandler
+	pids     atomic.Int64
+}
+
+func NewFake() *Fake {
+	f := &Fake{handler: EchoCommandHandler}
+	f.pids.Store(10000)
+	return f
+}
+
+// SetVsockHandler changes the guest side of vsock connections for machines
+// started afterwards.
+func (f *Fake) SetVsockHandler(handler VsockHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handler = handler
+}
+
+func (f *Fake) NewMachine(_ context.Context, cfg Config) (Machine, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	m := &FakeMachine{
+		cfg:     cfg,
+		pid:     int(f.pids.Add(1)),
+		handler: f.handler,
+		state:   FakeStateCreated,
+		exited:  make(chan struct{}),
+	}
+	f.machines = append(f.machines, m)
+	return m, nil
+}
+
+// Machines returns every machine created so far, in creation order.
+func (f *Fake) Machines() []*FakeMachine {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*FakeMachine(nil), f.machines...)
+}
+
+type FakeState string
+
+const (
+	FakeStateCreated FakeState = "created"
+	FakeStateRunning FakeState = "running"
+	FakeStatePaused  FakeState = "paused"
+	FakeStateExited  FakeState = "exited"
+)
+
+type FakeMachine struct {
+	cfg     Config
+	pid     int
+	handler VsockHandler
+
+	mu       sync.Mutex
+	state    FakeState
+	listener net.Listener
+	ports    uint32
+	metrics  map[string]uint64 // since the last flush
+	// resumeErr fails the next ResumeVM
+	resumeErr error
+
+	exitOnce sync.Once
+	exitErr  error
+	exited   chan struct{}
+}
+
+func (m *FakeMachine) Config() Config {
+	return m.cfg
+}
+
+func (m *FakeMachine) State() FakeState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+func (m *FakeMachine) Start(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state != FakeStateCreated {
+		return fmt.Errorf("machine already started")
+	}
+
+	m.state = FakeStateRunning
+	if m.cfg.Snapshot != nil {
+		for _, path := range []string{m.cfg.Snapshot.MemFilePath, m.cfg.Snapshot.SnapshotPath} {
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("failed to load snapshot: %v", err)
+			}
+		}
+		if !m.cfg.Snapshot.Resume {
+			m.state = FakeStatePaused
+		}
+	}
+
+	if m.cfg.Vsock.Path != "" {
+		listener, err := net.Listen("unix", m.cfg.Vsock.Path)
+		if err != nil {
+			return fmt.Errorf("failed to listen on vsock %s: %v", m.cfg.Vsock.Path, err)
+		}
+		m.listener = listener
+		go m.acceptVsock(listener)
+	}
+
+	return nil
+}
+
+func (m *FakeMachine) acceptVsock(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go m.serveVsock(conn)
+	}
+}
+
+// serveVsock answers "CONNECT <port>\n" with "OK <host port>\n" the way the
+// firecracker vsock device does, then hands the connection to the handler.
+func (m *FakeMachine) serveVsock(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	var port uint32
+	if _, err := fmt.Sscanf(line, "CONNECT %d\n", &port); err != nil {
+		conn.Close()
+		return
+	}
+
+	m.mu.Lock()
+	m.ports++
+	hostPort := 1<<30 + m.ports
+	m.mu.Unlock()
+
+	if _, err := fmt.Fprintf(conn, "OK %d\n", hostPort); err != nil {
+		conn.Close()
+		return
+	}
+
+	m.handler(port, &bufferedConn{Conn: conn, reader: reader})
+}
+
+func (m *FakeMachine) Shutdown(context.Context) error {
+	m.exit(nil)
+	return nil
+}
+
+func (m *FakeMachine) StopVMM() error {
+	m.exit(nil)
+	return nil
+}
+
+// Crash makes the machine exit as if the firecracker process had died.
+func (m *FakeMachine) Crash(err error) {
+	m.exit(err)
+}
+
+func (m *FakeMachine) exit(err error) {
+	m.exitOnce.Do(func() {
+		m.mu.Lock()
+		m.state = FakeStateExited
+		if m.listener != nil {
+			m.listener.Close()
+		}
+		m.mu.Unlock()
+
+		m.exitErr = err
+		close(m.exited)
+	})
+}
+
+func (m *FakeMachine) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.exited:
+		return m.exitErr
+	}
+}
+
+func (m *FakeMachine) PID() (int, error) {
+	return m.pid, nil
+}
+
+func (m *FakeMachine) PauseVM(context.Context) error {
+	return m.transition(FakeStateRunning, FakeStatePaused)
+}
+
+func (m *FakeMachine) ResumeVM(context.Context) error {
+	m.mu.Lock()
+	err := m.resumeErr
+	m.resumeErr = nil
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return m.transition(FakeStatePaused, FakeStateRunning)
+}
+
+// FailResume makes the next ResumeVM fail with err and leave the machine
+// paused.
+func (m *FakeMachine) FailResume(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resumeErr = err
+}
+
+func (m *FakeMachine) transition(from, to FakeState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state != from {
+		return fmt.Errorf("machine is %s, not %s", m.state, from)
+	}
+	m.state = to
+	return nil
+}
+
+// CreateSnapshot writes a small memory file, sparse for diff snapshots, and
+// a state file. Like firecracker it requires the machine to be paused.
+func (m *FakeMachine) CreateSnapshot(_ context.Context, memFilePath, snapshotPath string, diff bool) error {
+	if state := m.State(); state != FakeStatePaused {
+		return fmt.Errorf("machine is %s, not %s", state, FakeStatePaused)
+	}
+
+	mem, err := os.Create(memFilePath)
+	if err != nil {
+		return err
+	}
+	defer mem.Close()
+
+	if diff {
+		// only the second page is dirty
+		if err := mem.Truncate(fakeMemSize); err != nil {
+			return err
+		}
+		page := make([]byte, fakePageSize)
+		for i := range page {
+			page[i] = 'd'
+		}
+		if _, err := mem.WriteAt(page, fakePageSize); err != nil {
+			return err
+		}
+	} else {
+		page := make([]byte, fakeMemSize)
+		for i := range page {
+			page[i] = 'f'
+		}
+		if _, err := mem.Write(page); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(snapshotPath, []byte(m.cfg.Network.MacAddress), 0644)
+}
+
+// AddMetric counts n more of the metric with the given name, such as
+// "net.rx_bytes_count", until the next flush.
+func (m *FakeMachine) AddMetric(name string, n uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.metrics == nil {
+		m.metrics = make(map[string]uint64)
+	}
+	m.metrics[name] += n
+}
+
+// FlushMetrics appends the metrics added since the last flush to the
+// metrics file as a line of JSON, grouped like firecracker's.
+func (m *FakeMachine) FlushMetrics(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.state == FakeStateCreated || m.state == FakeStateExited {
+		return fmt.Errorf("machine is %s", m.state)
+	}
+
+	line := map[string]any{"utc_timestamp_ms": time.Now().UnixMilli()}
+	for name, n := range m.metrics {
+		group, metric, ok := strings.Cut(name, ".")
+		if !ok {
+			line[name] = n
+			continue
+		}
+		if _, ok := line[group].(map[string]uint64); !ok {
+			line[group] = make(map[string]uint64)
+		}
+		line[group].(map[string]uint64)[metric] = n
+	}
+	m.metrics = nil
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("failed to flush metrics: %v", err)
+	}
+	f, err := os.OpenFile(m.cfg.MetricsPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to flush metrics: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to flush metrics: %v", err)
+	}
+	return nil
+}
+
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
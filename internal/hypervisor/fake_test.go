@@ -0,0 +1,108 @@
+package hypervisor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func startFakeMachine(t *testing.T, fake *Fake) *FakeMachine {
+	t.Helper()
+
+	cfg := Config{Vsock: VsockConfig{Path: filepath.Join(t.TempDir(), "vsock.sock"), CID: 3}}
+	machine, err := fake.NewMachine(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { machine.StopVMM() })
+
+	return machine.(*FakeMachine)
+}
+
+func TestFakeVsockHandshake(t *testing.T) {
+	fake := NewFake()
+	fake.SetVsockHandler(CommandHandler(func(command string, out io.Writer) error {
+		_, err := fmt.Fprintf(out, "ran %q\n", command)
+		return err
+	}))
+	machine := startFakeMachine(t, fake)
+
+	conn, err := net.Dial("unix", machine.Config().Vsock.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "CONNECT 1234\n")
+	reader := bufio.NewReader(conn)
+	ack, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(ack, "OK ") {
+		t.Fatalf("got ack %q, want OK <port>", ack)
+	}
+
+	fmt.Fprintf(conn, "iperf3 -s\n")
+	out, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "ran \"iperf3 -s\"\n"; got != want {
+		t.Fatalf("got output %q, want %q", got, want)
+	}
+}
+
+func TestFakeVsockRejectsBadHandshake(t *testing.T) {
+	machine := startFakeMachine(t, NewFake())
+
+	conn, err := net.Dial("unix", machine.Config().Vsock.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "HELLO\n")
+	if out, _ := io.ReadAll(conn); len(out) != 0 {
+		t.Fatalf("got %q for a bad handshake, want the connection closed", out)
+	}
+}
+
+func TestFakeSnapshotRequiresPause(t *testing.T) {
+	machine := startFakeMachine(t, NewFake())
+	dir := t.TempDir()
+
+	mem, state := filepath.Join(dir, "mem"), filepath.Join(dir, "vmstate")
+	if err := machine.CreateSnapshot(context.Background(), mem, state, false); err == nil {
+		t.Fatal("snapshot of a running machine succeeded")
+	}
+
+	if err := machine.PauseVM(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := machine.CreateSnapshot(context.Background(), mem, state, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFakeWaitReturnsCrashError(t *testing.T) {
+	machine := startFakeMachine(t, NewFake())
+
+	crash := fmt.Errorf("signal: killed")
+	machine.Crash(crash)
+
+	if err := machine.Wait(context.Background()); err != crash {
+		t.Fatalf("Wait() = %v, want %v", err, crash)
+	}
+	if machine.State() != FakeStateExited {
+		t.Fatalf("machine is %s, want %s", machine.State(), FakeStateExited)
+	}
+}
@@ -0,0 +1,173 @@
+package hypervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/firecracker-microvm/firecracker-go-sdk"
+	"github.com/firecracker-microvm/firecracker-go-sdk/client/models"
+	"github.com/firecracker-microvm/firecracker-go-sdk/client/operations"
+)
+
+type Firecracker struct {
+	Bin string
+}
+
+// NewFirecracker runs machines with the firecracker binary bin, looked up in
+// PATH unless it is a path.
+func NewFirecracker(bin string) *Firecracker {
+	return &Firecracker{Bin: bin}
+}
+
+func (f *Firecracker) NewMachine(ctx context.Context, cfg Config) (Machine, error) {
+	stdoutFile, err := os.Create(cfg.StdoutPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout file: %v", err)
+	}
+
+	stderrFile, err := os.Create(cfg.StderrPath)
+	if err != nil {
+		stdoutFile.Close()
+		return nil, fmt.Errorf("failed to create stderr file: %v", err)
+	}
+
+	cmd := firecracker.VMCommandBuilder{}.
+		WithBin(f.Bin).
+		WithSocketPath(cfg.SocketPath).
+		WithStdin(os.Stdin).
+		WithStdout(stdoutFile).
+		WithStderr(stderrFile).
+		Build(ctx)
+
+	opts := []firecracker.Opt{firecracker.WithProcessRunner(cmd)}
+	if cfg.Snapshot != nil {
+		opts = append(opts, firecracker.WithSnapshot(cfg.Snapshot.MemFilePath, cfg.Snapshot.SnapshotPath, func(c *firecracker.SnapshotConfig) {
+			c.ResumeVM = cfg.Snapshot.Resume
+			c.EnableDiffSnapshots = cfg.Snapshot.EnableDiffSnapshots
+		}))
+	}
+
+	machine, err := firecracker.NewMachine(ctx, firecrackerConfig(cfg), opts...)
+	if err != nil {
+		stdoutFile.Close()
+		stderrFile.Close()
+		return nil, fmt.Errorf("failed to create machine: %v", err)
+	}
+
+	return firecrackerMachine{machine}, nil
+}
+
+func firecrackerConfig(cfg Config) firecracker.Config {
+	fcCfg := firecracker.Config{
+		SocketPath:     cfg.SocketPath, // host-FC process communication
+		ForwardSignals: []os.Signal{},
+		LogLevel:       "Debug",
+		LogPath:        cfg.LogPath,
+		MetricsPath:    cfg.MetricsPath,
+	}
+
+	// devices and machine configuration are part of the snapshot state
+	if cfg.Snapshot != nil {
+		return fcCfg
+	}
+
+	fcCfg.KernelImagePath = cfg.KernelPath
+	fcCfg.KernelArgs = cfg.KernelArgs
+	fcCfg.VsockDevices = []firecracker.VsockDevice{ // host-guest(vm) communication
+		{
+			ID:   cfg.Vsock.ID,
+			Path: cfg.Vsock.Path,
+			CID:  cfg.Vsock.CID,
+		},
+	}
+	fcCfg.Drives = []models.Drive{
+		{
+			DriveID:      firecracker.String("1"),
+			PathOnHost:   firecracker.String(cfg.RootfsPath),
+			IsRootDevice: firecracker.Bool(true),
+			IsReadOnly:   firecracker.Bool(true),
+			RateLimiter:  rateLimiterModel(cfg.RootfsRateLimiter),
+		},
+	}
+	fcCfg.NetworkInterfaces = []firecracker.NetworkInterface{
+		{
+			StaticConfiguration: &firecracker.StaticNetworkConfiguration{
+				MacAddress:  cfg.Network.MacAddress,
+				HostDevName: cfg.Network.TapName,
+				IPConfiguration: &firecracker.IPConfiguration{
+					IPAddr:      cfg.Network.IP,
+					Gateway:     cfg.Network.Gateway,
+					Nameservers: cfg.Network.Nameservers,
+				},
+			},
+			InRateLimiter:  rateLimiterModel(cfg.Network.RxRateLimiter),
+			OutRateLimiter: rateLimiterModel(cfg.Network.TxRateLimiter),
+		},
+	}
+	fcCfg.MachineCfg = models.MachineConfiguration{
+		VcpuCount:       firecracker.Int64(cfg.Machine.VcpuCount),
+		MemSizeMib:      firecracker.Int64(cfg.Machine.MemSizeMib),
+		Smt:             firecracker.Bool(cfg.Machine.Smt),
+		CPUTemplate:     models.CPUTemplate(cfg.Machine.CPUTemplate),
+		TrackDirtyPages: cfg.Machine.TrackDirtyPages,
+	}
+
+	return fcCfg
+}
+
+func rateLimiterModel(limiter *RateLimiter) *models.RateLimiter {
+	if limiter == nil {
+		return nil
+	}
+	return &models.RateLimiter{
+		Bandwidth: tokenBucketModel(limiter.Bandwidth),
+		Ops:       tokenBucketModel(limiter.Ops),
+	}
+}
+
+func tokenBucketModel(bucket *TokenBucket) *models.TokenBucket {
+	if bucket == nil {
+		return nil
+	}
+	return &models.TokenBucket{
+		Size:         firecracker.Int64(bucket.Size),
+		OneTimeBurst: firecracker.Int64(bucket.OneTimeBurst),
+		RefillTime:   firecracker.Int64(bucket.RefillTimeMs),
+	}
+}
+
+// firecrackerMachine adapts the variadic option methods of
+// firecracker.Machine to the Machine interface.
+type firecrackerMachine struct {
+	*firecracker.Machine
+}
+
+func (m firecrackerMachine) PauseVM(ctx context.Context) error {
+	return m.Machine.PauseVM(ctx)
+}
+
+func (m firecrackerMachine) ResumeVM(ctx context.Context) error {
+	return m.Machine.ResumeVM(ctx)
+}
+
+func (m firecrackerMachine) CreateSnapshot(ctx context.Context, memFilePath, snapshotPath string, diff bool) error {
+	snapshotType := models.SnapshotCreateParamsSnapshotTypeFull
+	if diff {
+		snapshotType = models.SnapshotCreateParamsSnapshotTypeDiff
+	}
+
+	withType := func(params *operations.CreateSnapshotParams) {
+		params.Body.SnapshotType = snapshotType
+	}
+	return m.Machine.CreateSnapshot(ctx, memFilePath, snapshotPath, withType)
+}
+
+func (m firecrackerMachine) FlushMetrics(ctx context.Context) error {
+	action := models.InstanceActionInfoActionTypeFlushMetrics
+	client := firecracker.NewClient(m.Cfg.SocketPath, m.Logger(), false)
+	if _, err := client.CreateSyncAction(ctx, &models.InstanceActionInfo{ActionType: &action}); err != nil {
+		return fmt.Errorf("failed to flush metrics: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package hypervisor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openFiles(t *testing.T) int {
+	t.Helper()
+
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("cannot count open files:", err)
+	}
+	return len(fds)
+}
+
+func TestFirecrackerNewMachineClosesFiles(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{
+		StdoutPath: filepath.Join(dir, "vm.stdout"),
+		StderrPath: filepath.Join(dir, "missing", "vm.stderr"),
+	}
+
+	// every failed create would leak the stdout file
+	const creates = 20
+	before := openFiles(t)
+	for i := 0; i < creates; i++ {
+		if _, err := NewFirecracker("firecracker").NewMachine(context.Background(), cfg); err == nil {
+			t.Fatal("created a machine without a stderr file")
+		}
+	}
+	if leaked := openFiles(t) - before; leaked >= creates {
+		t.Fatalf("%d more files are open after %d failed creates", leaked, creates)
+	}
+}
@@ -0,0 +1,92 @@
+package hypervisor
+
+import (
+	"context"
+	"net"
+)
+
+// Hypervisor creates machines. The firecracker implementation runs real
+// microVMs, Fake runs them in-process for tests.
+type Hypervisor interface {
+	NewMachine(ctx context.Context, cfg Config) (Machine, error)
+}
+
+// Machine is a single VM process.
+type Machine interface {
+	Start(ctx context.Context) error
+	// Shutdown asks the guest to shut down, Wait returns once it has
+	Shutdown(ctx context.Context) error
+	StopVMM() error
+	Wait(ctx context.Context) error
+	PID() (int, error)
+	PauseVM(ctx context.Context) error
+	ResumeVM(ctx context.Context) error
+	CreateSnapshot(ctx context.Context, memFilePath, snapshotPath string, diff bool) error
+	// FlushMetrics appends the metrics since the last flush to MetricsPath
+	FlushMetrics(ctx context.Context) error
+}
+
+type Config struct {
+	SocketPath  string // API socket of the VMM
+	LogPath     string
+	MetricsPath string
+	StdoutPath  string
+	StderrPath  string
+
+	KernelPath        string
+	KernelArgs        string
+	RootfsPath        string
+	RootfsRateLimiter *RateLimiter
+
+	Vsock    VsockConfig
+	Network  NetworkConfig
+	Machine  MachineConfig
+	Snapshot *SnapshotConfig // boot from a snapshot instead of the kernel
+}
+
+type VsockConfig struct {
+	ID   string
+	Path string // host side unix socket
+	CID  uint32
+}
+
+type NetworkConfig struct {
+	TapName     string
+	MacAddress  string
+	IP          net.IPNet
+	Gateway     net.IP
+	Nameservers []string
+	// RxRateLimiter limits what the guest receives, TxRateLimiter what it sends
+	RxRateLimiter *RateLimiter
+	TxRateLimiter *RateLimiter
+}
+
+type MachineConfig struct {
+	VcpuCount       int64
+	MemSizeMib      int64
+	Smt             bool
+	CPUTemplate     string
+	TrackDirtyPages bool
+}
+
+// RateLimiter is a firecracker device rate limiter with a bytes and an
+// operations bucket; either may be nil for no limit.
+type RateLimiter struct {
+	Bandwidth *TokenBucket `json:"bandwidth,omitempty"`
+	Ops       *TokenBucket `json:"ops,omitempty"`
+}
+
+// TokenBucket holds Size tokens (bytes or operations) and refills completely
+// every RefillTimeMs. OneTimeBurst is an extra initial allowance.
+type TokenBucket struct {
+	Size         int64 `json:"size"`
+	OneTimeBurst int64 `json:"oneTimeBurst,omitempty"`
+	RefillTimeMs int64 `json:"refillTimeMs"`
+}
+
+type SnapshotConfig struct {
+	MemFilePath         string
+	SnapshotPath        string
+	Resume              bool
+	EnableDiffSnapshots bool
+}
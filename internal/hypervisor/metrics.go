@@ -0,0 +1,65 @@
+package hypervisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricsSample is one line of a firecracker metrics file. Values are keyed
+// by the path of the metric, e.g. "net_eth0.rx_bytes_count" or
+// "vcpu.exit_io_in".
+type MetricsSample struct {
+	Time   time.Time
+	Values map[string]uint64
+}
+
+// IsTiming reports whether the metric with the given name is a time in
+// microseconds that firecracker stores as is. Every other metric counts
+// events since the previous flush.
+func IsTiming(name string) bool {
+	return strings.HasPrefix(name, "latencies_us.") || strings.HasSuffix(name, "_us")
+}
+
+// ParseMetrics parses a line of the newline-delimited JSON that firecracker
+// writes to Config.MetricsPath on every flush. Values that are not unsigned
+// integers are skipped.
+func ParseMetrics(line []byte) (MetricsSample, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	var raw map[string]any
+	if err := decoder.Decode(&raw); err != nil {
+		return MetricsSample{}, fmt.Errorf("failed to parse metrics: %v", err)
+	}
+
+	ts, ok := raw["utc_timestamp_ms"].(json.Number)
+	if !ok {
+		return MetricsSample{}, fmt.Errorf("failed to parse metrics: no utc_timestamp_ms")
+	}
+	ms, err := ts.Int64()
+	if err != nil {
+		return MetricsSample{}, fmt.Errorf("failed to parse metrics: invalid utc_timestamp_ms %s", ts)
+	}
+	delete(raw, "utc_timestamp_ms")
+
+	sample := MetricsSample{Time: time.UnixMilli(ms), Values: make(map[string]uint64)}
+	flattenMetrics(sample.Values, "", raw)
+	return sample, nil
+}
+
+func flattenMetrics(values map[string]uint64, prefix string, group map[string]any) {
+	for name, value := range group {
+		switch value := value.(type) {
+		case map[string]any:
+			flattenMetrics(values, prefix+name+".", value)
+		case json.Number:
+			if n, err := strconv.ParseUint(value.String(), 10, 64); err == nil {
+				values[prefix+name] = n
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package hypervisor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseMetrics(t *testing.T) {
+	line := `{"utc_timestamp_ms":1700000000123,"api_server":{"process_startup_time_us":1042,"sync_response_fails":0},` +
+		`"net_eth0":{"rx_bytes_count":1500,"tx_packets_count":3},"vcpu":{"exit_io_in":12,"exit_mmio_read":4},` +
+		`"latencies_us":{"pause_vm":85},"signals":{"sigbus":0},"state":"Running","ratio":0.5}`
+
+	sample, err := ParseMetrics([]byte(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.UnixMilli(1700000000123); !sample.Time.Equal(want) {
+		t.Errorf("time is %v, want %v", sample.Time, want)
+	}
+
+	want := map[string]uint64{
+		"api_server.process_startup_time_us": 1042,
+		"api_server.sync_response_fails":     0,
+		"net_eth0.rx_bytes_count":            1500,
+		"net_eth0.tx_packets_count":          3,
+		"vcpu.exit_io_in":                    12,
+		"vcpu.exit_mmio_read":                4,
+		"latencies_us.pause_vm":              85,
+		"signals.sigbus":                     0,
+	}
+	if !reflect.DeepEqual(sample.Values, want) {
+		t.Errorf("values are %v, want %v", sample.Values, want)
+	}
+
+	for name, timing := range map[string]bool{
+		"latencies_us.pause_vm":              true,
+		"api_server.process_startup_time_us": true,
+		"net_eth0.rx_bytes_count":            false,
+	} {
+		if IsTiming(name) != timing {
+			t.Errorf("IsTiming(%q) = %v, want %v", name, !timing, timing)
+		}
+	}
+
+	if _, err := ParseMetrics([]byte(`{"net":{"rx_bytes_count":1}}`)); err == nil {
+		t.Error("parsed metrics without a timestamp")
+	}
+}
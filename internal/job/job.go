@@ -0,0 +1,47 @@
+package job
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stream is the stream a line of output was written to. The values match
+// the OutputStream proto enums.
+type Stream int
+
+const (
+	Stdout Stream = iota + 1
+	Stderr
+)
+
+func (s Stream) String() string {
+	switch s {
+	case Stdout:
+		return "stdout"
+	case Stderr:
+		return "stderr"
+	}
+	return fmt.Sprintf("Stream(%d)", int(s))
+}
+
+// Output is a line of output, without its newline.
+type Output struct {
+	Stream Stream
+	Line   string
+}
+
+// Result is how a command ended.
+type Result struct {
+	// ExitCode is -1 if the command did not exit on its own or the exit
+	// code is unknown
+	ExitCode int
+	Duration time.Duration
+	// Killed is set if the command was stopped by a signal or cancelled
+	// before it exited
+	Killed bool
+	// Signal is the signal that killed the command, e.g. "killed", if one did
+	Signal string
+	// Error says why the command could not run to completion, if it could
+	// not
+	Error string
+}
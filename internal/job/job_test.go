@@ -0,0 +1,187 @@
+package job
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLines(t *testing.T) {
+	var got []Output
+	lines := NewLines(func(out Output) { got = append(got, out) })
+	lines.Write(Stdout, []byte("hel"))
+	lines.Write(Stderr, []byte("oops\nagain"))
+	lines.Write(Stdout, []byte("lo\nworld\n\nlast"))
+	lines.Flush()
+
+	want := []Output{
+		{Stderr, "oops"},
+		{Stdout, "hello"},
+		{Stdout, "world"},
+		{Stdout, ""},
+		{Stdout, "last"},
+		{Stderr, "again"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestProcessOutputOfOrphans(t *testing.T) {
+	// the background sleep keeps stdout open after sh exits
+	p, err := Start(Spec{Argv: []string{"sh", "-c", "echo hi; sleep 60 & exit 2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Kill()
+
+	start := time.Now()
+	var out []byte
+	result, _ := p.Wait(func(_ Stream, data []byte) { out = append(out, data...) })
+	if string(out) != "hi\n" || result.ExitCode != 2 {
+		t.Fatalf("got %q and %+v, want the output and exit code of sh", out, result)
+	}
+	if elapsed := time.Since(start); elapsed > 5*waitDelay {
+		t.Fatalf("waited %s for the orphan", elapsed)
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p, err := Start(Spec{Argv: []string{"sleep", "60"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	result, err := WithContext(ctx, p, func() { p.Kill() }).Wait(func(Stream, []byte) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Killed || result.ExitCode != -1 || result.Error != context.Canceled.Error() {
+		t.Fatalf("got %+v, want a command killed by the context", result)
+	}
+}
+
+// runJob starts argv as a job of r and returns its ID and a channel that
+// receives how it ended.
+func runJob(t *testing.T, r *Registry, ctx context.Context, target string, timeout time.Duration, argv ...string) (string, chan Result) {
+	t.Helper()
+
+	j, ctx := r.Add(ctx, Job{Target: target, Command: argv[0], Timeout: timeout})
+	p, err := Start(Spec{Argv: argv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetPid(j.ID, p.Pid())
+	h := r.Track(j.ID, WithContext(ctx, p, func() { p.Kill() }))
+
+	ended := make(chan Result, 1)
+	go func() {
+		result, _ := h.Wait(func(Stream, []byte) {})
+		ended <- result
+	}()
+	return j.ID, ended
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	ctx := context.Background()
+
+	exited, ended := runJob(t, r, ctx, "a", 0, "sh", "-c", "exit 3")
+	<-ended
+	cancelled, _ := runJob(t, r, ctx, "a", 0, "sleep", "60")
+	timedOut, ended := runJob(t, r, ctx, "b", 50*time.Millisecond, "sleep", "60")
+	if result := <-ended; result.Error != ErrTimedOut.Error() {
+		t.Fatalf("got %+v, want a timeout", result)
+	}
+
+	j, err := r.Get(cancelled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.State != Running || j.Pid <= 0 || !j.EndedAt.IsZero() {
+		t.Fatalf("got %+v, want a running job", j)
+	}
+	j, err = r.Cancel(ctx, cancelled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.State != Cancelled || !j.Result.Killed || j.Result.Error != ErrCancelled.Error() {
+		t.Fatalf("got %+v, want a cancelled job", j)
+	}
+
+	for id, want := range map[string]State{exited: Exited, cancelled: Cancelled, timedOut: TimedOut} {
+		if j, _ := r.Get(id); j.State != want {
+			t.Errorf("job %s is %v, want %v", id, j.State, want)
+		}
+	}
+	if j, _ := r.Get(exited); j.Result.ExitCode != 3 {
+		t.Errorf("got %+v, want exit code 3", j.Result)
+	}
+
+	var ids []string
+	for _, j := range r.List("a") {
+		ids = append(ids, j.ID)
+	}
+	if want := []string{exited, cancelled}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("got jobs %v on a, want %v", ids, want)
+	}
+	if jobs := r.List(""); len(jobs) != 3 {
+		t.Fatalf("got %d jobs, want 3", len(jobs))
+	}
+	if _, err := r.Cancel(ctx, "job-0"); err == nil {
+		t.Fatal("cancelled an unknown job")
+	}
+}
+
+func TestRegistryForgetsOldJobs(t *testing.T) {
+	r := NewRegistry()
+	r.maxEnded = 2
+	ctx := context.Background()
+
+	running, _ := r.Add(ctx, Job{Target: "a", Command: "iperf3 -s"})
+	var ids []string
+	for i := 0; i < 4; i++ {
+		j, _ := r.Add(ctx, Job{Target: "a", Command: "iperf3 -c"})
+		r.End(j.ID, Result{})
+		ids = append(ids, j.ID)
+	}
+
+	// running jobs are kept however many ended after them
+	var got []string
+	for _, j := range r.List("") {
+		got = append(got, j.ID)
+	}
+	if want := []string{running.ID, ids[2], ids[3]}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got jobs %v, want %v", got, want)
+	}
+	if _, err := r.Get(ids[0]); err == nil {
+		t.Fatal("got a forgotten job")
+	}
+
+	r.End(running.ID, Result{})
+	if _, err := r.Get(ids[2]); err == nil {
+		t.Fatal("got a forgotten job")
+	}
+	if _, err := r.Get(running.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistryCancelAll(t *testing.T) {
+	r := NewRegistry()
+	parent, stop := context.WithCancel(context.Background())
+	first, _ := runJob(t, r, context.Background(), "a", 0, "sleep", "60")
+	second, _ := runJob(t, r, parent, "a", 0, "sleep", "60")
+
+	// a job stopped with its parent context counts as cancelled too
+	stop()
+	r.CancelAll(context.Background())
+	for _, id := range []string{first, second} {
+		if j, _ := r.Get(id); j.State != Cancelled {
+			t.Errorf("job %s is %v, want cancelled", id, j.State)
+		}
+	}
+}
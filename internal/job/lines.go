@@ -0,0 +1,39 @@
+package job
+
+import "bytes"
+
+// Lines splits the output of a command, written in chunks of any size, into
+// lines.
+type Lines struct {
+	output  func(Output)
+	partial map[Stream][]byte
+}
+
+// NewLines passes every line of output to output, without its newline.
+func NewLines(output func(Output)) *Lines {
+	return &Lines{output: output, partial: make(map[Stream][]byte)}
+}
+
+// Write passes the lines data completes.
+func (l *Lines) Write(stream Stream, data []byte) {
+	buf := append(l.partial[stream], data...)
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		l.output(Output{Stream: stream, Line: string(buf[:i])})
+		buf = buf[i+1:]
+	}
+	l.partial[stream] = buf
+}
+
+// Flush passes the last line of every stream that has no newline.
+func (l *Lines) Flush() {
+	for _, stream := range []Stream{Stdout, Stderr} {
+		if len(l.partial[stream]) > 0 {
+			l.output(Output{Stream: stream, Line: string(l.partial[stream])})
+		}
+		delete(l.partial, stream)
+	}
+}
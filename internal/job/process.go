@@ -0,0 +1,266 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+// waitDelay is how long the output of a command is still read after it
+// exits, for children that keep its stdout, stderr or terminal open.
+const waitDelay = time.Second
+
+// Spec is a command to run.
+type Spec struct {
+	Argv []string
+	// Env holds KEY=value pairs added to the environment of this process
+	Env []string
+	// Dir is the working directory, the one of this process if empty
+	Dir string
+	// TTY runs the command in a terminal of the given size, which merges its
+	// stderr into stdout
+	TTY        bool
+	Rows, Cols uint16
+	// Stdin keeps the stdin of the command open for Write; it reads nothing
+	// otherwise
+	Stdin bool
+}
+
+// Handle controls a running command, on the node or in a VM.
+type Handle interface {
+	Pid() int
+	// Write writes to the stdin of the command
+	Write(data []byte) error
+	CloseStdin() error
+	// Resize changes the size of the terminal of the command
+	Resize(rows, cols uint16) error
+	// Signal sends sig to the command and its children
+	Signal(sig syscall.Signal) error
+	// Wait calls output with the output of the command until it exits. The
+	// error is set if how it exited is unknown.
+	Wait(output func(stream Stream, data []byte)) (Result, error)
+}
+
+// Process is a command running on this host. It runs in a process group of
+// its own, so that signals reach its children too.
+type Process struct {
+	cmd   *exec.Cmd
+	start time.Time
+
+	// output holds the read ends of stdout and stderr, or the terminal
+	output []*os.File
+	stdin  io.WriteCloser
+	tty    *os.File
+}
+
+// Start starts spec. Its output is not read until Wait.
+func Start(spec Spec) (*Process, error) {
+	if len(spec.Argv) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	cmd := exec.Command(spec.Argv[0], spec.Argv[1:]...)
+	cmd.Env = append(os.Environ(), spec.Env...)
+	cmd.Dir = spec.Dir
+	p := &Process{cmd: cmd}
+
+	if spec.TTY {
+		// a terminal makes the command the leader of a new session
+		tty, err := pty.StartWithSize(cmd, &pty.Winsize{Rows: spec.Rows, Cols: spec.Cols})
+		if err != nil {
+			return nil, fmt.Errorf("failed to start command in a terminal: %v", err)
+		}
+		p.tty, p.output, p.start = tty, []*os.File{tty}, time.Now()
+		return p, nil
+	}
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	// the write ends go to the command itself rather than through copying
+	// goroutines, so that Wait does not depend on its children closing them
+	var writeEnds []*os.File
+	defer func() {
+		for _, w := range writeEnds {
+			w.Close()
+		}
+	}()
+	for range 2 {
+		r, w, err := os.Pipe()
+		if err != nil {
+			p.closeOutput()
+			return nil, fmt.Errorf("failed to create output pipe: %v", err)
+		}
+		p.output = append(p.output, r)
+		writeEnds = append(writeEnds, w)
+	}
+	cmd.Stdout, cmd.Stderr = writeEnds[0], writeEnds[1]
+	if spec.Stdin {
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			p.closeOutput()
+			return nil, fmt.Errorf("failed to create stdin pipe: %v", err)
+		}
+		p.stdin = stdin
+	}
+	if err := cmd.Start(); err != nil {
+		p.closeOutput()
+		return nil, fmt.Errorf("failed to start command: %v", err)
+	}
+	p.start = time.Now()
+	return p, nil
+}
+
+func (p *Process) closeOutput() {
+	for _, r := range p.output {
+		r.Close()
+	}
+}
+
+func (p *Process) Pid() int {
+	return p.cmd.Process.Pid
+}
+
+func (p *Process) Write(data []byte) error {
+	switch {
+	case p.tty != nil:
+		_, err := p.tty.Write(data)
+		return err
+	case p.stdin != nil:
+		_, err := p.stdin.Write(data)
+		return err
+	}
+	return fmt.Errorf("stdin of the command is closed")
+}
+
+// CloseStdin closes stdin, or types ^D in a terminal.
+func (p *Process) CloseStdin() error {
+	if p.tty != nil {
+		_, err := p.tty.Write([]byte{4})
+		return err
+	}
+	if p.stdin == nil {
+		return nil
+	}
+	return p.stdin.Close()
+}
+
+func (p *Process) Resize(rows, cols uint16) error {
+	if p.tty == nil {
+		return fmt.Errorf("command has no terminal")
+	}
+	return pty.Setsize(p.tty, &pty.Winsize{Rows: rows, Cols: cols})
+}
+
+func (p *Process) Signal(sig syscall.Signal) error {
+	return syscall.Kill(-p.cmd.Process.Pid, sig)
+}
+
+// Kill kills the command and its children.
+func (p *Process) Kill() error {
+	return p.Signal(syscall.SIGKILL)
+}
+
+// Wait calls output, never concurrently, with the output of the command
+// until it exits. The error is always nil.
+func (p *Process) Wait(output func(stream Stream, data []byte)) (Result, error) {
+	var mu sync.Mutex
+	var stopped bool
+	var wg sync.WaitGroup
+	copyOutput := func(r io.Reader, stream Stream) {
+		defer wg.Done()
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				mu.Lock()
+				if !stopped {
+					output(stream, buf[:n])
+				}
+				mu.Unlock()
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	wg.Add(len(p.output))
+	for i, r := range p.output {
+		go copyOutput(r, Stream(i+1))
+	}
+	err := p.cmd.Wait()
+
+	// the output may never end if children of the command keep it open,
+	// and a read of a terminal is not interrupted by closing it
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(waitDelay):
+	}
+	mu.Lock()
+	stopped = true
+	mu.Unlock()
+	p.closeOutput()
+
+	result := exitResult(p.cmd.ProcessState, err)
+	result.Duration = time.Since(p.start)
+	return result, nil
+}
+
+// exitResult tells how a command ended from its state and the error Wait
+// returned.
+func exitResult(state *os.ProcessState, err error) Result {
+	if state != nil && state.Exited() {
+		return Result{ExitCode: state.ExitCode()}
+	}
+
+	result := Result{ExitCode: -1}
+	var exitErr *exec.ExitError
+	switch {
+	case state != nil:
+		// Exited is only false for a signal
+		result.Killed = true
+		if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			result.Signal = status.Signal().String()
+		}
+		result.Error = state.String()
+	case err != nil && !errors.As(err, &exitErr):
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// WithContext returns h with a Wait that calls kill once ctx is done, so
+// that the command ends with ctx. The cause of ctx is the error of the
+// result.
+func WithContext(ctx context.Context, h Handle, kill func()) Handle {
+	return &contextHandle{Handle: h, ctx: ctx, kill: kill}
+}
+
+type contextHandle struct {
+	Handle
+	ctx  context.Context
+	kill func()
+}
+
+func (h *contextHandle) Wait(output func(stream Stream, data []byte)) (Result, error) {
+	stop := context.AfterFunc(h.ctx, h.kill)
+	defer stop()
+
+	result, err := h.Handle.Wait(output)
+	// a command that exited on its own keeps its exit code
+	if h.ctx.Err() != nil && (err != nil || result.ExitCode == -1) {
+		return Result{ExitCode: -1, Duration: result.Duration, Killed: true, Error: context.Cause(h.ctx).Error()}, nil
+	}
+	return result, err
+}
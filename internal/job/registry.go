@@ -0,0 +1,269 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCancelled ends the context of a job cancelled through the registry.
+	ErrCancelled = errors.New("job cancelled")
+	// ErrTimedOut ends the context of a job that ran past its timeout.
+	ErrTimedOut = errors.New("job timed out")
+)
+
+// State is where a job is in its life. The values match the JobState proto
+// enums.
+type State int
+
+const (
+	Running State = iota + 1
+	// Exited jobs ended on their own, whatever their exit code
+	Exited
+	// Cancelled jobs were cancelled through the registry or stopped with
+	// the context they were added with
+	Cancelled
+	TimedOut
+)
+
+func (s State) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Exited:
+		return "exited"
+	case Cancelled:
+		return "cancelled"
+	case TimedOut:
+		return "timed out"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Job is a command tracked by a Registry.
+type Job struct {
+	ID string
+	// Target is where the command runs, e.g. the IP of a VM
+	Target  string
+	Command string
+	// Server is set for commands that run in the background
+	Server bool
+	// Pid is 0 if the PID of the command is unknown
+	Pid int
+	// Timeout is 0 for commands that may run forever
+	Timeout   time.Duration
+	LogPath   string
+	State     State
+	StartedAt time.Time
+	EndedAt   time.Time // zero while running
+	// Result is how the command ended, once it ended
+	Result Result
+}
+
+type entry struct {
+	info   Job
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+}
+
+// MaxEndedJobs is how many ended jobs a Registry keeps.
+const MaxEndedJobs = 1000
+
+// Registry tracks the jobs of a manager. Running jobs stay available until
+// they ended, and ended jobs until MaxEndedJobs jobs ended after them or the
+// Registry is discarded.
+type Registry struct {
+	mu   sync.Mutex
+	jobs map[string]*entry
+	// ended holds the IDs of the ended jobs, oldest first
+	ended    []string
+	maxEnded int
+}
+
+func NewRegistry() *Registry {
+	return &Registry{jobs: make(map[string]*entry), maxEnded: MaxEndedJobs}
+}
+
+// Add registers j, which is about to start, and returns it with its ID and a
+// context that is done once the job is cancelled, its timeout passes or
+// parent is done. The command must be stopped with the context, and End
+// called once it ended.
+func (r *Registry) Add(parent context.Context, j Job) (Job, context.Context) {
+	ctx, cancel := context.WithCancelCause(parent)
+	if j.Timeout > 0 {
+		var stop context.CancelFunc
+		ctx, stop = context.WithTimeoutCause(ctx, j.Timeout, ErrTimedOut)
+		cancelCause := cancel
+		cancel = func(cause error) {
+			cancelCause(cause)
+			stop()
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j.ID = r.newID()
+	j.State = Running
+	j.StartedAt = time.Now()
+	r.jobs[j.ID] = &entry{info: j, ctx: ctx, cancel: cancel, done: make(chan struct{})}
+	log.Printf("Started job %s on %s: %s", j.ID, j.Target, j.Command)
+	return j, ctx
+}
+
+// newID returns an unused job ID. The caller holds r.mu.
+func (r *Registry) newID() string {
+	for {
+		id := fmt.Sprintf("job-%d", time.Now().UnixNano())
+		if _, ok := r.jobs[id]; !ok {
+			return id
+		}
+	}
+}
+
+// SetLogPath records where the output of the job with the given ID is
+// logged, which is usually named after the job.
+func (r *Registry) SetLogPath(id, path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.jobs[id]; ok {
+		e.info.LogPath = path
+	}
+}
+
+// SetPid records the PID of the command of the job with the given ID.
+func (r *Registry) SetPid(id string, pid int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.jobs[id]; ok {
+		e.info.Pid = pid
+	}
+}
+
+// End records how the job with the given ID ended. Ending an ended job does
+// nothing.
+func (r *Registry) End(id string, result Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.jobs[id]
+	if !ok || e.info.State != Running {
+		return
+	}
+	e.info.Result = result
+	e.info.EndedAt = time.Now()
+	// a command that exited on its own as it was stopped keeps its exit
+	e.info.State = Exited
+	if e.ctx.Err() != nil && result.ExitCode == -1 {
+		e.info.State = Cancelled
+		if errors.Is(context.Cause(e.ctx), ErrTimedOut) {
+			e.info.State = TimedOut
+		}
+	}
+	e.cancel(nil)
+	close(e.done)
+	log.Printf("Job %s %s", id, e.info.State)
+
+	// forget the oldest ended jobs
+	r.ended = append(r.ended, id)
+	for len(r.ended) > r.maxEnded {
+		delete(r.jobs, r.ended[0])
+		r.ended = r.ended[1:]
+	}
+}
+
+// Track returns h with a Wait that ends the job with the given ID.
+func (r *Registry) Track(id string, h Handle) Handle {
+	return &trackedHandle{Handle: h, registry: r, id: id}
+}
+
+type trackedHandle struct {
+	Handle
+	registry *Registry
+	id       string
+}
+
+func (h *trackedHandle) Wait(output func(stream Stream, data []byte)) (Result, error) {
+	result, err := h.Handle.Wait(output)
+	if err != nil {
+		h.registry.End(h.id, Result{ExitCode: -1, Error: err.Error()})
+	} else {
+		h.registry.End(h.id, result)
+	}
+	return result, err
+}
+
+// Get returns the job with the given ID.
+func (r *Registry) Get(id string) (Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("job %s not found", id)
+	}
+	return e.info, nil
+}
+
+// List returns the jobs on target, or every job if target is empty, oldest
+// first. Of the ended jobs only the last MaxEndedJobs are kept.
+func (r *Registry) List(target string) []Job {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs := make([]Job, 0, len(r.jobs))
+	for _, e := range r.jobs {
+		if target == "" || e.info.Target == target {
+			jobs = append(jobs, e.info)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartedAt.Before(jobs[j].StartedAt) })
+	return jobs
+}
+
+// Cancel cancels the job with the given ID and returns it once it ended, or
+// as it is when ctx is done. Cancelling an ended job returns it.
+func (r *Registry) Cancel(ctx context.Context, id string) (Job, error) {
+	r.mu.Lock()
+	e, ok := r.jobs[id]
+	r.mu.Unlock()
+	if !ok {
+		return Job{}, fmt.Errorf("job %s not found", id)
+	}
+
+	e.cancel(ErrCancelled)
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+	}
+	return r.Get(id)
+}
+
+// CancelAll cancels every running job and waits until they ended or ctx is
+// done.
+func (r *Registry) CancelAll(ctx context.Context) {
+	r.mu.Lock()
+	var running []*entry
+	for _, e := range r.jobs {
+		if e.info.State == Running {
+			running = append(running, e)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, e := range running {
+		e.cancel(ErrCancelled)
+	}
+	for _, e := range running {
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
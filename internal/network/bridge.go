@@ -4,55 +4,54 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 )
 
 type Bridge struct {
-	Name string
-	IP   string
-	Taps []string
+	Name  string
+	IP    string
+	Taps  []string
+	links Links
 }
 
-func NewBridge(name, ip string) *Bridge {
+func NewBridge(links Links, name, ip string) *Bridge {
 	return &Bridge{
-		Name: name,
-		IP:   ip,
-		Taps: make([]string, 0),
+		Name:  name,
+		IP:    ip,
+		Taps:  make([]string, 0),
+		links: links,
 	}
 }
 
 func (b *Bridge) Setup() error {
 	// create bridge
-	cmd := exec.Command("sudo", "ip", "link", "add", "name", b.Name, "type", "bridge")
-	if err := cmd.Run(); err != nil {
-		log.Printf("Bridge creation: %v (might already exist)", err)
+	if err := b.links.AddBridge(b.Name); err != nil {
+		if err := ignoreExists(err); err != nil {
+			return fmt.Errorf("failed to create bridge: %w", err)
+		}
+		log.Printf("Bridge %s already exists", b.Name)
 	}
 
-	cmd = exec.Command("sudo", "ip", "link", "set", b.Name, "up")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to bring up bridge: %v", err)
+	if err := b.links.SetUp(b.Name); err != nil {
+		return fmt.Errorf("failed to bring up bridge: %w", err)
 	}
 
 	return nil
 }
 
 func (b *Bridge) AddTapAndBringUp(tapName string) error {
-	// create tap
-	cmd := exec.Command("sudo", "ip", "tuntap", "add", "dev", tapName, "mode", "tap", "user", os.Getenv("USER"))
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to create tap interface %s: %v", tapName, err)
+	// create tap, owned by us so firecracker can open it
+	if err := ignoreExists(b.links.AddTap(tapName, os.Getuid())); err != nil {
+		return fmt.Errorf("failed to create tap interface %s: %w", tapName, err)
 	}
 
 	// add tap to bridge
-	cmd = exec.Command("sudo", "ip", "link", "set", tapName, "master", b.Name)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add %s to bridge: %v", tapName, err)
+	if err := b.links.SetMaster(tapName, b.Name); err != nil {
+		return fmt.Errorf("failed to add %s to bridge: %w", tapName, err)
 	}
 
 	// bring up tap
-	cmd = exec.Command("sudo", "ip", "link", "set", tapName, "up")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to bring up %s: %v", tapName, err)
+	if err := b.links.SetUp(tapName); err != nil {
+		return fmt.Errorf("failed to bring up %s: %w", tapName, err)
 	}
 
 	log.Printf("Added tap %s to bridge %s", tapName, b.Name)
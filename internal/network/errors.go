@@ -0,0 +1,45 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrExists is returned when a link, address, route or rule is already there.
+	ErrExists = errors.New("already exists")
+	// ErrNotFound is returned when deleting something that is not there.
+	ErrNotFound = errors.New("not found")
+)
+
+// OpError records the operation and resource that failed. Use errors.Is with
+// ErrExists and ErrNotFound to tell those cases apart from real failures.
+type OpError struct {
+	Op       string
+	Resource string
+	Err      error
+}
+
+func (e *OpError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Op, e.Resource, e.Err)
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// ignoreExists turns ErrExists into nil for callers that only care that the
+// resource is there afterwards.
+func ignoreExists(err error) error {
+	if errors.Is(err, ErrExists) {
+		return nil
+	}
+	return err
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
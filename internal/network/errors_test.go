@@ -0,0 +1,60 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestOpError(t *testing.T) {
+	tests := []struct {
+		err          error
+		exists       bool
+		notFound     bool
+		wantUnwraps  error
+		wantContains string
+	}{
+		{err: unix.EEXIST, exists: true},
+		{err: fmt.Errorf("netlink: %w", unix.EEXIST), exists: true},
+		{err: unix.ENOENT, notFound: true},
+		{err: unix.ENODEV, notFound: true},
+		{err: unix.ESRCH, notFound: true},
+		{err: unix.EADDRNOTAVAIL, notFound: true},
+		{err: netlink.LinkNotFoundError{}, notFound: true},
+		{err: unix.EPERM, wantUnwraps: unix.EPERM},
+	}
+
+	for _, tt := range tests {
+		err := opError("add tap", "tap0", tt.err)
+		var opErr *OpError
+		if !errors.As(err, &opErr) || opErr.Op != "add tap" || opErr.Resource != "tap0" {
+			t.Errorf("opError(%v) = %v, want an OpError of add tap tap0", tt.err, err)
+			continue
+		}
+		if got := errors.Is(err, ErrExists); got != tt.exists {
+			t.Errorf("errors.Is(opError(%v), ErrExists) = %v, want %v", tt.err, got, tt.exists)
+		}
+		if got := errors.Is(err, ErrNotFound); got != tt.notFound {
+			t.Errorf("errors.Is(opError(%v), ErrNotFound) = %v, want %v", tt.err, got, tt.notFound)
+		}
+		if tt.wantUnwraps != nil && !errors.Is(err, tt.wantUnwraps) {
+			t.Errorf("opError(%v) = %v, lost the original error", tt.err, err)
+		}
+		if got := ignoreExists(err); (got == nil) != tt.exists {
+			t.Errorf("ignoreExists(opError(%v)) = %v", tt.err, got)
+		}
+		if got := ignoreNotFound(err); (got == nil) != tt.notFound {
+			t.Errorf("ignoreNotFound(opError(%v)) = %v", tt.err, got)
+		}
+	}
+
+	if err := opError("add tap", "tap0", nil); err != nil {
+		t.Errorf("opError(nil) = %v, want nil", err)
+	}
+	if got, want := opError("add tap", "tap0", unix.EEXIST).Error(), "add tap tap0: already exists"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,404 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+)
+
+// FakeLink is a link of FakeLinks.
+type FakeLink struct {
+	Type   string // bridge, tap or vxlan
+	Owner  int    // the uid of a tap
+	VXLAN  VXLAN
+	Master string
+	MTU    int
+	Up     bool
+	Addrs  []string
+	Netem  *Netem
+	Tbf    *Tbf
+}
+
+// FakeLinks keeps links, addresses, routes and qdiscs in memory for tests.
+// Like the netlink Links, it fails with ErrExists and ErrNotFound, and
+// deleting a link deletes its routes.
+type FakeLinks struct {
+	mu     sync.Mutex
+	links  map[string]*FakeLink
+	routes map[string]Route // by destination
+	ops    []string
+	fail   map[string]error
+}
+
+func NewFakeLinks() *FakeLinks {
+	return &FakeLinks{
+		links:  make(map[string]*FakeLink),
+		routes: make(map[string]Route),
+		fail:   make(map[string]error),
+	}
+}
+
+// Fail makes the next call of op on resource fail with err, e.g.
+// Fail("set up", "vxlan7", err).
+func (f *FakeLinks) Fail(op, resource string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fail[op+" "+resource] = err
+}
+
+// Ops returns every change made, in order, e.g. "add tap tap0".
+func (f *FakeLinks) Ops() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.ops...)
+}
+
+// Link returns a copy of the link with the given name.
+func (f *FakeLinks) Link(name string) (FakeLink, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	link, ok := f.links[name]
+	if !ok {
+		return FakeLink{}, false
+	}
+	return *link, true
+}
+
+// Links returns the names of the links, sorted.
+func (f *FakeLinks) Links() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	names := make([]string, 0, len(f.links))
+	for name := range f.links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Routes returns the routes by destination.
+func (f *FakeLinks) Routes() map[string]Route {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	routes := make(map[string]Route, len(f.routes))
+	for dst, route := range f.routes {
+		routes[dst] = route
+	}
+	return routes
+}
+
+// do records op on resource unless a failure was set up for it. The caller
+// holds f.mu.
+func (f *FakeLinks) do(op, resource string) error {
+	key := op + " " + resource
+	if err, ok := f.fail[key]; ok {
+		delete(f.fail, key)
+		return &OpError{Op: op, Resource: resource, Err: err}
+	}
+	f.ops = append(f.ops, key)
+	return nil
+}
+
+// link returns the link with the given name. The caller holds f.mu.
+func (f *FakeLinks) link(op, name string) (*FakeLink, error) {
+	link, ok := f.links[name]
+	if !ok {
+		return nil, &OpError{Op: op, Resource: name, Err: ErrNotFound}
+	}
+	return link, nil
+}
+
+func (f *FakeLinks) addLink(op, name string, link *FakeLink) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if existing, ok := f.links[name]; ok {
+		if existing.Type != link.Type {
+			return &OpError{Op: op, Resource: name, Err: fmt.Errorf("link is a %s, not a %s", existing.Type, link.Type)}
+		}
+		return &OpError{Op: op, Resource: name, Err: ErrExists}
+	}
+	if err := f.do(op, name); err != nil {
+		return err
+	}
+	link.MTU = 1500
+	f.links[name] = link
+	return nil
+}
+
+func (f *FakeLinks) AddBridge(name string) error {
+	return f.addLink("add bridge", name, &FakeLink{Type: "bridge"})
+}
+
+func (f *FakeLinks) AddTap(name string, uid int) error {
+	return f.addLink("add tap", name, &FakeLink{Type: "tap", Owner: uid})
+}
+
+func (f *FakeLinks) AddVXLAN(name string, vxlan VXLAN) error {
+	return f.addLink("add vxlan", name, &FakeLink{Type: "vxlan", VXLAN: vxlan})
+}
+
+func (f *FakeLinks) SetMaster(name, master string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	link, err := f.link("set master", name)
+	if err != nil {
+		return err
+	}
+	if _, err := f.link("set master", master); err != nil {
+		return err
+	}
+	if err := f.do("set master", name); err != nil {
+		return err
+	}
+	link.Master = master
+	return nil
+}
+
+func (f *FakeLinks) SetMTU(name string, mtu int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	link, err := f.link("set mtu", name)
+	if err != nil {
+		return err
+	}
+	if err := f.do("set mtu", name); err != nil {
+		return err
+	}
+	link.MTU = mtu
+	return nil
+}
+
+func (f *FakeLinks) SetUp(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	link, err := f.link("set up", name)
+	if err != nil {
+		return err
+	}
+	if err := f.do("set up", name); err != nil {
+		return err
+	}
+	link.Up = true
+	return nil
+}
+
+func (f *FakeLinks) DeleteLink(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.link("delete link", name); err != nil {
+		return err
+	}
+	if err := f.do("delete link", name); err != nil {
+		return err
+	}
+	delete(f.links, name)
+	for _, link := range f.links {
+		if link.Master == name {
+			link.Master = ""
+		}
+	}
+	for dst, route := range f.routes {
+		if route.Device == name {
+			delete(f.routes, dst)
+		}
+	}
+	return nil
+}
+
+func (f *FakeLinks) AddAddr(name string, addr *net.IPNet) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	op := "add addr " + addr.String() + " to"
+	link, err := f.link(op, name)
+	if err != nil {
+		return err
+	}
+	for _, existing := range link.Addrs {
+		if existing == addr.String() {
+			return &OpError{Op: op, Resource: name, Err: ErrExists}
+		}
+	}
+	if err := f.do(op, name); err != nil {
+		return err
+	}
+	link.Addrs = append(link.Addrs, addr.String())
+	return nil
+}
+
+func (f *FakeLinks) DeleteAddr(name string, addr *net.IPNet) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	op := "delete addr " + addr.String() + " from"
+	link, err := f.link(op, name)
+	if err != nil {
+		return err
+	}
+	for i, existing := range link.Addrs {
+		if existing == addr.String() {
+			if err := f.do(op, name); err != nil {
+				return err
+			}
+			link.Addrs = append(link.Addrs[:i], link.Addrs[i+1:]...)
+			return nil
+		}
+	}
+	return &OpError{Op: op, Resource: name, Err: ErrNotFound}
+}
+
+func (f *FakeLinks) Route(dst *net.IPNet) (Route, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	route, ok := f.routes[dst.String()]
+	if !ok {
+		return Route{}, &OpError{Op: "get route", Resource: dst.String(), Err: ErrNotFound}
+	}
+	return route, nil
+}
+
+func (f *FakeLinks) ReplaceRoute(route Route) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if route.Device != "" {
+		if _, err := f.link("replace route", route.Device); err != nil {
+			return err
+		}
+	}
+	if err := f.do("replace route", route.Dst.String()); err != nil {
+		return err
+	}
+	f.routes[route.Dst.String()] = route
+	return nil
+}
+
+func (f *FakeLinks) DeleteRoute(dst *net.IPNet) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.routes[dst.String()]; !ok {
+		return &OpError{Op: "delete route", Resource: dst.String(), Err: ErrNotFound}
+	}
+	if err := f.do("delete route", dst.String()); err != nil {
+		return err
+	}
+	delete(f.routes, dst.String())
+	return nil
+}
+
+func (f *FakeLinks) ReplaceQdiscs(name string, netem *Netem, tbf *Tbf) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	link, err := f.link("shape", name)
+	if err != nil {
+		return err
+	}
+	if err := f.do("shape", name); err != nil {
+		return err
+	}
+	link.Netem, link.Tbf = netem, tbf
+	return nil
+}
+
+func (f *FakeLinks) DeleteQdiscs(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	link, err := f.link("delete qdiscs of", name)
+	if err != nil {
+		return err
+	}
+	if link.Netem == nil && link.Tbf == nil {
+		return &OpError{Op: "delete qdiscs of", Resource: name, Err: ErrNotFound}
+	}
+	if err := f.do("delete qdiscs of", name); err != nil {
+		return err
+	}
+	link.Netem, link.Tbf = nil, nil
+	return nil
+}
+
+// FakeFirewall keeps rules in memory for tests. Like the iptables Firewall,
+// it fails with ErrExists and ErrNotFound.
+type FakeFirewall struct {
+	mu    sync.Mutex
+	rules []Rule
+	ops   []string
+}
+
+func NewFakeFirewall() *FakeFirewall {
+	return &FakeFirewall{}
+}
+
+// Ops returns every change made, in order, e.g. "insert rule filter/INPUT ...".
+func (f *FakeFirewall) Ops() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.ops...)
+}
+
+// Rules returns the rules of every chain, each chain in order.
+func (f *FakeFirewall) Rules() []Rule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Rule(nil), f.rules...)
+}
+
+// index returns the index of rule, or -1. The caller holds f.mu.
+func (f *FakeFirewall) index(rule Rule) int {
+	for i, existing := range f.rules {
+		if existing.String() == rule.String() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *FakeFirewall) InsertRule(rule Rule) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.index(rule) >= 0 {
+		return &OpError{Op: "insert rule", Resource: rule.String(), Err: ErrExists}
+	}
+	f.ops = append(f.ops, "insert rule "+rule.String())
+	f.rules = append([]Rule{rule}, f.rules...)
+	return nil
+}
+
+func (f *FakeFirewall) AppendRule(rule Rule) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.index(rule) >= 0 {
+		return &OpError{Op: "append rule", Resource: rule.String(), Err: ErrExists}
+	}
+	f.ops = append(f.ops, "append rule "+rule.String())
+	f.rules = append(f.rules, rule)
+	return nil
+}
+
+func (f *FakeFirewall) DeleteRule(rule Rule) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	i := f.index(rule)
+	if i < 0 {
+		return &OpError{Op: "delete rule", Resource: rule.String(), Err: ErrNotFound}
+	}
+	f.ops = append(f.ops, "delete rule "+rule.String())
+	f.rules = append(f.rules[:i], f.rules[i+1:]...)
+	return nil
+}
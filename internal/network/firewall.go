@@ -0,0 +1,84 @@
+package network
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coreos/go-iptables/iptables"
+)
+
+// Rule is a firewall rule in iptables syntax, without the table and chain.
+type Rule struct {
+	Table string   `json:"table"`
+	Chain string   `json:"chain"`
+	Spec  []string `json:"spec"`
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%s/%s %s", r.Table, r.Chain, strings.Join(r.Spec, " "))
+}
+
+// Firewall adds and removes rules. Like Links, adding a rule that is already
+// there fails with ErrExists and deleting a missing one with ErrNotFound.
+type Firewall interface {
+	// InsertRule puts the rule at the top of its chain.
+	InsertRule(rule Rule) error
+	AppendRule(rule Rule) error
+	DeleteRule(rule Rule) error
+}
+
+type iptablesFirewall struct {
+	ipt *iptables.IPTables
+}
+
+// NewIptablesFirewall returns a Firewall backed by the iptables binary. The
+// process needs CAP_NET_ADMIN, not sudo.
+func NewIptablesFirewall() (Firewall, error) {
+	ipt, err := iptables.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize iptables: %v", err)
+	}
+	return &iptablesFirewall{ipt: ipt}, nil
+}
+
+func (f *iptablesFirewall) InsertRule(rule Rule) error {
+	return f.add("insert rule", rule, func() error {
+		return f.ipt.Insert(rule.Table, rule.Chain, 1, rule.Spec...)
+	})
+}
+
+func (f *iptablesFirewall) AppendRule(rule Rule) error {
+	return f.add("append rule", rule, func() error {
+		return f.ipt.Append(rule.Table, rule.Chain, rule.Spec...)
+	})
+}
+
+func (f *iptablesFirewall) add(op string, rule Rule, add func() error) error {
+	exists, err := f.ipt.Exists(rule.Table, rule.Chain, rule.Spec...)
+	if err != nil {
+		return &OpError{Op: op, Resource: rule.String(), Err: err}
+	}
+	if exists {
+		return &OpError{Op: op, Resource: rule.String(), Err: ErrExists}
+	}
+
+	if err := add(); err != nil {
+		return &OpError{Op: op, Resource: rule.String(), Err: err}
+	}
+	return nil
+}
+
+func (f *iptablesFirewall) DeleteRule(rule Rule) error {
+	exists, err := f.ipt.Exists(rule.Table, rule.Chain, rule.Spec...)
+	if err != nil {
+		return &OpError{Op: "delete rule", Resource: rule.String(), Err: err}
+	}
+	if !exists {
+		return &OpError{Op: "delete rule", Resource: rule.String(), Err: ErrNotFound}
+	}
+
+	if err := f.ipt.Delete(rule.Table, rule.Chain, rule.Spec...); err != nil {
+		return &OpError{Op: "delete rule", Resource: rule.String(), Err: err}
+	}
+	return nil
+}
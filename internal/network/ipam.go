@@ -0,0 +1,231 @@
+package network
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const leasesFile = "leases.json"
+
+// Lease is a guest IP handed out by the IPAM.
+type Lease struct {
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// IPAM hands out the guest IPs of an IPv4 subnet. The network and broadcast
+// addresses are never leased, and neither is the first host address, which
+// is the gateway on the bridge. Leases are persisted, so a restarted runner
+// does not hand out addresses that guests of the previous run may still use.
+type IPAM struct {
+	subnet  *net.IPNet
+	gateway net.IP
+	path    string
+
+	mu     sync.Mutex
+	leases map[string]Lease
+}
+
+// NewIPAM loads the leases of subnet from stateDir. The directory is only
+// created once the first lease is written.
+func NewIPAM(subnet, stateDir string) (*IPAM, error) {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet %q: %v", subnet, err)
+	}
+	if ipNet.IP.To4() == nil {
+		return nil, fmt.Errorf("subnet %s is not IPv4", subnet)
+	}
+	if ones, bits := ipNet.Mask.Size(); bits-ones < 2 {
+		return nil, fmt.Errorf("subnet %s has no room for guests", subnet)
+	}
+
+	ipam := &IPAM{
+		subnet:  ipNet,
+		gateway: uint32ToIP(ipToUint32(ipNet.IP) + 1),
+		path:    filepath.Join(stateDir, leasesFile),
+		leases:  make(map[string]Lease),
+	}
+	if err := ipam.load(); err != nil {
+		return nil, err
+	}
+	return ipam, nil
+}
+
+// Subnet returns a copy of the subnet.
+func (a *IPAM) Subnet() *net.IPNet {
+	return &net.IPNet{IP: append(net.IP(nil), a.subnet.IP...), Mask: append(net.IPMask(nil), a.subnet.Mask...)}
+}
+
+func (a *IPAM) Gateway() net.IP {
+	return append(net.IP(nil), a.gateway...)
+}
+
+func (a *IPAM) PrefixLength() int {
+	ones, _ := a.subnet.Mask.Size()
+	return ones
+}
+
+// Allocate leases the lowest free address.
+func (a *IPAM) Allocate() (net.IP, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	first, last := a.hostRange()
+	for n := first; n <= last; n++ {
+		ip := uint32ToIP(n)
+		if _, ok := a.leases[ip.String()]; ok {
+			continue
+		}
+
+		a.leases[ip.String()] = Lease{IP: ip.String(), CreatedAt: time.Now()}
+		if err := a.save(); err != nil {
+			delete(a.leases, ip.String())
+			return nil, err
+		}
+		return ip, nil
+	}
+
+	return nil, fmt.Errorf("no free addresses in %s", a.subnet)
+}
+
+// Reserve leases a caller-chosen address. Addresses outside the subnet, the
+// gateway and addresses that are already leased, by this or an earlier run,
+// are refused.
+func (a *IPAM) Reserve(ip net.IP) error {
+	ip = ip.To4()
+	if ip == nil || !a.subnet.Contains(ip) {
+		return fmt.Errorf("%s is not in subnet %s", ip, a.subnet)
+	}
+	first, last := a.hostRange()
+	if n := ipToUint32(ip); n < first || n > last {
+		return fmt.Errorf("%s is reserved in subnet %s", ip, a.subnet)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if lease, ok := a.leases[ip.String()]; ok {
+		return fmt.Errorf("%s is already leased since %s", ip, lease.CreatedAt.Format(time.RFC3339))
+	}
+
+	a.leases[ip.String()] = Lease{IP: ip.String(), CreatedAt: time.Now()}
+	if err := a.save(); err != nil {
+		delete(a.leases, ip.String())
+		return err
+	}
+	return nil
+}
+
+func (a *IPAM) Release(ip net.IP) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.leases[ip.String()]; !ok {
+		return nil
+	}
+	delete(a.leases, ip.String())
+	return a.save()
+}
+
+func (a *IPAM) ReleaseAll() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.leases = make(map[string]Lease)
+	return a.save()
+}
+
+// Leases returns the current leases ordered by address.
+func (a *IPAM) Leases() []Lease {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	leases := make([]Lease, 0, len(a.leases))
+	for _, lease := range a.leases {
+		leases = append(leases, lease)
+	}
+	sort.Slice(leases, func(i, j int) bool {
+		return ipToUint32(net.ParseIP(leases[i].IP)) < ipToUint32(net.ParseIP(leases[j].IP))
+	})
+	return leases
+}
+
+// hostRange returns the first and last leasable address, skipping the
+// network address, the gateway and the broadcast address.
+func (a *IPAM) hostRange() (uint32, uint32) {
+	network := ipToUint32(a.subnet.IP)
+	broadcast := network | ^binary.BigEndian.Uint32(a.subnet.Mask)
+	return network + 2, broadcast - 1
+}
+
+func (a *IPAM) load() error {
+	data, err := os.ReadFile(a.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read leases: %v", err)
+	}
+
+	var leases []Lease
+	if err := json.Unmarshal(data, &leases); err != nil {
+		return fmt.Errorf("failed to parse leases %s: %v", a.path, err)
+	}
+
+	for _, lease := range leases {
+		ip := net.ParseIP(lease.IP)
+		if ip == nil || !a.subnet.Contains(ip) {
+			log.Printf("Dropping lease %s outside of subnet %s", lease.IP, a.subnet)
+			continue
+		}
+		a.leases[ip.String()] = lease
+	}
+	return nil
+}
+
+// save writes the leases to a temporary file and renames it over the old
+// one. The caller holds a.mu.
+func (a *IPAM) save() error {
+	leases := make([]Lease, 0, len(a.leases))
+	for _, lease := range a.leases {
+		leases = append(leases, lease)
+	}
+	sort.Slice(leases, func(i, j int) bool { return leases[i].IP < leases[j].IP })
+
+	data, err := json.MarshalIndent(leases, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode leases: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		return fmt.Errorf("failed to create leases directory: %v", err)
+	}
+	tmp := a.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write leases: %v", err)
+	}
+	if err := os.Rename(tmp, a.path); err != nil {
+		return fmt.Errorf("failed to write leases: %v", err)
+	}
+	return nil
+}
+
+func ipToUint32(ip net.IP) uint32 {
+	return binary.BigEndian.Uint32(ip.To4())
+}
+
+func uint32ToIP(n uint32) net.IP {
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, n)
+	return ip
+}
@@ -0,0 +1,175 @@
+package network
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewIPAM(t *testing.T) {
+	tests := []struct {
+		subnet  string
+		gateway string
+		prefix  int
+		wantErr string
+	}{
+		{subnet: "192.168.100.0/24", gateway: "192.168.100.1", prefix: 24},
+		{subnet: "10.0.7.9/16", gateway: "10.0.0.1", prefix: 16},
+		{subnet: "10.0.0.0/30", gateway: "10.0.0.1", prefix: 30},
+		{subnet: "10.0.0.0/31", wantErr: "no room"},
+		{subnet: "fd00::/64", wantErr: "not IPv4"},
+		{subnet: "10.0.0.0", wantErr: "invalid subnet"},
+	}
+
+	for _, tt := range tests {
+		ipam, err := NewIPAM(tt.subnet, t.TempDir())
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewIPAM(%q): got %v, want an error containing %q", tt.subnet, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewIPAM(%q): %v", tt.subnet, err)
+			continue
+		}
+		if got := ipam.Gateway().String(); got != tt.gateway {
+			t.Errorf("NewIPAM(%q): got gateway %s, want %s", tt.subnet, got, tt.gateway)
+		}
+		if got := ipam.PrefixLength(); got != tt.prefix {
+			t.Errorf("NewIPAM(%q): got prefix length %d, want %d", tt.subnet, got, tt.prefix)
+		}
+	}
+}
+
+func TestIPAMAllocate(t *testing.T) {
+	ipam, err := NewIPAM("10.0.0.0/29", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the network, gateway and broadcast addresses are never leased
+	var got []string
+	for {
+		ip, err := ipam.Allocate()
+		if err != nil {
+			if !strings.Contains(err.Error(), "no free addresses") {
+				t.Fatal(err)
+			}
+			break
+		}
+		got = append(got, ip.String())
+	}
+	want := []string{"10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// the lowest free address comes next
+	if err := ipam.Release(net.ParseIP("10.0.0.4")); err != nil {
+		t.Fatal(err)
+	}
+	if err := ipam.Release(net.ParseIP("10.0.0.3")); err != nil {
+		t.Fatal(err)
+	}
+	if ip, err := ipam.Allocate(); err != nil || ip.String() != "10.0.0.3" {
+		t.Errorf("got %v, %v, want 10.0.0.3", ip, err)
+	}
+	// releasing a free address does nothing
+	if err := ipam.Release(net.ParseIP("10.0.0.4")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIPAMReserve(t *testing.T) {
+	tests := []struct {
+		ip      string
+		wantErr string
+	}{
+		{ip: "192.168.100.2"},
+		{ip: "192.168.100.254"},
+		{ip: "192.168.100.0", wantErr: "is reserved"},
+		{ip: "192.168.100.1", wantErr: "is reserved"},
+		{ip: "192.168.100.255", wantErr: "is reserved"},
+		{ip: "192.168.101.2", wantErr: "not in subnet"},
+		{ip: "fd00::2", wantErr: "not in subnet"},
+		{ip: "192.168.100.10", wantErr: "already leased"},
+	}
+
+	ipam, err := NewIPAM("192.168.100.0/24", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ipam.Reserve(net.ParseIP("192.168.100.10")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		err := ipam.Reserve(net.ParseIP(tt.ip))
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("Reserve(%s): %v", tt.ip, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Reserve(%s): got %v, want an error containing %q", tt.ip, err, tt.wantErr)
+		}
+	}
+
+	// reserved addresses are skipped by Allocate
+	if ip, err := ipam.Allocate(); err != nil || ip.String() != "192.168.100.3" {
+		t.Errorf("got %v, %v, want 192.168.100.3", ip, err)
+	}
+}
+
+func TestIPAMLeasesPersist(t *testing.T) {
+	dir := t.TempDir()
+	ipam, err := NewIPAM("192.168.100.0/24", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ipam.Allocate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ipam.Reserve(net.ParseIP("192.168.100.50")); err != nil {
+		t.Fatal(err)
+	}
+
+	ipam, err = NewIPAM("192.168.100.0/24", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, lease := range ipam.Leases() {
+		got = append(got, lease.IP)
+	}
+	if want := []string{"192.168.100.2", "192.168.100.50"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got leases %v, want %v", got, want)
+	}
+	// a lease of an earlier run is still taken
+	if err := ipam.Reserve(net.ParseIP("192.168.100.50")); err == nil {
+		t.Error("reserved an address leased by an earlier run")
+	}
+
+	// leases outside a changed subnet are dropped
+	ipam, err = NewIPAM("192.168.100.0/27", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leases := ipam.Leases(); len(leases) != 1 || leases[0].IP != "192.168.100.2" {
+		t.Errorf("got leases %v", leases)
+	}
+
+	if err := ipam.ReleaseAll(); err != nil {
+		t.Fatal(err)
+	}
+	ipam, err = NewIPAM("192.168.100.0/24", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leases := ipam.Leases(); len(leases) != 0 {
+		t.Errorf("got leases %v after ReleaseAll", leases)
+	}
+}
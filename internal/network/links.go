@@ -0,0 +1,303 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// Links creates and removes the links, addresses and routes of the VM network.
+// Adding something that is already there fails with ErrExists and deleting
+// something that is not there fails with ErrNotFound.
+type Links interface {
+	AddBridge(name string) error
+	// AddTap creates a persistent tap device owned by uid, so firecracker can
+	// open it without privileges.
+	AddTap(name string, uid int) error
+	AddVXLAN(name string, vxlan VXLAN) error
+	SetMaster(name, master string) error
+	SetMTU(name string, mtu int) error
+	SetUp(name string) error
+	DeleteLink(name string) error
+	AddAddr(name string, addr *net.IPNet) error
+	DeleteAddr(name string, addr *net.IPNet) error
+	// Route returns the route to dst.
+	Route(dst *net.IPNet) (Route, error)
+	// ReplaceRoute adds the route or overwrites an existing route to the same destination.
+	ReplaceRoute(route Route) error
+	DeleteRoute(dst *net.IPNet) error
+	// ReplaceQdiscs replaces the root qdisc of the link with a tbf, a netem or
+	// a tbf with a netem child. DeleteQdiscs restores the default qdisc.
+	ReplaceQdiscs(name string, netem *Netem, tbf *Tbf) error
+	DeleteQdiscs(name string) error
+}
+
+type VXLAN struct {
+	VNI    uint32
+	Remote net.IP
+	Local  net.IP // optional
+	Port   int
+}
+
+// Route goes either via Gateway or directly out of Device.
+type Route struct {
+	Dst     *net.IPNet
+	Gateway net.IP
+	Device  string
+}
+
+type netlinkLinks struct{}
+
+// NewNetlinkLinks returns Links that talk to the kernel over netlink. The
+// process needs CAP_NET_ADMIN, not sudo.
+func NewNetlinkLinks() Links {
+	return netlinkLinks{}
+}
+
+func (netlinkLinks) AddBridge(name string) error {
+	return addLink("add bridge", &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: name}})
+}
+
+func (netlinkLinks) AddTap(name string, uid int) error {
+	return addLink("add tap", &netlink.Tuntap{
+		LinkAttrs: netlink.LinkAttrs{Name: name},
+		Mode:      netlink.TUNTAP_MODE_TAP,
+		Flags:     netlink.TUNTAP_NO_PI,
+		Owner:     uint32(uid),
+		Group:     ^uint32(0), // no group, like ip tuntap
+	})
+}
+
+func (netlinkLinks) AddVXLAN(name string, vxlan VXLAN) error {
+	return addLink("add vxlan", &netlink.Vxlan{
+		LinkAttrs: netlink.LinkAttrs{Name: name},
+		VxlanId:   int(vxlan.VNI),
+		Group:     vxlan.Remote,
+		SrcAddr:   vxlan.Local,
+		Port:      vxlan.Port,
+	})
+}
+
+func (netlinkLinks) SetMaster(name, master string) error {
+	link, err := linkByName("set master", name)
+	if err != nil {
+		return err
+	}
+	masterLink, err := linkByName("set master", master)
+	if err != nil {
+		return err
+	}
+	return opError("set master", name, netlink.LinkSetMaster(link, masterLink))
+}
+
+func (netlinkLinks) SetMTU(name string, mtu int) error {
+	link, err := linkByName("set mtu", name)
+	if err != nil {
+		return err
+	}
+	return opError("set mtu", name, netlink.LinkSetMTU(link, mtu))
+}
+
+func (netlinkLinks) SetUp(name string) error {
+	link, err := linkByName("set up", name)
+	if err != nil {
+		return err
+	}
+	return opError("set up", name, netlink.LinkSetUp(link))
+}
+
+func (netlinkLinks) DeleteLink(name string) error {
+	link, err := linkByName("delete link", name)
+	if err != nil {
+		return err
+	}
+	return opError("delete link", name, netlink.LinkDel(link))
+}
+
+func (netlinkLinks) AddAddr(name string, addr *net.IPNet) error {
+	op := "add addr " + addr.String() + " to"
+	link, err := linkByName(op, name)
+	if err != nil {
+		return err
+	}
+	return opError(op, name, netlink.AddrAdd(link, &netlink.Addr{IPNet: addr}))
+}
+
+func (netlinkLinks) DeleteAddr(name string, addr *net.IPNet) error {
+	op := "delete addr " + addr.String() + " from"
+	link, err := linkByName(op, name)
+	if err != nil {
+		return err
+	}
+	return opError(op, name, netlink.AddrDel(link, &netlink.Addr{IPNet: addr}))
+}
+
+func (netlinkLinks) Route(dst *net.IPNet) (Route, error) {
+	family := netlink.FAMILY_V4
+	if dst.IP.To4() == nil {
+		family = netlink.FAMILY_V6
+	}
+	routes, err := netlink.RouteListFiltered(family, &netlink.Route{Dst: dst}, netlink.RT_FILTER_DST)
+	if err != nil {
+		return Route{}, opError("get route", dst.String(), err)
+	}
+	if len(routes) == 0 {
+		return Route{}, &OpError{Op: "get route", Resource: dst.String(), Err: ErrNotFound}
+	}
+
+	route := Route{Dst: dst, Gateway: routes[0].Gw}
+	if routes[0].LinkIndex > 0 {
+		link, err := netlink.LinkByIndex(routes[0].LinkIndex)
+		if err != nil {
+			return Route{}, opError("get route", dst.String(), err)
+		}
+		route.Device = link.Attrs().Name
+	}
+	return route, nil
+}
+
+func (netlinkLinks) ReplaceRoute(route Route) error {
+	r := &netlink.Route{Dst: route.Dst, Gw: route.Gateway}
+	if route.Device != "" {
+		link, err := linkByName("replace route", route.Device)
+		if err != nil {
+			return err
+		}
+		r.LinkIndex = link.Attrs().Index
+	}
+	return opError("replace route", route.Dst.String(), netlink.RouteReplace(r))
+}
+
+func (netlinkLinks) DeleteRoute(dst *net.IPNet) error {
+	return opError("delete route", dst.String(), netlink.RouteDel(&netlink.Route{Dst: dst}))
+}
+
+func (netlinkLinks) ReplaceQdiscs(name string, netem *Netem, tbf *Tbf) error {
+	link, err := linkByName("shape", name)
+	if err != nil {
+		return err
+	}
+	if err := ignoreNotFound(deleteRootQdisc(link)); err != nil {
+		return err
+	}
+
+	index := link.Attrs().Index
+	parent := uint32(netlink.HANDLE_ROOT)
+	handle := netlink.MakeHandle(1, 0)
+
+	if tbf != nil {
+		rate := tbf.RateKbit * 1000 / 8 // bytes per second
+		qdisc := &netlink.Tbf{
+			QdiscAttrs: netlink.QdiscAttrs{LinkIndex: index, Handle: handle, Parent: parent},
+			Rate:       rate,
+			Buffer:     netlink.Xmittime(rate, tbf.BurstBytes),
+			Limit:      uint32(rate*uint64(tbf.LatencyMs)/1000) + tbf.BurstBytes,
+		}
+		if err := netlink.QdiscAdd(qdisc); err != nil {
+			return opError("add tbf qdisc to", name, err)
+		}
+		// netem goes below the tbf class
+		parent, handle = netlink.MakeHandle(1, 1), netlink.MakeHandle(10, 0)
+	}
+
+	if netem != nil {
+		qdisc := netlink.NewNetem(netlink.QdiscAttrs{LinkIndex: index, Handle: handle, Parent: parent}, netlink.NetemQdiscAttrs{
+			Latency:     netem.DelayMs * 1000,
+			Jitter:      netem.JitterMs * 1000,
+			Loss:        netem.LossPercent,
+			Duplicate:   netem.DuplicatePercent,
+			ReorderProb: netem.ReorderPercent,
+			CorruptProb: netem.CorruptPercent,
+			Limit:       netem.Limit,
+		})
+		if err := netlink.QdiscAdd(qdisc); err != nil {
+			return opError("add netem qdisc to", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (netlinkLinks) DeleteQdiscs(name string) error {
+	link, err := linkByName("delete qdiscs of", name)
+	if err != nil {
+		return err
+	}
+	return deleteRootQdisc(link)
+}
+
+// deleteRootQdisc deletes a tbf or netem root qdisc, which also deletes its
+// children. Other root qdiscs are the kernel's defaults and left alone.
+func deleteRootQdisc(link netlink.Link) error {
+	name := link.Attrs().Name
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return opError("list qdiscs of", name, err)
+	}
+
+	for _, qdisc := range qdiscs {
+		if qdisc.Attrs().Parent != netlink.HANDLE_ROOT {
+			continue
+		}
+		switch qdisc.Type() {
+		case "tbf", "netem":
+			return opError("delete qdiscs of", name, netlink.QdiscDel(qdisc))
+		}
+	}
+
+	return &OpError{Op: "delete qdiscs of", Resource: name, Err: ErrNotFound}
+}
+
+// addLink creates link unless a link with its name exists. An existing link
+// of another type is an error rather than ErrExists, since it cannot be used.
+func addLink(op string, link netlink.Link) error {
+	name := link.Attrs().Name
+	existing, err := netlink.LinkByName(name)
+	if err == nil {
+		if existing.Type() != link.Type() {
+			return &OpError{Op: op, Resource: name, Err: fmt.Errorf("link is a %s, not a %s", existing.Type(), link.Type())}
+		}
+		return &OpError{Op: op, Resource: name, Err: ErrExists}
+	}
+	if !isNotFound(err) {
+		return opError(op, name, err)
+	}
+
+	return opError(op, name, netlink.LinkAdd(link))
+}
+
+func linkByName(op, name string) (netlink.Link, error) {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return nil, opError(op, name, err)
+	}
+	return link, nil
+}
+
+// opError wraps a netlink error, mapping the errnos for "already there" and
+// "not there" to ErrExists and ErrNotFound.
+func opError(op, resource string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, unix.EEXIST):
+		err = ErrExists
+	case isNotFound(err):
+		err = ErrNotFound
+	}
+	return &OpError{Op: op, Resource: resource, Err: err}
+}
+
+func isNotFound(err error) bool {
+	var linkNotFound netlink.LinkNotFoundError
+	return errors.As(err, &linkNotFound) ||
+		errors.Is(err, unix.ENODEV) ||
+		errors.Is(err, unix.ENOENT) ||
+		errors.Is(err, unix.ESRCH) ||
+		errors.Is(err, unix.EADDRNOTAVAIL)
+}
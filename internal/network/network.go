@@ -1,96 +1,241 @@
 package network
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"os/exec"
+	"net"
+	"os"
 	"strings"
+	"sync"
 )
 
-func Setup(numVMs int, bridgeIP string) (*Bridge, error) {
-	bridge := NewBridge("br0", bridgeIP)
+const ipForwardPath = "/proc/sys/net/ipv4/ip_forward"
+
+// Options name what a Manager creates on the host, so that several runners
+// can share it.
+type Options struct {
+	StateDir  string // holds the network state
+	Bridge    string
+	TapPrefix string // taps are named after it and the slot of their VM
+}
+
+// Manager sets up the bridge, taps, firewall rules and cross-node routes of
+// this node through the Links and Firewall backends. Everything it creates is
+// recorded in a state file under stateDir, so Cleanup can remove exactly
+// that, even after a restart.
+type Manager struct {
+	links    Links
+	firewall Firewall
+	state    *resourceState
+	opts     Options
+	// enableForwarding is replaced in tests
+	enableForwarding func() error
+
+	mu         sync.Mutex
+	bridge     *Bridge
+	routes     *CrossNodeRoutes
+	resolveTap func(ip string) (string, error)
+}
+
+func NewManager(links Links, firewall Firewall, opts Options) (*Manager, error) {
+	state, err := loadResourceState(opts.StateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{
+		links:            &trackingLinks{Links: links, state: state},
+		firewall:         &trackingFirewall{Firewall: firewall, state: state},
+		state:            state,
+		opts:             opts,
+		enableForwarding: enableIPForwarding,
+	}
+	m.bridge = NewBridge(m.links, opts.Bridge, "")
+	m.routes = newCrossNodeRoutes(m.links, m.firewall, state)
+
+	if n := len(state.list()); n > 0 {
+		log.Printf("Loaded %d network resources created by an earlier run", n)
+	}
+	return m, nil
+}
+
+// NewDefaultManager uses netlink and iptables.
+func NewDefaultManager(opts Options) (*Manager, error) {
+	firewall, err := NewIptablesFirewall()
+	if err != nil {
+		return nil, err
+	}
+	return NewManager(NewNetlinkLinks(), firewall, opts)
+}
+
+// Setup creates the bridge and numVMs taps and lets the guests reach each
+// other and the outside world. Calling it again with the same arguments
+// leaves existing links and rules as they are.
+func (m *Manager) Setup(numVMs int, bridgeIP string) (*Bridge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bridge := NewBridge(m.links, m.opts.Bridge, bridgeIP)
 	err := bridge.Setup()
 	if err != nil {
-		return nil, fmt.Errorf("failed to setup bridge: %v", err)
+		return nil, fmt.Errorf("failed to setup bridge: %w", err)
 	}
 
 	// create tap interfaces for each VM
 	for i := 0; i < numVMs; i++ {
-		err = bridge.AddTapAndBringUp(fmt.Sprintf("tap%d", i))
+		tap := fmt.Sprintf("%s%d", m.opts.TapPrefix, i)
+		err = bridge.AddTapAndBringUp(tap)
 		if err != nil {
-			return nil, fmt.Errorf("failed to add tap%d to bridge: %v", i, err)
+			return nil, fmt.Errorf("failed to add %s to bridge: %w", tap, err)
 		}
 	}
 
 	// configure bridge IP with subnet mask
-	// Always ensure the bridge IP has /24 suffix
-	bridgeIPWithMask := bridge.IP
-	if !strings.Contains(bridgeIPWithMask, "/") {
-		bridgeIPWithMask = bridgeIPWithMask + "/24"
+	ip, subnet, err := parseBridgeIP(bridge.IP)
+	if err != nil {
+		return nil, err
+	}
+	addr := &net.IPNet{IP: ip, Mask: subnet.Mask}
+	if err := ignoreExists(m.links.AddAddr(bridge.Name, addr)); err != nil {
+		return nil, fmt.Errorf("failed to add IP to bridge: %w", err)
 	}
 
-	cmd := exec.Command("sudo", "ip", "addr", "add", bridgeIPWithMask, "dev", bridge.Name)
-	if err := cmd.Run(); err != nil {
-		log.Printf("Failed to add IP to bridge (might already exist): %v", err)
+	if err := m.enableForwarding(); err != nil {
+		return nil, err
 	}
 
 	// set up iptables rules for forwarding
-	setupIptables(bridge)
+	if err := m.setupIptables(bridge, subnet); err != nil {
+		return nil, err
+	}
 
+	m.bridge = bridge
 	log.Println("Networking setup completed successfully")
 	return bridge, nil
 }
 
-func setupIptables(bridge *Bridge) {
-	// Extract subnet from bridge IP (e.g., "192.168.100.1" -> "192.168.100.0/24")
-	bridgeSubnet := getBridgeSubnet(bridge.IP)
+func (m *Manager) setupIptables(bridge *Bridge, subnet *net.IPNet) error {
+	for _, rule := range bridgeRules(bridge) {
+		if err := ignoreExists(m.firewall.InsertRule(rule)); err != nil {
+			return fmt.Errorf("failed to set up forwarding: %w", err)
+		}
+	}
 
-	commands := [][]string{
-		{"sudo", "sh", "-c", "echo 1 > /proc/sys/net/ipv4/ip_forward"},
-		{"sudo", "iptables", "-I", "INPUT", "-i", bridge.Name, "-p", "udp", "-j", "ACCEPT"},
-		{"sudo", "iptables", "-I", "INPUT", "-i", bridge.Name, "-p", "tcp", "-j", "ACCEPT"},
-		{"sudo", "iptables", "-I", "INPUT", "-i", bridge.Name, "-p", "icmp", "-j", "ACCEPT"},
-		{"sudo", "iptables", "-I", "FORWARD", "-i", bridge.Name, "-p", "udp", "-j", "ACCEPT"},
-		{"sudo", "iptables", "-I", "FORWARD", "-i", bridge.Name, "-p", "tcp", "-j", "ACCEPT"},
-		{"sudo", "iptables", "-I", "FORWARD", "-i", bridge.Name, "-p", "icmp", "-j", "ACCEPT"},
-		{"sudo", "iptables", "-I", "FORWARD", "-o", bridge.Name, "-p", "icmp", "-j", "ACCEPT"},
-		{"sudo", "iptables", "-I", "FORWARD", "1", "-i", bridge.Name, "-o", bridge.Name, "-j", "ACCEPT"},
-		// Enable masquerading for outgoing traffic from the bridge subnet
-		{"sudo", "iptables", "-t", "nat", "-A", "POSTROUTING", "-s", bridgeSubnet, "!", "-d", bridgeSubnet, "-j", "MASQUERADE"},
+	// Enable masquerading for outgoing traffic from the bridge subnet
+	if err := ignoreExists(m.firewall.AppendRule(masqueradeRule(subnet))); err != nil {
+		return fmt.Errorf("failed to set up masquerading: %w", err)
 	}
 
-	for _, cmd := range commands {
-		if err := exec.Command(cmd[0], cmd[1:]...).Run(); err != nil {
-			log.Printf("iptables command failed (might already exist): %v", err)
-		}
+	return nil
+}
+
+func bridgeRules(bridge *Bridge) []Rule {
+	filter := func(chain string, spec ...string) Rule {
+		return Rule{Table: "filter", Chain: chain, Spec: spec}
+	}
+
+	return []Rule{
+		filter("INPUT", "-i", bridge.Name, "-p", "udp", "-j", "ACCEPT"),
+		filter("INPUT", "-i", bridge.Name, "-p", "tcp", "-j", "ACCEPT"),
+		filter("INPUT", "-i", bridge.Name, "-p", "icmp", "-j", "ACCEPT"),
+		filter("FORWARD", "-i", bridge.Name, "-p", "udp", "-j", "ACCEPT"),
+		filter("FORWARD", "-i", bridge.Name, "-p", "tcp", "-j", "ACCEPT"),
+		filter("FORWARD", "-i", bridge.Name, "-p", "icmp", "-j", "ACCEPT"),
+		filter("FORWARD", "-o", bridge.Name, "-p", "icmp", "-j", "ACCEPT"),
+		filter("FORWARD", "-i", bridge.Name, "-o", bridge.Name, "-j", "ACCEPT"),
+	}
+}
+
+func masqueradeRule(subnet *net.IPNet) Rule {
+	return Rule{
+		Table: "nat",
+		Chain: "POSTROUTING",
+		Spec:  []string{"-s", subnet.String(), "!", "-d", subnet.String(), "-j", "MASQUERADE"},
 	}
 }
 
-func getBridgeSubnet(bridgeIP string) string {
-	// Extract first 3 octets and add .0/24
-	// e.g., "192.168.100.1" -> "192.168.100.0/24"
-	parts := strings.Split(bridgeIP, ".")
-	if len(parts) >= 3 {
-		return fmt.Sprintf("%s.%s.%s.0/24", parts[0], parts[1], parts[2])
+func enableIPForwarding() error {
+	if err := os.WriteFile(ipForwardPath, []byte("1\n"), 0644); err != nil {
+		return fmt.Errorf("failed to enable IP forwarding: %v", err)
 	}
-	return "192.168.0.0/24" // fallback
+	return nil
 }
 
-func Cleanup(numVMs int) error {
+// parseBridgeIP parses a bridge IP in CIDR notation, e.g. "10.0.0.1/16"
+// gives 10.0.0.1 and 10.0.0.0/16. A bridge IP without a prefix length is
+// taken to be a /24, as it always was.
+func parseBridgeIP(bridgeIP string) (net.IP, *net.IPNet, error) {
+	if !strings.Contains(bridgeIP, "/") {
+		bridgeIP += "/24"
+	}
+
+	ip, subnet, err := net.ParseCIDR(bridgeIP)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid bridge IP %q: %v", bridgeIP, err)
+	}
+	return ip, subnet, nil
+}
+
+// Cleanup removes every link, addr, route and firewall rule the runner
+// created, newest first, and nothing else, and puts back the routes it
+// replaced. Resources that fail to delete stay recorded so a later Cleanup
+// can retry them.
+func (m *Manager) Cleanup() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	log.Printf("Cleaning up networking...")
-	for i := 0; i < numVMs; i++ {
-		tapName := fmt.Sprintf("tap%d", i)
-		cmd := exec.Command("sudo", "ip", "link", "delete", tapName)
-		if err := cmd.Run(); err != nil {
-			log.Printf("failed to delete tap interface %s: %v, might already be deleted", tapName, err)
+	resources := m.state.list()
+	var errs []error
+	for i := len(resources) - 1; i >= 0; i-- {
+		if err := m.deleteResource(resources[i]); err != nil {
+			log.Printf("failed to delete %s: %v", resources[i].key(), err)
+			errs = append(errs, err)
 		}
 	}
-
-	cmd := exec.Command("sudo", "ip", "link", "delete", "br0")
-	if err := cmd.Run(); err != nil {
-		log.Printf("failed to delete bridge: %v, might already be deleted", err)
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to delete %d of %d network resources: %w", len(errs), len(resources), errors.Join(errs...))
 	}
 
-	log.Println("Networking cleanup completed")
+	m.bridge = NewBridge(m.links, m.opts.Bridge, "")
+	if err := m.routes.reset(); err != nil {
+		return err
+	}
+	log.Printf("Networking cleanup completed, deleted %d resources", len(resources))
 	return nil
 }
+
+// Resources returns what the runner has created on the host, in creation order.
+func (m *Manager) Resources() []Resource {
+	return m.state.list()
+}
+
+// Taps returns the names of the taps the runner has created.
+func (m *Manager) Taps() []string {
+	var taps []string
+	for _, r := range m.state.list() {
+		if r.Type == ResourceLink && strings.HasPrefix(r.Link, m.opts.TapPrefix) {
+			taps = append(taps, r.Link)
+		}
+	}
+	return taps
+}
+
+func (m *Manager) SetupCrossNodeRoute(route *CrossNodeRoute) error {
+	return m.routes.Setup(m.bridgeName(), route)
+}
+
+func (m *Manager) TeardownCrossNodeRoute(remoteSubnet string) error {
+	return m.routes.Teardown(m.bridgeName(), remoteSubnet)
+}
+
+func (m *Manager) ListCrossNodeRoutes() []CrossNodeRoute {
+	return m.routes.List()
+}
+
+func (m *Manager) bridgeName() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.bridge.Name
+}
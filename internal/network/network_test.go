@@ -0,0 +1,109 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestManager(t *testing.T, links *FakeLinks, firewall *FakeFirewall, stateDir string) *Manager {
+	t.Helper()
+
+	m, err := NewManager(links, firewall, Options{StateDir: stateDir, Bridge: "br0", TapPrefix: "tap"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.enableForwarding = func() error { return nil }
+	return m
+}
+
+func TestSetup(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	m := newTestManager(t, links, firewall, t.TempDir())
+
+	bridge, err := m.Setup(2, "10.0.0.1/16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"tap0", "tap1"}; !reflect.DeepEqual(bridge.Taps, want) {
+		t.Errorf("got taps %v, want %v", bridge.Taps, want)
+	}
+
+	br, _ := links.Link("br0")
+	if br.Type != "bridge" || !br.Up || !reflect.DeepEqual(br.Addrs, []string{"10.0.0.1/16"}) {
+		t.Errorf("got bridge %+v", br)
+	}
+	for _, name := range []string{"tap0", "tap1"} {
+		if tap, _ := links.Link(name); tap.Type != "tap" || !tap.Up || tap.Master != "br0" {
+			t.Errorf("got %s %+v", name, tap)
+		}
+	}
+
+	rules := firewall.Rules()
+	if n := len(bridgeRules(bridge)) + 1; len(rules) != n {
+		t.Fatalf("got %d rules, want %d", len(rules), n)
+	}
+	_, subnet, _ := parseBridgeIP("10.0.0.1/16")
+	if got, want := rules[len(rules)-1].String(), markRule(masqueradeRule(subnet)).String(); got != want {
+		t.Errorf("got last rule %s, want %s", got, want)
+	}
+}
+
+func TestSetupTwice(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	m := newTestManager(t, links, firewall, t.TempDir())
+
+	if _, err := m.Setup(2, "10.0.0.1/16"); err != nil {
+		t.Fatal(err)
+	}
+	linkOps, ruleOps := len(links.Ops()), len(firewall.Ops())
+	resources := m.Resources()
+
+	// a restarted runner sets up the same network
+	m = newTestManager(t, links, firewall, m.opts.StateDir)
+	if _, err := m.Setup(2, "10.0.0.1/16"); err != nil {
+		t.Fatal(err)
+	}
+	for _, op := range links.Ops()[linkOps:] {
+		if op != "set up br0" && op != "set up tap0" && op != "set up tap1" &&
+			op != "set master tap0" && op != "set master tap1" {
+			t.Errorf("second Setup changed the links: %s", op)
+		}
+	}
+	if ops := firewall.Ops()[ruleOps:]; len(ops) > 0 {
+		t.Errorf("second Setup changed the rules: %v", ops)
+	}
+	if got := m.Resources(); !reflect.DeepEqual(got, resources) {
+		t.Errorf("got resources %v, want %v", got, resources)
+	}
+}
+
+func TestParseBridgeIP(t *testing.T) {
+	tests := []struct {
+		bridgeIP string
+		ip       string
+		subnet   string
+		wantErr  bool
+	}{
+		{bridgeIP: "10.0.0.1/16", ip: "10.0.0.1", subnet: "10.0.0.0/16"},
+		{bridgeIP: "192.168.100.1", ip: "192.168.100.1", subnet: "192.168.100.0/24"},
+		{bridgeIP: "192.168.100.300", wantErr: true},
+		{bridgeIP: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		ip, subnet, err := parseBridgeIP(tt.bridgeIP)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseBridgeIP(%q) = %v, %v, want an error", tt.bridgeIP, ip, subnet)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseBridgeIP(%q): %v", tt.bridgeIP, err)
+			continue
+		}
+		if ip.String() != tt.ip || subnet.String() != tt.subnet {
+			t.Errorf("parseBridgeIP(%q) = %v, %v, want %s, %s", tt.bridgeIP, ip, subnet, tt.ip, tt.subnet)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package network
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sync"
+)
+
+type RouteMode string
+
+const (
+	// RouteModeStatic routes the remote subnet via the remote node's host IP.
+	// The hosts must share an L2 network or have routes to each other.
+	RouteModeStatic RouteMode = "static"
+	// RouteModeVXLAN tunnels to the remote node with a VXLAN device attached
+	// to the bridge, so guests on both nodes share one L2 segment.
+	RouteModeVXLAN RouteMode = "vxlan"
+
+	defaultVXLANPort = 4789
+	// 50 bytes of VXLAN encapsulation on a 1500 byte underlay
+	vxlanMTU = 1450
+)
+
+// CrossNodeRoute connects the guests on this node to the guests of another runner node.
+type CrossNodeRoute struct {
+	RemoteSubnet  string    `json:"remoteSubnet"`
+	RemoteNodeIP  string    `json:"remoteNodeIP"`
+	LocalBridgeIP string    `json:"localBridgeIP"`
+	LocalSubnet   string    `json:"localSubnet"`
+	Mode          RouteMode `json:"mode"`
+	VNI           uint32    `json:"vni,omitempty"`
+	Port          int       `json:"port,omitempty"`
+	LocalNodeIP   string    `json:"localNodeIP,omitempty"`
+	Device        string    `json:"device,omitempty"` // the VXLAN device in vxlan mode
+}
+
+// CrossNodeRoutes sets up and tears down the routes of this node. The routes
+// are kept in the network state, keyed by remote subnet, so a restarted
+// runner can still list and tear down the routes of the previous one.
+type CrossNodeRoutes struct {
+	links    Links
+	firewall Firewall
+	state    *resourceState
+
+	mu sync.Mutex // serializes Setup and Teardown
+}
+
+func newCrossNodeRoutes(links Links, firewall Firewall, state *resourceState) *CrossNodeRoutes {
+	return &CrossNodeRoutes{
+		links:    links,
+		firewall: firewall,
+		state:    state,
+	}
+}
+
+func (r *CrossNodeRoutes) Setup(bridgeName string, route *CrossNodeRoute) error {
+	_, remoteNet, err := net.ParseCIDR(route.RemoteSubnet)
+	if err != nil {
+		return fmt.Errorf("invalid remote subnet %q: %v", route.RemoteSubnet, err)
+	}
+	route.RemoteSubnet = remoteNet.String()
+
+	remoteNodeIP := net.ParseIP(route.RemoteNodeIP)
+	if remoteNodeIP == nil {
+		return fmt.Errorf("invalid remote node IP %q", route.RemoteNodeIP)
+	}
+	_, localNet, err := parseBridgeIP(route.LocalBridgeIP)
+	if err != nil {
+		return err
+	}
+	route.LocalSubnet = localNet.String()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.state.route(route.RemoteSubnet); ok {
+		return fmt.Errorf("route to %s already exists", route.RemoteSubnet)
+	}
+
+	switch route.Mode {
+	case RouteModeStatic:
+		err = r.links.ReplaceRoute(Route{Dst: remoteNet, Gateway: remoteNodeIP})
+	case RouteModeVXLAN:
+		err = r.setupVXLANRoute(bridgeName, remoteNet, route)
+	default:
+		err = fmt.Errorf("unknown route mode %q", route.Mode)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range crossNodeRules(route) {
+		if err := ignoreExists(r.firewall.InsertRule(rule)); err != nil {
+			r.deleteRules(route)
+			r.teardownRoute(route)
+			return err
+		}
+	}
+
+	if err := r.state.setRoute(*route); err != nil {
+		r.deleteRules(route)
+		r.teardownRoute(route)
+		return err
+	}
+	log.Printf("Set up %s route to %s via %s", route.Mode, route.RemoteSubnet, route.RemoteNodeIP)
+	return nil
+}
+
+func (r *CrossNodeRoutes) Teardown(bridgeName, remoteSubnet string) error {
+	if _, remoteNet, err := net.ParseCIDR(remoteSubnet); err == nil {
+		remoteSubnet = remoteNet.String()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	route, ok := r.state.route(remoteSubnet)
+	if !ok {
+		return fmt.Errorf("route to %s not found", remoteSubnet)
+	}
+
+	r.deleteRules(&route)
+	if err := r.teardownRoute(&route); err != nil {
+		return err
+	}
+
+	if err := r.state.removeRoute(remoteSubnet); err != nil {
+		return err
+	}
+	log.Printf("Tore down %s route to %s", route.Mode, remoteSubnet)
+	return nil
+}
+
+// reset forgets all routes, after Cleanup removed them from the host.
+func (r *CrossNodeRoutes) reset() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state.removeRoutes()
+}
+
+// List returns the routes ordered by remote subnet.
+func (r *CrossNodeRoutes) List() []CrossNodeRoute {
+	return r.state.routeList()
+}
+
+func (r *CrossNodeRoutes) setupVXLANRoute(bridgeName string, remoteNet *net.IPNet, route *CrossNodeRoute) error {
+	if route.VNI == 0 {
+		return fmt.Errorf("vxlan mode requires a VNI")
+	}
+	if route.Port == 0 {
+		route.Port = defaultVXLANPort
+	}
+
+	vxlan := VXLAN{VNI: route.VNI, Remote: net.ParseIP(route.RemoteNodeIP), Port: route.Port}
+	if route.LocalNodeIP != "" {
+		vxlan.Local = net.ParseIP(route.LocalNodeIP)
+		if vxlan.Local == nil {
+			return fmt.Errorf("invalid local node IP %q", route.LocalNodeIP)
+		}
+	}
+
+	device := fmt.Sprintf("vxlan%d", route.VNI)
+	for _, other := range r.state.routeList() {
+		if other.Device == device {
+			return fmt.Errorf("VNI %d is already used by the route to %s", route.VNI, other.RemoteSubnet)
+		}
+	}
+	if err := r.links.AddVXLAN(device, vxlan); err != nil {
+		return err
+	}
+	route.Device = device
+
+	steps := []func() error{
+		func() error { return r.links.SetMTU(device, vxlanMTU) },
+		func() error { return r.links.SetMaster(device, bridgeName) },
+		func() error { return r.links.SetUp(device) },
+		// the remote guests answer ARP on the shared segment
+		func() error { return r.links.ReplaceRoute(Route{Dst: remoteNet, Device: bridgeName}) },
+	}
+
+	for _, step := range steps {
+		if err := step(); err != nil {
+			r.teardownRoute(route)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *CrossNodeRoutes) teardownRoute(route *CrossNodeRoute) error {
+	_, remoteNet, _ := net.ParseCIDR(route.RemoteSubnet)
+	if err := ignoreNotFound(r.links.DeleteRoute(remoteNet)); err != nil {
+		log.Printf("failed to delete route to %s: %v", route.RemoteSubnet, err)
+	}
+
+	if route.Device != "" {
+		if err := ignoreNotFound(r.links.DeleteLink(route.Device)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *CrossNodeRoutes) deleteRules(route *CrossNodeRoute) {
+	for _, rule := range crossNodeRules(route) {
+		if err := ignoreNotFound(r.firewall.DeleteRule(rule)); err != nil {
+			log.Printf("failed to remove firewall rule for %s: %v", route.RemoteSubnet, err)
+		}
+	}
+}
+
+// crossNodeRules let traffic between the two subnets through and keep it from
+// being masqueraded, so guests see each other's real addresses.
+func crossNodeRules(route *CrossNodeRoute) []Rule {
+	return []Rule{
+		{Table: "filter", Chain: "FORWARD", Spec: []string{"-s", route.LocalSubnet, "-d", route.RemoteSubnet, "-j", "ACCEPT"}},
+		{Table: "filter", Chain: "FORWARD", Spec: []string{"-s", route.RemoteSubnet, "-d", route.LocalSubnet, "-j", "ACCEPT"}},
+		{Table: "nat", Chain: "POSTROUTING", Spec: []string{"-s", route.LocalSubnet, "-d", route.RemoteSubnet, "-j", "RETURN"}},
+	}
+}
@@ -0,0 +1,229 @@
+package network
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetupCrossNodeRoute(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   CrossNodeRoute
+		wantErr string
+		// check runs against the fake links once the route is set up
+		check func(t *testing.T, links *FakeLinks)
+	}{
+		{
+			name:  "static",
+			route: CrossNodeRoute{RemoteSubnet: "10.1.0.5/16", RemoteNodeIP: "192.168.1.20", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeStatic},
+			check: func(t *testing.T, links *FakeLinks) {
+				route, ok := links.Routes()["10.1.0.0/16"]
+				if !ok || route.Gateway.String() != "192.168.1.20" || route.Device != "" {
+					t.Errorf("got route %+v", route)
+				}
+			},
+		},
+		{
+			name:  "vxlan",
+			route: CrossNodeRoute{RemoteSubnet: "10.1.0.0/16", RemoteNodeIP: "192.168.1.20", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeVXLAN, VNI: 7, LocalNodeIP: "192.168.1.10"},
+			check: func(t *testing.T, links *FakeLinks) {
+				vxlan, ok := links.Link("vxlan7")
+				if !ok || vxlan.Type != "vxlan" || vxlan.Master != "br0" || !vxlan.Up || vxlan.MTU != vxlanMTU {
+					t.Errorf("got vxlan7 %+v", vxlan)
+				}
+				if vxlan.VXLAN.VNI != 7 || vxlan.VXLAN.Port != defaultVXLANPort ||
+					vxlan.VXLAN.Remote.String() != "192.168.1.20" || vxlan.VXLAN.Local.String() != "192.168.1.10" {
+					t.Errorf("got vxlan settings %+v", vxlan.VXLAN)
+				}
+				route, ok := links.Routes()["10.1.0.0/16"]
+				if !ok || route.Device != "br0" || route.Gateway != nil {
+					t.Errorf("got route %+v", route)
+				}
+			},
+		},
+		{
+			name:    "vxlan without VNI",
+			route:   CrossNodeRoute{RemoteSubnet: "10.1.0.0/16", RemoteNodeIP: "192.168.1.20", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeVXLAN},
+			wantErr: "requires a VNI",
+		},
+		{
+			name:    "invalid remote subnet",
+			route:   CrossNodeRoute{RemoteSubnet: "10.1.0.0", RemoteNodeIP: "192.168.1.20", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeStatic},
+			wantErr: "invalid remote subnet",
+		},
+		{
+			name:    "invalid remote node IP",
+			route:   CrossNodeRoute{RemoteSubnet: "10.1.0.0/16", RemoteNodeIP: "node2", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeStatic},
+			wantErr: "invalid remote node IP",
+		},
+		{
+			name:    "invalid local node IP",
+			route:   CrossNodeRoute{RemoteSubnet: "10.1.0.0/16", RemoteNodeIP: "192.168.1.20", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeVXLAN, VNI: 7, LocalNodeIP: "node1"},
+			wantErr: "invalid local node IP",
+		},
+		{
+			name:    "unknown mode",
+			route:   CrossNodeRoute{RemoteSubnet: "10.1.0.0/16", RemoteNodeIP: "192.168.1.20", LocalBridgeIP: "10.0.0.1/16", Mode: "gre"},
+			wantErr: "unknown route mode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, firewall := NewFakeLinks(), NewFakeFirewall()
+			m := newTestManager(t, links, firewall, t.TempDir())
+			if _, err := m.Setup(1, "10.0.0.1/16"); err != nil {
+				t.Fatal(err)
+			}
+			linkCount, ruleCount := len(links.Links()), len(firewall.Rules())
+
+			route := tt.route
+			err := m.SetupCrossNodeRoute(&route)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %v, want an error containing %q", err, tt.wantErr)
+				}
+				if len(links.Links()) != linkCount || len(firewall.Rules()) != ruleCount || len(links.Routes()) != 0 {
+					t.Errorf("a failed setup left links %v, rules %v, routes %v", links.Links(), firewall.Rules(), links.Routes())
+				}
+				if got := m.ListCrossNodeRoutes(); len(got) != 0 {
+					t.Errorf("got routes %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, links)
+
+			for _, rule := range crossNodeRules(&route) {
+				found := false
+				for _, existing := range firewall.Rules() {
+					found = found || existing.String() == markRule(rule).String()
+				}
+				if !found {
+					t.Errorf("missing rule %s", rule)
+				}
+			}
+			if got := m.ListCrossNodeRoutes(); !reflect.DeepEqual(got, []CrossNodeRoute{route}) {
+				t.Errorf("got routes %+v, want %+v", got, route)
+			}
+			if err := m.SetupCrossNodeRoute(&CrossNodeRoute{RemoteSubnet: route.RemoteSubnet, RemoteNodeIP: "192.168.1.30", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeStatic}); err == nil {
+				t.Error("set up a second route to the same subnet")
+			}
+
+			if err := m.TeardownCrossNodeRoute(tt.route.RemoteSubnet); err != nil {
+				t.Fatal(err)
+			}
+			if len(links.Links()) != linkCount || len(firewall.Rules()) != ruleCount || len(links.Routes()) != 0 {
+				t.Errorf("teardown left links %v, rules %v, routes %v", links.Links(), firewall.Rules(), links.Routes())
+			}
+			if got := m.ListCrossNodeRoutes(); len(got) != 0 {
+				t.Errorf("got routes %v after teardown", got)
+			}
+			if err := m.TeardownCrossNodeRoute(tt.route.RemoteSubnet); err == nil {
+				t.Error("tore down a route twice")
+			}
+		})
+	}
+}
+
+func TestSetupCrossNodeRouteRollsBack(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	m := newTestManager(t, links, firewall, t.TempDir())
+	if _, err := m.Setup(1, "10.0.0.1/16"); err != nil {
+		t.Fatal(err)
+	}
+	resources := m.Resources()
+
+	down := errors.New("link down")
+	links.Fail("set up", "vxlan7", down)
+	route := &CrossNodeRoute{RemoteSubnet: "10.1.0.0/16", RemoteNodeIP: "192.168.1.20", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeVXLAN, VNI: 7}
+	if err := m.SetupCrossNodeRoute(route); !errors.Is(err, down) {
+		t.Fatalf("got %v, want %v", err, down)
+	}
+	if _, ok := links.Link("vxlan7"); ok {
+		t.Error("a failed setup left vxlan7")
+	}
+	if got := m.Resources(); !reflect.DeepEqual(got, resources) {
+		t.Errorf("got resources %v, want %v", got, resources)
+	}
+}
+
+func TestCrossNodeRoutesAfterRestart(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	stateDir := t.TempDir()
+	m := newTestManager(t, links, firewall, stateDir)
+	if _, err := m.Setup(1, "10.0.0.1/16"); err != nil {
+		t.Fatal(err)
+	}
+	route := &CrossNodeRoute{RemoteSubnet: "10.1.0.0/16", RemoteNodeIP: "192.168.1.20", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeVXLAN, VNI: 7}
+	if err := m.SetupCrossNodeRoute(route); err != nil {
+		t.Fatal(err)
+	}
+
+	m = newTestManager(t, links, firewall, stateDir)
+	if got := m.ListCrossNodeRoutes(); !reflect.DeepEqual(got, []CrossNodeRoute{*route}) {
+		t.Fatalf("got routes %+v after a restart, want %+v", got, *route)
+	}
+	if err := m.TeardownCrossNodeRoute(route.RemoteSubnet); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := links.Link("vxlan7"); ok {
+		t.Error("teardown after a restart left vxlan7")
+	}
+
+	// Cleanup forgets the routes it removed
+	if err := m.SetupCrossNodeRoute(route); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	m = newTestManager(t, links, firewall, stateDir)
+	if got := m.ListCrossNodeRoutes(); len(got) != 0 {
+		t.Errorf("got routes %+v after Cleanup", got)
+	}
+}
+
+func TestSetupCrossNodeRouteExistingVXLAN(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	stateDir := t.TempDir()
+	m := newTestManager(t, links, firewall, stateDir)
+	if _, err := m.Setup(1, "10.0.0.1/16"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a device left by a runner that crashed while setting up a route
+	if err := m.links.AddVXLAN("vxlan7", VXLAN{VNI: 7, Remote: []byte{192, 168, 1, 99}}); err != nil {
+		t.Fatal(err)
+	}
+	m = newTestManager(t, links, firewall, stateDir)
+	route := &CrossNodeRoute{RemoteSubnet: "10.1.0.0/16", RemoteNodeIP: "192.168.1.20", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeVXLAN, VNI: 7}
+	if err := m.SetupCrossNodeRoute(route); err != nil {
+		t.Fatal(err)
+	}
+	if vxlan, _ := links.Link("vxlan7"); vxlan.VXLAN.Remote.String() != "192.168.1.20" || vxlan.Master != "br0" {
+		t.Errorf("got vxlan7 %+v, want it replaced", vxlan)
+	}
+
+	// a second route cannot take the device of the first
+	other := &CrossNodeRoute{RemoteSubnet: "10.2.0.0/16", RemoteNodeIP: "192.168.1.30", LocalBridgeIP: "10.0.0.1/16", Mode: RouteModeVXLAN, VNI: 7}
+	if err := m.SetupCrossNodeRoute(other); err == nil || !strings.Contains(err.Error(), "already used") {
+		t.Errorf("got %v, want the VNI to be in use", err)
+	}
+
+	// a device that is not the runner's is left alone
+	if err := links.AddVXLAN("vxlan8", VXLAN{VNI: 8}); err != nil {
+		t.Fatal(err)
+	}
+	other.VNI = 8
+	if err := m.SetupCrossNodeRoute(other); !errors.Is(err, ErrExists) {
+		t.Errorf("got %v, want %v", err, ErrExists)
+	}
+	if _, ok := links.Link("vxlan8"); !ok {
+		t.Error("deleted a foreign vxlan8")
+	}
+}
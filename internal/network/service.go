@@ -13,33 +13,153 @@ type Service interface {
 
 type serviceImpl struct {
 	proto.UnimplementedNetworkServiceServer
-	bridge *Bridge
-	log    *zap.Logger
+	manager *Manager
+	log     *zap.Logger
 }
 
-func NewService(log *zap.Logger) Service {
+func NewService(manager *Manager, log *zap.Logger) Service {
 	return &serviceImpl{
-		bridge: NewBridge("", ""),
-		log:    log,
+		manager: manager,
+		log:     log,
 	}
 }
 
 func (s *serviceImpl) Setup(ctx context.Context, req *proto.SetupNetworkRequest) (*proto.SetupNetworkResponse, error) {
-	bridge, err := Setup(int(req.NumVMs), req.BridgeIP)
+	_, err := s.manager.Setup(int(req.NumVMs), req.BridgeIP)
 	if err != nil {
 		return nil, err
 	}
 
-	s.bridge = bridge
-
 	return &proto.SetupNetworkResponse{}, nil
 }
 
 func (s *serviceImpl) Cleanup(ctx context.Context, req *proto.CleanupNetworkRequest) (*proto.CleanupNetworkResponse, error) {
-	err := Cleanup(int(req.NumVMs))
+	err := s.manager.Cleanup()
 	if err != nil {
 		return nil, err
 	}
 
 	return &proto.CleanupNetworkResponse{}, nil
 }
+
+func (s *serviceImpl) SetupCrossNodeRoute(ctx context.Context, req *proto.SetupCrossNodeRouteRequest) (*proto.SetupCrossNodeRouteResponse, error) {
+	mode := RouteModeStatic
+	if req.Mode == proto.RouteMode_ROUTE_MODE_VXLAN {
+		mode = RouteModeVXLAN
+	}
+
+	route := &CrossNodeRoute{
+		RemoteSubnet:  req.RemoteSubnet,
+		RemoteNodeIP:  req.RemoteNodeIP,
+		LocalBridgeIP: req.LocalBridgeIP,
+		Mode:          mode,
+		VNI:           req.Vni,
+		Port:          int(req.Port),
+		LocalNodeIP:   req.LocalNodeIP,
+	}
+	if err := s.manager.SetupCrossNodeRoute(route); err != nil {
+		return nil, err
+	}
+
+	return &proto.SetupCrossNodeRouteResponse{Route: routeToProto(route)}, nil
+}
+
+func (s *serviceImpl) TeardownCrossNodeRoute(ctx context.Context, req *proto.TeardownCrossNodeRouteRequest) (*proto.TeardownCrossNodeRouteResponse, error) {
+	if err := s.manager.TeardownCrossNodeRoute(req.RemoteSubnet); err != nil {
+		return nil, err
+	}
+
+	return &proto.TeardownCrossNodeRouteResponse{}, nil
+}
+
+func (s *serviceImpl) ListCrossNodeRoutes(ctx context.Context, req *proto.ListCrossNodeRoutesRequest) (*proto.ListCrossNodeRoutesResponse, error) {
+	routes := s.manager.ListCrossNodeRoutes()
+
+	res := &proto.ListCrossNodeRoutesResponse{Routes: make([]*proto.CrossNodeRoute, 0, len(routes))}
+	for i := range routes {
+		res.Routes = append(res.Routes, routeToProto(&routes[i]))
+	}
+
+	return res, nil
+}
+
+func (s *serviceImpl) ShapeTraffic(ctx context.Context, req *proto.ShapeTrafficRequest) (*proto.ShapeTrafficResponse, error) {
+	shape := TrafficShape{IP: req.Ip, TapName: req.TapName}
+	if n := req.Netem; n != nil {
+		shape.Netem = &Netem{
+			DelayMs:          n.DelayMs,
+			JitterMs:         n.JitterMs,
+			LossPercent:      n.LossPercent,
+			DuplicatePercent: n.DuplicatePercent,
+			ReorderPercent:   n.ReorderPercent,
+			CorruptPercent:   n.CorruptPercent,
+			Limit:            n.Limit,
+		}
+	}
+	if t := req.Tbf; t != nil {
+		shape.Tbf = &Tbf{RateKbit: t.RateKbit, BurstBytes: t.BurstBytes, LatencyMs: t.LatencyMs}
+	}
+
+	applied, err := s.manager.ShapeTraffic(shape)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ShapeTrafficResponse{Shape: shapeToProto(applied)}, nil
+}
+
+func (s *serviceImpl) ClearTrafficShaping(ctx context.Context, req *proto.ClearTrafficShapingRequest) (*proto.ClearTrafficShapingResponse, error) {
+	if err := s.manager.ClearTrafficShaping(req.Ip, req.TapName); err != nil {
+		return nil, err
+	}
+
+	return &proto.ClearTrafficShapingResponse{}, nil
+}
+
+func (s *serviceImpl) ListTrafficShaping(ctx context.Context, req *proto.ListTrafficShapingRequest) (*proto.ListTrafficShapingResponse, error) {
+	shapes := s.manager.ListTrafficShaping()
+
+	res := &proto.ListTrafficShapingResponse{Shapes: make([]*proto.TrafficShape, 0, len(shapes))}
+	for i := range shapes {
+		res.Shapes = append(res.Shapes, shapeToProto(&shapes[i]))
+	}
+
+	return res, nil
+}
+
+func shapeToProto(shape *TrafficShape) *proto.TrafficShape {
+	res := &proto.TrafficShape{Ip: shape.IP, TapName: shape.TapName}
+	if n := shape.Netem; n != nil {
+		res.Netem = &proto.Netem{
+			DelayMs:          n.DelayMs,
+			JitterMs:         n.JitterMs,
+			LossPercent:      n.LossPercent,
+			DuplicatePercent: n.DuplicatePercent,
+			ReorderPercent:   n.ReorderPercent,
+			CorruptPercent:   n.CorruptPercent,
+			Limit:            n.Limit,
+		}
+	}
+	if t := shape.Tbf; t != nil {
+		res.Tbf = &proto.Tbf{RateKbit: t.RateKbit, BurstBytes: t.BurstBytes, LatencyMs: t.LatencyMs}
+	}
+	return res
+}
+
+func routeToProto(route *CrossNodeRoute) *proto.CrossNodeRoute {
+	mode := proto.RouteMode_ROUTE_MODE_STATIC
+	if route.Mode == RouteModeVXLAN {
+		mode = proto.RouteMode_ROUTE_MODE_VXLAN
+	}
+
+	return &proto.CrossNodeRoute{
+		RemoteSubnet:  route.RemoteSubnet,
+		RemoteNodeIP:  route.RemoteNodeIP,
+		LocalBridgeIP: route.LocalBridgeIP,
+		Mode:          mode,
+		Vni:           route.VNI,
+		Port:          int32(route.Port),
+		LocalNodeIP:   route.LocalNodeIP,
+		Device:        route.Device,
+	}
+}
@@ -0,0 +1,157 @@
+package network
+
+import (
+	"fmt"
+	"log"
+)
+
+const (
+	defaultTbfBurstBytes = 32 * 1024
+	defaultTbfLatencyMs  = 50
+)
+
+// Netem emulates a WAN: delay with jitter, loss, duplication, reordering
+// and corruption. Percentages are 0 to 100.
+type Netem struct {
+	DelayMs          uint32  `json:"delayMs,omitempty"`
+	JitterMs         uint32  `json:"jitterMs,omitempty"`
+	LossPercent      float32 `json:"lossPercent,omitempty"`
+	DuplicatePercent float32 `json:"duplicatePercent,omitempty"`
+	ReorderPercent   float32 `json:"reorderPercent,omitempty"` // requires a delay
+	CorruptPercent   float32 `json:"corruptPercent,omitempty"`
+	Limit            uint32  `json:"limit,omitempty"` // packets, default 1000
+}
+
+// Tbf caps the bandwidth with a token bucket filter.
+type Tbf struct {
+	RateKbit   uint64 `json:"rateKbit"`
+	BurstBytes uint32 `json:"burstBytes,omitempty"` // default 32KiB
+	LatencyMs  uint32 `json:"latencyMs,omitempty"`  // how long packets may queue, default 50ms
+}
+
+// TrafficShape is the shaping of a VM's tap. It applies to the tap's egress,
+// i.e. to traffic towards the guest; use the firecracker tx rate limiter to
+// cap what the guest sends.
+type TrafficShape struct {
+	IP      string `json:"ip,omitempty"`
+	TapName string `json:"tapName"`
+	Netem   *Netem `json:"netem,omitempty"`
+	Tbf     *Tbf   `json:"tbf,omitempty"`
+}
+
+func (s *TrafficShape) withDefaults() {
+	if s.Tbf == nil {
+		return
+	}
+	if s.Tbf.BurstBytes == 0 {
+		s.Tbf.BurstBytes = defaultTbfBurstBytes
+	}
+	if s.Tbf.LatencyMs == 0 {
+		s.Tbf.LatencyMs = defaultTbfLatencyMs
+	}
+}
+
+func (s *TrafficShape) validate() error {
+	if s.Netem == nil && s.Tbf == nil {
+		return fmt.Errorf("traffic shape needs netem or tbf settings")
+	}
+
+	if n := s.Netem; n != nil {
+		percents := map[string]float32{
+			"loss":      n.LossPercent,
+			"duplicate": n.DuplicatePercent,
+			"reorder":   n.ReorderPercent,
+			"corrupt":   n.CorruptPercent,
+		}
+		for name, p := range percents {
+			if p < 0 || p > 100 {
+				return fmt.Errorf("%s must be between 0 and 100 percent, got %v", name, p)
+			}
+		}
+		if n.JitterMs > 0 && n.DelayMs == 0 {
+			return fmt.Errorf("jitter requires a delay")
+		}
+		if n.ReorderPercent > 0 && n.DelayMs == 0 {
+			return fmt.Errorf("reordering requires a delay")
+		}
+	}
+
+	if s.Tbf != nil && s.Tbf.RateKbit == 0 {
+		return fmt.Errorf("tbf needs a rate")
+	}
+
+	return nil
+}
+
+// SetTapResolver lets ShapeTraffic find the tap of a VM by its IP.
+func (m *Manager) SetTapResolver(resolve func(ip string) (string, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resolveTap = resolve
+}
+
+// ShapeTraffic replaces the shaping of the tap of the VM with shape.IP, or of
+// shape.TapName if no IP is given.
+func (m *Manager) ShapeTraffic(shape TrafficShape) (*TrafficShape, error) {
+	shape.withDefaults()
+	if err := shape.validate(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tapName, err := m.tapName(shape.IP, shape.TapName)
+	if err != nil {
+		return nil, err
+	}
+	shape.TapName = tapName
+
+	if err := m.links.ReplaceQdiscs(tapName, shape.Netem, shape.Tbf); err != nil {
+		return nil, fmt.Errorf("failed to shape traffic of %s: %w", tapName, err)
+	}
+
+	if err := m.state.setShape(shape); err != nil {
+		return nil, err
+	}
+	log.Printf("Shaping traffic of %s: netem %+v, tbf %+v", tapName, shape.Netem, shape.Tbf)
+	return &shape, nil
+}
+
+func (m *Manager) ClearTrafficShaping(ip, tapName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tapName, err := m.tapName(ip, tapName)
+	if err != nil {
+		return err
+	}
+
+	if err := m.links.DeleteQdiscs(tapName); err != nil {
+		return fmt.Errorf("failed to clear traffic shaping of %s: %w", tapName, err)
+	}
+
+	log.Printf("Cleared traffic shaping of %s", tapName)
+	return nil
+}
+
+// ListTrafficShaping returns the shaping applied by this or an earlier run,
+// ordered by tap name.
+func (m *Manager) ListTrafficShaping() []TrafficShape {
+	return m.state.shapeList()
+}
+
+// tapName resolves the tap of a VM. The caller holds m.mu.
+func (m *Manager) tapName(ip, tapName string) (string, error) {
+	if ip == "" {
+		if tapName == "" {
+			return "", fmt.Errorf("either a VM IP or a tap name is required")
+		}
+		return tapName, nil
+	}
+
+	if m.resolveTap == nil {
+		return "", fmt.Errorf("cannot resolve the tap of VM %s", ip)
+	}
+	return m.resolveTap(ip)
+}
@@ -0,0 +1,121 @@
+package network
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTrafficShapeValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		shape   TrafficShape
+		wantErr string
+	}{
+		{name: "netem", shape: TrafficShape{Netem: &Netem{DelayMs: 50, JitterMs: 10, LossPercent: 1, ReorderPercent: 25}}},
+		{name: "tbf", shape: TrafficShape{Tbf: &Tbf{RateKbit: 1000}}},
+		{name: "both", shape: TrafficShape{Netem: &Netem{LossPercent: 100}, Tbf: &Tbf{RateKbit: 1000}}},
+		{name: "nothing", shape: TrafficShape{}, wantErr: "needs netem or tbf"},
+		{name: "negative loss", shape: TrafficShape{Netem: &Netem{LossPercent: -1}}, wantErr: "loss must be between"},
+		{name: "too much corruption", shape: TrafficShape{Netem: &Netem{CorruptPercent: 101}}, wantErr: "corrupt must be between"},
+		{name: "jitter without delay", shape: TrafficShape{Netem: &Netem{JitterMs: 10}}, wantErr: "jitter requires a delay"},
+		{name: "reordering without delay", shape: TrafficShape{Netem: &Netem{ReorderPercent: 10}}, wantErr: "reordering requires a delay"},
+		{name: "tbf without rate", shape: TrafficShape{Tbf: &Tbf{BurstBytes: 1024}}, wantErr: "tbf needs a rate"},
+	}
+
+	for _, tt := range tests {
+		err := tt.shape.validate()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestShapeTraffic(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	stateDir := t.TempDir()
+	m := newTestManager(t, links, firewall, stateDir)
+	if _, err := m.Setup(2, "10.0.0.1/24"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.ShapeTraffic(TrafficShape{IP: "10.0.0.2", Netem: &Netem{DelayMs: 10}}); err == nil {
+		t.Error("shaped a VM without a tap resolver")
+	}
+	m.SetTapResolver(func(ip string) (string, error) {
+		if ip == "10.0.0.2" {
+			return "tap0", nil
+		}
+		return "", fmt.Errorf("vm %s not found", ip)
+	})
+	if _, err := m.ShapeTraffic(TrafficShape{IP: "10.0.0.9", Netem: &Netem{DelayMs: 10}}); err == nil {
+		t.Error("shaped an unknown VM")
+	}
+	if _, err := m.ShapeTraffic(TrafficShape{Netem: &Netem{DelayMs: 10}}); err == nil {
+		t.Error("shaped without a VM or tap")
+	}
+
+	applied, err := m.ShapeTraffic(TrafficShape{IP: "10.0.0.2", Netem: &Netem{DelayMs: 100}, Tbf: &Tbf{RateKbit: 8000}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TrafficShape{
+		IP:      "10.0.0.2",
+		TapName: "tap0",
+		Netem:   &Netem{DelayMs: 100},
+		Tbf:     &Tbf{RateKbit: 8000, BurstBytes: defaultTbfBurstBytes, LatencyMs: defaultTbfLatencyMs},
+	}
+	if !reflect.DeepEqual(*applied, want) {
+		t.Errorf("got %+v, want %+v", *applied, want)
+	}
+	if tap, _ := links.Link("tap0"); !reflect.DeepEqual(tap.Netem, want.Netem) || !reflect.DeepEqual(tap.Tbf, want.Tbf) {
+		t.Errorf("got qdiscs %+v %+v on tap0", tap.Netem, tap.Tbf)
+	}
+
+	// replacing a shape drops what the new one leaves out
+	if _, err := m.ShapeTraffic(TrafficShape{TapName: "tap1", Netem: &Netem{LossPercent: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.ShapeTraffic(TrafficShape{TapName: "tap1", Tbf: &Tbf{RateKbit: 1000}}); err != nil {
+		t.Fatal(err)
+	}
+	if tap, _ := links.Link("tap1"); tap.Netem != nil || tap.Tbf == nil {
+		t.Errorf("got qdiscs %+v %+v on tap1", tap.Netem, tap.Tbf)
+	}
+
+	// shapes outlive the runner
+	m = newTestManager(t, links, firewall, stateDir)
+	shapes := m.ListTrafficShaping()
+	if len(shapes) != 2 || !reflect.DeepEqual(shapes[0], want) || shapes[1].TapName != "tap1" || shapes[1].Netem != nil {
+		t.Fatalf("got shapes %+v after a restart", shapes)
+	}
+
+	if err := m.ClearTrafficShaping("", "tap0"); err != nil {
+		t.Fatal(err)
+	}
+	if tap, _ := links.Link("tap0"); tap.Netem != nil || tap.Tbf != nil {
+		t.Errorf("got qdiscs %+v %+v on tap0 after clearing", tap.Netem, tap.Tbf)
+	}
+	if shapes := m.ListTrafficShaping(); len(shapes) != 1 || shapes[0].TapName != "tap1" {
+		t.Errorf("got shapes %+v after clearing tap0", shapes)
+	}
+
+	// the shaping goes with the tap
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if shapes := m.ListTrafficShaping(); len(shapes) != 0 {
+		t.Errorf("got shapes %+v after Cleanup", shapes)
+	}
+	m = newTestManager(t, links, firewall, stateDir)
+	if shapes := m.ListTrafficShaping(); len(shapes) != 0 {
+		t.Errorf("got shapes %+v after Cleanup and a restart", shapes)
+	}
+}
@@ -0,0 +1,485 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ruleComment marks every firewall rule the runner adds, so its rules are
+// never confused with identical rules added by someone else.
+const ruleComment = "firecracker-runner"
+
+const stateFile = "state.json"
+
+type ResourceType string
+
+const (
+	ResourceLink  ResourceType = "link"
+	ResourceAddr  ResourceType = "addr"
+	ResourceRoute ResourceType = "route"
+	ResourceRule  ResourceType = "rule"
+	ResourceQdisc ResourceType = "qdisc"
+)
+
+// Resource is something the runner created on the host.
+type Resource struct {
+	Type ResourceType `json:"type"`
+	Link string       `json:"link,omitempty"` // the link, or the link an addr or qdisc is on
+	Addr string       `json:"addr,omitempty"` // the addr, or the route destination
+	Rule *Rule        `json:"rule,omitempty"`
+	// Replaced is the route that a route replaced, which deleting the route
+	// puts back
+	Replaced *ReplacedRoute `json:"replaced,omitempty"`
+}
+
+// ReplacedRoute is a route that was there before the runner replaced it.
+type ReplacedRoute struct {
+	Gateway string `json:"gateway,omitempty"`
+	Device  string `json:"device,omitempty"`
+}
+
+func (r Resource) key() string {
+	if r.Rule != nil {
+		return fmt.Sprintf("%s %s", r.Type, r.Rule)
+	}
+	return fmt.Sprintf("%s %s %s", r.Type, r.Link, r.Addr)
+}
+
+// resourceState is the persisted list of created resources, in creation
+// order, and the cross-node routes and traffic shapes made of them.
+type resourceState struct {
+	path string
+
+	mu        sync.Mutex
+	resources []Resource
+	routes    map[string]CrossNodeRoute // by remote subnet
+	shapes    map[string]TrafficShape   // by tap name
+}
+
+// stateData is the content of the state file.
+type stateData struct {
+	Resources []Resource       `json:"resources"`
+	Routes    []CrossNodeRoute `json:"routes,omitempty"`
+	Shapes    []TrafficShape   `json:"shapes,omitempty"`
+}
+
+func loadResourceState(dir string) (*resourceState, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create network state directory: %v", err)
+	}
+
+	s := &resourceState{
+		path:   filepath.Join(dir, stateFile),
+		routes: make(map[string]CrossNodeRoute),
+		shapes: make(map[string]TrafficShape),
+	}
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read network state: %v", err)
+	}
+
+	var data stateData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		// earlier runners wrote just the list of resources
+		if json.Unmarshal(raw, &data.Resources) != nil {
+			return nil, fmt.Errorf("failed to parse network state %s: %v", s.path, err)
+		}
+	}
+	s.resources = data.Resources
+	for _, route := range data.Routes {
+		s.routes[route.RemoteSubnet] = route
+	}
+	for _, shape := range data.Shapes {
+		s.shapes[shape.TapName] = shape
+	}
+	return s, nil
+}
+
+func (s *resourceState) add(r Resource) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.resources {
+		if existing.key() == r.key() {
+			return nil
+		}
+	}
+
+	s.resources = append(s.resources, r)
+	return s.save()
+}
+
+// remove forgets every resource for which match returns true.
+func (s *resourceState) remove(match func(Resource) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.resources[:0]
+	for _, r := range s.resources {
+		if !match(r) {
+			kept = append(kept, r)
+		}
+	}
+	s.resources = kept
+	return s.save()
+}
+
+// find returns the resource with the key of r.
+func (s *resourceState) find(r Resource) (Resource, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.resources {
+		if existing.key() == r.key() {
+			return existing, true
+		}
+	}
+	return Resource{}, false
+}
+
+// list returns the resources in creation order.
+func (s *resourceState) list() []Resource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Resource(nil), s.resources...)
+}
+
+// setRoute records a cross-node route once it is set up.
+func (s *resourceState) setRoute(route CrossNodeRoute) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes[route.RemoteSubnet] = route
+	if err := s.save(); err != nil {
+		delete(s.routes, route.RemoteSubnet)
+		return err
+	}
+	return nil
+}
+
+func (s *resourceState) removeRoute(remoteSubnet string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.routes[remoteSubnet]; !ok {
+		return nil
+	}
+	delete(s.routes, remoteSubnet)
+	return s.save()
+}
+
+// removeRoutes forgets every cross-node route, once Cleanup deleted what
+// they were made of.
+func (s *resourceState) removeRoutes() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes = make(map[string]CrossNodeRoute)
+	return s.save()
+}
+
+func (s *resourceState) route(remoteSubnet string) (CrossNodeRoute, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	route, ok := s.routes[remoteSubnet]
+	return route, ok
+}
+
+// routeList returns the cross-node routes ordered by remote subnet.
+func (s *resourceState) routeList() []CrossNodeRoute {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedRoutes()
+}
+
+// sortedRoutes returns the cross-node routes ordered by remote subnet. The
+// caller holds s.mu.
+func (s *resourceState) sortedRoutes() []CrossNodeRoute {
+	routes := make([]CrossNodeRoute, 0, len(s.routes))
+	for _, route := range s.routes {
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].RemoteSubnet < routes[j].RemoteSubnet })
+	return routes
+}
+
+// setShape records the shaping of shape.TapName, replacing any earlier one.
+func (s *resourceState) setShape(shape TrafficShape) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.shapes[shape.TapName]
+	s.shapes[shape.TapName] = shape
+	if err := s.save(); err != nil {
+		if ok {
+			s.shapes[shape.TapName] = old
+		} else {
+			delete(s.shapes, shape.TapName)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *resourceState) removeShape(tapName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.shapes[tapName]; !ok {
+		return nil
+	}
+	delete(s.shapes, tapName)
+	return s.save()
+}
+
+// shapeList returns the traffic shapes ordered by tap name.
+func (s *resourceState) shapeList() []TrafficShape {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedShapes()
+}
+
+// sortedShapes returns the traffic shapes ordered by tap name. The caller
+// holds s.mu.
+func (s *resourceState) sortedShapes() []TrafficShape {
+	shapes := make([]TrafficShape, 0, len(s.shapes))
+	for _, shape := range s.shapes {
+		shapes = append(shapes, shape)
+	}
+	sort.Slice(shapes, func(i, j int) bool { return shapes[i].TapName < shapes[j].TapName })
+	return shapes
+}
+
+// save writes the state to a temporary file and renames it over the old one,
+// so a crash never leaves a truncated file. The caller holds s.mu.
+func (s *resourceState) save() error {
+	data, err := json.MarshalIndent(stateData{Resources: s.resources, Routes: s.sortedRoutes(), Shapes: s.sortedShapes()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode network state: %v", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write network state: %v", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write network state: %v", err)
+	}
+	return nil
+}
+
+// trackingLinks records every link, addr and route that it creates and
+// forgets them again once they are deleted. Links that already existed are
+// not recorded, since they are not ours to delete.
+type trackingLinks struct {
+	Links
+	state *resourceState
+}
+
+func (l *trackingLinks) AddBridge(name string) error {
+	return l.addLink(name, l.Links.AddBridge(name))
+}
+
+func (l *trackingLinks) AddTap(name string, uid int) error {
+	return l.addLink(name, l.Links.AddTap(name, uid))
+}
+
+// AddVXLAN replaces a VXLAN device of the same name that the runner created
+// earlier, e.g. before it crashed, since its remote may differ. A device
+// someone else created is left alone.
+func (l *trackingLinks) AddVXLAN(name string, vxlan VXLAN) error {
+	err := l.Links.AddVXLAN(name, vxlan)
+	if _, ok := l.state.find(Resource{Type: ResourceLink, Link: name}); ok && errors.Is(err, ErrExists) {
+		log.Printf("Replacing VXLAN device %s left by an earlier run", name)
+		if err := l.DeleteLink(name); err != nil {
+			return err
+		}
+		err = l.Links.AddVXLAN(name, vxlan)
+	}
+	return l.addLink(name, err)
+}
+
+func (l *trackingLinks) addLink(name string, err error) error {
+	if err != nil {
+		return err
+	}
+	return l.state.add(Resource{Type: ResourceLink, Link: name})
+}
+
+func (l *trackingLinks) DeleteLink(name string) error {
+	if err := ignoreNotFound(l.Links.DeleteLink(name)); err != nil {
+		return err
+	}
+
+	// the link's addrs, qdiscs and shaping are gone with it
+	if err := l.state.remove(func(r Resource) bool { return r.Type != ResourceRule && r.Link == name }); err != nil {
+		return err
+	}
+	return l.state.removeShape(name)
+}
+
+func (l *trackingLinks) AddAddr(name string, addr *net.IPNet) error {
+	if err := l.Links.AddAddr(name, addr); err != nil {
+		return err
+	}
+	return l.state.add(Resource{Type: ResourceAddr, Link: name, Addr: addr.String()})
+}
+
+func (l *trackingLinks) DeleteAddr(name string, addr *net.IPNet) error {
+	if err := ignoreNotFound(l.Links.DeleteAddr(name, addr)); err != nil {
+		return err
+	}
+
+	return l.state.remove(func(r Resource) bool {
+		return r.Type == ResourceAddr && r.Link == name && r.Addr == addr.String()
+	})
+}
+
+// ReplaceRoute records a route to a destination that had none as created. A
+// route that was already there is recorded as replaced, so deleting the
+// runner's route puts it back rather than leaving the destination unrouted.
+func (l *trackingLinks) ReplaceRoute(route Route) error {
+	resource := Resource{Type: ResourceRoute, Addr: route.Dst.String()}
+	if _, ok := l.state.find(resource); !ok {
+		existing, err := l.Links.Route(route.Dst)
+		if err := ignoreNotFound(err); err != nil {
+			return err
+		}
+		if err == nil {
+			resource.Replaced = &ReplacedRoute{Device: existing.Device}
+			if existing.Gateway != nil {
+				resource.Replaced.Gateway = existing.Gateway.String()
+			}
+		}
+	}
+
+	if err := l.Links.ReplaceRoute(route); err != nil {
+		return err
+	}
+	return l.state.add(resource)
+}
+
+func (l *trackingLinks) DeleteRoute(dst *net.IPNet) error {
+	resource, _ := l.state.find(Resource{Type: ResourceRoute, Addr: dst.String()})
+	var err error
+	if replaced := resource.Replaced; replaced != nil {
+		err = l.Links.ReplaceRoute(Route{Dst: dst, Gateway: net.ParseIP(replaced.Gateway), Device: replaced.Device})
+		// the device of the old route may be gone by now
+		if errors.Is(err, ErrNotFound) {
+			err = l.Links.DeleteRoute(dst)
+		}
+	} else {
+		err = l.Links.DeleteRoute(dst)
+	}
+	if err := ignoreNotFound(err); err != nil {
+		return err
+	}
+
+	return l.state.remove(func(r Resource) bool {
+		return r.Type == ResourceRoute && r.Addr == dst.String()
+	})
+}
+
+func (l *trackingLinks) ReplaceQdiscs(name string, netem *Netem, tbf *Tbf) error {
+	if err := l.Links.ReplaceQdiscs(name, netem, tbf); err != nil {
+		return err
+	}
+	return l.state.add(Resource{Type: ResourceQdisc, Link: name})
+}
+
+func (l *trackingLinks) DeleteQdiscs(name string) error {
+	if err := ignoreNotFound(l.Links.DeleteQdiscs(name)); err != nil {
+		return err
+	}
+
+	if err := l.state.remove(func(r Resource) bool { return r.Type == ResourceQdisc && r.Link == name }); err != nil {
+		return err
+	}
+	return l.state.removeShape(name)
+}
+
+// trackingFirewall tags every rule with ruleComment and records it. A rule
+// that already exists with the tag is ours from an earlier run, so it is
+// recorded as well.
+type trackingFirewall struct {
+	Firewall
+	state *resourceState
+}
+
+func (f *trackingFirewall) InsertRule(rule Rule) error {
+	rule = markRule(rule)
+	return f.addRule(rule, f.Firewall.InsertRule(rule))
+}
+
+func (f *trackingFirewall) AppendRule(rule Rule) error {
+	rule = markRule(rule)
+	return f.addRule(rule, f.Firewall.AppendRule(rule))
+}
+
+func (f *trackingFirewall) addRule(rule Rule, err error) error {
+	if err != nil && !errors.Is(err, ErrExists) {
+		return err
+	}
+	if err := f.state.add(Resource{Type: ResourceRule, Rule: &rule}); err != nil {
+		return err
+	}
+	return err
+}
+
+func (f *trackingFirewall) DeleteRule(rule Rule) error {
+	rule = markRule(rule)
+	if err := ignoreNotFound(f.Firewall.DeleteRule(rule)); err != nil {
+		return err
+	}
+
+	key := Resource{Type: ResourceRule, Rule: &rule}.key()
+	return f.state.remove(func(r Resource) bool { return r.key() == key })
+}
+
+func markRule(rule Rule) Rule {
+	n := len(rule.Spec)
+	if n >= 2 && rule.Spec[n-2] == "--comment" && rule.Spec[n-1] == ruleComment {
+		return rule
+	}
+
+	spec := make([]string, 0, n+4)
+	spec = append(spec, rule.Spec...)
+	rule.Spec = append(spec, "-m", "comment", "--comment", ruleComment)
+	return rule
+}
+
+// deleteResource removes a recorded resource. Resources that are already
+// gone count as deleted.
+func (m *Manager) deleteResource(r Resource) error {
+	switch r.Type {
+	case ResourceLink:
+		return m.links.DeleteLink(r.Link)
+	case ResourceAddr, ResourceRoute:
+		ip, addr, err := net.ParseCIDR(r.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q in network state: %v", r.Type, r.Addr, err)
+		}
+		if r.Type == ResourceRoute {
+			return m.links.DeleteRoute(addr)
+		}
+		addr.IP = ip
+		return m.links.DeleteAddr(r.Link, addr)
+	case ResourceRule:
+		return m.firewall.DeleteRule(*r.Rule)
+	case ResourceQdisc:
+		return m.links.DeleteQdiscs(r.Link)
+	default:
+		return fmt.Errorf("unknown resource type %q in network state", r.Type)
+	}
+}
@@ -0,0 +1,324 @@
+package network
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTrackingMarksRules(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	m := newTestManager(t, links, firewall, t.TempDir())
+	if _, err := m.Setup(1, "10.0.0.1/24"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rule := range firewall.Rules() {
+		if n := len(rule.Spec); n < 2 || rule.Spec[n-2] != "--comment" || rule.Spec[n-1] != ruleComment {
+			t.Errorf("rule %s is not marked", rule)
+		}
+	}
+	// marking a marked rule changes nothing
+	rule := markRule(Rule{Table: "filter", Chain: "INPUT", Spec: []string{"-j", "ACCEPT"}})
+	if got := markRule(rule); !reflect.DeepEqual(got, rule) {
+		t.Errorf("got %s, want %s", got, rule)
+	}
+}
+
+func TestTrackingResources(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	// the bridge is someone else's
+	if err := links.AddBridge("br0"); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestManager(t, links, firewall, t.TempDir())
+	bridge, err := m.Setup(1, "10.0.0.1/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Resource{
+		{Type: ResourceLink, Link: "tap0"},
+		{Type: ResourceAddr, Link: "br0", Addr: "10.0.0.1/24"},
+	}
+	for _, rule := range bridgeRules(bridge) {
+		rule = markRule(rule)
+		want = append(want, Resource{Type: ResourceRule, Rule: &rule})
+	}
+	_, subnet, _ := parseBridgeIP("10.0.0.1/24")
+	masquerade := markRule(masqueradeRule(subnet))
+	want = append(want, Resource{Type: ResourceRule, Rule: &masquerade})
+
+	if got := m.Resources(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got resources\n%v\nwant\n%v", got, want)
+	}
+	if got := m.Taps(); !reflect.DeepEqual(got, []string{"tap0"}) {
+		t.Errorf("got taps %v", got)
+	}
+}
+
+func TestCleanup(t *testing.T) {
+	tests := []struct {
+		name string
+		// before runs before the runner sets up its network
+		before func(t *testing.T, links *FakeLinks, firewall *FakeFirewall)
+		// keepLinks and keepRules are what Cleanup must leave alone
+		keepLinks []string
+		keepRules int
+	}{
+		{
+			name: "everything created",
+		},
+		{
+			name: "existing bridge",
+			before: func(t *testing.T, links *FakeLinks, firewall *FakeFirewall) {
+				if err := links.AddBridge("br0"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			keepLinks: []string{"br0"},
+		},
+		{
+			name: "foreign links and rules",
+			before: func(t *testing.T, links *FakeLinks, firewall *FakeFirewall) {
+				if err := links.AddTap("othertap0", 0); err != nil {
+					t.Fatal(err)
+				}
+				// the same rule as the runner's, but not marked
+				rule := Rule{Table: "filter", Chain: "INPUT", Spec: []string{"-i", "br0", "-p", "udp", "-j", "ACCEPT"}}
+				if err := firewall.AppendRule(rule); err != nil {
+					t.Fatal(err)
+				}
+			},
+			keepLinks: []string{"othertap0"},
+			keepRules: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, firewall := NewFakeLinks(), NewFakeFirewall()
+			if tt.before != nil {
+				tt.before(t, links, firewall)
+			}
+			m := newTestManager(t, links, firewall, t.TempDir())
+			if _, err := m.Setup(2, "10.0.0.1/24"); err != nil {
+				t.Fatal(err)
+			}
+			created := m.Resources()
+			linkOps, ruleOps := len(links.Ops()), len(firewall.Ops())
+
+			if err := m.Cleanup(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := links.Links(); !reflect.DeepEqual(got, append([]string{}, tt.keepLinks...)) {
+				t.Errorf("got links %v after Cleanup, want %v", got, tt.keepLinks)
+			}
+			if got := len(firewall.Rules()); got != tt.keepRules {
+				t.Errorf("got %d rules after Cleanup, want %d", got, tt.keepRules)
+			}
+			if got := m.Resources(); len(got) != 0 {
+				t.Errorf("got resources %v after Cleanup", got)
+			}
+
+			// newest first
+			var want []string
+			for i := len(created) - 1; i >= 0; i-- {
+				switch r := created[i]; r.Type {
+				case ResourceLink:
+					want = append(want, "delete link "+r.Link)
+				case ResourceAddr:
+					want = append(want, "delete addr "+r.Addr+" from "+r.Link)
+				}
+			}
+			if got := links.Ops()[linkOps:]; !reflect.DeepEqual(got, want) {
+				t.Errorf("got link ops %v, want %v", got, want)
+			}
+			want = nil
+			for i := len(created) - 1; i >= 0; i-- {
+				if r := created[i]; r.Type == ResourceRule {
+					want = append(want, "delete rule "+r.Rule.String())
+				}
+			}
+			if got := firewall.Ops()[ruleOps:]; !reflect.DeepEqual(got, want) {
+				t.Errorf("got rule ops %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCleanupTwice(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	m := newTestManager(t, links, firewall, t.TempDir())
+	if _, err := m.Setup(2, "10.0.0.1/24"); err != nil {
+		t.Fatal(err)
+	}
+	// a tap deleted behind the runner's back counts as deleted
+	if err := links.DeleteLink("tap1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	linkOps, ruleOps := len(links.Ops()), len(firewall.Ops())
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if len(links.Ops()) != linkOps || len(firewall.Ops()) != ruleOps {
+		t.Errorf("second Cleanup changed something: %v, %v", links.Ops()[linkOps:], firewall.Ops()[ruleOps:])
+	}
+}
+
+func TestCleanupRetriesFailedResources(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	m := newTestManager(t, links, firewall, t.TempDir())
+	if _, err := m.Setup(2, "10.0.0.1/24"); err != nil {
+		t.Fatal(err)
+	}
+
+	busy := errors.New("device busy")
+	links.Fail("delete link", "tap0", busy)
+	err := m.Cleanup()
+	if !errors.Is(err, busy) {
+		t.Fatalf("got %v, want %v", err, busy)
+	}
+	if got := m.Taps(); !reflect.DeepEqual(got, []string{"tap0"}) {
+		t.Errorf("got taps %v after a failed Cleanup, want tap0", got)
+	}
+
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if got := links.Links(); len(got) != 0 {
+		t.Errorf("got links %v", got)
+	}
+}
+
+func TestCleanupAfterRestart(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	stateDir := t.TempDir()
+	m := newTestManager(t, links, firewall, stateDir)
+	if _, err := m.Setup(2, "10.0.0.1/24"); err != nil {
+		t.Fatal(err)
+	}
+
+	m = newTestManager(t, links, firewall, stateDir)
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if got := links.Links(); len(got) != 0 {
+		t.Errorf("got links %v", got)
+	}
+	if got := firewall.Rules(); len(got) != 0 {
+		t.Errorf("got rules %v", got)
+	}
+}
+
+func TestTrackingAdoptsMarkedRules(t *testing.T) {
+	links, firewall := NewFakeLinks(), NewFakeFirewall()
+	// a marked rule is the runner's even if its state was lost
+	rule := markRule(Rule{Table: "filter", Chain: "INPUT", Spec: []string{"-i", "br0", "-p", "udp", "-j", "ACCEPT"}})
+	if err := firewall.AppendRule(rule); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestManager(t, links, firewall, t.TempDir())
+	if _, err := m.Setup(1, "10.0.0.1/24"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if got := firewall.Rules(); len(got) != 0 {
+		t.Errorf("got rules %v after Cleanup", got)
+	}
+}
+
+func TestTrackingReplacedRoute(t *testing.T) {
+	_, dst, _ := net.ParseCIDR("10.1.0.0/16")
+	tests := []struct {
+		name     string
+		existing *Route
+	}{
+		{name: "no route"},
+		{name: "route via gateway", existing: &Route{Dst: dst, Gateway: net.ParseIP("192.168.1.1")}},
+		{name: "route via device", existing: &Route{Dst: dst, Device: "eth0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links, firewall := NewFakeLinks(), NewFakeFirewall()
+			if err := links.AddTap("eth0", 0); err != nil {
+				t.Fatal(err)
+			}
+			if tt.existing != nil {
+				if err := links.ReplaceRoute(*tt.existing); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stateDir := t.TempDir()
+			m := newTestManager(t, links, firewall, stateDir)
+			route := Route{Dst: dst, Gateway: net.ParseIP("192.168.1.20")}
+			if err := m.links.ReplaceRoute(route); err != nil {
+				t.Fatal(err)
+			}
+			// replacing its own route keeps the original one to put back
+			if err := m.links.ReplaceRoute(route); err != nil {
+				t.Fatal(err)
+			}
+			if got := links.Routes()[dst.String()]; !got.Gateway.Equal(route.Gateway) {
+				t.Fatalf("got route %+v, want %+v", got, route)
+			}
+
+			// even after a restart
+			m = newTestManager(t, links, firewall, stateDir)
+			if err := m.Cleanup(); err != nil {
+				t.Fatal(err)
+			}
+			got, ok := links.Routes()[dst.String()]
+			if tt.existing == nil {
+				if ok {
+					t.Errorf("got route %+v after Cleanup, want none", got)
+				}
+				return
+			}
+			if !ok || !got.Gateway.Equal(tt.existing.Gateway) || got.Device != tt.existing.Device {
+				t.Errorf("got route %+v after Cleanup, want %+v", got, *tt.existing)
+			}
+		})
+	}
+}
+
+func TestLoadLegacyState(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `[{"type": "link", "link": "tap0"}, {"type": "addr", "link": "br0", "addr": "10.0.0.1/24"}]`
+	if err := os.WriteFile(filepath.Join(dir, stateFile), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := loadResourceState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Resource{
+		{Type: ResourceLink, Link: "tap0"},
+		{Type: ResourceAddr, Link: "br0", Addr: "10.0.0.1/24"},
+	}
+	if got := state.list(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, stateFile), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadResourceState(dir); err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("got %v for a broken state file", err)
+	}
+}
@@ -1,113 +1,76 @@
 package node
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
 )
 
-func (n *NodeManager) trackSyscalls(pid int) error {
+func (n *NodeManager) trackSyscalls(pid int) (tracer.Session, error) {
 	log.Printf("NodeManager: Tracking syscalls for PID: %d", pid)
-	tracePath, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %v", err)
-	}
-
-	tracePath = filepath.Join(tracePath, "trace_syscalls.sh")
-	command := fmt.Sprintf("sudo %s %d", tracePath, pid)
-	logPath := filepath.Join(n.logsDir, fmt.Sprintf("node-syscalls-%d.log", pid))
-
-	_, err = n.captureCommandOutput(n.traceCtx, command, logPath, false)
-	if err != nil {
-		return fmt.Errorf("failed to track syscalls of node: %v", err)
-	}
-
-	return nil
+	return n.TrackSyscalls([]int{pid}, nil, false)
 }
 
-func (n *NodeManager) captureCommandOutput(ctx context.Context, command, logPath string, wait bool) (int, error) {
-	logFile, err := os.Create(logPath)
-	if err != nil {
-		log.Printf("captureCommand: Failed to create log file %s: %v", logPath, err)
-		return 0, fmt.Errorf("failed to create log file %s: %v", logPath, err)
-	}
+// nodeCommand is a command running on the node whose output is not read yet.
+type nodeCommand struct {
+	process *job.Process
+	logFile *os.File
+	ctx     context.Context
+}
 
+// startCommand starts command, which is killed once ctx is done, and creates
+// the log file at logPath for its output.
+func startCommand(ctx context.Context, command, logPath string) (*nodeCommand, error) {
 	// Split the command properly for exec
 	args := strings.Fields(command)
 	if len(args) == 0 {
-		logFile.Close()
-		return 0, fmt.Errorf("empty command")
+		return nil, fmt.Errorf("empty command")
 	}
-	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
 
-	stdout, err := cmd.StdoutPipe()
+	logFile, err := os.Create(logPath)
 	if err != nil {
-		logFile.Close()
-		return 0, fmt.Errorf("failed to create stdout pipe: %v", err)
+		log.Printf("captureCommand: Failed to create log file %s: %v", logPath, err)
+		return nil, fmt.Errorf("failed to create log file %s: %v", logPath, err)
 	}
 
-	stderr, err := cmd.StderrPipe()
+	process, err := job.Start(job.Spec{Argv: args})
 	if err != nil {
 		logFile.Close()
-		return 0, fmt.Errorf("failed to create stderr pipe: %v", err)
-	}
-
-	if err := cmd.Start(); err != nil {
-		logFile.Close()
-		return 0, fmt.Errorf("failed to start command: %v", err)
-	}
-
-	// Get PID immediately after start
-	pid := cmd.Process.Pid
-	log.Printf("Started command with PID: %d, wait=%v", pid, wait)
-
-	if wait {
-		n.wg.Add(1)
+		return nil, err
 	}
+	log.Printf("Started command with PID: %d", process.Pid())
 
-	// Now handle I/O and waiting in goroutine
-	go func() {
-		if wait {
-			defer n.wg.Done()
-		}
-		defer logFile.Close()
+	return &nodeCommand{process: process, logFile: logFile, ctx: ctx}, nil
+}
 
-		go func() {
-			scanner := bufio.NewScanner(stdout)
-			for scanner.Scan() {
-				logFile.WriteString(fmt.Sprintf("[STDOUT] %s\n", scanner.Text()))
-			}
-		}()
+func (c *nodeCommand) pid() int {
+	return c.process.Pid()
+}
 
-		go func() {
-			scanner := bufio.NewScanner(stderr)
-			for scanner.Scan() {
-				logFile.WriteString(fmt.Sprintf("[STDERR] %s\n", scanner.Text()))
-			}
-		}()
+// wait writes the output of the command to its log file and passes it to
+// output, which may be nil, until the command exits. output is not called
+// concurrently.
+func (c *nodeCommand) wait(output func(job.Output)) job.Result {
+	defer c.logFile.Close()
 
-		if wait {
-			err := cmd.Wait()
-			if err != nil {
-				log.Printf("Command (PID %d) completed with error: %v, output saved to %s", pid, err, logPath)
-			} else {
-				log.Printf("Command (PID %d) completed successfully, output saved to %s", pid, logPath)
-			}
-		} else {
-			// for trace/server: stop when ctx is canceled
-			log.Printf("Command (PID %d) running, waiting for context cancellation", pid)
-			<-ctx.Done()
-			log.Printf("Context cancelled, killing command (PID %d)", pid)
-			cmd.Process.Kill()
-			cmd.Wait()
-			log.Printf("Command (PID %d) stopped, logs saved to %s", pid, logPath)
+	lines := job.NewLines(func(out job.Output) {
+		c.logFile.WriteString(fmt.Sprintf("[%s] %s\n", strings.ToUpper(out.Stream.String()), out.Line))
+		if output != nil {
+			output(out)
 		}
-	}()
-
-	return pid, nil
+	})
+	process := job.WithContext(c.ctx, c.process, func() { c.process.Kill() })
+	result, _ := process.Wait(lines.Write)
+	lines.Flush()
+
+	c.logFile.WriteString(fmt.Sprintf("[EXIT] code %d after %s, killed %v\n", result.ExitCode, result.Duration, result.Killed))
+	if result.Error != "" {
+		c.logFile.WriteString(fmt.Sprintf("Error: %s\n", result.Error))
+	}
+	return result
 }
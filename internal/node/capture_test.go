@@ -0,0 +1,82 @@
+package node
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+)
+
+func runTestCommand(t *testing.T, ctx context.Context, command string) ([]job.Output, job.Result) {
+	t.Helper()
+
+	logPath := filepath.Join(t.TempDir(), "command.log")
+	c, err := startCommand(ctx, command, logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []job.Output
+	result := c.wait(func(out job.Output) {
+		lines = append(lines, out)
+	})
+	return lines, result
+}
+
+func TestCommandStreamsOutputAndExitCode(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "fail.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho out\necho err >&2\nexit 3\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, result := runTestCommand(t, context.Background(), script)
+	stdout := []job.Output{{Stream: job.Stdout, Line: "out"}}
+	stderr := []job.Output{{Stream: job.Stderr, Line: "err"}}
+	// the streams are read concurrently, so only their own order is kept
+	var gotStdout, gotStderr []job.Output
+	for _, line := range lines {
+		if line.Stream == job.Stdout {
+			gotStdout = append(gotStdout, line)
+		} else {
+			gotStderr = append(gotStderr, line)
+		}
+	}
+	if !reflect.DeepEqual(gotStdout, stdout) || !reflect.DeepEqual(gotStderr, stderr) {
+		t.Fatalf("got output %v, want %v and %v", lines, stdout, stderr)
+	}
+	if result.ExitCode != 3 || result.Killed || result.Error != "" {
+		t.Fatalf("got %+v, want exit code 3", result)
+	}
+}
+
+func TestCommandKilledByContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	logPath := filepath.Join(t.TempDir(), "command.log")
+	c, err := startCommand(ctx, "sleep 60", logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	result := c.wait(nil)
+	if !result.Killed || result.ExitCode != -1 {
+		t.Fatalf("got %+v, want a killed command", result)
+	}
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "[EXIT] code -1") {
+		t.Fatalf("log is %q, want the exit", log)
+	}
+}
+
+func TestStartCommandRejectsEmptyCommand(t *testing.T) {
+	if _, err := startCommand(context.Background(), " ", filepath.Join(t.TempDir(), "command.log")); err == nil {
+		t.Fatal("started an empty command")
+	}
+}
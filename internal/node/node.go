@@ -5,70 +5,254 @@ import (
 	"fmt"
 	"log"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/bookpanda/firecracker-runner-node/internal/config"
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	"github.com/bookpanda/firecracker-runner-node/internal/run"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
 )
 
+// nodeTarget is the target of the jobs and of the commands of the node.
+const nodeTarget = "node"
+
+// NodeManager runs commands on the node itself. traceCtx ends the server
+// commands; it is replaced once they are stopped.
 type NodeManager struct {
 	config      *config.Config
+	mu          sync.Mutex
 	traceCtx    context.Context
 	cancelTrace context.CancelFunc
-	wg          sync.WaitGroup
-	logsDir     string
+	runs        *run.Manager
+	syscalls    *tracer.Sessions
+	jobs        *job.Registry
 }
 
-func NewManager(cfg *config.Config) *NodeManager {
+// NewManager writes the logs of the commands to the current run of runs.
+func NewManager(cfg *config.Config, runs *run.Manager) *NodeManager {
 	traceCtx, cancelTrace := context.WithCancel(context.Background())
-	return &NodeManager{
+	n := &NodeManager{
 		config:      cfg,
 		traceCtx:    traceCtx,
 		cancelTrace: cancelTrace,
-		wg:          sync.WaitGroup{},
-		logsDir:     "./node-logs",
+		runs:        runs,
+		jobs:        job.NewRegistry(),
 	}
+	n.syscalls = tracer.NewSessions(tracer.NewEBPF(), n.syscallsLogPath)
+	return n
 }
 
-func (n *NodeManager) SendServerCommand(command string) error {
+// SendServerCommand starts command in the background as a job and traces
+// its syscalls until it exits or StopSyscalls. The command is killed after timeout,
+// unless it is 0.
+func (n *NodeManager) SendServerCommand(command string, timeout time.Duration) (job.Job, tracer.Session, error) {
 	log.Printf("NodeManager: Sending server command: %s", command)
-	testLogPath := filepath.Join(n.logsDir, "node-server.log")
+	n.recordCommand(command, true)
 
-	pid, err := n.captureCommandOutput(n.traceCtx, command, testLogPath, false)
+	j, ctx := n.jobs.Add(n.commandCtx(), job.Job{Target: nodeTarget, Command: command, Server: true, Timeout: timeout})
+	testLogPath := n.jobLogPath(&j, "server")
+	c, err := startCommand(ctx, command, testLogPath)
 	if err != nil {
+		n.jobs.End(j.ID, job.Result{ExitCode: -1, Error: err.Error()})
 		log.Printf("failed to send command to node: %v", err)
-		return fmt.Errorf("failed to send command to node: %v", err)
+		return job.Job{}, tracer.Session{}, fmt.Errorf("failed to send command to node: %v", err)
 	}
+	j.Pid = c.pid()
+	n.jobs.SetPid(j.ID, j.Pid)
+	traced := make(chan tracer.Session, 1)
+	go func() {
+		result := c.wait(nil)
+		n.jobs.End(j.ID, result)
+		log.Printf("Command (PID %d) exited with code %d, killed %v, logs saved to %s", j.Pid, result.ExitCode, result.Killed, testLogPath)
+		// nothing is left to trace
+		if session, ok := <-traced; ok {
+			n.syscalls.Stop(session.ID)
+		}
+	}()
 
-	if err := n.trackSyscalls(pid); err != nil {
+	session, err := n.trackSyscalls(j.Pid)
+	if err != nil {
+		close(traced)
+		// the caller gets no job to stop the server with
+		n.jobs.Cancel(context.Background(), j.ID)
 		log.Printf("failed to track syscalls of node: %v", err)
-		return fmt.Errorf("failed to track syscalls of node: %v", err)
+		return job.Job{}, tracer.Session{}, fmt.Errorf("failed to track syscalls of node: %v", err)
 	}
+	traced <- session
 
-	return nil
+	return j, session, nil
 }
 
-func (n *NodeManager) SendClientCommand(command string) error {
+// jobLogPath names the log of j after its role, server or client, and the
+// job, so that commands running at the same time do not share a log.
+func (n *NodeManager) jobLogPath(j *job.Job, role string) string {
+	j.LogPath = filepath.Join(n.runs.Dir(run.NodeLogsDir), fmt.Sprintf("node-%s-%s.log", role, j.ID))
+	n.jobs.SetLogPath(j.ID, j.LogPath)
+	return j.LogPath
+}
+
+// SendClientCommand runs command as a job until it exits, ctx is done,
+// timeout passes or the server commands are stopped, tracing its syscalls
+// until it exits.
+// A timeout of 0 never passes. started is called with the job and the
+// tracing session before output is called with the first line of output.
+func (n *NodeManager) SendClientCommand(ctx context.Context, command string, timeout time.Duration, started func(job.Job, tracer.Session), output func(job.Output)) (job.Result, error) {
 	log.Printf("NodeManager: Sending client command: %s", command)
-	testLogPath := filepath.Join(n.logsDir, "node-client.log")
+	n.recordCommand(command, false)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(n.commandCtx(), cancel)
+	defer stop()
 
-	pid, err := n.captureCommandOutput(n.traceCtx, command, testLogPath, true)
+	j, ctx := n.jobs.Add(ctx, job.Job{Target: nodeTarget, Command: command, Timeout: timeout})
+	testLogPath := n.jobLogPath(&j, "client")
+	c, err := startCommand(ctx, command, testLogPath)
 	if err != nil {
+		n.jobs.End(j.ID, job.Result{ExitCode: -1, Error: err.Error()})
 		log.Printf("failed to send command to node: %v", err)
-		return fmt.Errorf("failed to send command to node: %v", err)
+		return job.Result{}, fmt.Errorf("failed to send command to node: %v", err)
 	}
+	j.Pid = c.pid()
+	n.jobs.SetPid(j.ID, j.Pid)
 
-	if err := n.trackSyscalls(pid); err != nil {
+	session, err := n.trackSyscalls(j.Pid)
+	if err != nil {
+		cancel()
+		n.jobs.End(j.ID, c.wait(nil))
 		log.Printf("failed to track syscalls of node: %v", err)
-		return fmt.Errorf("failed to track syscalls of node: %v", err)
+		return job.Result{}, fmt.Errorf("failed to track syscalls of node: %v", err)
 	}
+	started(j, session)
 
-	n.wg.Wait()
+	result := c.wait(output)
+	n.jobs.End(j.ID, result)
+	n.syscalls.Stop(session.ID)
+	log.Printf("Command (PID %d) exited with code %d after %s, output saved to %s", j.Pid, result.ExitCode, result.Duration, testLogPath)
 
-	return nil
+	return result, nil
+}
+
+// Exec starts spec on the node as a job. The command is killed once ctx is
+// done or after timeout, unless it is 0.
+func (n *NodeManager) Exec(ctx context.Context, spec job.Spec, timeout time.Duration) (job.Job, job.Handle, error) {
+	log.Printf("NodeManager: Exec %q", spec.Argv)
+	command := strings.Join(spec.Argv, " ")
+	n.recordCommand(command, false)
+
+	j, ctx := n.jobs.Add(ctx, job.Job{Target: nodeTarget, Command: command, Timeout: timeout})
+	process, err := job.Start(spec)
+	if err != nil {
+		n.jobs.End(j.ID, job.Result{ExitCode: -1, Error: err.Error()})
+		log.Printf("failed to exec on node: %v", err)
+		return job.Job{}, nil, fmt.Errorf("failed to exec on node: %v", err)
+	}
+	j.Pid = process.Pid()
+	n.jobs.SetPid(j.ID, j.Pid)
+	return j, n.jobs.Track(j.ID, job.WithContext(ctx, process, func() { process.Kill() })), nil
+}
+
+// ListJobs returns the jobs of the node, oldest first. Of the jobs that ended
+// only the last job.MaxEndedJobs are kept.
+func (n *NodeManager) ListJobs() []job.Job {
+	return n.jobs.List("")
+}
+
+func (n *NodeManager) GetJob(id string) (job.Job, error) {
+	return n.jobs.Get(id)
+}
+
+// CancelJob kills the command of the job with the given ID and returns the
+// job once the command ended, or as it is when ctx is done.
+func (n *NodeManager) CancelJob(ctx context.Context, id string) (job.Job, error) {
+	log.Printf("NodeManager: Cancelling job %s", id)
+	return n.jobs.Cancel(ctx, id)
+}
+
+// CancelJobs kills every running command and waits until they ended or ctx
+// is done.
+func (n *NodeManager) CancelJobs(ctx context.Context) {
+	log.Printf("NodeManager: Cancelling jobs")
+	n.jobs.CancelAll(ctx)
 }
 
-func (n *NodeManager) StopSyscalls() error {
+// TrackSyscalls starts a tracing session of pids. syscalls is an allowlist of
+// syscall names and families, such as "network", empty to count every
+// syscall. latency also builds latency histograms of the syscalls.
+func (n *NodeManager) TrackSyscalls(pids []int, syscalls []string, latency bool) (tracer.Session, error) {
+	targets := make([]tracer.Target, 0, len(pids))
+	for _, pid := range pids {
+		targets = append(targets, tracer.Target{Name: strconv.Itoa(pid), PID: pid})
+	}
+
+	session, err := n.syscalls.Start(targets, syscalls, latency)
+	if err != nil {
+		return tracer.Session{}, fmt.Errorf("failed to track syscalls: %v", err)
+	}
+	return session, nil
+}
+
+// StopSyscalls stops the tracing session with the given ID. Without an ID it
+// stops every session and kills the server commands.
+func (n *NodeManager) StopSyscalls(id string) error {
+	if id != "" {
+		log.Printf("NodeManager: Stopping syscalls of session %s", id)
+		return n.syscalls.Stop(id)
+	}
+
 	log.Printf("NodeManager: Stopping syscalls")
+	n.syscalls.StopAll()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
 	n.cancelTrace()
+	n.traceCtx, n.cancelTrace = context.WithCancel(context.Background())
 	return nil
 }
+
+func (n *NodeManager) ListTraceSessions() []tracer.Session {
+	return n.syscalls.List()
+}
+
+// SyscallStats returns the latest syscall counts of the process pid, or of
+// every process if pid is 0, in the tracing session with the given ID, or in
+// the latest session if id is empty.
+func (n *NodeManager) SyscallStats(id string, pid int) ([]tracer.TargetStats, error) {
+	return n.syscalls.Stats(id, targetName(pid))
+}
+
+// WatchSyscalls calls send with the counts of the process pid, or of every
+// process if pid is 0, at the end of every interval of the tracing session
+// with the given ID, or of the latest session if id is empty. It returns once
+// ctx is done, send fails, or the session has stopped and the final counts
+// are sent.
+func (n *NodeManager) WatchSyscalls(ctx context.Context, id string, pid int, send func(tracer.TargetStats) error) error {
+	return n.syscalls.Watch(ctx, id, targetName(pid), send)
+}
+
+func (n *NodeManager) commandCtx() context.Context {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.traceCtx
+}
+
+func (n *NodeManager) syscallsLogPath(session string, target tracer.Target) string {
+	return filepath.Join(n.runs.Dir(run.NodeLogsDir), session, fmt.Sprintf("node-syscalls-%s.log", target.Name))
+}
+
+func (n *NodeManager) recordCommand(command string, server bool) {
+	err := n.runs.AddCommand(run.Command{Target: nodeTarget, Command: command, Server: server, StartedAt: time.Now()})
+	if err != nil {
+		log.Printf("failed to record command in the run manifest: %v", err)
+	}
+}
+
+func targetName(pid int) string {
+	if pid == 0 {
+		return ""
+	}
+	return strconv.Itoa(pid)
+}
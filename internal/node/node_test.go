@@ -0,0 +1,201 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/config"
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	"github.com/bookpanda/firecracker-runner-node/internal/run"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
+)
+
+func newTestManager(t *testing.T) *NodeManager {
+	t.Helper()
+
+	runs, err := run.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewManager(config.Default(), runs)
+}
+
+func TestExecTerminal(t *testing.T) {
+	n := newTestManager(t)
+	_, handle, err := n.Exec(context.Background(), job.Spec{Argv: []string{"sh", "-c", "tty; stty size"}, TTY: true, Rows: 30, Cols: 100}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	result, err := handle.Wait(func(_ job.Stream, data []byte) { out.Write(data) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "/dev/pts/") || !strings.HasSuffix(out.String(), "30 100\r\n") || result.ExitCode != 0 {
+		t.Fatalf("got %q and %+v, want the terminal and its size", out.String(), result)
+	}
+	if cmds := n.runs.Current().Commands; len(cmds) != 1 || cmds[0].Command != "sh -c tty; stty size" {
+		t.Fatalf("got run manifest commands %+v, want the exec", cmds)
+	}
+}
+
+func TestExecKilledWithContext(t *testing.T) {
+	n := newTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	// the child of sh must be killed too
+	_, handle, err := n.Exec(ctx, job.Spec{Argv: []string{"sh", "-c", "sleep 60; true"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	result, err := handle.Wait(func(job.Stream, []byte) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Killed || result.ExitCode != -1 || time.Since(start) > 5*time.Second {
+		t.Fatalf("got %+v after %s, want a killed command", result, time.Since(start))
+	}
+}
+
+func TestJobs(t *testing.T) {
+	n := newTestManager(t)
+	ctx := context.Background()
+
+	timed, handle, err := n.Exec(ctx, job.Spec{Argv: []string{"sleep", "60"}}, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handle.Wait(func(job.Stream, []byte) {}); err != nil {
+		t.Fatal(err)
+	}
+	if j, err := n.GetJob(timed.ID); err != nil || j.State != job.TimedOut || j.Result.Error != job.ErrTimedOut.Error() {
+		t.Fatalf("got %+v and %v, want a timed out job", j, err)
+	}
+
+	running, handle, err := n.Exec(ctx, job.Spec{Argv: []string{"sleep", "60"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go handle.Wait(func(job.Stream, []byte) {})
+	if jobs := n.ListJobs(); len(jobs) != 2 || jobs[1].ID != running.ID || jobs[1].State != job.Running || jobs[1].Pid != running.Pid {
+		t.Fatalf("got jobs %+v, want the running one last", jobs)
+	}
+
+	// Cleanup cancels the jobs left
+	n.CancelJobs(ctx)
+	if j, _ := n.GetJob(running.ID); j.State != job.Cancelled || !j.Result.Killed {
+		t.Fatalf("got %+v, want a cancelled job", j)
+	}
+}
+
+type failingTracer struct{}
+
+func (failingTracer) Trace(context.Context, tracer.Options, func(tracer.Snapshot)) error {
+	return errors.New("no tracefs")
+}
+
+func TestSendServerCommandStopsUntracedServer(t *testing.T) {
+	n := newTestManager(t)
+	n.syscalls = tracer.NewSessions(failingTracer{}, n.syscallsLogPath)
+
+	if _, _, err := n.SendServerCommand("sleep 60", 0); err == nil {
+		t.Fatal("started a server without tracing it")
+	}
+	jobs := n.ListJobs()
+	if len(jobs) != 1 || jobs[0].State != job.Cancelled || !jobs[0].Result.Killed {
+		t.Fatalf("got jobs %+v, want the server cancelled", jobs)
+	}
+}
+
+func TestJobLogs(t *testing.T) {
+	n := newTestManager(t)
+	n.syscalls = tracer.NewSessions(tracer.NewFake(), n.syscallsLogPath)
+
+	server, session, err := n.SendServerCommand("echo server; sleep 60", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.StopSyscalls(session.ID)
+	defer n.CancelJobs(context.Background())
+
+	var clients []job.Job
+	for _, word := range []string{"first", "second"} {
+		if _, err := n.SendClientCommand(context.Background(), "echo "+word, 0, func(j job.Job, _ tracer.Session) { clients = append(clients, j) }, func(job.Output) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// each job logs to its own file, named after its role and ID
+	for _, tt := range []struct {
+		j      job.Job
+		role   string
+		output string
+	}{
+		{server, "server", "server"},
+		{clients[0], "client", "first"},
+		{clients[1], "client", "second"},
+	} {
+		want := filepath.Join(n.runs.Dir(run.NodeLogsDir), fmt.Sprintf("node-%s-%s.log", tt.role, tt.j.ID))
+		if got, _ := n.GetJob(tt.j.ID); tt.j.LogPath != want || got.LogPath != want {
+			t.Errorf("got log %s and %s for job %s, want %s", tt.j.LogPath, got.LogPath, tt.j.ID, want)
+		}
+		// the server may not have written its output yet
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			data, err := os.ReadFile(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(data), tt.output) {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("got log %q for job %s, want %q in it", data, tt.j.ID, tt.output)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestTracingEndsWithCommand(t *testing.T) {
+	n := newTestManager(t)
+	n.syscalls = tracer.NewSessions(tracer.NewFake(), n.syscallsLogPath)
+
+	var clientSession tracer.Session
+	if _, err := n.SendClientCommand(context.Background(), "true", 0, func(_ job.Job, s tracer.Session) { clientSession = s }, func(job.Output) {}); err != nil {
+		t.Fatal(err)
+	}
+	_, serverSession, err := n.SendServerCommand("sleep 0.1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stopped := func(id string) bool {
+		for _, s := range n.ListTraceSessions() {
+			if s.ID == id {
+				return !s.StoppedAt.IsZero()
+			}
+		}
+		t.Fatalf("session %s not found", id)
+		return false
+	}
+	if !stopped(clientSession.ID) {
+		t.Error("the session of the client command runs after it exited")
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !stopped(serverSession.ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("the session of the server command runs after it exited")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -2,9 +2,14 @@ package node
 
 import (
 	"context"
+	"fmt"
 	"log"
-	"os/exec"
+	"sync"
+	"sync/atomic"
 
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	"github.com/bookpanda/firecracker-runner-node/internal/protoconv"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
 	proto "github.com/bookpanda/firecracker-runner-node/proto/node/v1"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -12,63 +17,232 @@ import (
 
 type Service interface {
 	proto.NodeServiceServer
+	// Manager returns the manager in use, which Cleanup replaces
+	Manager() *NodeManager
 }
 
 type serviceImpl struct {
 	proto.UnimplementedNodeServiceServer
-	manager *NodeManager
-	log     *zap.Logger
+	log *zap.Logger
+
+	// cleanupMu serializes Cleanup; readers load manager without it
+	cleanupMu sync.Mutex
+	manager   atomic.Pointer[NodeManager]
 }
 
 func NewService(manager *NodeManager, log *zap.Logger) Service {
-	return &serviceImpl{
-		manager: manager,
-		log:     log,
-	}
+	s := &serviceImpl{log: log}
+	s.manager.Store(manager)
+	return s
+}
+
+func (s *serviceImpl) Manager() *NodeManager {
+	return s.manager.Load()
 }
 
 func (s *serviceImpl) SendServerCommand(_ context.Context, req *proto.SendServerCommandNodeRequest) (*proto.SendServerCommandNodeResponse, error) {
-	if err := s.manager.SendServerCommand(req.Command); err != nil {
+	j, session, err := s.Manager().SendServerCommand(req.Command, protoconv.Timeout(req.TimeoutMs))
+	if err != nil {
 		return nil, err
 	}
 
-	return &proto.SendServerCommandNodeResponse{}, nil
+	return &proto.SendServerCommandNodeResponse{Pid: int64(j.Pid), SessionId: session.ID, JobId: j.ID}, nil
 }
 
 func (s *serviceImpl) SendClientCommand(req *proto.SendClientCommandNodeRequest, stream grpc.ServerStreamingServer[proto.SendClientCommandNodeResponse]) error {
-	if err := s.manager.SendClientCommand(req.Command); err != nil {
+	// a client that stops reading cancels the stream, which stops the command
+	var sessionID, jobID string
+	var sendErr error
+	result, err := s.Manager().SendClientCommand(stream.Context(), req.Command, protoconv.Timeout(req.TimeoutMs), func(j job.Job, session tracer.Session) {
+		jobID, sessionID = j.ID, session.ID
+	}, func(out job.Output) {
+		if sendErr != nil {
+			return
+		}
+		sendErr = stream.Send(&proto.SendClientCommandNodeResponse{Output: out.Line, SessionId: sessionID, Stream: proto.OutputStream(out.Stream), JobId: jobID})
+	})
+	if err != nil {
+		return err
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return stream.Send(&proto.SendClientCommandNodeResponse{SessionId: sessionID, Exit: protoconv.CommandExit[*proto.CommandExit](result), JobId: jobID})
+}
+
+func (s *serviceImpl) Exec(stream grpc.BidiStreamingServer[proto.ExecNodeRequest, proto.ExecNodeResponse]) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	start := req.Start
+	if start == nil {
+		return fmt.Errorf("the first request must start the command")
+	}
+
+	// ending the call cancels its context, which kills the command
+	j, handle, err := s.Manager().Exec(stream.Context(), job.Spec{
+		Argv:  start.Argv,
+		Env:   start.Env,
+		Dir:   start.Cwd,
+		TTY:   start.Tty,
+		Rows:  uint16(start.GetSize().GetRows()),
+		Cols:  uint16(start.GetSize().GetCols()),
+		Stdin: start.Stdin,
+	}, protoconv.Timeout(start.TimeoutMs))
+	if err != nil {
 		return err
 	}
 
-	response := &proto.SendClientCommandNodeResponse{Output: "Command finished executing"}
-	if err := stream.Send(response); err != nil {
+	sendErr := stream.Send(&proto.ExecNodeResponse{Pid: int64(j.Pid), JobId: j.ID})
+	go protoconv.ForwardExecInput[*proto.TerminalSize](stream.Recv, handle)
+	result, err := handle.Wait(func(out job.Stream, data []byte) {
+		if sendErr == nil {
+			sendErr = stream.Send(&proto.ExecNodeResponse{Stream: proto.OutputStream(out), Data: data})
+		}
+	})
+	if err != nil {
 		return err
 	}
+	if sendErr != nil {
+		return sendErr
+	}
 
-	return nil
+	return stream.Send(&proto.ExecNodeResponse{Exit: protoconv.CommandExit[*proto.CommandExit](result)})
+}
+
+func (s *serviceImpl) ListJobs(_ context.Context, req *proto.ListJobsNodeRequest) (*proto.ListJobsNodeResponse, error) {
+	jobs := s.Manager().ListJobs()
+
+	res := &proto.ListJobsNodeResponse{Jobs: make([]*proto.Job, 0, len(jobs))}
+	for _, j := range jobs {
+		res.Jobs = append(res.Jobs, jobToProto(j))
+	}
+
+	return res, nil
+}
+
+func (s *serviceImpl) GetJob(_ context.Context, req *proto.GetJobNodeRequest) (*proto.GetJobNodeResponse, error) {
+	j, err := s.Manager().GetJob(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.GetJobNodeResponse{Job: jobToProto(j)}, nil
+}
+
+func (s *serviceImpl) CancelJob(ctx context.Context, req *proto.CancelJobNodeRequest) (*proto.CancelJobNodeResponse, error) {
+	j, err := s.Manager().CancelJob(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CancelJobNodeResponse{Job: jobToProto(j)}, nil
+}
+
+func (s *serviceImpl) TrackSyscalls(_ context.Context, req *proto.TrackSyscallsNodeRequest) (*proto.TrackSyscallsNodeResponse, error) {
+	pids := make([]int, 0, len(req.Pids))
+	for _, pid := range req.Pids {
+		pids = append(pids, int(pid))
+	}
+
+	session, err := s.Manager().TrackSyscalls(pids, req.Syscalls, req.Latency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.TrackSyscallsNodeResponse{Session: traceSessionToProto(session)}, nil
 }
 
 func (s *serviceImpl) StopSyscalls(_ context.Context, req *proto.StopSyscallsNodeRequest) (*proto.StopSyscallsNodeResponse, error) {
-	if err := s.manager.StopSyscalls(); err != nil {
+	if err := s.Manager().StopSyscalls(req.SessionId); err != nil {
 		return nil, err
 	}
 
 	return &proto.StopSyscallsNodeResponse{}, nil
 }
 
-func (s *serviceImpl) Cleanup(_ context.Context, req *proto.CleanupNodeRequest) (*proto.CleanupNodeResponse, error) {
-	log.Printf("Cleaning up node...")
-	cmd := exec.Command("sudo", "pkill", "-f", "iperf3")
-	if err := cmd.Run(); err != nil {
-		log.Printf("Warning: failed to kill iperf3 processes: %v", err)
+func (s *serviceImpl) ListTraceSessions(_ context.Context, req *proto.ListTraceSessionsNodeRequest) (*proto.ListTraceSessionsNodeResponse, error) {
+	sessions := s.Manager().ListTraceSessions()
+
+	res := &proto.ListTraceSessionsNodeResponse{Sessions: make([]*proto.TraceSession, 0, len(sessions))}
+	for _, session := range sessions {
+		res.Sessions = append(res.Sessions, traceSessionToProto(session))
 	}
 
-	cmd = exec.Command("sudo", "pkill", "-f", "sockperf")
-	if err := cmd.Run(); err != nil {
-		log.Printf("Warning: failed to kill sockperf processes: %v", err)
+	return res, nil
+}
+
+func (s *serviceImpl) GetSyscallStats(_ context.Context, req *proto.GetSyscallStatsNodeRequest) (*proto.GetSyscallStatsNodeResponse, error) {
+	stats, err := s.Manager().SyscallStats(req.SessionId, int(req.Pid))
+	if err != nil {
+		return nil, err
 	}
 
-	s.manager = NewManager(s.manager.config)
+	res := &proto.GetSyscallStatsNodeResponse{Stats: make([]*proto.SyscallStats, 0, len(stats))}
+	for _, stat := range stats {
+		res.Stats = append(res.Stats, protoconv.SyscallStats[*proto.SyscallStats](stat))
+	}
+
+	return res, nil
+}
+
+func (s *serviceImpl) WatchSyscalls(req *proto.WatchSyscallsNodeRequest, stream grpc.ServerStreamingServer[proto.WatchSyscallsNodeResponse]) error {
+	return s.Manager().WatchSyscalls(stream.Context(), req.SessionId, int(req.Pid), func(stats tracer.TargetStats) error {
+		return stream.Send(&proto.WatchSyscallsNodeResponse{Stats: protoconv.SyscallStats[*proto.SyscallStats](stats)})
+	})
+}
+
+func (s *serviceImpl) Cleanup(ctx context.Context, req *proto.CleanupNodeRequest) (*proto.CleanupNodeResponse, error) {
+	s.cleanupMu.Lock()
+	defer s.cleanupMu.Unlock()
+
+	log.Printf("Cleaning up node...")
+	old := s.Manager()
+	// ends the traces and watchers of the old manager
+	old.StopSyscalls("")
+	// kills the commands of the old manager, servers included
+	old.CancelJobs(ctx)
+
+	s.manager.Store(NewManager(old.config, old.runs))
 
 	return &proto.CleanupNodeResponse{}, nil
 }
+
+func jobToProto(j job.Job) *proto.Job {
+	res := &proto.Job{
+		Id:        j.ID,
+		Command:   j.Command,
+		Server:    j.Server,
+		Pid:       int64(j.Pid),
+		TimeoutMs: j.Timeout.Milliseconds(),
+		LogPath:   j.LogPath,
+		State:     proto.JobState(j.State),
+		StartedAt: j.StartedAt.UnixMilli(),
+	}
+	if !j.EndedAt.IsZero() {
+		res.EndedAt = j.EndedAt.UnixMilli()
+		res.Exit = protoconv.CommandExit[*proto.CommandExit](j.Result)
+	}
+	return res
+}
+
+func traceSessionToProto(session tracer.Session) *proto.TraceSession {
+	pids := make([]int64, 0, len(session.Targets))
+	for _, target := range session.Targets {
+		pids = append(pids, int64(target.PID))
+	}
+
+	res := &proto.TraceSession{
+		Id:        session.ID,
+		Pids:      pids,
+		Syscalls:  session.Syscalls,
+		Latency:   session.Latency,
+		StartedAt: session.StartedAt.UnixMilli(),
+	}
+	if !session.StoppedAt.IsZero() {
+		res.StoppedAt = session.StoppedAt.UnixMilli()
+	}
+	return res
+}
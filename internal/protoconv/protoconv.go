@@ -0,0 +1,173 @@
+// Package protoconv converts jobs and syscall stats to and from the messages
+// of VmService and NodeService. The two services declare messages of the
+// same shape, such as CommandExit, in their own proto packages, so the
+// conversions read them through their getters and fill them in by field
+// name.
+package protoconv
+
+import (
+	"fmt"
+	"log"
+	"syscall"
+	"time"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Timeout converts a timeout in milliseconds, 0 for none.
+func Timeout(ms int64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
+
+// unixMilli is 0 for the zero time.
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// CommandExit returns a CommandExit message of type M for result.
+func CommandExit[M proto.Message](result job.Result) M {
+	m := newMessage[M]()
+	set(m, "exitCode", protoreflect.ValueOfInt32(int32(result.ExitCode)))
+	set(m, "durationMs", protoreflect.ValueOfInt64(result.Duration.Milliseconds()))
+	set(m, "killed", protoreflect.ValueOfBool(result.Killed))
+	set(m, "error", protoreflect.ValueOfString(result.Error))
+	set(m, "signal", protoreflect.ValueOfString(result.Signal))
+	return m.Interface().(M)
+}
+
+// SyscallStats returns a SyscallStats message of type M for stats. Its ip
+// field, if it has one, is the name of the target.
+func SyscallStats[M proto.Message](stats tracer.TargetStats) M {
+	m := newMessage[M]()
+	set(m, "sessionId", protoreflect.ValueOfString(stats.Session))
+	if m.Descriptor().Fields().ByName("ip") != nil {
+		set(m, "ip", protoreflect.ValueOfString(stats.Target.Name))
+	}
+	set(m, "pid", protoreflect.ValueOfInt64(int64(stats.Target.PID)))
+	set(m, "time", protoreflect.ValueOfInt64(unixMilli(stats.Snapshot.Time)))
+	set(m, "intervalMs", protoreflect.ValueOfInt64(stats.Snapshot.Period.Milliseconds()))
+	set(m, "final", protoreflect.ValueOfBool(stats.Snapshot.Final))
+	appendCounts(m, "interval", stats.Snapshot.Interval)
+	appendCounts(m, "total", stats.Snapshot.Total)
+	appendLatencies(m, "intervalLatency", stats.Snapshot.IntervalLatency)
+	appendLatencies(m, "totalLatency", stats.Snapshot.TotalLatency)
+	return m.Interface().(M)
+}
+
+// appendCounts appends counts to the repeated SyscallCount field name of m,
+// most called first.
+func appendCounts(m protoreflect.Message, name string, counts tracer.Counts) {
+	list := m.Mutable(field(m, name)).List()
+	for _, c := range counts.BySyscall() {
+		count := list.NewElement().Message()
+		set(count, "comm", protoreflect.ValueOfString(c.Comm))
+		set(count, "syscall", protoreflect.ValueOfString(c.Syscall))
+		set(count, "count", protoreflect.ValueOfUint64(c.Count))
+		list.Append(protoreflect.ValueOfMessage(count))
+	}
+}
+
+// appendLatencies appends latencies to the repeated SyscallLatency field name
+// of m, most time spent first. Only the buckets with samples are appended.
+func appendLatencies(m protoreflect.Message, name string, latencies tracer.Latencies) {
+	if latencies == nil {
+		return
+	}
+
+	list := m.Mutable(field(m, name)).List()
+	for _, l := range latencies.ByThread() {
+		latency := list.NewElement().Message()
+		set(latency, "comm", protoreflect.ValueOfString(l.Comm))
+		set(latency, "tid", protoreflect.ValueOfInt64(int64(l.TID)))
+		set(latency, "syscall", protoreflect.ValueOfString(l.Syscall))
+		set(latency, "count", protoreflect.ValueOfUint64(l.Latency.Count))
+		set(latency, "totalNs", protoreflect.ValueOfUint64(uint64(l.Latency.Total.Nanoseconds())))
+
+		buckets := latency.Mutable(field(latency, "buckets")).List()
+		for slot, n := range l.Latency.Buckets {
+			if n == 0 {
+				continue
+			}
+			lo, hi := tracer.BucketRange(slot)
+			bucket := buckets.NewElement().Message()
+			set(bucket, "minNs", protoreflect.ValueOfUint64(lo))
+			set(bucket, "maxNs", protoreflect.ValueOfUint64(hi))
+			set(bucket, "count", protoreflect.ValueOfUint64(n))
+			buckets.Append(protoreflect.ValueOfMessage(bucket))
+		}
+		list.Append(protoreflect.ValueOfMessage(latency))
+	}
+}
+
+func newMessage[M proto.Message]() protoreflect.Message {
+	var zero M
+	return zero.ProtoReflect().Type().New()
+}
+
+// field returns the field name of m. A missing field is a programming error
+// the tests catch, so it panics.
+func field(m protoreflect.Message, name string) protoreflect.FieldDescriptor {
+	fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+	if fd == nil {
+		panic(fmt.Sprintf("protoconv: %s has no field %s", m.Descriptor().FullName(), name))
+	}
+	return fd
+}
+
+func set(m protoreflect.Message, name string, v protoreflect.Value) {
+	m.Set(field(m, name), v)
+}
+
+// TerminalSize is the TerminalSize message of either service.
+type TerminalSize interface {
+	proto.Message
+	GetRows() uint32
+	GetCols() uint32
+}
+
+// ExecRequest is a request of the Exec call of either service after the
+// first, which started the command.
+type ExecRequest[T TerminalSize] interface {
+	GetStdin() []byte
+	GetCloseStdin() bool
+	GetResize() T
+	GetSignal() int32
+}
+
+// ForwardExecInput passes the stdin, terminal resizes and signals of the
+// requests that recv returns on to handle, until recv fails.
+func ForwardExecInput[T TerminalSize, R ExecRequest[T]](recv func() (R, error), handle job.Handle) {
+	for {
+		req, err := recv()
+		if err != nil {
+			return
+		}
+
+		if stdin := req.GetStdin(); len(stdin) > 0 {
+			if err := handle.Write(stdin); err != nil {
+				log.Printf("Warning: failed to write to stdin of PID %d: %v", handle.Pid(), err)
+			}
+		}
+		if req.GetCloseStdin() {
+			if err := handle.CloseStdin(); err != nil {
+				log.Printf("Warning: failed to close stdin of PID %d: %v", handle.Pid(), err)
+			}
+		}
+		if resize := req.GetResize(); resize.ProtoReflect().IsValid() {
+			if err := handle.Resize(uint16(resize.GetRows()), uint16(resize.GetCols())); err != nil {
+				log.Printf("Warning: failed to resize terminal of PID %d: %v", handle.Pid(), err)
+			}
+		}
+		if signal := req.GetSignal(); signal != 0 {
+			if err := handle.Signal(syscall.Signal(signal)); err != nil {
+				log.Printf("Warning: failed to send signal %d to PID %d: %v", signal, handle.Pid(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package protoconv
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
+	nodeproto "github.com/bookpanda/firecracker-runner-node/proto/node/v1"
+	vmproto "github.com/bookpanda/firecracker-runner-node/proto/vm/v1"
+)
+
+func TestCommandExit(t *testing.T) {
+	result := job.Result{ExitCode: -1, Duration: 1500 * time.Millisecond, Killed: true, Signal: "killed", Error: "timed out"}
+
+	vm := CommandExit[*vmproto.CommandExit](result)
+	if vm.ExitCode != -1 || vm.DurationMs != 1500 || !vm.Killed || vm.Signal != "killed" || vm.Error != "timed out" {
+		t.Errorf("got %v", vm)
+	}
+	node := CommandExit[*nodeproto.CommandExit](result)
+	if node.ExitCode != -1 || node.DurationMs != 1500 || !node.Killed || node.Signal != "killed" || node.Error != "timed out" {
+		t.Errorf("got %v", node)
+	}
+}
+
+func TestSyscallStats(t *testing.T) {
+	latency := tracer.Latency{Count: 3, Total: 30 * time.Microsecond}
+	latency.Buckets[4] = 2
+	latency.Buckets[6] = 1
+	stats := tracer.TargetStats{
+		Session: "s1",
+		Target:  tracer.Target{Name: "10.0.0.2", PID: 42},
+		Snapshot: tracer.Snapshot{
+			Time:            time.UnixMilli(1700000000000),
+			Period:          time.Second,
+			Final:           true,
+			Interval:        tracer.Counts{{PID: 42, TID: 42, Comm: "iperf3", Syscall: "read"}: 5},
+			Total:           tracer.Counts{{PID: 42, TID: 42, Comm: "iperf3", Syscall: "read"}: 9, {PID: 42, TID: 43, Comm: "iperf3", Syscall: "write"}: 20},
+			TotalLatency:    tracer.Latencies{{PID: 42, TID: 43, Comm: "iperf3", Syscall: "write"}: latency},
+			IntervalLatency: nil,
+		},
+	}
+	lo4, hi4 := tracer.BucketRange(4)
+	lo6, hi6 := tracer.BucketRange(6)
+
+	vm := SyscallStats[*vmproto.SyscallStats](stats)
+	if vm.Ip != "10.0.0.2" || vm.SessionId != "s1" || vm.Pid != 42 || vm.Time != 1700000000000 || vm.IntervalMs != 1000 || !vm.Final {
+		t.Errorf("got %v", vm)
+	}
+	if len(vm.Interval) != 1 || vm.Interval[0].Syscall != "read" || vm.Interval[0].Count != 5 {
+		t.Errorf("got interval %v", vm.Interval)
+	}
+	if len(vm.Total) != 2 || vm.Total[0].Syscall != "write" || vm.Total[0].Count != 20 || vm.Total[1].Count != 9 {
+		t.Errorf("got total %v, want the most called first", vm.Total)
+	}
+	if len(vm.IntervalLatency) != 0 || len(vm.TotalLatency) != 1 {
+		t.Fatalf("got latencies %v and %v", vm.IntervalLatency, vm.TotalLatency)
+	}
+	l := vm.TotalLatency[0]
+	if l.Comm != "iperf3" || l.Tid != 43 || l.Syscall != "write" || l.Count != 3 || l.TotalNs != 30000 {
+		t.Errorf("got latency %v", l)
+	}
+	var buckets [][3]uint64
+	for _, b := range l.Buckets {
+		buckets = append(buckets, [3]uint64{b.MinNs, b.MaxNs, b.Count})
+	}
+	if want := [][3]uint64{{lo4, hi4, 2}, {lo6, hi6, 1}}; !reflect.DeepEqual(buckets, want) {
+		t.Errorf("got buckets %v, want %v", buckets, want)
+	}
+
+	// NodeService has no ip field
+	node := SyscallStats[*nodeproto.SyscallStats](stats)
+	if node.SessionId != "s1" || node.Pid != 42 || len(node.Total) != 2 || len(node.TotalLatency) != 1 || len(node.TotalLatency[0].Buckets) != 2 {
+		t.Errorf("got %v", node)
+	}
+}
+
+type fakeHandle struct {
+	job.Handle
+	stdin   []byte
+	closed  bool
+	sizes   [][2]uint16
+	signals []syscall.Signal
+}
+
+func (h *fakeHandle) Pid() int { return 1 }
+
+func (h *fakeHandle) Write(data []byte) error {
+	h.stdin = append(h.stdin, data...)
+	return nil
+}
+
+func (h *fakeHandle) CloseStdin() error {
+	h.closed = true
+	return nil
+}
+
+func (h *fakeHandle) Resize(rows, cols uint16) error {
+	h.sizes = append(h.sizes, [2]uint16{rows, cols})
+	return nil
+}
+
+func (h *fakeHandle) Signal(sig syscall.Signal) error {
+	h.signals = append(h.signals, sig)
+	return errors.New("no such process")
+}
+
+func TestForwardExecInput(t *testing.T) {
+	requests := []*nodeproto.ExecNodeRequest{
+		{Stdin: []byte("ls\n")},
+		{Resize: &nodeproto.TerminalSize{Rows: 24, Cols: 80}},
+		{Stdin: []byte("exit\n"), CloseStdin: true},
+		{Signal: int32(syscall.SIGTERM)},
+		{},
+	}
+	recv := func() (*nodeproto.ExecNodeRequest, error) {
+		if len(requests) == 0 {
+			return nil, io.EOF
+		}
+		req := requests[0]
+		requests = requests[1:]
+		return req, nil
+	}
+
+	handle := &fakeHandle{}
+	ForwardExecInput[*nodeproto.TerminalSize](recv, handle)
+	if string(handle.stdin) != "ls\nexit\n" || !handle.closed {
+		t.Errorf("got stdin %q, closed %v", handle.stdin, handle.closed)
+	}
+	if !reflect.DeepEqual(handle.sizes, [][2]uint16{{24, 80}}) {
+		t.Errorf("got sizes %v", handle.sizes)
+	}
+	// a failed signal does not stop the forwarding
+	if !reflect.DeepEqual(handle.signals, []syscall.Signal{syscall.SIGTERM}) || len(requests) != 0 {
+		t.Errorf("got signals %v with %d requests left", handle.signals, len(requests))
+	}
+}
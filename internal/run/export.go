@@ -0,0 +1,90 @@
+package run
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Export writes the directory of the run with the given ID, or of the
+// current run if id is empty, to w as a tar.gz archive. Every path in the
+// archive starts with the run's ID. Files still being written are cut off at
+// their size when they are reached, and files truncated since are padded with
+// zeros to that size.
+func (m *Manager) Export(id string, w io.Writer) error {
+	manifest, err := m.Manifest(id)
+	if err != nil {
+		return err
+	}
+	dir := m.Path(manifest.ID)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+		// the manifest is only ever replaced whole
+		if strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(manifest.ID, rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := m.open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		n, err := io.CopyN(tw, f, header.Size)
+		if err == io.EOF {
+			// the header already promised header.Size bytes
+			_, err = io.CopyN(tw, zeros{}, header.Size-n)
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to archive run %s: %v", manifest.ID, err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to archive run %s: %v", manifest.ID, err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to archive run %s: %v", manifest.ID, err)
+	}
+	return nil
+}
+
+// zeros reads an endless run of zero bytes.
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
@@ -0,0 +1,247 @@
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+const manifestFile = "manifest.json"
+
+// The directories of a run, named after the fixed directories the runner
+// used to write to its working directory.
+const (
+	VMLogsDir     = "vm-logs"
+	VMTestDir     = "vm-test"
+	VMSyscallsDir = "vm-syscalls"
+	NodeLogsDir   = "node-logs"
+)
+
+var runDirs = []string{VMLogsDir, VMTestDir, VMSyscallsDir, NodeLogsDir}
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Manifest records what a run used, so that its results can be reproduced.
+// It is kept in manifest.json in the run's directory.
+type Manifest struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"startedAt"`
+	VMs       []VM      `json:"vms"`
+	Commands  []Command `json:"commands"`
+}
+
+// Image is a kernel or rootfs image, with its size and modification time
+// when the VM booted to tell apart images rebuilt at the same path.
+type Image struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size,omitempty"`
+	ModTime time.Time `json:"modTime,omitempty"`
+}
+
+// NewImage describes the image at path. An image that cannot be read is
+// described by its path only.
+func NewImage(path string) Image {
+	image := Image{Path: path}
+	if info, err := os.Stat(path); err == nil {
+		image.Size = info.Size()
+		image.ModTime = info.ModTime()
+	}
+	return image
+}
+
+// VM is a VM created or restored during a run.
+type VM struct {
+	IP         string    `json:"ip"`
+	Kernel     Image     `json:"kernel"`
+	Rootfs     Image     `json:"rootfs"`
+	VcpuCount  int64     `json:"vcpuCount"`
+	MemSizeMib int64     `json:"memSizeMib"`
+	KernelArgs string    `json:"kernelArgs,omitempty"`
+	Snapshot   string    `json:"snapshot,omitempty"` // the snapshot it was restored from
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Command is a command sent to a VM or the node during a run.
+type Command struct {
+	Target    string    `json:"target"` // the IP of the VM, or "node"
+	Command   string    `json:"command"`
+	Server    bool      `json:"server"` // runs in the background, a client runs to completion
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// Manager keeps every run in its own directory under root. There is always
+// a current run, which the artifacts of the runner go to; starting a run
+// ends the previous one.
+type Manager struct {
+	root string
+	// open opens the files Export archives
+	open func(name string) (*os.File, error)
+
+	mu      sync.Mutex
+	current *Manifest
+}
+
+// NewManager starts a run under root.
+func NewManager(root string) (*Manager, error) {
+	m := &Manager{root: root, open: os.Open}
+	if _, err := m.Start(""); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Start begins a run with the given ID, or a generated one if id is empty,
+// and creates its directories.
+func (m *Manager) Start(id string) (Manifest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if id == "" {
+		id = m.newID(now)
+	} else if !validID.MatchString(id) {
+		return Manifest{}, fmt.Errorf("invalid run ID %q", id)
+	} else if _, err := os.Stat(filepath.Join(m.root, id)); err == nil {
+		return Manifest{}, fmt.Errorf("run %s already exists", id)
+	}
+
+	for _, dir := range runDirs {
+		if err := os.MkdirAll(filepath.Join(m.root, id, dir), 0755); err != nil {
+			return Manifest{}, fmt.Errorf("failed to create run directory: %v", err)
+		}
+	}
+
+	manifest := &Manifest{ID: id, StartedAt: now, VMs: []VM{}, Commands: []Command{}}
+	if err := m.save(manifest); err != nil {
+		return Manifest{}, err
+	}
+	m.current = manifest
+
+	log.Printf("Started run %s in %s", id, m.Path(id))
+	return *manifest, nil
+}
+
+// newID returns an unused ID for a run started at now. The caller holds
+// m.mu.
+func (m *Manager) newID(now time.Time) string {
+	base := "run-" + now.Format("20060102-150405")
+	id := base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(m.root, id)); errors.Is(err, os.ErrNotExist) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// Current returns the manifest of the current run.
+func (m *Manager) Current() Manifest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return *m.current
+}
+
+// Path returns the directory of the run with the given ID.
+func (m *Manager) Path(id string) string {
+	return filepath.Join(m.root, id)
+}
+
+// Dir returns the directory with the given name, such as VMLogsDir, in the
+// current run.
+func (m *Manager) Dir(name string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return filepath.Join(m.root, m.current.ID, name)
+}
+
+// AddVM records vm in the manifest of the current run.
+func (m *Manager) AddVM(vm VM) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.current.VMs = append(m.current.VMs, vm)
+	return m.save(m.current)
+}
+
+// AddCommand records cmd in the manifest of the current run.
+func (m *Manager) AddCommand(cmd Command) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.current.Commands = append(m.current.Commands, cmd)
+	return m.save(m.current)
+}
+
+// Manifest returns the manifest of the run with the given ID, or of the
+// current run if id is empty.
+func (m *Manager) Manifest(id string) (Manifest, error) {
+	if id == "" {
+		return m.Current(), nil
+	}
+	if !validID.MatchString(id) {
+		return Manifest{}, fmt.Errorf("invalid run ID %q", id)
+	}
+
+	data, err := os.ReadFile(filepath.Join(m.root, id, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return Manifest{}, fmt.Errorf("run %s not found", id)
+	}
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to read manifest of run %s: %v", id, err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse manifest of run %s: %v", id, err)
+	}
+	return manifest, nil
+}
+
+// List returns the manifests of every run under the root, oldest first.
+func (m *Manager) List() ([]Manifest, error) {
+	entries, err := os.ReadDir(m.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs directory: %v", err)
+	}
+
+	var manifests []Manifest
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		manifest, err := m.Manifest(entry.Name())
+		if err != nil {
+			log.Printf("Skipping run %s: %v", entry.Name(), err)
+			continue
+		}
+		manifests = append(manifests, manifest)
+	}
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].StartedAt.Before(manifests[j].StartedAt) })
+	return manifests, nil
+}
+
+// save writes manifest to a temporary file and renames it over the old one.
+// The caller holds m.mu.
+func (m *Manager) save(manifest *Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %v", err)
+	}
+
+	path := filepath.Join(m.root, manifest.ID, manifestFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write manifest: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package run
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManagerKeepsRunsApart(t *testing.T) {
+	root := t.TempDir()
+	m, err := NewManager(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := m.Current()
+
+	logPath := filepath.Join(m.Dir(VMLogsDir), "vm-192.168.100.2.log")
+	if err := os.WriteFile(logPath, []byte("first run\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddVM(VM{IP: "192.168.100.2", Kernel: Image{Path: "vmlinux"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := m.Start("iperf-baseline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Start("iperf-baseline"); err == nil {
+		t.Fatal("started a run with the ID of an existing run")
+	}
+	if _, err := m.Start("../escape"); err == nil {
+		t.Fatal("started a run outside the root")
+	}
+	if err := m.AddCommand(Command{Target: "node", Command: "iperf3 -s", Server: true, StartedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if m.Dir(VMLogsDir) != filepath.Join(root, "iperf-baseline", VMLogsDir) {
+		t.Fatalf("got %s as the log directory of the new run", m.Dir(VMLogsDir))
+	}
+
+	runs, err := m.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ID != first.ID || runs[1].ID != second.ID {
+		t.Fatalf("got runs %+v, want %s then %s", runs, first.ID, second.ID)
+	}
+	if len(runs[0].VMs) != 1 || len(runs[0].Commands) != 0 || len(runs[1].VMs) != 0 || len(runs[1].Commands) != 1 {
+		t.Fatalf("got manifests %+v, want the VM in the first run and the command in the second", runs)
+	}
+}
+
+// readArchive exports the run with the given ID and returns the content of
+// every file in the archive by its name.
+func readArchive(t *testing.T, m *Manager, id string) map[string]string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := m.Export(id, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = string(data)
+	}
+}
+
+func TestManagerExport(t *testing.T) {
+	m, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := m.Current().ID
+	if err := os.WriteFile(filepath.Join(m.Dir(VMTestDir), "vm-192.168.100.2.log"), []byte("[OUTPUT] done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readArchive(t, m, "")
+	if got := files[id+"/vm-test/vm-192.168.100.2.log"]; got != "[OUTPUT] done\n" {
+		t.Fatalf("got test log %q in the archive", got)
+	}
+	if _, ok := files[id+"/manifest.json"]; !ok || len(files) != 2 {
+		t.Fatalf("got files %v, want the test log and the manifest", files)
+	}
+
+	if err := m.Export("no-such-run", io.Discard); err == nil {
+		t.Fatal("exported a run that does not exist")
+	}
+}
+
+func TestManagerExportTruncatedFile(t *testing.T) {
+	m, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := m.Current().ID
+	path := filepath.Join(m.Dir(VMTestDir), "vm-192.168.100.2.log")
+	if err := os.WriteFile(path, []byte("[OUTPUT] done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	next := filepath.Join(m.Dir(VMTestDir), "vm-192.168.100.3.log")
+	if err := os.WriteFile(next, []byte("[OUTPUT] next\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the log shrinks after the walk reached it
+	m.open = func(name string) (*os.File, error) {
+		if name == path {
+			if err := os.Truncate(name, 4); err != nil {
+				return nil, err
+			}
+		}
+		return os.Open(name)
+	}
+	files := readArchive(t, m, "")
+	if got, want := files[id+"/vm-test/vm-192.168.100.2.log"], "[OUT"+strings.Repeat("\x00", 10); got != want {
+		t.Fatalf("got test log %q in the archive, want %q", got, want)
+	}
+	if got := files[id+"/vm-test/vm-192.168.100.3.log"]; got != "[OUTPUT] next\n" {
+		t.Fatalf("got the next log %q in the archive", got)
+	}
+}
@@ -0,0 +1,493 @@
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/rlimit"
+)
+
+const (
+	maxTargets   = 1024
+	maxCounts    = 64 * 1024
+	maxStarts    = 16 * 1024 // syscalls in flight
+	maxLatencies = 4 * maxCounts
+)
+
+// countKey is the key of the counts map, laid out like the key the program
+// builds on its stack.
+type countKey struct {
+	TGID uint32
+	TID  uint32
+	Nr   uint32
+	Pad  uint32
+	Comm [16]byte
+}
+
+// latencyKey is the key of the latencies map, a countKey with the histogram
+// slot in place of the padding.
+type latencyKey struct {
+	TGID uint32
+	TID  uint32
+	Nr   uint32
+	Slot uint32
+	Comm [16]byte
+}
+
+// latencyValue is the value of the latencies map.
+type latencyValue struct {
+	Count   uint64
+	TotalNs uint64
+}
+
+// EBPF counts syscalls with an eBPF program on the raw_syscalls:sys_enter
+// tracepoint. To time syscalls, the program also records when each one
+// entered and a second program on raw_syscalls:sys_exit adds its latency to
+// a histogram. Every trace loads its own programs and maps, so traces do not
+// interfere. The process needs CAP_BPF and CAP_PERFMON, or root.
+type EBPF struct{}
+
+func NewEBPF() *EBPF {
+	return &EBPF{}
+}
+
+var (
+	memlockOnce sync.Once
+	memlockErr  error
+)
+
+func (t *EBPF) Trace(ctx context.Context, opts Options, emit func(Snapshot)) error {
+	opts = opts.withDefaults()
+	if len(opts.PIDs) == 0 {
+		return fmt.Errorf("no processes to trace")
+	}
+	if len(opts.PIDs) > maxTargets {
+		return fmt.Errorf("cannot trace more than %d processes", maxTargets)
+	}
+
+	// kernels before 5.11 charge eBPF maps to the memlock rlimit
+	memlockOnce.Do(func() { memlockErr = rlimit.RemoveMemlock() })
+	if memlockErr != nil {
+		return fmt.Errorf("failed to remove memlock limit: %v", memlockErr)
+	}
+
+	var closers []interface{ Close() error }
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i].Close()
+		}
+	}
+
+	targets, err := ebpf.NewMap(&ebpf.MapSpec{
+		Name:       "targets",
+		Type:       ebpf.Hash,
+		KeySize:    4,
+		ValueSize:  1,
+		MaxEntries: maxTargets,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create targets map: %v", err)
+	}
+	closers = append(closers, targets)
+
+	counts, err := ebpf.NewMap(&ebpf.MapSpec{
+		Name:       "counts",
+		Type:       ebpf.Hash,
+		KeySize:    32,
+		ValueSize:  8,
+		MaxEntries: maxCounts,
+	})
+	if err != nil {
+		closeAll()
+		return fmt.Errorf("failed to create counts map: %v", err)
+	}
+	closers = append(closers, counts)
+
+	for _, pid := range opts.PIDs {
+		if err := targets.Put(uint32(pid), uint8(1)); err != nil {
+			closeAll()
+			return fmt.Errorf("failed to add PID %d to targets: %v", pid, err)
+		}
+	}
+
+	allowedFD := -1
+	if len(opts.Syscalls) > 0 {
+		allowed, err := ebpf.NewMap(&ebpf.MapSpec{
+			Name:       "allowed",
+			Type:       ebpf.Hash,
+			KeySize:    4,
+			ValueSize:  1,
+			MaxEntries: uint32(len(opts.Syscalls)),
+		})
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("failed to create syscall allowlist: %v", err)
+		}
+		closers = append(closers, allowed)
+
+		for _, name := range opts.Syscalls {
+			nr, ok := SyscallNumber(name)
+			if !ok {
+				closeAll()
+				return fmt.Errorf("unknown syscall %q", name)
+			}
+			if err := allowed.Put(nr, uint8(1)); err != nil {
+				closeAll()
+				return fmt.Errorf("failed to add %s to the syscall allowlist: %v", name, err)
+			}
+		}
+		allowedFD = allowed.FD()
+	}
+
+	startsFD := -1
+	var latencies *ebpf.Map
+	if opts.Latency {
+		starts, err := ebpf.NewMap(&ebpf.MapSpec{
+			Name:       "starts",
+			Type:       ebpf.LRUHash, // exit_group and execve may never return
+			KeySize:    4,
+			ValueSize:  8,
+			MaxEntries: maxStarts,
+		})
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("failed to create starts map: %v", err)
+		}
+		closers = append(closers, starts)
+		startsFD = starts.FD()
+
+		latencies, err = ebpf.NewMap(&ebpf.MapSpec{
+			Name:       "latencies",
+			Type:       ebpf.Hash,
+			KeySize:    32,
+			ValueSize:  16,
+			MaxEntries: maxLatencies,
+		})
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("failed to create latencies map: %v", err)
+		}
+		closers = append(closers, latencies)
+
+		exitProg, err := ebpf.NewProgram(&ebpf.ProgramSpec{
+			Name:         "time_syscalls",
+			Type:         ebpf.TracePoint,
+			License:      "GPL",
+			Instructions: timeSyscalls(startsFD, latencies.FD()),
+		})
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("failed to load syscall timer: %v", err)
+		}
+		closers = append(closers, exitProg)
+
+		tp, err := link.Tracepoint("raw_syscalls", "sys_exit", exitProg, nil)
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("failed to attach to raw_syscalls:sys_exit: %v", err)
+		}
+		closers = append(closers, tp)
+	}
+
+	prog, err := ebpf.NewProgram(&ebpf.ProgramSpec{
+		Name:         "count_syscalls",
+		Type:         ebpf.TracePoint,
+		License:      "GPL",
+		Instructions: countSyscalls(targets.FD(), counts.FD(), allowedFD, startsFD),
+	})
+	if err != nil {
+		closeAll()
+		return fmt.Errorf("failed to load syscall counter: %v", err)
+	}
+	closers = append(closers, prog)
+
+	tp, err := link.Tracepoint("raw_syscalls", "sys_enter", prog, nil)
+	if err != nil {
+		closeAll()
+		return fmt.Errorf("failed to attach to raw_syscalls:sys_enter: %v", err)
+	}
+	closers = append(closers, tp)
+
+	go func() {
+		defer closeAll()
+		poll(ctx, opts.Interval, func() (Counts, Latencies, error) {
+			c, err := readCounts(counts)
+			if err != nil || latencies == nil {
+				return c, nil, err
+			}
+			l, err := readLatencies(latencies)
+			return c, l, err
+		}, emit)
+	}()
+	return nil
+}
+
+func readCounts(counts *ebpf.Map) (Counts, error) {
+	var (
+		key   countKey
+		value uint64
+	)
+	result := make(Counts)
+	entries := counts.Iterate()
+	for entries.Next(&key, &value) {
+		result[Key{
+			PID:     int(key.TGID),
+			TID:     int(key.TID),
+			Comm:    string(bytes.TrimRight(key.Comm[:], "\x00")),
+			Syscall: SyscallName(key.Nr),
+		}] = value
+	}
+	if err := entries.Err(); err != nil && !errors.Is(err, ebpf.ErrIterationAborted) {
+		return nil, err
+	}
+	return result, nil
+}
+
+func readLatencies(latencies *ebpf.Map) (Latencies, error) {
+	var (
+		key   latencyKey
+		value latencyValue
+	)
+	result := make(Latencies)
+	entries := latencies.Iterate()
+	for entries.Next(&key, &value) {
+		if key.Slot >= latencySlots {
+			continue
+		}
+		k := Key{
+			PID:     int(key.TGID),
+			TID:     int(key.TID),
+			Comm:    string(bytes.TrimRight(key.Comm[:], "\x00")),
+			Syscall: SyscallName(key.Nr),
+		}
+		l := result[k]
+		l.add(int(key.Slot), value.Count, time.Duration(value.TotalNs))
+		result[k] = l
+	}
+	if err := entries.Err(); err != nil && !errors.Is(err, ebpf.ErrIterationAborted) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// countSyscalls is the tracepoint program. In C it would read
+//
+//	u64 pid_tgid = bpf_get_current_pid_tgid();
+//	struct count_key key = {.tgid = pid_tgid >> 32, .tid = (u32)pid_tgid};
+//	if (!bpf_map_lookup_elem(&targets, &key.tgid))
+//		return 0;
+//	key.nr = ctx->id;
+//	if (filtered && !bpf_map_lookup_elem(&allowed, &key.nr))
+//		return 0;
+//	bpf_get_current_comm(key.comm, sizeof(key.comm));
+//	u64 *count = bpf_map_lookup_elem(&counts, &key);
+//	if (count)
+//		__sync_fetch_and_add(count, 1);
+//	else
+//		bpf_map_update_elem(&counts, &key, &one, BPF_ANY);
+//	if (timed) {
+//		u64 now = bpf_ktime_get_ns();
+//		bpf_map_update_elem(&starts, &key.tid, &now, BPF_ANY);
+//	}
+//	return 0;
+//
+// It is assembled here so the runner needs no clang toolchain. The key lives
+// at fp-32, the initial count at fp-40 and the start time at fp-48. allowed
+// is the allowlist map, or -1 to count every syscall. starts is the map of
+// start times per thread, or -1 to not time syscalls.
+func countSyscalls(targets, counts, allowed, starts int) asm.Instructions {
+	insns := asm.Instructions{
+		asm.Mov.Reg(asm.R6, asm.R1), // r6 = ctx
+
+		asm.FnGetCurrentPidTgid.Call(),
+		asm.StoreMem(asm.RFP, -28, asm.R0, asm.Word), // key.tid
+		asm.RSh.Imm(asm.R0, 32),
+		asm.StoreMem(asm.RFP, -32, asm.R0, asm.Word), // key.tgid
+
+		asm.LoadMapPtr(asm.R1, targets),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -32),
+		asm.FnMapLookupElem.Call(),
+		asm.JEq.Imm(asm.R0, 0, "exit"),
+
+		// struct trace_event_raw_sys_enter { u64 common; long id; ... }
+		asm.LoadMem(asm.R1, asm.R6, 8, asm.DWord),
+		asm.StoreMem(asm.RFP, -24, asm.R1, asm.Word), // key.nr
+		asm.StoreImm(asm.RFP, -20, 0, asm.Word),      // key.pad
+	}
+
+	if allowed >= 0 {
+		insns = append(insns,
+			asm.LoadMapPtr(asm.R1, allowed),
+			asm.Mov.Reg(asm.R2, asm.RFP),
+			asm.Add.Imm(asm.R2, -24),
+			asm.FnMapLookupElem.Call(),
+			asm.JEq.Imm(asm.R0, 0, "exit"),
+		)
+	}
+
+	counted := "exit"
+	if starts >= 0 {
+		counted = "start"
+	}
+
+	insns = append(insns,
+		asm.Mov.Reg(asm.R1, asm.RFP),
+		asm.Add.Imm(asm.R1, -16),
+		asm.Mov.Imm(asm.R2, 16),
+		asm.FnGetCurrentComm.Call(), // key.comm
+
+		asm.LoadMapPtr(asm.R1, counts),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -32),
+		asm.FnMapLookupElem.Call(),
+		asm.JEq.Imm(asm.R0, 0, "insert"),
+		asm.Mov.Imm(asm.R1, 1),
+		asm.StoreXAdd(asm.R0, asm.R1, asm.DWord),
+		asm.Ja.Label(counted),
+
+		asm.StoreImm(asm.RFP, -40, 1, asm.DWord).WithSymbol("insert"),
+		asm.LoadMapPtr(asm.R1, counts),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -32),
+		asm.Mov.Reg(asm.R3, asm.RFP),
+		asm.Add.Imm(asm.R3, -40),
+		asm.Mov.Imm(asm.R4, 0), // BPF_ANY
+		asm.FnMapUpdateElem.Call(),
+	)
+
+	if starts >= 0 {
+		insns = append(insns,
+			asm.FnKtimeGetNs.Call().WithSymbol("start"),
+			asm.StoreMem(asm.RFP, -48, asm.R0, asm.DWord),
+			asm.LoadMapPtr(asm.R1, starts),
+			asm.Mov.Reg(asm.R2, asm.RFP),
+			asm.Add.Imm(asm.R2, -28), // key.tid
+			asm.Mov.Reg(asm.R3, asm.RFP),
+			asm.Add.Imm(asm.R3, -48),
+			asm.Mov.Imm(asm.R4, 0), // BPF_ANY
+			asm.FnMapUpdateElem.Call(),
+		)
+	}
+
+	return append(insns,
+		asm.Mov.Imm(asm.R0, 0).WithSymbol("exit"),
+		asm.Return(),
+	)
+}
+
+// timeSyscalls is the sys_exit program of timed traces. In C it would read
+//
+//	u64 pid_tgid = bpf_get_current_pid_tgid();
+//	struct latency_key key = {.tgid = pid_tgid >> 32, .tid = (u32)pid_tgid};
+//	u64 *start = bpf_map_lookup_elem(&starts, &key.tid);
+//	if (!start)
+//		return 0;
+//	u64 ns = bpf_ktime_get_ns() - *start;
+//	bpf_map_delete_elem(&starts, &key.tid);
+//	key.nr = ctx->id;
+//	key.slot = ns ? 64 - __builtin_clzll(ns) : 0;
+//	bpf_get_current_comm(key.comm, sizeof(key.comm));
+//	struct latency_value *value = bpf_map_lookup_elem(&latencies, &key);
+//	if (value) {
+//		__sync_fetch_and_add(&value->count, 1);
+//		__sync_fetch_and_add(&value->total_ns, ns);
+//	} else {
+//		struct latency_value init = {1, ns};
+//		bpf_map_update_elem(&latencies, &key, &init, BPF_ANY);
+//	}
+//	return 0;
+//
+// Only the syscalls that countSyscalls let through have a start time, so
+// this needs no filters of its own. The key lives at fp-32, the initial
+// value at fp-48.
+func timeSyscalls(starts, latencies int) asm.Instructions {
+	insns := asm.Instructions{
+		asm.Mov.Reg(asm.R6, asm.R1), // r6 = ctx
+
+		asm.FnGetCurrentPidTgid.Call(),
+		asm.StoreMem(asm.RFP, -28, asm.R0, asm.Word), // key.tid
+		asm.RSh.Imm(asm.R0, 32),
+		asm.StoreMem(asm.RFP, -32, asm.R0, asm.Word), // key.tgid
+
+		asm.LoadMapPtr(asm.R1, starts),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -28),
+		asm.FnMapLookupElem.Call(),
+		asm.JEq.Imm(asm.R0, 0, "exit"),
+		asm.LoadMem(asm.R7, asm.R0, 0, asm.DWord), // r7 = *start
+
+		asm.FnKtimeGetNs.Call(),
+		asm.Sub.Reg(asm.R0, asm.R7),
+		asm.Mov.Reg(asm.R7, asm.R0), // r7 = ns
+
+		asm.LoadMapPtr(asm.R1, starts),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -28),
+		asm.FnMapDeleteElem.Call(),
+
+		// struct trace_event_raw_sys_exit { u64 common; long id; long ret; }
+		asm.LoadMem(asm.R1, asm.R6, 8, asm.DWord),
+		asm.StoreMem(asm.RFP, -24, asm.R1, asm.Word), // key.nr
+
+		// r8 = slot, found by halving the bits left to search, as the
+		// verifier rejects loops on older kernels
+		asm.Mov.Imm(asm.R8, 0),
+		asm.JEq.Imm(asm.R7, 0, "slot"),
+		asm.Mov.Imm(asm.R8, 1),
+		asm.Mov.Reg(asm.R2, asm.R7),
+	}
+	for i, shift := range []int32{32, 16, 8, 4, 2, 1} {
+		next := "slot"
+		if i < 5 {
+			next = fmt.Sprintf("shift%d", shift/2)
+		}
+		insns = append(insns,
+			asm.Mov.Reg(asm.R1, asm.R2).WithSymbol(fmt.Sprintf("shift%d", shift)),
+			asm.RSh.Imm(asm.R1, shift),
+			asm.JEq.Imm(asm.R1, 0, next),
+			asm.Mov.Reg(asm.R2, asm.R1),
+			asm.Add.Imm(asm.R8, shift),
+		)
+	}
+
+	return append(insns,
+		asm.StoreMem(asm.RFP, -20, asm.R8, asm.Word).WithSymbol("slot"), // key.slot
+
+		asm.Mov.Reg(asm.R1, asm.RFP),
+		asm.Add.Imm(asm.R1, -16),
+		asm.Mov.Imm(asm.R2, 16),
+		asm.FnGetCurrentComm.Call(), // key.comm
+
+		asm.LoadMapPtr(asm.R1, latencies),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -32),
+		asm.FnMapLookupElem.Call(),
+		asm.JEq.Imm(asm.R0, 0, "insert"),
+		asm.Mov.Imm(asm.R1, 1),
+		asm.StoreXAdd(asm.R0, asm.R1, asm.DWord), // value->count
+		asm.Add.Imm(asm.R0, 8),
+		asm.StoreXAdd(asm.R0, asm.R7, asm.DWord), // value->total_ns
+		asm.Ja.Label("exit"),
+
+		asm.StoreImm(asm.RFP, -48, 1, asm.DWord).WithSymbol("insert"),
+		asm.StoreMem(asm.RFP, -40, asm.R7, asm.DWord),
+		asm.LoadMapPtr(asm.R1, latencies),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -32),
+		asm.Mov.Reg(asm.R3, asm.RFP),
+		asm.Add.Imm(asm.R3, -48),
+		asm.Mov.Imm(asm.R4, 0), // BPF_ANY
+		asm.FnMapUpdateElem.Call(),
+
+		asm.Mov.Imm(asm.R0, 0).WithSymbol("exit"),
+		asm.Return(),
+	)
+}
@@ -0,0 +1,114 @@
+package tracer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Fake is a Tracer for tests. It counts the syscalls passed to Add, and
+// times those passed to AddLatency, for every running trace of the calling
+// PID.
+type Fake struct {
+	mu     sync.Mutex
+	traces map[*fakeTrace]struct{}
+}
+
+type fakeTrace struct {
+	pids      map[int]bool
+	syscalls  map[string]bool // nil for every syscall
+	counts    Counts
+	latencies Latencies // nil unless Options.Latency
+}
+
+func NewFake() *Fake {
+	return &Fake{traces: make(map[*fakeTrace]struct{})}
+}
+
+func (f *Fake) Trace(ctx context.Context, opts Options, emit func(Snapshot)) error {
+	opts = opts.withDefaults()
+	trace := &fakeTrace{pids: make(map[int]bool), counts: make(Counts)}
+	if opts.Latency {
+		trace.latencies = make(Latencies)
+	}
+	for _, pid := range opts.PIDs {
+		trace.pids[pid] = true
+	}
+	if len(opts.Syscalls) > 0 {
+		trace.syscalls = make(map[string]bool)
+		for _, syscall := range opts.Syscalls {
+			trace.syscalls[syscall] = true
+		}
+	}
+
+	f.mu.Lock()
+	f.traces[trace] = struct{}{}
+	f.mu.Unlock()
+
+	go func() {
+		poll(ctx, opts.Interval, func() (Counts, Latencies, error) {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+
+			counts := make(Counts, len(trace.counts))
+			for key, n := range trace.counts {
+				counts[key] = n
+			}
+			if trace.latencies == nil {
+				return counts, nil, nil
+			}
+			latencies := make(Latencies, len(trace.latencies))
+			for key, l := range trace.latencies {
+				latencies[key] = l
+			}
+			return counts, latencies, nil
+		}, emit)
+
+		f.mu.Lock()
+		delete(f.traces, trace)
+		f.mu.Unlock()
+	}()
+	return nil
+}
+
+// Add records n calls of syscall by thread tid of process pid in every trace
+// of pid that does not filter the syscall out.
+func (f *Fake) Add(pid, tid int, comm, syscall string, n uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := Key{PID: pid, TID: tid, Comm: comm, Syscall: syscall}
+	for trace := range f.traces {
+		if trace.traces(pid, syscall) {
+			trace.counts[key] += n
+		}
+	}
+}
+
+// AddLatency records a call of syscall by thread tid of process pid that
+// took d in every trace of pid that times syscalls and does not filter the
+// syscall out. It does not count the call; see Add.
+func (f *Fake) AddLatency(pid, tid int, comm, syscall string, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := Key{PID: pid, TID: tid, Comm: comm, Syscall: syscall}
+	for trace := range f.traces {
+		if trace.latencies != nil && trace.traces(pid, syscall) {
+			l := trace.latencies[key]
+			l.add(latencySlot(uint64(d.Nanoseconds())), 1, d)
+			trace.latencies[key] = l
+		}
+	}
+}
+
+func (t *fakeTrace) traces(pid int, syscall string) bool {
+	return t.pids[pid] && (t.syscalls == nil || t.syscalls[syscall])
+}
+
+// Traces returns the number of running traces.
+func (f *Fake) Traces() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.traces)
+}
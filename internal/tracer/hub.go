@@ -0,0 +1,107 @@
+package tracer
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// watchBuffer is how many updates a watcher may fall behind before updates
+// are dropped for it.
+const watchBuffer = 64
+
+// Hub fans the updates of a set of traces out to any number of watchers.
+// Once its context is done and every trace has sent its last update, the
+// hub closes the channels of its watchers.
+type Hub[T any] struct {
+	mu       sync.Mutex
+	watchers map[chan T]struct{}
+	active   int  // traces that have not sent their last update
+	stopped  bool // the context is done
+	closed   bool
+}
+
+func NewHub[T any](ctx context.Context) *Hub[T] {
+	h := &Hub[T]{watchers: make(map[chan T]struct{})}
+	context.AfterFunc(ctx, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.stopped = true
+		h.closeIfDone()
+	})
+	return h
+}
+
+// Add registers a trace that will publish to the hub. Done must be called
+// once it has published its last update.
+func (h *Hub[T]) Add() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.active++
+}
+
+func (h *Hub[T]) Done() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.active--
+	h.closeIfDone()
+}
+
+// Publish sends update to every watcher. A watcher that has fallen too far
+// behind misses the update rather than stalling the trace.
+func (h *Hub[T]) Publish(update T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for watcher := range h.watchers {
+		select {
+		case watcher <- update:
+		default:
+			log.Printf("Dropping trace update for a slow watcher")
+		}
+	}
+}
+
+// Watch returns a channel with the updates published from now on. It is
+// closed once the traces are over, or when stop is called.
+func (h *Hub[T]) Watch() (updates <-chan T, stop func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	watcher := make(chan T, watchBuffer)
+	if h.closed {
+		close(watcher)
+		return watcher, func() {}
+	}
+
+	h.watchers[watcher] = struct{}{}
+	return watcher, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.watchers[watcher]; ok {
+			delete(h.watchers, watcher)
+			close(watcher)
+		}
+	}
+}
+
+// Watchers returns the number of watchers.
+func (h *Hub[T]) Watchers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.watchers)
+}
+
+// closeIfDone closes the watchers once no more updates can come. The caller
+// holds h.mu.
+func (h *Hub[T]) closeIfDone() {
+	if h.closed || !h.stopped || h.active > 0 {
+		return
+	}
+
+	h.closed = true
+	for watcher := range h.watchers {
+		delete(h.watchers, watcher)
+		close(watcher)
+	}
+}
@@ -0,0 +1,153 @@
+package tracer
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+	"time"
+)
+
+// latencySlots is the number of histogram buckets: one for 0ns and one per
+// power of two up to 2^64ns.
+const latencySlots = 65
+
+// Latency is the log2 histogram of the time calls of a syscall took from
+// sys_enter to sys_exit. Buckets[0] counts the calls that took 0ns,
+// Buckets[i] those that took [2^(i-1), 2^i) ns, like bpftrace's hist().
+type Latency struct {
+	Count   uint64
+	Total   time.Duration
+	Buckets [latencySlots]uint64
+}
+
+// Latencies holds the latency histogram per thread and syscall.
+type Latencies map[Key]Latency
+
+// latencySlot returns the bucket of a call that took ns nanoseconds.
+func latencySlot(ns uint64) int {
+	return bits.Len64(ns)
+}
+
+// BucketRange returns the smallest and largest latency in nanoseconds that
+// Buckets[slot] counts.
+func BucketRange(slot int) (lo, hi uint64) {
+	if slot == 0 {
+		return 0, 0
+	}
+	lo = 1 << (slot - 1)
+	// wraps around to the largest uint64 for the last slot
+	return lo, lo<<1 - 1
+}
+
+// Mean returns the average time a call took.
+func (l Latency) Mean() time.Duration {
+	if l.Count == 0 {
+		return 0
+	}
+	return l.Total / time.Duration(l.Count)
+}
+
+func (l *Latency) add(slot int, n uint64, total time.Duration) {
+	l.Count += n
+	l.Total += total
+	l.Buckets[slot] += n
+}
+
+func (l Latency) sub(prev Latency) Latency {
+	l.Count -= prev.Count
+	l.Total -= prev.Total
+	for i := range l.Buckets {
+		l.Buckets[i] -= prev.Buckets[i]
+	}
+	return l
+}
+
+// SyscallLatency is the latency histogram of a syscall called by one thread.
+type SyscallLatency struct {
+	Comm    string
+	TID     int
+	Syscall string
+	Latency Latency
+}
+
+// ByThread lists the histograms, most time spent first.
+func (l Latencies) ByThread() []SyscallLatency {
+	latencies := make([]SyscallLatency, 0, len(l))
+	for key, latency := range l {
+		latencies = append(latencies, SyscallLatency{Comm: key.Comm, TID: key.TID, Syscall: key.Syscall, Latency: latency})
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		a, b := latencies[i], latencies[j]
+		if a.Latency.Total != b.Latency.Total {
+			return a.Latency.Total > b.Latency.Total
+		}
+		if a.Comm != b.Comm {
+			return a.Comm < b.Comm
+		}
+		if a.TID != b.TID {
+			return a.TID < b.TID
+		}
+		return a.Syscall < b.Syscall
+	})
+	return latencies
+}
+
+// writeLatencies prints a bpftrace hist() per thread and syscall, least time
+// spent first, followed by the total time spent as a bpftrace sum().
+func writeLatencies(printf func(string, ...any), name string, latencies Latencies) {
+	byThread := latencies.ByThread()
+	sort.SliceStable(byThread, func(i, j int) bool { return byThread[i].Latency.Total < byThread[j].Latency.Total })
+
+	for _, l := range byThread {
+		printf("@%s_latency_ns[%s, %d, tracepoint:syscalls:sys_enter_%s]:\n", name, l.Comm, l.TID, l.Syscall)
+		writeHistogram(printf, l.Latency)
+	}
+	for _, l := range byThread {
+		printf("@%s_time_ns[%s, %d, tracepoint:syscalls:sys_enter_%s]: %d\n", name, l.Comm, l.TID, l.Syscall, l.Latency.Total.Nanoseconds())
+	}
+}
+
+// histogramWidth is the width of the largest bar, as in bpftrace.
+const histogramWidth = 52
+
+// writeHistogram prints the buckets from the first to the last one that is
+// not empty.
+func writeHistogram(printf func(string, ...any), l Latency) {
+	first, last := -1, -1
+	var peak uint64
+	for slot, n := range l.Buckets {
+		if n == 0 {
+			continue
+		}
+		if first < 0 {
+			first = slot
+		}
+		last = slot
+		peak = max(peak, n)
+	}
+
+	for slot := first; slot >= 0 && slot <= last; slot++ {
+		n := l.Buckets[slot]
+		bar := strings.Repeat("@", int(n*histogramWidth/peak))
+		printf("%-16s%8d |%-*s|\n", bucketLabel(slot), n, histogramWidth, bar)
+	}
+	printf("\n")
+}
+
+// bucketLabel names a bucket like bpftrace does, e.g. "[0]", "[2, 4)" or
+// "[1K, 2K)".
+func bucketLabel(slot int) string {
+	switch slot {
+	case 0:
+		return "[0]"
+	case 1:
+		return "[1]"
+	}
+	return fmt.Sprintf("[%s, %s)", powerOfTwo(slot-1), powerOfTwo(slot))
+}
+
+// powerOfTwo formats 2^exp with the suffixes bpftrace uses for powers of 1024.
+func powerOfTwo(exp int) string {
+	return fmt.Sprintf("%d%s", 1<<(exp%10), []string{"", "K", "M", "G", "T", "P", "E"}[exp/10])
+}
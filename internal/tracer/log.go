@@ -0,0 +1,48 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TraceToFile traces with t until ctx is done and writes every snapshot to
+// the file at path, closing it after the final one. emit, if not nil,
+// receives every snapshot as well.
+func TraceToFile(ctx context.Context, t Tracer, opts Options, path string, emit func(Snapshot)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %v", err)
+	}
+	logFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create log file %s: %v", path, err)
+	}
+	fmt.Fprintf(logFile, "Tracing syscalls of PID %v...\n", opts.PIDs)
+	if len(opts.Syscalls) > 0 {
+		fmt.Fprintf(logFile, "Only counting %s\n", strings.Join(opts.Syscalls, ", "))
+	}
+	if opts.Latency {
+		fmt.Fprintf(logFile, "Timing syscalls from sys_enter to sys_exit\n")
+	}
+
+	err = t.Trace(ctx, opts, func(s Snapshot) {
+		if err := WriteSnapshot(logFile, s); err != nil {
+			log.Printf("failed to write syscall counts to %s: %v", path, err)
+		}
+		if emit != nil {
+			emit(s)
+		}
+		if s.Final {
+			logFile.Close()
+			log.Printf("Stopped tracing syscalls of PID %v, logs saved to %s", opts.PIDs, path)
+		}
+	})
+	if err != nil {
+		logFile.Close()
+		return err
+	}
+	return nil
+}
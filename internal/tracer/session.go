@@ -0,0 +1,291 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Target is a process traced by a session. Name is how callers refer to it,
+// e.g. the IP of the VM that the process runs.
+type Target struct {
+	Name string
+	PID  int
+}
+
+// Session is a set of traces that are started and stopped together.
+type Session struct {
+	ID        string
+	Targets   []Target
+	Syscalls  []string // the allowlist, empty for every syscall
+	Latency   bool     // syscalls are timed, see Options.Latency
+	StartedAt time.Time
+	StoppedAt time.Time // zero while running
+}
+
+// TargetStats is the latest snapshot of the trace of a target.
+type TargetStats struct {
+	Session  string
+	Target   Target
+	Snapshot Snapshot
+}
+
+type session struct {
+	info   Session
+	cancel context.CancelFunc
+	hub    *Hub[TargetStats]
+	stats  map[string]*TargetStats // by target name
+}
+
+// Sessions runs tracing sessions, each with its own targets and syscall
+// allowlist, that are stopped independently. The counts of a stopped session
+// stay available until the Sessions are discarded.
+type Sessions struct {
+	tracer  Tracer
+	logPath func(session string, target Target) string
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	latest   string
+}
+
+// NewSessions traces with t and logs every target to the file logPath
+// returns for it.
+func NewSessions(t Tracer, logPath func(session string, target Target) string) *Sessions {
+	return &Sessions{
+		tracer:   t,
+		logPath:  logPath,
+		sessions: make(map[string]*session),
+	}
+}
+
+// Start traces targets until Stop. syscalls is an allowlist of syscall names
+// and SyscallFamilies, empty to trace every syscall. latency also builds
+// latency histograms of the syscalls.
+func (s *Sessions) Start(targets []Target, syscalls []string, latency bool) (Session, error) {
+	if len(targets) == 0 {
+		return Session{}, fmt.Errorf("no targets to trace")
+	}
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		if seen[target.Name] {
+			return Session{}, fmt.Errorf("%s is traced twice", target.Name)
+		}
+		seen[target.Name] = true
+	}
+	syscalls, err := ExpandSyscalls(syscalls)
+	if err != nil {
+		return Session{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sess := &session{
+		info: Session{
+			Targets:   append([]Target(nil), targets...),
+			Syscalls:  syscalls,
+			Latency:   latency,
+			StartedAt: time.Now(),
+		},
+		cancel: cancel,
+		hub:    NewHub[TargetStats](ctx),
+		stats:  make(map[string]*TargetStats),
+	}
+
+	s.mu.Lock()
+	sess.info.ID = s.newID()
+	s.mu.Unlock()
+	for _, target := range targets {
+		sess.stats[target.Name] = &TargetStats{
+			Session:  sess.info.ID,
+			Target:   target,
+			Snapshot: Snapshot{Time: sess.info.StartedAt},
+		}
+	}
+
+	for _, target := range targets {
+		if err := s.trace(ctx, sess, target); err != nil {
+			cancel()
+			return Session{}, fmt.Errorf("failed to trace %s: %v", target.Name, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sess.info.ID] = sess
+	s.latest = sess.info.ID
+
+	log.Printf("Started tracing session %s of %d targets", sess.info.ID, len(targets))
+	return sess.info, nil
+}
+
+func (s *Sessions) trace(ctx context.Context, sess *session, target Target) error {
+	sess.hub.Add()
+	record := func(snap Snapshot) {
+		stats := TargetStats{Session: sess.info.ID, Target: target, Snapshot: snap}
+		s.mu.Lock()
+		sess.stats[target.Name] = &stats
+		s.mu.Unlock()
+
+		sess.hub.Publish(stats)
+		if snap.Final {
+			sess.hub.Done()
+		}
+	}
+
+	opts := Options{PIDs: []int{target.PID}, Syscalls: sess.info.Syscalls, Latency: sess.info.Latency}
+	if err := TraceToFile(ctx, s.tracer, opts, s.logPath(sess.info.ID, target), record); err != nil {
+		sess.hub.Done()
+		return err
+	}
+	return nil
+}
+
+// newID returns an unused session ID. The caller holds s.mu.
+func (s *Sessions) newID() string {
+	for {
+		id := fmt.Sprintf("trace-%d", time.Now().UnixNano())
+		if _, ok := s.sessions[id]; !ok {
+			return id
+		}
+	}
+}
+
+// Stop stops the session with the given ID. Stopping a stopped session
+// succeeds.
+func (s *Sessions) Stop(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return fmt.Errorf("tracing session %s not found", id)
+	}
+	s.stop(sess)
+	return nil
+}
+
+// StopAll stops every running session.
+func (s *Sessions) StopAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sess := range s.sessions {
+		s.stop(sess)
+	}
+}
+
+// stop cancels the traces of sess. The caller holds s.mu.
+func (s *Sessions) stop(sess *session) {
+	if !sess.info.StoppedAt.IsZero() {
+		return
+	}
+	sess.cancel()
+	sess.info.StoppedAt = time.Now()
+	log.Printf("Stopped tracing session %s", sess.info.ID)
+}
+
+// List returns every session, oldest first.
+func (s *Sessions) List() []Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions := make([]Session, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		sessions = append(sessions, sess.info)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartedAt.Before(sessions[j].StartedAt) })
+	return sessions
+}
+
+// Stats returns the latest counts of the target with the given name, or of
+// every target if name is empty, in the session with the given ID, or in the
+// latest session if id is empty.
+func (s *Sessions) Stats(id, name string) ([]TargetStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != "" {
+		stats, ok := sess.stats[name]
+		if !ok {
+			return nil, fmt.Errorf("%s is not traced in session %s", name, sess.info.ID)
+		}
+		return []TargetStats{*stats}, nil
+	}
+
+	all := make([]TargetStats, 0, len(sess.stats))
+	for _, target := range sess.info.Targets {
+		all = append(all, *sess.stats[target.Name])
+	}
+	return all, nil
+}
+
+// Watch calls send with the counts of the target with the given name, or of
+// every target if name is empty, at the end of every interval of the session
+// with the given ID, or of the latest session if id is empty. It returns once
+// ctx is done, send fails, or the session has stopped and the final counts
+// are sent.
+func (s *Sessions) Watch(ctx context.Context, id, name string, send func(TargetStats) error) error {
+	s.mu.Lock()
+	sess, err := s.get(id)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	updates, stop := sess.hub.Watch()
+	defer stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case stats, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if name != "" && stats.Target.Name != name {
+				continue
+			}
+			if err := send(stats); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Watchers returns the number of watchers of the session with the given ID.
+func (s *Sessions) Watchers(id string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, err := s.get(id)
+	if err != nil {
+		return 0
+	}
+	return sess.hub.Watchers()
+}
+
+// get returns the session with the given ID, or the latest session if id is
+// empty. The caller holds s.mu.
+func (s *Sessions) get(id string) (*session, error) {
+	if id == "" {
+		id = s.latest
+		if id == "" {
+			return nil, fmt.Errorf("no tracing sessions")
+		}
+	}
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("tracing session %s not found", id)
+	}
+	return sess, nil
+}
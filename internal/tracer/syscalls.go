@@ -0,0 +1,72 @@
+package tracer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SyscallFamilies are groups of syscalls that can be traced by name. A
+// syscall of a family that does not exist on this architecture is skipped.
+var SyscallFamilies = map[string][]string{
+	"network": {
+		"socket", "socketpair", "bind", "listen", "accept", "accept4", "connect",
+		"getsockname", "getpeername", "setsockopt", "getsockopt", "shutdown",
+		"sendto", "recvfrom", "sendmsg", "recvmsg", "sendmmsg", "recvmmsg",
+	},
+	"polling": {
+		"select", "pselect6", "poll", "ppoll",
+		"epoll_create", "epoll_create1", "epoll_ctl", "epoll_wait", "epoll_pwait", "epoll_pwait2",
+	},
+	"file": {
+		"open", "openat", "openat2", "close", "read", "write", "readv", "writev",
+		"pread64", "pwrite64", "lseek", "fstat", "newfstatat", "statx",
+		"fsync", "fdatasync", "sendfile", "splice",
+	},
+	"memory": {"brk", "mmap", "munmap", "mremap", "mprotect", "madvise"},
+}
+
+var (
+	syscallNumbersOnce sync.Once
+	syscallNumbers     map[string]uint32
+)
+
+// SyscallNumber returns the number of the named syscall on this architecture.
+func SyscallNumber(name string) (uint32, bool) {
+	syscallNumbersOnce.Do(func() {
+		syscallNumbers = make(map[string]uint32, len(syscallNames))
+		for nr, name := range syscallNames {
+			syscallNumbers[name] = nr
+		}
+	})
+	nr, ok := syscallNumbers[name]
+	return nr, ok
+}
+
+// ExpandSyscalls resolves syscall names and the names of SyscallFamilies to
+// a sorted list of syscalls that exist on this architecture.
+func ExpandSyscalls(names []string) ([]string, error) {
+	set := make(map[string]bool)
+	for _, name := range names {
+		if family, ok := SyscallFamilies[name]; ok {
+			for _, syscall := range family {
+				if _, ok := SyscallNumber(syscall); ok {
+					set[syscall] = true
+				}
+			}
+			continue
+		}
+
+		if _, ok := SyscallNumber(name); !ok {
+			return nil, fmt.Errorf("unknown syscall or syscall family %q", name)
+		}
+		set[name] = true
+	}
+
+	syscalls := make([]string, 0, len(set))
+	for syscall := range set {
+		syscalls = append(syscalls, syscall)
+	}
+	sort.Strings(syscalls)
+	return syscalls, nil
+}
@@ -0,0 +1,6 @@
+//go:build !linux || (!amd64 && !arm64)
+
+package tracer
+
+// syscallNames is unknown here, syscalls are reported by number.
+var syscallNames = map[uint32]string{}
@@ -0,0 +1,206 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"time"
+)
+
+// DefaultInterval is how often a trace emits a snapshot, as trace_syscalls.sh did.
+const DefaultInterval = 2 * time.Second
+
+// Tracer counts the syscalls of a set of processes, and optionally times
+// them. The eBPF implementation traces the kernel, Fake counts what tests
+// feed it.
+type Tracer interface {
+	// Trace counts the syscalls of every thread of opts.PIDs until ctx is
+	// done. It returns once tracing has started and calls emit with a
+	// snapshot every interval, and with a final snapshot once ctx is done.
+	Trace(ctx context.Context, opts Options, emit func(Snapshot)) error
+}
+
+type Options struct {
+	PIDs     []int
+	Interval time.Duration // default DefaultInterval
+	// Syscalls limits the trace to these syscalls, all if empty. See
+	// ExpandSyscalls for the names it takes.
+	Syscalls []string
+	// Latency also pairs every sys_enter with its sys_exit to build latency
+	// histograms, at the cost of a second probe on every syscall.
+	Latency bool
+}
+
+func (o Options) withDefaults() Options {
+	if o.Interval <= 0 {
+		o.Interval = DefaultInterval
+	}
+	return o
+}
+
+// Key identifies the syscalls of one thread. PID is the process, TID the thread.
+type Key struct {
+	PID     int
+	TID     int
+	Comm    string
+	Syscall string
+}
+
+// Counts holds the number of syscalls per thread and syscall.
+type Counts map[Key]uint64
+
+// Snapshot is the state of a trace at the end of an interval.
+type Snapshot struct {
+	Time     time.Time
+	Period   time.Duration
+	Interval Counts // counts during the last Period
+	Total    Counts // counts since the trace started
+	// IntervalLatency and TotalLatency are like Interval and Total for
+	// latencies. They are nil unless Options.Latency is set.
+	IntervalLatency Latencies
+	TotalLatency    Latencies
+	Final           bool // the trace has stopped
+}
+
+// poll emits a snapshot of the cumulative counts and latencies returned by
+// read every interval, and a final one once ctx is done. read returns nil
+// latencies if the trace does not time syscalls.
+func poll(ctx context.Context, interval time.Duration, read func() (Counts, Latencies, error), emit func(Snapshot)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	prev := Counts{}
+	var prevLatency Latencies
+	snapshot := func(final bool) {
+		total, totalLatency, err := read()
+		if err != nil {
+			log.Printf("failed to read syscall counts: %v", err)
+			total, totalLatency = prev, prevLatency
+		}
+
+		delta := make(Counts)
+		for key, n := range total {
+			if d := n - prev[key]; d > 0 {
+				delta[key] = d
+			}
+		}
+
+		var deltaLatency Latencies
+		if totalLatency != nil {
+			deltaLatency = make(Latencies)
+			for key, l := range totalLatency {
+				if d := l.sub(prevLatency[key]); d.Count > 0 {
+					deltaLatency[key] = d
+				}
+			}
+		}
+
+		prev, prevLatency = total, totalLatency
+		emit(Snapshot{
+			Time:            time.Now(),
+			Period:          interval,
+			Interval:        delta,
+			Total:           total,
+			IntervalLatency: deltaLatency,
+			TotalLatency:    totalLatency,
+			Final:           final,
+		})
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			snapshot(true)
+			return
+		case <-ticker.C:
+			snapshot(false)
+		}
+	}
+}
+
+// WriteSnapshot writes s in the format of the bpftrace map dumps that
+// trace_syscalls.sh used to print, with the counts summed per comm and
+// syscall, so existing tooling can still read the logs. Latencies follow
+// the counts as bpftrace histograms per thread, those of the interval in
+// every snapshot and the cumulative ones in the final snapshot.
+func WriteSnapshot(w io.Writer, s Snapshot) error {
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	if s.Final {
+		printf("\n=== FINAL cumulative syscall counts ===\n")
+		writeCounts(printf, "total", s.Total)
+		if s.TotalLatency != nil {
+			printf("\n--- cumulative syscall latency ---\n")
+			writeLatencies(printf, "total", s.TotalLatency)
+		}
+		printf("=== END OF TRACE ===\n")
+		return err
+	}
+
+	printf("\n--- syscall counts (last %s) ---\n", s.Period)
+	writeCounts(printf, "interval", s.Interval)
+	printf("\n--- cumulative syscall counts ---\n")
+	writeCounts(printf, "total", s.Total)
+	if s.IntervalLatency != nil {
+		printf("\n--- syscall latency (last %s) ---\n", s.Period)
+		writeLatencies(printf, "interval", s.IntervalLatency)
+	}
+	return err
+}
+
+// writeCounts prints one line per comm and syscall, smallest count first
+// like bpftrace does.
+func writeCounts(printf func(string, ...any), name string, counts Counts) {
+	bySyscall := counts.BySyscall()
+	sort.SliceStable(bySyscall, func(i, j int) bool { return bySyscall[i].Count < bySyscall[j].Count })
+	for _, c := range bySyscall {
+		printf("@%s[%s, tracepoint:syscalls:sys_enter_%s]: %d\n", name, c.Comm, c.Syscall, c.Count)
+	}
+}
+
+// SyscallCount is the number of calls of a syscall by every thread with
+// the same comm.
+type SyscallCount struct {
+	Comm    string
+	Syscall string
+	Count   uint64
+}
+
+// BySyscall sums the counts per comm and syscall, largest count first.
+func (c Counts) BySyscall() []SyscallCount {
+	type commSyscall struct{ comm, syscall string }
+	sums := make(map[commSyscall]uint64)
+	for key, n := range c {
+		sums[commSyscall{key.Comm, key.Syscall}] += n
+	}
+
+	counts := make([]SyscallCount, 0, len(sums))
+	for key, n := range sums {
+		counts = append(counts, SyscallCount{Comm: key.comm, Syscall: key.syscall, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		if counts[i].Comm != counts[j].Comm {
+			return counts[i].Comm < counts[j].Comm
+		}
+		return counts[i].Syscall < counts[j].Syscall
+	})
+	return counts
+}
+
+// SyscallName returns the name of syscall number nr on this architecture.
+func SyscallName(nr uint32) string {
+	if name, ok := syscallNames[nr]; ok {
+		return name
+	}
+	return fmt.Sprintf("syscall_%d", nr)
+}
@@ -0,0 +1,167 @@
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFakeSnapshotsAreDeltas(t *testing.T) {
+	fake := NewFake()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	snapshots := make(chan Snapshot, 16)
+	if err := fake.Trace(ctx, Options{PIDs: []int{100}, Interval: 10 * time.Millisecond}, func(s Snapshot) { snapshots <- s }); err != nil {
+		t.Fatal(err)
+	}
+
+	read := Key{PID: 100, TID: 101, Comm: "iperf3", Syscall: "read"}
+	fake.Add(100, 101, "iperf3", "read", 3)
+	fake.Add(200, 201, "other", "read", 5) // not traced
+
+	first := <-snapshots
+	for first.Interval[read] == 0 {
+		first = <-snapshots
+	}
+	if first.Interval[read] != 3 || first.Total[read] != 3 || len(first.Total) != 1 {
+		t.Fatalf("got interval %v and total %v, want 3 reads of thread 101 only", first.Interval, first.Total)
+	}
+
+	fake.Add(100, 101, "iperf3", "read", 2)
+	second := <-snapshots
+	for second.Interval[read] == 0 {
+		second = <-snapshots
+	}
+	if second.Interval[read] != 2 || second.Total[read] != 5 {
+		t.Fatalf("got %d reads in the interval and %d in total, want 2 and 5", second.Interval[read], second.Total[read])
+	}
+
+	cancel()
+	for s := range snapshots {
+		if s.Final {
+			if s.Total[read] != 5 {
+				t.Fatalf("got %d reads in the final snapshot, want 5", s.Total[read])
+			}
+			break
+		}
+	}
+	for fake.Traces() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWriteSnapshot(t *testing.T) {
+	counts := Counts{
+		{PID: 1, TID: 1, Comm: "iperf3", Syscall: "write"}: 7,
+		{PID: 1, TID: 2, Comm: "iperf3", Syscall: "write"}: 3,
+		{PID: 1, TID: 1, Comm: "iperf3", Syscall: "read"}:  4,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, Snapshot{Period: 2 * time.Second, Interval: counts, Total: counts}); err != nil {
+		t.Fatal(err)
+	}
+	want := `
+--- syscall counts (last 2s) ---
+@interval[iperf3, tracepoint:syscalls:sys_enter_read]: 4
+@interval[iperf3, tracepoint:syscalls:sys_enter_write]: 10
+
+--- cumulative syscall counts ---
+@total[iperf3, tracepoint:syscalls:sys_enter_read]: 4
+@total[iperf3, tracepoint:syscalls:sys_enter_write]: 10
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	buf.Reset()
+	if err := WriteSnapshot(&buf, Snapshot{Total: counts, Final: true}); err != nil {
+		t.Fatal(err)
+	}
+	want = `
+=== FINAL cumulative syscall counts ===
+@total[iperf3, tracepoint:syscalls:sys_enter_read]: 4
+@total[iperf3, tracepoint:syscalls:sys_enter_write]: 10
+=== END OF TRACE ===
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteSnapshotLatency(t *testing.T) {
+	var sendmsg Latency
+	sendmsg.add(latencySlot(1500), 3, 4500*time.Nanosecond)
+	sendmsg.add(latencySlot(5000), 1, 5000*time.Nanosecond)
+	var read Latency
+	read.add(latencySlot(0), 1, 0)
+	latencies := Latencies{
+		{PID: 1, TID: 2, Comm: "iperf3", Syscall: "sendmsg"}: sendmsg,
+		{PID: 1, TID: 1, Comm: "iperf3", Syscall: "read"}:    read,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, Snapshot{Total: Counts{}, TotalLatency: latencies, Final: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := `
+=== FINAL cumulative syscall counts ===
+
+--- cumulative syscall latency ---
+@total_latency_ns[iperf3, 1, tracepoint:syscalls:sys_enter_read]:
+[0]                    1 |@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@|
+
+@total_latency_ns[iperf3, 2, tracepoint:syscalls:sys_enter_sendmsg]:
+[1K, 2K)               3 |@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@@|
+[2K, 4K)               0 |                                                    |
+[4K, 8K)               1 |@@@@@@@@@@@@@@@@@                                   |
+
+@total_time_ns[iperf3, 1, tracepoint:syscalls:sys_enter_read]: 0
+@total_time_ns[iperf3, 2, tracepoint:syscalls:sys_enter_sendmsg]: 9500
+=== END OF TRACE ===
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if lo, hi := BucketRange(latencySlot(1500)); lo != 1024 || hi != 2047 {
+		t.Fatalf("got bucket [%d, %d] for 1500ns, want [1024, 2047]", lo, hi)
+	}
+	if _, hi := BucketRange(latencySlots - 1); hi != 1<<64-1 {
+		t.Fatalf("got %d as the end of the last bucket, want the largest uint64", hi)
+	}
+}
+
+func TestHubClosesAfterLastUpdate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	hub := NewHub[int](ctx)
+
+	first, stopFirst := hub.Watch()
+	defer stopFirst()
+	second, stopSecond := hub.Watch()
+	defer stopSecond()
+
+	hub.Add()
+	hub.Publish(1)
+	cancel()
+	hub.Publish(2) // the final update, after the context is done
+	hub.Done()
+
+	for _, updates := range []<-chan int{first, second} {
+		var got []int
+		for update := range updates {
+			got = append(got, update)
+		}
+		if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+			t.Fatalf("got updates %v, want [1 2]", got)
+		}
+	}
+
+	late, stopLate := hub.Watch()
+	defer stopLate()
+	if _, ok := <-late; ok {
+		t.Fatal("got an update after the hub closed")
+	}
+}
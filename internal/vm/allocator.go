@@ -0,0 +1,70 @@
+package vm
+
+import (
+	"fmt"
+	"sync"
+)
+
+// maxSlots bounds the number of VMs on a node. CIDs 0-2 are reserved by
+// vsock, so slot i gets CID i+3.
+const maxSlots = 4096
+
+// slotAllocator hands out VM slots. A slot determines the tap name, vsock CID
+// and MAC address of a VM, so two live VMs must never share one. Released
+// slots are reused lowest first, which keeps VMs on the taps created by
+// network.Manager.Setup.
+type slotAllocator struct {
+	mu   sync.Mutex
+	used map[int]bool
+}
+
+func newSlotAllocator() *slotAllocator {
+	return &slotAllocator{used: make(map[int]bool)}
+}
+
+func (a *slotAllocator) allocate() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := 0; i < maxSlots; i++ {
+		if !a.used[i] {
+			a.used[i] = true
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("all %d VM slots are in use", maxSlots)
+}
+
+// reserve claims a specific slot, e.g. the one baked into a snapshot.
+func (a *slotAllocator) reserve(slot int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if slot < 0 || slot >= maxSlots {
+		return fmt.Errorf("slot %d out of range", slot)
+	}
+	if a.used[slot] {
+		return fmt.Errorf("slot %d is in use by another VM", slot)
+	}
+	a.used[slot] = true
+	return nil
+}
+
+func (a *slotAllocator) release(slot int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.used, slot)
+}
+
+func slotTapName(prefix string, slot int) string {
+	return fmt.Sprintf("%s%d", prefix, slot)
+}
+
+func slotCID(slot int) uint32 {
+	return uint32(slot + 3)
+}
+
+func slotMacAddress(slot int) string {
+	n := slot + 1
+	return fmt.Sprintf("AA:FC:00:00:%02X:%02X", n>>8, n&0xff)
+}
@@ -0,0 +1,193 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/hypervisor"
+	"github.com/firecracker-microvm/firecracker-go-sdk/client/models"
+)
+
+const (
+	defaultVcpuCount  = 1
+	defaultMemSizeMib = 512
+	defaultKernelArgs = "console=ttyS0 noapic reboot=k panic=1 pci=off rw"
+
+	maxVcpuCount = 32
+	// memory kept back for the host and the firecracker processes themselves
+	hostReservedMemMib = 512
+)
+
+var defaultNameservers = []string{"8.8.8.8", "8.8.4.4"}
+
+// MachineConfig is the per-VM hardware and boot configuration.
+// Zero values are replaced by the defaults in withDefaults.
+type MachineConfig struct {
+	VcpuCount       int64    `json:"vcpuCount"`
+	MemSizeMib      int64    `json:"memSizeMib"`
+	Smt             bool     `json:"smt"`
+	CPUTemplate     string   `json:"cpuTemplate,omitempty"`
+	KernelArgs      string   `json:"kernelArgs,omitempty"` // appended to the default boot args
+	Nameservers     []string `json:"nameservers,omitempty"`
+	TrackDirtyPages bool     `json:"trackDirtyPages"` // required for diff snapshots
+
+	NetworkRxRateLimiter *hypervisor.RateLimiter `json:"networkRxRateLimiter,omitempty"`
+	NetworkTxRateLimiter *hypervisor.RateLimiter `json:"networkTxRateLimiter,omitempty"`
+	DriveRateLimiter     *hypervisor.RateLimiter `json:"driveRateLimiter,omitempty"`
+}
+
+func (c MachineConfig) withDefaults() MachineConfig {
+	if c.VcpuCount == 0 {
+		c.VcpuCount = defaultVcpuCount
+	}
+	if c.MemSizeMib == 0 {
+		c.MemSizeMib = defaultMemSizeMib
+	}
+	if len(c.Nameservers) == 0 {
+		c.Nameservers = defaultNameservers
+	}
+	return c
+}
+
+func (c MachineConfig) bootArgs() string {
+	if c.KernelArgs == "" {
+		return defaultKernelArgs
+	}
+	return defaultKernelArgs + " " + c.KernelArgs
+}
+
+func (c MachineConfig) validate() error {
+	if c.VcpuCount < 1 || c.VcpuCount > maxVcpuCount {
+		return fmt.Errorf("vcpu count must be between 1 and %d, got %d", maxVcpuCount, c.VcpuCount)
+	}
+	if c.Smt && c.VcpuCount != 1 && c.VcpuCount%2 != 0 {
+		return fmt.Errorf("vcpu count must be 1 or even with SMT enabled, got %d", c.VcpuCount)
+	}
+	if c.MemSizeMib < 1 {
+		return fmt.Errorf("memory size must be positive, got %d MiB", c.MemSizeMib)
+	}
+
+	switch models.CPUTemplate(c.CPUTemplate) {
+	case "", models.CPUTemplateC3, models.CPUTemplateT2:
+	default:
+		return fmt.Errorf("unknown cpu template %q", c.CPUTemplate)
+	}
+
+	for _, ns := range c.Nameservers {
+		if net.ParseIP(ns) == nil {
+			return fmt.Errorf("invalid nameserver %q", ns)
+		}
+	}
+
+	limiters := map[string]*hypervisor.RateLimiter{
+		"network rx": c.NetworkRxRateLimiter,
+		"network tx": c.NetworkTxRateLimiter,
+		"drive":      c.DriveRateLimiter,
+	}
+	for name, limiter := range limiters {
+		if err := validateRateLimiter(limiter); err != nil {
+			return fmt.Errorf("invalid %s rate limiter: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+func validateRateLimiter(limiter *hypervisor.RateLimiter) error {
+	if limiter == nil {
+		return nil
+	}
+
+	buckets := map[string]*hypervisor.TokenBucket{"bandwidth": limiter.Bandwidth, "ops": limiter.Ops}
+	for name, bucket := range buckets {
+		if bucket == nil {
+			continue
+		}
+		if bucket.Size <= 0 || bucket.RefillTimeMs <= 0 {
+			return fmt.Errorf("%s bucket needs a positive size and refill time", name)
+		}
+		if bucket.OneTimeBurst < 0 {
+			return fmt.Errorf("%s bucket has a negative one time burst", name)
+		}
+	}
+
+	return nil
+}
+
+func (c MachineConfig) hypervisorConfig() hypervisor.MachineConfig {
+	return hypervisor.MachineConfig{
+		VcpuCount:       c.VcpuCount,
+		MemSizeMib:      c.MemSizeMib,
+		Smt:             c.Smt,
+		CPUTemplate:     c.CPUTemplate,
+		TrackDirtyPages: c.TrackDirtyPages,
+	}
+}
+
+// checkHostCapacity rejects a VM whose vCPUs or memory do not fit next to the
+// VMs that are already running on this host. The caller holds m.mu.
+func (m *Manager) checkHostCapacity(cfg MachineConfig) error {
+	hostCPUs, hostMemMib, err := m.hostCapacity()
+	if err != nil {
+		return err
+	}
+
+	var usedVcpus, usedMemMib int64
+	for _, vm := range m.vms {
+		switch vm.State() {
+		case StateStopped, StateFailed:
+			continue
+		}
+		usedVcpus += vm.MachineConfig.VcpuCount
+		usedMemMib += vm.MachineConfig.MemSizeMib
+	}
+
+	if usedVcpus+cfg.VcpuCount > hostCPUs {
+		return fmt.Errorf("not enough CPUs: requested %d vCPUs, %d of %d already in use", cfg.VcpuCount, usedVcpus, hostCPUs)
+	}
+
+	availableMemMib := hostMemMib - hostReservedMemMib
+	if usedMemMib+cfg.MemSizeMib > availableMemMib {
+		return fmt.Errorf("not enough memory: requested %d MiB, %d of %d MiB already in use", cfg.MemSizeMib, usedMemMib, availableMemMib)
+	}
+
+	return nil
+}
+
+func hostCapacity() (int64, int64, error) {
+	memMib, err := hostMemoryMib()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int64(runtime.NumCPU()), memMib, nil
+}
+
+func hostMemoryMib() (int64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read host memory: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// MemTotal:       16318412 kB
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse MemTotal: %v", err)
+		}
+		return kb / 1024, nil
+	}
+
+	return 0, fmt.Errorf("MemTotal not found in /proc/meminfo")
+}
@@ -2,64 +2,268 @@ package vm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/bookpanda/firecracker-runner-node/internal/agent"
 	"github.com/bookpanda/firecracker-runner-node/internal/config"
+	"github.com/bookpanda/firecracker-runner-node/internal/hypervisor"
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	"github.com/bookpanda/firecracker-runner-node/internal/network"
+	"github.com/bookpanda/firecracker-runner-node/internal/run"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
 )
 
+// Manager owns the VMs of this node. mu guards the vms and snapshots maps;
+// slow operations such as booting or stopping a VM run without holding it.
 type Manager struct {
-	config      *config.Config
-	vmCtx       context.Context
-	traceCtx    context.Context
-	cancelTrace context.CancelFunc
-	vms         map[string]*SimplifiedVM
-	wg          sync.WaitGroup
-	syscallsDir string
-	testDir     string
+	config       *config.Config
+	vmCtx        context.Context
+	mu           sync.Mutex
+	vms          map[string]*SimplifiedVM
+	snapshots    map[string]*Snapshot
+	slots        *slotAllocator
+	runs         *run.Manager
+	syscalls     *tracer.Sessions
+	jobs         *job.Registry
+	snapshotsDir string
+	ipam         *network.IPAM
+
+	hypervisor   hypervisor.Hypervisor
+	hostCapacity func() (cpus, memMib int64, err error)
 }
 
-func NewManager(cfg *config.Config, vmCtx context.Context) *Manager {
-	traceCtx, cancelTrace := context.WithCancel(context.Background())
-	return &Manager{
-		config:      cfg,
-		vmCtx:       vmCtx,
-		traceCtx:    traceCtx,
-		cancelTrace: cancelTrace,
-		vms:         make(map[string]*SimplifiedVM),
-		wg:          sync.WaitGroup{},
-		syscallsDir: "./vm-syscalls",
-		testDir:     "./vm-test",
+// NewManager writes the logs of the VMs to the current run of runs.
+func NewManager(cfg *config.Config, vmCtx context.Context, runs *run.Manager) (*Manager, error) {
+	ipam, err := network.NewIPAM(cfg.Subnet, cfg.NetworkStateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{
+		config:       cfg,
+		vmCtx:        vmCtx,
+		vms:          make(map[string]*SimplifiedVM),
+		snapshots:    make(map[string]*Snapshot),
+		slots:        newSlotAllocator(),
+		runs:         runs,
+		jobs:         job.NewRegistry(),
+		snapshotsDir: cfg.SnapshotsDir,
+		ipam:         ipam,
+		hypervisor:   hypervisor.NewFirecracker(cfg.FirecrackerBin),
+		hostCapacity: hostCapacity,
 	}
+	m.syscalls = tracer.NewSessions(tracer.NewEBPF(), m.syscallsLogPath)
+	m.loadSnapshots()
+
+	return m, nil
 }
 
-func (m *Manager) CreateVM(ip, kernelPath, rootfsPath, gatewayIP string) (*SimplifiedVM, error) {
-	vm, err := CreateVM(m.vmCtx, ip, kernelPath, rootfsPath, gatewayIP, len(m.vms))
+// CreateVM boots a new VM. An empty ip leases the next free address of the
+// node's subnet and an empty gatewayIP defaults to the subnet's gateway.
+func (m *Manager) CreateVM(ip, kernelPath, rootfsPath, gatewayIP string, machineCfg MachineConfig) (*SimplifiedVM, error) {
+	machineCfg = machineCfg.withDefaults()
+	if err := machineCfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid machine config: %v", err)
+	}
+
+	if gatewayIP == "" {
+		gatewayIP = m.ipam.Gateway().String()
+	}
+
+	vm, err := m.addVM(ip, machineCfg, -1, func(slot int, ip string) (*SimplifiedVM, error) {
+		return CreateVM(m.vmCtx, m.hypervisor, m.host(), ip, kernelPath, rootfsPath, gatewayIP, m.ipam.PrefixLength(), slot, machineCfg)
+	})
 	if err != nil {
 		return nil, err
 	}
+	m.recordVM(vm, "")
 
 	if err := vm.Start(m.vmCtx); err != nil {
-		return nil, fmt.Errorf("failed to start VM %d: %v", len(m.vms), err)
+		return nil, fmt.Errorf("failed to start VM %d: %v", vm.VMID, err)
+	}
+	log.Printf("VM %d started successfully. Socket: %s", vm.VMID, vm.SocketPath)
+
+	return vm, nil
+}
+
+// addVM claims ip and a slot and registers the VM returned by build in one
+// step, so concurrent creates cannot race on either. An empty ip leases the
+// next free address and a slot of -1 allocates the lowest free slot. A
+// stopped or failed VM with the same ip is replaced; failed VMs otherwise
+// stay in the inventory until they are deleted.
+func (m *Manager) addVM(ip string, machineCfg MachineConfig, slot int, build func(slot int, ip string) (*SimplifiedVM, error)) (*SimplifiedVM, error) {
+	if ip != "" {
+		addr := net.ParseIP(ip)
+		if addr == nil {
+			return nil, fmt.Errorf("invalid ip %q", ip)
+		}
+		ip = addr.String()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.vms[ip]
+	if ok {
+		switch existing.State() {
+		case StateStopped, StateFailed:
+		default:
+			return nil, fmt.Errorf("vm %s already exists and is %s", ip, existing.State())
+		}
+	}
+
+	if err := m.checkHostCapacity(machineCfg); err != nil {
+		return nil, err
+	}
+
+	// a replaced VM keeps its lease
+	if !ok {
+		leased, err := m.leaseIP(ip)
+		if err != nil {
+			return nil, err
+		}
+		ip = leased
+	}
+	releaseIP := func() {
+		if !ok {
+			m.releaseIP(ip)
+		}
+	}
+
+	if ok {
+		// make sure the process of a failed VM is gone before reusing its slot
+		if err := existing.Machine.StopVMM(); err != nil {
+			log.Printf("Failed to stop VMM of VM %d: %v", existing.VMID, err)
+		}
+		m.slots.release(existing.VMID)
+	}
+
+	var err error
+	if slot < 0 {
+		slot, err = m.slots.allocate()
+	} else {
+		err = m.slots.reserve(slot)
+	}
+	if err != nil {
+		if ok {
+			m.slots.reserve(existing.VMID)
+		}
+		releaseIP()
+		return nil, err
+	}
+
+	vm, err := build(slot, ip)
+	if err != nil {
+		m.slots.release(slot)
+		if ok {
+			m.slots.reserve(existing.VMID)
+		}
+		releaseIP()
+		return nil, err
+	}
+
+	m.vms[ip] = vm
+	return vm, nil
+}
+
+// leaseIP leases ip, or the next free address if ip is empty.
+func (m *Manager) leaseIP(ip string) (string, error) {
+	if ip == "" {
+		addr, err := m.ipam.Allocate()
+		if err != nil {
+			return "", err
+		}
+		return addr.String(), nil
+	}
+
+	if err := m.ipam.Reserve(net.ParseIP(ip)); err != nil {
+		return "", err
+	}
+	return ip, nil
+}
+
+func (m *Manager) releaseIP(ip string) {
+	if err := m.ipam.Release(net.ParseIP(ip)); err != nil {
+		log.Printf("Failed to release lease of %s: %v", ip, err)
+	}
+}
+
+func (m *Manager) getVM(ip string) (*SimplifiedVM, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	vm, ok := m.vms[ip]
+	if !ok {
+		return nil, fmt.Errorf("vm %s not found", ip)
 	}
-	log.Printf("VM %d started successfully. Socket: %s", len(m.vms), vm.SocketPath)
 
-	m.vms[vm.IP] = vm
 	return vm, nil
 }
 
+func (m *Manager) ListVMs() []*SimplifiedVM {
+	m.mu.Lock()
+	vms := make([]*SimplifiedVM, 0, len(m.vms))
+	for _, vm := range m.vms {
+		vms = append(vms, vm)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(vms, func(i, j int) bool { return vms[i].VMID < vms[j].VMID })
+	return vms
+}
+
+func (m *Manager) GetVM(ip string) (*SimplifiedVM, error) {
+	return m.getVM(ip)
+}
+
+// DeleteVM stops the VM if it is still running, removes it from the
+// inventory and frees its slot.
+func (m *Manager) DeleteVM(ip string) error {
+	vm, err := m.getVM(ip)
+	if err != nil {
+		return err
+	}
+
+	vm.opMu.Lock()
+	defer vm.opMu.Unlock()
+
+	if err := vm.Stop(m.vmCtx); err != nil {
+		return fmt.Errorf("failed to stop VM %d: %v", vm.VMID, err)
+	}
+
+	m.mu.Lock()
+	// the entry may have been replaced or deleted while we were stopping
+	if m.vms[ip] == vm {
+		delete(m.vms, ip)
+		m.slots.release(vm.VMID)
+		m.releaseIP(ip)
+	}
+	m.mu.Unlock()
+
+	log.Printf("VM %d deleted", vm.VMID)
+	return nil
+}
+
 func (m *Manager) StopAllVMs() error {
 	if err := m.vmCtx.Err(); err != nil {
 		return err
 	}
 
 	var wg sync.WaitGroup
-	for _, vm := range m.vms {
+	for _, vm := range m.ListVMs() {
 		wg.Add(1)
 		go func(vm *SimplifiedVM) {
 			defer wg.Done()
+			vm.opMu.Lock()
+			defer vm.opMu.Unlock()
 			if err := vm.Stop(m.vmCtx); err != nil {
 				log.Printf("Failed to stop VM %d: %v", vm.VMID, err)
 			}
@@ -70,43 +274,228 @@ func (m *Manager) StopAllVMs() error {
 }
 
 func (m *Manager) LogNetworkingInfo() {
-	log.Printf("All %d VMs started successfully", len(m.vms))
+	vms := m.ListVMs()
+	log.Printf("All %d VMs started successfully", len(vms))
 	log.Println("VM networking setup:")
-	for ip, vm := range m.vms {
-		log.Printf("  VM %s: tap%d, MAC: AA:FC:00:00:00:%02X, IP: %s/24", ip, vm.VMID, vm.VMID+1, vm.IP)
+	for _, vm := range vms {
+		log.Printf("  VM %s: %s, MAC: %s, IP: %s/%d, state: %s", vm.IP, vm.TapName, vm.MacAddress, vm.IP, vm.PrefixLength, vm.State())
 	}
 }
 
-func (m *Manager) SendServerCommand(ip, command string, wait bool) error {
-	vm, ok := m.vms[ip]
-	if !ok {
-		log.Printf("vm %s not found", ip)
-		return fmt.Errorf("vm %s not found", ip)
+// SendServerCommand starts command on the VM at ip in the background as a
+// job, which runs until it exits, timeout passes or the VMs are stopped. A
+// timeout of 0 never passes.
+func (m *Manager) SendServerCommand(ip, command string, wait bool, timeout time.Duration) (job.Job, error) {
+	vm, err := m.getVM(ip)
+	if err != nil {
+		log.Printf("%v", err)
+		return job.Job{}, err
 	}
-	logPath := filepath.Join(m.testDir, fmt.Sprintf("vm-%s.log", vm.IP))
+	m.recordCommand(vm.IP, command, true)
 
-	if err := m.captureCommandOutputVsock(vm.VsockPath, 1234, command, logPath, false); err != nil {
+	j, ctx := m.jobs.Add(m.vmCtx, job.Job{Target: vm.IP, Command: command, Server: true, Timeout: timeout})
+	logPath := m.jobLogPath(&j)
+	done := func(result job.Result) { m.jobs.End(j.ID, result) }
+	if err := startCommandVsock(ctx, vm, uint32(m.config.VsockPort), command, logPath, done); err != nil {
+		m.jobs.End(j.ID, job.Result{ExitCode: -1, Error: err.Error()})
 		log.Printf("failed to send command to vm %s: %v", vm.IP, err)
-		return fmt.Errorf("failed to send command to vm %s: %v", vm.IP, err)
+		return job.Job{}, fmt.Errorf("failed to send command to vm %s: %v", vm.IP, err)
 	}
 
-	return nil
+	return j, nil
 }
 
-func (m *Manager) SendClientCommand(ip, command string) error {
-	vm, ok := m.vms[ip]
-	if !ok {
-		log.Printf("vm %s not found", ip)
-		return fmt.Errorf("vm %s not found", ip)
+// jobLogPath names the log of j, a command on a VM, after the VM and the job,
+// so that the commands a VM runs at the same time do not share a log.
+func (m *Manager) jobLogPath(j *job.Job) string {
+	j.LogPath = filepath.Join(m.runs.Dir(run.VMTestDir), fmt.Sprintf("vm-%s-%s.log", j.Target, j.ID))
+	m.jobs.SetLogPath(j.ID, j.LogPath)
+	return j.LogPath
+}
+
+// SendClientCommand runs command on the VM at ip as a job until it exits, ctx
+// is done, timeout passes or the VMs are stopped, passing every line of its
+// output to output. A timeout of 0 never passes. started is called with the
+// job before output is called.
+func (m *Manager) SendClientCommand(ctx context.Context, ip, command string, timeout time.Duration, started func(job.Job), output func(job.Output)) (job.Result, error) {
+	vm, err := m.getVM(ip)
+	if err != nil {
+		log.Printf("%v", err)
+		return job.Result{}, err
 	}
-	logPath := filepath.Join(m.testDir, fmt.Sprintf("vm-%s.log", vm.IP))
+	m.recordCommand(vm.IP, command, false)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(m.vmCtx, cancel)
+	defer stop()
 
-	if err := m.captureCommandOutputVsock(vm.VsockPath, 1234, command, logPath, true); err != nil {
+	j, ctx := m.jobs.Add(ctx, job.Job{Target: vm.IP, Command: command, Timeout: timeout})
+	logPath := m.jobLogPath(&j)
+	started(j)
+	result, err := runCommandVsock(ctx, vm, uint32(m.config.VsockPort), command, logPath, output)
+	if err != nil {
+		m.jobs.End(j.ID, job.Result{ExitCode: -1, Error: err.Error()})
 		log.Printf("failed to send command to vm %s: %v", vm.IP, err)
-		return fmt.Errorf("failed to send command to vm %s: %v", vm.IP, err)
+		return job.Result{}, fmt.Errorf("failed to send command to vm %s: %v", vm.IP, err)
 	}
+	m.jobs.End(j.ID, result)
+	log.Printf("VM %s: command exited with code %d after %s, logs saved to %s", vm.IP, result.ExitCode, result.Duration, logPath)
 
-	m.wg.Wait()
+	return result, nil
+}
 
-	return nil
+// Exec starts spec in the VM at ip as a job through the guest agent, which
+// must speak version 2 of the protocol for stdin or a terminal. The command
+// is killed once ctx is done or after timeout, unless it is 0.
+func (m *Manager) Exec(ctx context.Context, ip string, spec job.Spec, timeout time.Duration) (job.Job, job.Handle, error) {
+	vm, err := m.getVM(ip)
+	if err != nil {
+		return job.Job{}, nil, err
+	}
+	command := strings.Join(spec.Argv, " ")
+	m.recordCommand(vm.IP, command, false)
+
+	client, err := m.connectAgent(vm, "exec")
+	if err != nil {
+		return job.Job{}, nil, err
+	}
+
+	// stopping the VMs ends the exec too, and the job's context is done
+	// once it ended
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(m.vmCtx, cancel)
+	j, ctx := m.jobs.Add(ctx, job.Job{Target: vm.IP, Command: command, Timeout: timeout})
+	context.AfterFunc(ctx, func() {
+		stop()
+		cancel()
+	})
+	if err := client.Start(spec); err != nil {
+		client.Close()
+		m.jobs.End(j.ID, job.Result{ExitCode: -1, Error: err.Error()})
+		return job.Job{}, nil, fmt.Errorf("failed to exec in vm %s: %v", vm.IP, err)
+	}
+	j.Pid = client.Pid()
+	m.jobs.SetPid(j.ID, j.Pid)
+	log.Printf("VM %s: exec %q with PID %d", vm.IP, spec.Argv, j.Pid)
+
+	// hanging up kills the command in the guest
+	return j, m.jobs.Track(j.ID, job.WithContext(ctx, client, func() { client.Close() })), nil
+}
+
+// ListJobs returns the jobs on the VM at ip, or on every VM if ip is empty,
+// oldest first. The jobs of deleted VMs are kept, and of the jobs that ended
+// the last job.MaxEndedJobs.
+func (m *Manager) ListJobs(ip string) []job.Job {
+	return m.jobs.List(ip)
+}
+
+func (m *Manager) GetJob(id string) (job.Job, error) {
+	return m.jobs.Get(id)
+}
+
+// CancelJob kills the command of the job with the given ID and returns the
+// job once the command ended, or as it is when ctx is done.
+func (m *Manager) CancelJob(ctx context.Context, id string) (job.Job, error) {
+	log.Printf("Cancelling job %s", id)
+	return m.jobs.Cancel(ctx, id)
+}
+
+// CancelJobs kills every running command and waits until they ended or ctx
+// is done.
+func (m *Manager) CancelJobs(ctx context.Context) {
+	m.jobs.CancelAll(ctx)
+}
+
+// PutFile starts copying f into the VM at ip through the guest agent, which
+// must speak version 3 of the protocol. The content continues from the
+// Offset of the upload. The upload is aborted once ctx is done.
+func (m *Manager) PutFile(ctx context.Context, ip string, f agent.File) (*agent.Upload, error) {
+	vm, err := m.getVM(ip)
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.connectAgent(vm, "copying files")
+	if err != nil {
+		return nil, err
+	}
+	upload, err := client.PutFile(f)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to copy %s into vm %s: %v", f.Path, vm.IP, err)
+	}
+	log.Printf("VM %s: copying %d bytes into %s from offset %d", vm.IP, f.Size, f.Path, upload.Offset)
+
+	context.AfterFunc(ctx, func() { client.Close() })
+	return upload, nil
+}
+
+// GetFile starts copying the file at path out of the VM at ip from offset,
+// like PutFile.
+func (m *Manager) GetFile(ctx context.Context, ip, path string, offset int64) (*agent.Download, error) {
+	vm, err := m.getVM(ip)
+	if err != nil {
+		return nil, err
+	}
+	client, err := m.connectAgent(vm, "copying files")
+	if err != nil {
+		return nil, err
+	}
+	download, err := client.GetFile(path, offset)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to copy %s out of vm %s: %v", path, vm.IP, err)
+	}
+	log.Printf("VM %s: copying %d bytes out of %s from offset %d", vm.IP, download.File.Size, path, offset)
+
+	context.AfterFunc(ctx, func() { client.Close() })
+	return download, nil
+}
+
+// connectAgent connects to the guest agent of vm for feature, which the line
+// protocol lacks.
+func (m *Manager) connectAgent(vm *SimplifiedVM, feature string) (*agent.Client, error) {
+	client, err := dialAgent(vm, uint32(m.config.VsockPort))
+	if errors.Is(err, agent.ErrLegacyAgent) {
+		return nil, fmt.Errorf("guest agent of vm %s only speaks the line protocol, %s needs cmd/guest-agent", vm.IP, feature)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the guest agent of vm %s: %v", vm.IP, err)
+	}
+	return client, nil
+}
+
+// host returns where the host side of new VMs goes.
+func (m *Manager) host() Host {
+	return Host{
+		LogDir:      m.runs.Dir(run.VMLogsDir),
+		SocketDir:   m.config.SocketDir,
+		TapPrefix:   m.config.TapPrefix,
+		StopTimeout: m.config.VMStopTimeout,
+	}
+}
+
+// recordVM adds vm to the manifest of the current run. snapshot is the ID of
+// the snapshot it was restored from, if any.
+func (m *Manager) recordVM(vm *SimplifiedVM, snapshot string) {
+	err := m.runs.AddVM(run.VM{
+		IP:         vm.IP,
+		Kernel:     run.NewImage(vm.KernelPath),
+		Rootfs:     run.NewImage(vm.RootfsPath),
+		VcpuCount:  vm.MachineConfig.VcpuCount,
+		MemSizeMib: vm.MachineConfig.MemSizeMib,
+		KernelArgs: vm.MachineConfig.KernelArgs,
+		Snapshot:   snapshot,
+		CreatedAt:  vm.Status().CreatedAt,
+	})
+	if err != nil {
+		log.Printf("failed to record vm %s in the run manifest: %v", vm.IP, err)
+	}
+}
+
+func (m *Manager) recordCommand(ip, command string, server bool) {
+	err := m.runs.AddCommand(run.Command{Target: ip, Command: command, Server: server, StartedAt: time.Now()})
+	if err != nil {
+		log.Printf("failed to record command in the run manifest: %v", err)
+	}
 }
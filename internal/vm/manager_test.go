@@ -0,0 +1,936 @@
+package vm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/agent"
+	"github.com/bookpanda/firecracker-runner-node/internal/config"
+	"github.com/bookpanda/firecracker-runner-node/internal/hypervisor"
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	"github.com/bookpanda/firecracker-runner-node/internal/network"
+	"github.com/bookpanda/firecracker-runner-node/internal/run"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
+)
+
+func newTestManager(t *testing.T) (*Manager, *hypervisor.Fake) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	fake := hypervisor.NewFake()
+	runs, err := run.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.Subnet = "192.168.0.0/16"
+	cfg.NetworkStateDir = t.TempDir()
+	cfg.SnapshotsDir = t.TempDir()
+	m, err := NewManager(cfg, ctx, runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.hypervisor = fake
+	m.hostCapacity = func() (int64, int64, error) { return 1 << 10, 1 << 30, nil }
+	t.Cleanup(func() { m.StopAllVMs() })
+
+	return m, fake
+}
+
+func createTestVM(m *Manager, ip string) (*SimplifiedVM, error) {
+	return m.CreateVM(ip, "vmlinux", "rootfs.ext4", "192.168.100.1", MachineConfig{})
+}
+
+// checkUniqueSlots fails if two live VMs share a slot, tap, CID or MAC.
+func checkUniqueSlots(t *testing.T, m *Manager) {
+	t.Helper()
+
+	seen := make(map[string]string)
+	for _, vm := range m.ListVMs() {
+		for _, key := range []string{
+			fmt.Sprintf("slot %d", vm.VMID),
+			"tap " + vm.TapName,
+			fmt.Sprintf("cid %d", vm.VsockCID),
+			"mac " + vm.MacAddress,
+		} {
+			if other, ok := seen[key]; ok {
+				t.Errorf("%s used by both %s and %s", key, other, vm.IP)
+			}
+			seen[key] = vm.IP
+		}
+	}
+}
+
+func TestSlotAllocatorReusesLowestFreeSlot(t *testing.T) {
+	a := newSlotAllocator()
+	for want := 0; want < 3; want++ {
+		got, err := a.allocate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("allocate() = %d, want %d", got, want)
+		}
+	}
+
+	a.release(1)
+	if got, _ := a.allocate(); got != 1 {
+		t.Fatalf("allocate() after release(1) = %d, want 1", got)
+	}
+
+	if err := a.reserve(2); err == nil {
+		t.Fatal("reserve(2) succeeded for a slot in use")
+	}
+}
+
+func TestSlotMacAddress(t *testing.T) {
+	tests := map[int]string{
+		0:   "AA:FC:00:00:00:01",
+		254: "AA:FC:00:00:00:FF",
+		255: "AA:FC:00:00:01:00",
+	}
+	for slot, want := range tests {
+		if got := slotMacAddress(slot); got != want {
+			t.Errorf("slotMacAddress(%d) = %s, want %s", slot, got, want)
+		}
+	}
+}
+
+func TestManagerConcurrentCreate(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	const n = 32
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := createTestVM(m, fmt.Sprintf("192.168.100.%d", i+2)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	vms := m.ListVMs()
+	if len(vms) != n {
+		t.Fatalf("got %d VMs, want %d", len(vms), n)
+	}
+	for i, vm := range vms {
+		if vm.VMID != i {
+			t.Errorf("VM %s got slot %d, want %d", vm.IP, vm.VMID, i)
+		}
+		if vm.State() != StateRunning {
+			t.Errorf("VM %s is %s, want %s", vm.IP, vm.State(), StateRunning)
+		}
+	}
+	checkUniqueSlots(t, m)
+}
+
+func TestManagerConcurrentCreateSameIP(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	const n = 16
+	var created atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := createTestVM(m, "192.168.100.2"); err == nil {
+				created.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := created.Load(); got != 1 {
+		t.Fatalf("%d creates succeeded, want 1", got)
+	}
+	if got := len(m.ListVMs()); got != 1 {
+		t.Fatalf("got %d VMs, want 1", got)
+	}
+}
+
+func TestManagerConcurrentCreateDelete(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	const n = 16
+	for i := 0; i < n; i++ {
+		if _, err := createTestVM(m, fmt.Sprintf("192.168.100.%d", i+2)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// delete the even VMs while creating replacements in parallel
+	var wg sync.WaitGroup
+	for i := 0; i < n; i += 2 {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := m.DeleteVM(fmt.Sprintf("192.168.100.%d", i+2)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := createTestVM(m, fmt.Sprintf("192.168.101.%d", i+2)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(m.ListVMs()); got != n {
+		t.Fatalf("got %d VMs, want %d", got, n)
+	}
+	checkUniqueSlots(t, m)
+
+	// once everything is deleted the slots start from zero again
+	for _, vm := range m.ListVMs() {
+		if err := m.DeleteVM(vm.IP); err != nil {
+			t.Fatal(err)
+		}
+	}
+	vm, err := createTestVM(m, "192.168.100.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.VMID != 0 || vm.TapName != "tap0" || vm.VsockCID != 3 {
+		t.Fatalf("got slot %d, %s, CID %d, want slot 0, tap0, CID 3", vm.VMID, vm.TapName, vm.VsockCID)
+	}
+}
+
+func TestManagerReplacesFailedVM(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	vm, err := createTestVM(m, "192.168.100.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.setFailed(errors.New("boom"))
+
+	replacement, err := createTestVM(m, "192.168.100.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replacement.VMID != vm.VMID {
+		t.Fatalf("replacement got slot %d, want %d", replacement.VMID, vm.VMID)
+	}
+	if got := len(m.ListVMs()); got != 1 {
+		t.Fatalf("got %d VMs, want 1", got)
+	}
+}
+
+func TestManagerPauseResume(t *testing.T) {
+	m, fake := newTestManager(t)
+
+	vm, err := createTestVM(m, "192.168.102.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.ResumeVM(vm.IP); err == nil {
+		t.Fatal("resuming a running VM succeeded")
+	}
+	if err := m.PauseVM(vm.IP); err != nil {
+		t.Fatal(err)
+	}
+	if vm.State() != StatePaused || fake.Machines()[0].State() != hypervisor.FakeStatePaused {
+		t.Fatalf("VM is %s, machine is %s after pause", vm.State(), fake.Machines()[0].State())
+	}
+	if err := m.ResumeVM(vm.IP); err != nil {
+		t.Fatal(err)
+	}
+	if vm.State() != StateRunning {
+		t.Fatalf("VM is %s after resume, want %s", vm.State(), StateRunning)
+	}
+}
+
+func TestManagerSnapshotAndRestore(t *testing.T) {
+	m, fake := newTestManager(t)
+
+	vm, err := m.CreateVM("192.168.102.3", "vmlinux", "rootfs.ext4", "192.168.102.1", MachineConfig{TrackDirtyPages: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.CreateSnapshot(vm.IP, SnapshotTypeDiff, false); err == nil {
+		t.Fatal("diff snapshot without a parent succeeded")
+	}
+
+	full, err := m.CreateSnapshot(vm.IP, SnapshotTypeFull, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.State() != StateRunning {
+		t.Fatalf("VM is %s after snapshot, want %s", vm.State(), StateRunning)
+	}
+
+	diff, err := m.CreateSnapshot(vm.IP, SnapshotTypeDiff, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.ParentID != full.ID {
+		t.Fatalf("diff parent is %q, want %q", diff.ParentID, full.ID)
+	}
+	if vm.State() != StatePaused {
+		t.Fatalf("VM is %s after snapshot with keepPaused, want %s", vm.State(), StatePaused)
+	}
+
+	// the merged memory keeps the first page of the full snapshot and takes
+	// the dirty second page from the diff
+	mem, err := os.ReadFile(diff.MemFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(bytes.Repeat([]byte{'f'}, 4096), bytes.Repeat([]byte{'d'}, 4096)...)
+	if !bytes.Equal(mem, want) {
+		t.Fatal("merged diff memory file does not match")
+	}
+
+	if _, err := m.RestoreFromSnapshot(diff.ID, true); err == nil {
+		t.Fatal("restoring over a live VM succeeded")
+	}
+	if err := m.DeleteVM(vm.IP); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := m.RestoreFromSnapshot(diff.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.VMID != vm.VMID || restored.TapName != vm.TapName || restored.MacAddress != vm.MacAddress {
+		t.Fatalf("restored VM got slot %d, %s, %s, want %d, %s, %s",
+			restored.VMID, restored.TapName, restored.MacAddress, vm.VMID, vm.TapName, vm.MacAddress)
+	}
+	if restored.State() != StatePaused {
+		t.Fatalf("restored VM is %s, want %s", restored.State(), StatePaused)
+	}
+
+	cfg := fake.Machines()[1].Config()
+	if cfg.Snapshot == nil || cfg.Snapshot.MemFilePath != diff.MemFilePath {
+		t.Fatalf("restored machine did not boot from snapshot %s", diff.ID)
+	}
+	if vms := m.runs.Current().VMs; len(vms) != 2 || vms[0].Kernel.Path != "vmlinux" || vms[1].Snapshot != diff.ID {
+		t.Fatalf("got run manifest VMs %+v, want the VM and its restore from %s", vms, diff.ID)
+	}
+
+	// snapshots survive a manager restart
+	reloaded, err := NewManager(m.config, m.vmCtx, m.runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.getSnapshot(full.ID) == nil || reloaded.getSnapshot(diff.ID) == nil {
+		t.Fatal("snapshots were not reloaded from disk")
+	}
+}
+
+func TestManagerSnapshotResumeFailure(t *testing.T) {
+	m, fake := newTestManager(t)
+
+	vm, err := m.CreateVM("192.168.102.3", "vmlinux", "rootfs.ext4", "192.168.102.1", MachineConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Machines()[0].FailResume(errors.New("resume failed"))
+	snap, err := m.CreateSnapshot(vm.IP, SnapshotTypeFull, false)
+	if err == nil || !strings.Contains(err.Error(), "resume failed") {
+		t.Fatalf("got %v, want the VM to fail to resume", err)
+	}
+	if vm.State() != StatePaused {
+		t.Fatalf("VM is %s, want %s", vm.State(), StatePaused)
+	}
+
+	// the snapshot was written before the VM failed to resume, so it is kept
+	if snap == nil || m.getSnapshot(snap.ID) != snap || vm.lastSnapshotID != snap.ID {
+		t.Fatalf("got snapshot %+v, want it recorded", snap)
+	}
+	if _, err := os.Stat(filepath.Join(m.snapshotsDir, snap.ID, snapshotMetadataFile)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.RestoreFromSnapshot(snap.ID, false); err == nil {
+		t.Fatal("restored over the live VM")
+	}
+}
+
+func TestManagerAllocatesIPs(t *testing.T) {
+	m, _ := newTestManager(t)
+
+	leasesDir := t.TempDir()
+	var err error
+	m.ipam, err = network.NewIPAM("10.0.0.0/29", leasesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// .0 is the network, .1 the gateway and .7 the broadcast address
+	if _, err := createTestVM(m, "10.0.0.3"); err != nil {
+		t.Fatal(err)
+	}
+	var ips []string
+	for i := 0; i < 4; i++ {
+		vm, err := m.CreateVM("", "vmlinux", "rootfs.ext4", "", MachineConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if vm.GatewayIP != "10.0.0.1" || vm.PrefixLength != 29 {
+			t.Errorf("vm %s has gateway %s/%d, want 10.0.0.1/29", vm.IP, vm.GatewayIP, vm.PrefixLength)
+		}
+		ips = append(ips, vm.IP)
+	}
+	if got := fmt.Sprint(ips); got != "[10.0.0.2 10.0.0.4 10.0.0.5 10.0.0.6]" {
+		t.Fatalf("allocated %s", got)
+	}
+
+	if _, err := m.CreateVM("", "vmlinux", "rootfs.ext4", "", MachineConfig{}); err == nil {
+		t.Fatal("allocated an address from a full subnet")
+	}
+	for _, ip := range []string{"10.0.0.1", "10.0.0.7", "10.0.1.2"} {
+		if _, err := createTestVM(m, ip); err == nil {
+			t.Errorf("created a VM with reserved or foreign address %s", ip)
+		}
+	}
+
+	if err := m.DeleteVM("10.0.0.4"); err != nil {
+		t.Fatal(err)
+	}
+	vm, err := m.CreateVM("", "vmlinux", "rootfs.ext4", "", MachineConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.IP != "10.0.0.4" {
+		t.Fatalf("allocated %s after deleting 10.0.0.4", vm.IP)
+	}
+
+	// leases survive a restart
+	reloaded, err := network.NewIPAM("10.0.0.0/29", leasesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(reloaded.Leases()); got != 5 {
+		t.Fatalf("reloaded %d leases, want 5", got)
+	}
+}
+
+func TestManagerPassesRateLimiters(t *testing.T) {
+	m, fake := newTestManager(t)
+
+	rx := &hypervisor.RateLimiter{Bandwidth: &hypervisor.TokenBucket{Size: 1 << 20, RefillTimeMs: 100}}
+	drive := &hypervisor.RateLimiter{Ops: &hypervisor.TokenBucket{Size: 1000, OneTimeBurst: 5000, RefillTimeMs: 1000}}
+	cfg := MachineConfig{NetworkRxRateLimiter: rx, DriveRateLimiter: drive}
+	if _, err := m.CreateVM("192.168.104.2", "vmlinux", "rootfs.ext4", "", cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	got := fake.Machines()[0].Config()
+	if got.Network.RxRateLimiter != rx || got.Network.TxRateLimiter != nil || got.RootfsRateLimiter != drive {
+		t.Fatalf("machine got rate limiters rx %+v, tx %+v, drive %+v", got.Network.RxRateLimiter, got.Network.TxRateLimiter, got.RootfsRateLimiter)
+	}
+
+	bad := MachineConfig{NetworkTxRateLimiter: &hypervisor.RateLimiter{Bandwidth: &hypervisor.TokenBucket{Size: 1 << 20}}}
+	if _, err := m.CreateVM("192.168.104.3", "vmlinux", "rootfs.ext4", "", bad); err == nil {
+		t.Fatal("created a VM with a rate limiter without refill time")
+	}
+}
+
+func TestManagerUsesConfiguredHost(t *testing.T) {
+	m, fake := newTestManager(t)
+	m.config.SocketDir = t.TempDir()
+	m.config.TapPrefix = "fc1tap"
+
+	vm, err := createTestVM(m, "192.168.104.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if vm.TapName != "fc1tap0" {
+		t.Errorf("VM got tap %s, want fc1tap0", vm.TapName)
+	}
+	got := fake.Machines()[0].Config()
+	for _, path := range []string{got.SocketPath, got.Vsock.Path} {
+		if filepath.Dir(path) != m.config.SocketDir {
+			t.Errorf("socket %s is not in %s", path, m.config.SocketDir)
+		}
+	}
+	if filepath.Dir(got.LogPath) != m.runs.Dir(run.VMLogsDir) {
+		t.Errorf("log %s is not in the current run", got.LogPath)
+	}
+}
+
+func TestManagerMarksCrashedVMFailed(t *testing.T) {
+	m, fake := newTestManager(t)
+
+	vm, err := createTestVM(m, "192.168.102.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake.Machines()[0].Crash(errors.New("signal: killed"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for vm.State() != StateFailed {
+		if time.Now().After(deadline) {
+			t.Fatalf("VM is %s after crash, want %s", vm.State(), StateFailed)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if status := vm.Status(); status.Error == "" || status.StoppedAt.IsZero() {
+		t.Fatalf("failed VM status is missing error or stop time: %+v", status)
+	}
+}
+
+func TestManagerSendClientCommandCancel(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.SetVsockHandler(hypervisor.CommandHandler(func(command string, out io.Writer) error {
+		fmt.Fprintf(out, "started\n")
+		// runs until the host hangs up
+		_, err := io.Copy(io.Discard, out.(io.Reader))
+		return err
+	}))
+	if _, err := createTestVM(m, "192.168.102.6"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var lines []string
+	result, err := m.SendClientCommand(ctx, "192.168.102.6", "sleep infinity", 0, func(job.Job) {}, func(out job.Output) {
+		lines = append(lines, out.Line)
+		cancel()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lines, []string{"started"}) {
+		t.Fatalf("got output %q, want the line before the cancel", lines)
+	}
+	if !result.Killed || result.ExitCode != -1 {
+		t.Fatalf("got %+v, want a killed command", result)
+	}
+}
+
+func TestManagerProbesLegacyAgentOnce(t *testing.T) {
+	m, fake := newTestManager(t)
+	var mu sync.Mutex
+	var commands []string
+	fake.SetVsockHandler(hypervisor.CommandHandler(func(command string, out io.Writer) error {
+		mu.Lock()
+		commands = append(commands, command)
+		mu.Unlock()
+		_, err := fmt.Fprintf(out, "%s\n", command)
+		return err
+	}))
+	if _, err := createTestVM(m, "192.168.102.8"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, command := range []string{"echo a", "echo b"} {
+		if _, err := m.SendClientCommand(context.Background(), "192.168.102.8", command, 0, func(job.Job) {}, func(job.Output) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the agent runs the probe as a command, so it must run only once
+	mu.Lock()
+	defer mu.Unlock()
+	if len(commands) != 3 || !strings.HasPrefix(commands[0], "HELLO ") || commands[1] != "echo a" || commands[2] != "echo b" {
+		t.Fatalf("got commands %q, want one probe", commands)
+	}
+}
+
+func TestManagerJobLogs(t *testing.T) {
+	m, _ := newTestManager(t)
+	if _, err := createTestVM(m, "192.168.102.9"); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := m.SendServerCommand("192.168.102.9", "iperf3 -s", false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var client job.Job
+	if _, err := m.SendClientCommand(context.Background(), "192.168.102.9", "iperf3 -c 192.168.102.1", 0, func(j job.Job) { client = j }, func(job.Output) {}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		j, err := m.GetJob(server.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j.State != job.Running {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("server command did not exit")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// each job logs to its own file, which the job reports
+	for _, tt := range []struct {
+		j      job.Job
+		output string
+	}{
+		{server, "[OUTPUT] iperf3 -s\n"},
+		{client, "[OUTPUT] iperf3 -c 192.168.102.1\n"},
+	} {
+		want := filepath.Join(m.runs.Dir(run.VMTestDir), fmt.Sprintf("vm-192.168.102.9-%s.log", tt.j.ID))
+		if got, _ := m.GetJob(tt.j.ID); tt.j.LogPath != want || got.LogPath != want {
+			t.Errorf("got log %s and %s for job %s, want %s", tt.j.LogPath, got.LogPath, tt.j.ID, want)
+		}
+		data, err := os.ReadFile(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(data), tt.output) {
+			t.Errorf("got log %q for job %s, want it to start with %q", data, tt.j.ID, tt.output)
+		}
+	}
+}
+
+func TestManagerExecEndsWithVMs(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.SetVsockHandler(func(_ uint32, conn net.Conn) { agent.ServeConn(conn) })
+	vmCtx, stopVMs := context.WithCancel(context.Background())
+	defer stopVMs()
+	m.vmCtx = vmCtx
+	vm, err := createTestVM(m, "192.168.102.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// StopAllVMs does nothing once the VMs were stopped with vmCtx
+	defer vm.Stop(context.Background())
+
+	// the call that started the exec is still open
+	j, handle, err := m.Exec(context.Background(), "192.168.102.10", job.Spec{Argv: []string{"sleep", "60"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stopVMs()
+
+	start := time.Now()
+	result, err := handle.Wait(func(job.Stream, []byte) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Killed || time.Since(start) > 10*time.Second {
+		t.Fatalf("got %+v after %s, want a killed command", result, time.Since(start))
+	}
+	if got, _ := m.GetJob(j.ID); got.State != job.Cancelled {
+		t.Fatalf("got job %+v, want it cancelled", got)
+	}
+}
+
+func TestManagerSendClientCommandAgent(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.SetVsockHandler(func(_ uint32, conn net.Conn) { agent.ServeConn(conn) })
+	if _, err := createTestVM(m, "192.168.102.7"); err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []job.Output
+	result, err := m.SendClientCommand(context.Background(), "192.168.102.7", "echo out; sleep 0.1; echo err >&2; exit 4", 0, func(job.Job) {}, func(out job.Output) {
+		lines = append(lines, out)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []job.Output{{Stream: job.Stdout, Line: "out"}, {Stream: job.Stderr, Line: "err"}}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("got output %v, want %v", lines, want)
+	}
+	if result.ExitCode != 4 || result.Killed {
+		t.Fatalf("got %+v, want exit code 4", result)
+	}
+
+	// cancelling kills the command in the guest
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	result, err = m.SendClientCommand(ctx, "192.168.102.7", "echo started; sleep 60", 0, func(job.Job) {}, func(job.Output) { cancel() })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Killed || time.Since(start) > 10*time.Second {
+		t.Fatalf("got %+v after %s, want a killed command", result, time.Since(start))
+	}
+}
+
+func TestManagerVMMetrics(t *testing.T) {
+	m, fake := newTestManager(t)
+
+	vm, err := createTestVM(m, "192.168.102.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	machine := fake.Machines()[0]
+
+	flush := func(metrics map[string]uint64) time.Time {
+		t.Helper()
+		for name, n := range metrics {
+			machine.AddMetric(name, n)
+		}
+		if err := machine.FlushMetrics(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		flushed := time.Now()
+		// samples are timestamped in milliseconds
+		time.Sleep(5 * time.Millisecond)
+		return flushed
+	}
+	flush(map[string]uint64{"net_eth0.rx_bytes_count": 100, "latencies_us.pause_vm": 30})
+	first := flush(map[string]uint64{"net_eth0.rx_bytes_count": 50, "vcpu.exit_io_in": 7})
+	flush(map[string]uint64{"net_eth0.rx_bytes_count": 25, "latencies_us.pause_vm": 20})
+
+	all, err := vm.Metrics(time.Time{}, time.Time{}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]uint64{"net_eth0.rx_bytes_count": 175, "vcpu.exit_io_in": 7, "latencies_us.pause_vm": 20}
+	if !reflect.DeepEqual(all.Delta, want) {
+		t.Fatalf("delta of every sample is %v, want %v", all.Delta, want)
+	}
+	if all.Samples != nil {
+		t.Fatalf("got %d samples without asking for the series", len(all.Samples))
+	}
+
+	later, err := vm.Metrics(first, time.Time{}, []string{"net_eth0."}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]uint64{"net_eth0.rx_bytes_count": 25}; !reflect.DeepEqual(later.Delta, want) {
+		t.Fatalf("delta after the second flush is %v, want %v", later.Delta, want)
+	}
+	if len(later.Samples) == 0 || !later.From.After(first.Truncate(time.Millisecond)) || later.To.Before(later.From) {
+		t.Fatalf("samples after the second flush: from %v to %v, %d samples", later.From, later.To, len(later.Samples))
+	}
+
+	none, err := vm.Metrics(time.Time{}, all.From.Add(-time.Millisecond), nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none.Delta) != 0 || len(none.Samples) != 0 || !none.From.IsZero() {
+		t.Fatalf("metrics before the first sample: %+v", none)
+	}
+}
+
+// useFakeTracer makes the tracing sessions of m count the syscalls passed
+// to the returned fake.
+func useFakeTracer(m *Manager) *tracer.Fake {
+	fake := tracer.NewFake()
+	m.syscalls = tracer.NewSessions(fake, m.syscallsLogPath)
+	return fake
+}
+
+func waitForTraces(fake *tracer.Fake, n int) {
+	for fake.Traces() != n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestManagerTraceSessions(t *testing.T) {
+	m, _ := newTestManager(t)
+	fake := useFakeTracer(m)
+	var pids []int
+	for _, ip := range []string{"192.168.100.2", "192.168.100.3"} {
+		vm, err := createTestVM(m, ip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pid, _ := vm.Machine.PID()
+		pids = append(pids, pid)
+	}
+
+	netSession, err := m.TrackSyscalls([]string{"192.168.100.2"}, []string{"network"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := m.TrackSyscalls(nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Targets) != 2 {
+		t.Fatalf("got targets %v, want both VMs", all.Targets)
+	}
+
+	fake.Add(pids[0], pids[0], "firecracker", "sendto", 3)
+	fake.Add(pids[0], pids[0], "firecracker", "ioctl", 42)
+	fake.Add(pids[1], pids[1], "firecracker", "ioctl", 7)
+
+	// stopping one session leaves the other running
+	if err := m.StopSyscalls(netSession.ID); err != nil {
+		t.Fatal(err)
+	}
+	waitForTraces(fake, 2)
+
+	logs, err := os.ReadFile(filepath.Join(m.runs.Dir(run.VMSyscallsDir), netSession.ID, "vm-192.168.100.2.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "@total[firecracker, tracepoint:syscalls:sys_enter_sendto]: 3\n=== END OF TRACE ===\n"; !strings.HasSuffix(string(logs), want) {
+		t.Fatalf("got syscall log\n%s\nwant it to end with\n%s", logs, want)
+	}
+
+	stats, err := m.SyscallStats(netSession.ID, "192.168.100.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []tracer.SyscallCount{{Comm: "firecracker", Syscall: "sendto", Count: 3}}
+	if got := stats[0].Snapshot.Total.BySyscall(); !stats[0].Snapshot.Final || !reflect.DeepEqual(got, want) {
+		t.Fatalf("got final=%v total %v, want final total %v", stats[0].Snapshot.Final, got, want)
+	}
+	if _, err := m.SyscallStats(netSession.ID, "192.168.100.3"); err == nil {
+		t.Fatal("got stats of a VM outside the session")
+	}
+
+	if err := m.StopSyscalls(""); err != nil {
+		t.Fatal(err)
+	}
+	waitForTraces(fake, 0)
+
+	// the latest session is the default
+	stats, err = m.SyscallStats("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 || stats[0].Session != all.ID || stats[1].Snapshot.Total.BySyscall()[0].Count != 7 {
+		t.Fatalf("got stats %+v, want the counts of both VMs in session %s", stats, all.ID)
+	}
+
+	// tracing works again after every session was stopped
+	again, err := m.TrackSyscalls([]string{"192.168.100.3"}, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForTraces(fake, 1)
+	if sessions := m.ListTraceSessions(); len(sessions) != 3 || sessions[2].ID != again.ID || !sessions[2].StoppedAt.IsZero() {
+		t.Fatalf("got sessions %+v, want the new session running after the two stopped ones", sessions)
+	}
+	m.StopSyscalls(again.ID)
+}
+
+func TestManagerTrackSyscallsRejectsUnknownSyscalls(t *testing.T) {
+	m, _ := newTestManager(t)
+	useFakeTracer(m)
+	if _, err := createTestVM(m, "192.168.100.2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.TrackSyscalls(nil, []string{"sendto", "no_such_syscall"}, false); err == nil {
+		t.Fatal("traced an unknown syscall")
+	}
+	if _, err := m.TrackSyscalls([]string{"192.168.100.9"}, nil, false); err == nil {
+		t.Fatal("traced an unknown VM")
+	}
+}
+
+func TestManagerSyscallLatency(t *testing.T) {
+	m, _ := newTestManager(t)
+	fake := useFakeTracer(m)
+	vm, err := createTestVM(m, "192.168.100.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, _ := vm.Machine.PID()
+
+	session, err := m.TrackSyscalls(nil, []string{"sendmsg"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForTraces(fake, 1)
+	fake.AddLatency(pid, pid+1, "fc_vcpu 0", "sendmsg", 3*time.Microsecond)
+	fake.AddLatency(pid, pid+1, "fc_vcpu 0", "sendmsg", 5*time.Microsecond)
+	fake.AddLatency(pid, pid+1, "fc_vcpu 0", "recvmsg", time.Millisecond) // filtered out
+
+	if err := m.StopSyscalls(session.ID); err != nil {
+		t.Fatal(err)
+	}
+	waitForTraces(fake, 0)
+
+	stats, err := m.SyscallStats(session.ID, vm.IP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latencies := stats[0].Snapshot.TotalLatency.ByThread()
+	if len(latencies) != 1 || latencies[0].TID != pid+1 || latencies[0].Syscall != "sendmsg" {
+		t.Fatalf("got latencies %+v, want sendmsg of thread %d only", latencies, pid+1)
+	}
+	if l := latencies[0].Latency; l.Count != 2 || l.Mean() != 4*time.Microsecond {
+		t.Fatalf("got %d calls with a mean of %s, want 2 with a mean of 4µs", l.Count, l.Mean())
+	}
+
+	logs, err := os.ReadFile(filepath.Join(m.runs.Dir(run.VMSyscallsDir), session.ID, "vm-192.168.100.2.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("@total_time_ns[fc_vcpu 0, %d, tracepoint:syscalls:sys_enter_sendmsg]: 8000\n=== END OF TRACE ===\n", pid+1)
+	if !strings.HasSuffix(string(logs), want) {
+		t.Fatalf("got syscall log\n%s\nwant it to end with\n%s", logs, want)
+	}
+}
+
+func TestManagerWatchSyscalls(t *testing.T) {
+	m, _ := newTestManager(t)
+	fake := useFakeTracer(m)
+	vm, err := createTestVM(m, "192.168.100.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, _ := vm.Machine.PID()
+
+	session, err := m.TrackSyscalls(nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// two watchers, each collecting every update until the session stops
+	results := make(chan uint64, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			var total uint64
+			m.WatchSyscalls(context.Background(), session.ID, vm.IP, func(stats tracer.TargetStats) error {
+				for _, c := range stats.Snapshot.Interval.BySyscall() {
+					total += c.Count
+				}
+				return nil
+			})
+			results <- total
+		}()
+	}
+	for m.syscalls.Watchers(session.ID) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	fake.Add(pid, pid, "firecracker", "ioctl", 5)
+	fake.Add(pid, pid+1, "fc_vcpu 0", "ioctl", 7)
+	if err := m.StopSyscalls(session.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case total := <-results:
+			if total != 12 {
+				t.Fatalf("watcher got %d syscalls in its deltas, want 12", total)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("WatchSyscalls did not return after StopSyscalls")
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package vm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/hypervisor"
+)
+
+// metricsInterval is how often the metrics of a running VM are flushed and
+// read.
+const metricsInterval = time.Second
+
+// maxMetricsSamples is the most samples kept per VM, an hour at
+// metricsInterval. Older samples are dropped but stay in the metrics file.
+const maxMetricsSamples = 3600
+
+// Metrics are the firecracker metrics of a VM between two times.
+type Metrics struct {
+	// From and To are the times of the first and last sample, zero if
+	// there are none
+	From time.Time
+	To   time.Time
+	// Delta sums every counter over the samples. Timings, see
+	// hypervisor.IsTiming, hold their latest value instead.
+	Delta   map[string]uint64
+	Samples []hypervisor.MetricsSample
+}
+
+// metricsSeries is the time series of a VM's metrics, read from the file
+// firecracker appends them to.
+type metricsSeries struct {
+	path string
+
+	mu      sync.Mutex
+	offset  int64 // of the first line not read yet
+	samples []hypervisor.MetricsSample
+	totals  map[string]uint64 // of every sample, including dropped ones
+}
+
+// newMetricsSeries reads the metrics appended to the file at path from now
+// on, skipping those of an earlier VM with the same IP.
+func newMetricsSeries(path string) *metricsSeries {
+	s := &metricsSeries{path: path, totals: make(map[string]uint64)}
+	if info, err := os.Stat(path); err == nil {
+		s.offset = info.Size()
+	}
+	return s
+}
+
+// read appends the samples of the lines written since the last read. A line
+// still being written is left for the next read.
+func (s *metricsSeries) read() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open metrics file: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(s.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read metrics file: %v", err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("failed to read metrics file: %v", err)
+	}
+
+	for {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			return nil
+		}
+		line := data[:end]
+		data = data[end+1:]
+		s.offset += int64(end + 1)
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		sample, err := hypervisor.ParseMetrics(line)
+		if err != nil {
+			log.Printf("Skipping metrics line of %s: %v", s.path, err)
+			continue
+		}
+		for name, value := range sample.Values {
+			if hypervisor.IsTiming(name) {
+				s.totals[name] = value
+			} else {
+				s.totals[name] += value
+			}
+		}
+		s.samples = append(s.samples, sample)
+		if len(s.samples) > maxMetricsSamples {
+			s.samples = s.samples[len(s.samples)-maxMetricsSamples:]
+		}
+	}
+}
+
+// query returns the metrics of the samples taken after from and up to to,
+// where a zero time leaves that end open. With prefixes, only the metrics
+// whose names start with one of them are kept. series also returns the
+// samples themselves.
+func (s *metricsSeries) query(from, to time.Time, prefixes []string, series bool) Metrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	metrics := Metrics{Delta: make(map[string]uint64)}
+	for _, sample := range s.samples {
+		if !from.IsZero() && !sample.Time.After(from) {
+			continue
+		}
+		if !to.IsZero() && sample.Time.After(to) {
+			break
+		}
+
+		if metrics.From.IsZero() {
+			metrics.From = sample.Time
+		}
+		metrics.To = sample.Time
+
+		values := make(map[string]uint64)
+		for name, value := range sample.Values {
+			if !hasAnyPrefix(name, prefixes) {
+				continue
+			}
+			values[name] = value
+			if hypervisor.IsTiming(name) {
+				metrics.Delta[name] = value
+			} else {
+				metrics.Delta[name] += value
+			}
+		}
+		if series {
+			metrics.Samples = append(metrics.Samples, hypervisor.MetricsSample{Time: sample.Time, Values: values})
+		}
+	}
+	return metrics
+}
+
+func hasAnyPrefix(name string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// collectMetrics flushes and reads the metrics of the VM every
+// metricsInterval until it stops, then reads what is left.
+func (v *SimplifiedVM) collectMetrics(ctx context.Context) {
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+
+	flushFailed := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		state := v.State()
+		if state == StateRunning || state == StatePaused {
+			if err := v.Machine.FlushMetrics(ctx); err != nil && !flushFailed {
+				// firecracker still flushes on its own every minute
+				log.Printf("Failed to flush metrics of VM %d: %v", v.VMID, err)
+				flushFailed = true
+			}
+		}
+		if err := v.metrics.read(); err != nil {
+			log.Printf("Failed to read metrics of VM %d: %v", v.VMID, err)
+		}
+
+		if state != StateRunning && state != StatePaused {
+			return
+		}
+	}
+}
+
+// Metrics returns the metrics of the VM collected after from and up to to,
+// see metricsSeries.query.
+func (v *SimplifiedVM) Metrics(from, to time.Time, prefixes []string, series bool) (Metrics, error) {
+	if err := v.metrics.read(); err != nil {
+		return Metrics{}, err
+	}
+	return v.metrics.query(from, to, prefixes, series), nil
+}
+
+// MetricsTotals returns every counter of the VM summed since it started, and
+// the latest value of every timing.
+func (v *SimplifiedVM) MetricsTotals() map[string]uint64 {
+	v.metrics.mu.Lock()
+	defer v.metrics.mu.Unlock()
+
+	totals := make(map[string]uint64, len(v.metrics.totals))
+	for name, value := range v.metrics.totals {
+		totals[name] = value
+	}
+	return totals
+}
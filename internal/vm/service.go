@@ -2,8 +2,22 @@ package vm
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/bookpanda/firecracker-runner-node/internal/agent"
+	"github.com/bookpanda/firecracker-runner-node/internal/hypervisor"
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
+	"github.com/bookpanda/firecracker-runner-node/internal/protoconv"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
 	proto "github.com/bookpanda/firecracker-runner-node/proto/vm/v1"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -11,73 +25,545 @@ import (
 
 type Service interface {
 	proto.VmServiceServer
+	// Manager returns the manager in use, which Cleanup replaces
+	Manager() *Manager
 }
 
 type serviceImpl struct {
 	proto.UnimplementedVmServiceServer
-	manager *Manager
-	log     *zap.Logger
+	log *zap.Logger
+
+	// cleanupMu serializes Cleanup; readers load manager without it
+	cleanupMu sync.Mutex
+	manager   atomic.Pointer[Manager]
 }
 
 func NewService(manager *Manager, log *zap.Logger) Service {
-	return &serviceImpl{
-		manager: manager,
-		log:     log,
-	}
+	s := &serviceImpl{log: log}
+	s.manager.Store(manager)
+	return s
+}
+
+func (s *serviceImpl) Manager() *Manager {
+	return s.manager.Load()
 }
 
 func (s *serviceImpl) Create(_ context.Context, req *proto.CreateVmRequest) (*proto.CreateVmResponse, error) {
-	vm, err := s.manager.CreateVM(req.Ip, req.KernelPath, req.RootfsPath, req.GatewayIP)
+	machineCfg := MachineConfig{
+		VcpuCount:       req.VcpuCount,
+		MemSizeMib:      req.MemSizeMib,
+		Smt:             req.Smt,
+		CPUTemplate:     req.CpuTemplate,
+		KernelArgs:      req.KernelArgs,
+		Nameservers:     req.Nameservers,
+		TrackDirtyPages: req.TrackDirtyPages,
+
+		NetworkRxRateLimiter: rateLimiterFromProto(req.NetworkRxRateLimiter),
+		NetworkTxRateLimiter: rateLimiterFromProto(req.NetworkTxRateLimiter),
+		DriveRateLimiter:     rateLimiterFromProto(req.DriveRateLimiter),
+	}
+
+	vm, err := s.Manager().CreateVM(req.Ip, req.KernelPath, req.RootfsPath, req.GatewayIP, machineCfg)
 	if err != nil {
 		return nil, err
 	}
-	return &proto.CreateVmResponse{Vm: &proto.Vm{Ip: vm.IP, KernelPath: vm.KernelPath, RootfsPath: vm.RootfsPath}}, nil
+	return &proto.CreateVmResponse{Vm: vmToProto(vm)}, nil
 }
 
 func (s *serviceImpl) SendServerCommand(_ context.Context, req *proto.SendServerCommandVmRequest) (*proto.SendServerCommandVmResponse, error) {
-	if err := s.manager.SendServerCommand(req.Ip, req.Command, req.Wait); err != nil {
+	j, err := s.Manager().SendServerCommand(req.Ip, req.Command, req.Wait, protoconv.Timeout(req.TimeoutMs))
+	if err != nil {
 		return nil, err
 	}
 
-	return &proto.SendServerCommandVmResponse{}, nil
+	return &proto.SendServerCommandVmResponse{JobId: j.ID}, nil
 }
 
 func (s *serviceImpl) SendClientCommand(req *proto.SendClientCommandVmRequest, stream grpc.ServerStreamingServer[proto.SendClientCommandVmResponse]) error {
-	if err := s.manager.SendClientCommand(req.Ip, req.Command); err != nil {
+	// a client that stops reading cancels the stream, which stops the command
+	var jobID string
+	var sendErr error
+	result, err := s.Manager().SendClientCommand(stream.Context(), req.Ip, req.Command, protoconv.Timeout(req.TimeoutMs), func(j job.Job) {
+		jobID = j.ID
+	}, func(out job.Output) {
+		if sendErr != nil {
+			return
+		}
+		sendErr = stream.Send(&proto.SendClientCommandVmResponse{Output: out.Line, Stream: proto.OutputStream(out.Stream), JobId: jobID})
+	})
+	if err != nil {
+		return err
+	}
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return stream.Send(&proto.SendClientCommandVmResponse{Exit: protoconv.CommandExit[*proto.CommandExit](result), JobId: jobID})
+}
+
+func (s *serviceImpl) Exec(stream grpc.BidiStreamingServer[proto.ExecVmRequest, proto.ExecVmResponse]) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	start := req.Start
+	if start == nil {
+		return fmt.Errorf("the first request must start the command")
+	}
+
+	// ending the call cancels its context, which kills the command
+	j, handle, err := s.Manager().Exec(stream.Context(), start.Ip, job.Spec{
+		Argv:  start.Argv,
+		Env:   start.Env,
+		Dir:   start.Cwd,
+		TTY:   start.Tty,
+		Rows:  uint16(start.GetSize().GetRows()),
+		Cols:  uint16(start.GetSize().GetCols()),
+		Stdin: start.Stdin,
+	}, protoconv.Timeout(start.TimeoutMs))
+	if err != nil {
 		return err
 	}
 
-	response := &proto.SendClientCommandVmResponse{Output: "Command finished executing"}
-	if err := stream.Send(response); err != nil {
+	sendErr := stream.Send(&proto.ExecVmResponse{Pid: int64(j.Pid), JobId: j.ID})
+	go protoconv.ForwardExecInput[*proto.TerminalSize](stream.Recv, handle)
+	result, err := handle.Wait(func(out job.Stream, data []byte) {
+		if sendErr == nil {
+			sendErr = stream.Send(&proto.ExecVmResponse{Stream: proto.OutputStream(out), Data: data})
+		}
+	})
+	if err != nil {
 		return err
 	}
+	if sendErr != nil {
+		return sendErr
+	}
 
-	return nil
+	return stream.Send(&proto.ExecVmResponse{Exit: protoconv.CommandExit[*proto.CommandExit](result)})
+}
+
+func (s *serviceImpl) PutFile(stream grpc.BidiStreamingServer[proto.PutFileVmRequest, proto.PutFileVmResponse]) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	start := req.Start
+	if start == nil {
+		return fmt.Errorf("the first request must name the file")
+	}
+
+	// ending the call cancels its context, which aborts the upload
+	upload, err := s.Manager().PutFile(stream.Context(), start.Ip, agent.File{
+		Path:   start.Path,
+		Mode:   os.FileMode(start.Mode),
+		Size:   start.Size,
+		SHA256: start.Sha256,
+	})
+	if err != nil {
+		return err
+	}
+	defer upload.Abort()
+	if err := stream.Send(&proto.PutFileVmResponse{Offset: upload.Offset}); err != nil {
+		return err
+	}
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.Offset != upload.Offset {
+			return fmt.Errorf("got data at offset %d, want %d", req.Offset, upload.Offset)
+		}
+		if err := upload.Write(req.Data); err != nil {
+			return err
+		}
+	}
+	file, err := upload.Close()
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&proto.PutFileVmResponse{File: fileToProto(file)})
+}
+
+func (s *serviceImpl) GetFile(req *proto.GetFileVmRequest, stream grpc.ServerStreamingServer[proto.GetFileVmResponse]) error {
+	download, err := s.Manager().GetFile(stream.Context(), req.Ip, req.Path, req.Offset)
+	if err != nil {
+		return err
+	}
+	defer download.Close()
+	if err := stream.Send(&proto.GetFileVmResponse{File: fileToProto(download.File)}); err != nil {
+		return err
+	}
+
+	for {
+		offset, data, err := download.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if len(data) > 0 {
+			if err := stream.Send(&proto.GetFileVmResponse{Offset: offset, Data: data}); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *serviceImpl) ListJobs(_ context.Context, req *proto.ListJobsVmRequest) (*proto.ListJobsVmResponse, error) {
+	jobs := s.Manager().ListJobs(req.Ip)
+
+	res := &proto.ListJobsVmResponse{Jobs: make([]*proto.Job, 0, len(jobs))}
+	for _, j := range jobs {
+		res.Jobs = append(res.Jobs, jobToProto(j))
+	}
+
+	return res, nil
+}
+
+func (s *serviceImpl) GetJob(_ context.Context, req *proto.GetJobVmRequest) (*proto.GetJobVmResponse, error) {
+	j, err := s.Manager().GetJob(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.GetJobVmResponse{Job: jobToProto(j)}, nil
+}
+
+func (s *serviceImpl) CancelJob(ctx context.Context, req *proto.CancelJobVmRequest) (*proto.CancelJobVmResponse, error) {
+	j, err := s.Manager().CancelJob(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CancelJobVmResponse{Job: jobToProto(j)}, nil
 }
 
 func (s *serviceImpl) TrackSyscalls(_ context.Context, req *proto.TrackSyscallsVmRequest) (*proto.TrackSyscallsVmResponse, error) {
-	if err := s.manager.TrackSyscalls(); err != nil {
+	session, err := s.Manager().TrackSyscalls(req.Ips, req.Syscalls, req.Latency)
+	if err != nil {
 		return nil, err
 	}
 
-	return &proto.TrackSyscallsVmResponse{}, nil
+	return &proto.TrackSyscallsVmResponse{Session: traceSessionToProto(session)}, nil
 }
 
 func (s *serviceImpl) StopSyscalls(_ context.Context, req *proto.StopSyscallsVmRequest) (*proto.StopSyscallsVmResponse, error) {
-	if err := s.manager.StopSyscalls(); err != nil {
+	if err := s.Manager().StopSyscalls(req.SessionId); err != nil {
 		return nil, err
 	}
 
 	return &proto.StopSyscallsVmResponse{}, nil
 }
 
-func (s *serviceImpl) Cleanup(_ context.Context, req *proto.CleanupVmRequest) (*proto.CleanupVmResponse, error) {
-	cmd := exec.Command("sudo", "pkill", "-f", "firecracker")
+func (s *serviceImpl) ListTraceSessions(_ context.Context, req *proto.ListTraceSessionsVmRequest) (*proto.ListTraceSessionsVmResponse, error) {
+	sessions := s.Manager().ListTraceSessions()
+
+	res := &proto.ListTraceSessionsVmResponse{Sessions: make([]*proto.TraceSession, 0, len(sessions))}
+	for _, session := range sessions {
+		res.Sessions = append(res.Sessions, traceSessionToProto(session))
+	}
+
+	return res, nil
+}
+
+func (s *serviceImpl) GetSyscallStats(_ context.Context, req *proto.GetSyscallStatsVmRequest) (*proto.GetSyscallStatsVmResponse, error) {
+	stats, err := s.Manager().SyscallStats(req.SessionId, req.Ip)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &proto.GetSyscallStatsVmResponse{Stats: make([]*proto.SyscallStats, 0, len(stats))}
+	for _, stat := range stats {
+		res.Stats = append(res.Stats, protoconv.SyscallStats[*proto.SyscallStats](stat))
+	}
+
+	return res, nil
+}
+
+func (s *serviceImpl) WatchSyscalls(req *proto.WatchSyscallsVmRequest, stream grpc.ServerStreamingServer[proto.WatchSyscallsVmResponse]) error {
+	return s.Manager().WatchSyscalls(stream.Context(), req.SessionId, req.Ip, func(stats tracer.TargetStats) error {
+		return stream.Send(&proto.WatchSyscallsVmResponse{Stats: protoconv.SyscallStats[*proto.SyscallStats](stats)})
+	})
+}
+
+func (s *serviceImpl) Cleanup(ctx context.Context, req *proto.CleanupVmRequest) (*proto.CleanupVmResponse, error) {
+	s.cleanupMu.Lock()
+	defer s.cleanupMu.Unlock()
+
+	old := s.Manager()
+	// ends the traces and watchers of the old manager
+	old.StopSyscalls("")
+	// the commands of the old manager run until the VMs are stopped otherwise
+	old.CancelJobs(ctx)
+
+	// only the VMs of this runner have their API socket in its socket dir;
+	// "[-]" keeps the pattern from matching the sudo running pkill
+	apiSockets := "[-]-api-sock " + regexp.QuoteMeta(filepath.Join(old.config.SocketDir, "vm-"))
+	cmd := exec.Command("sudo", "pkill", "-f", apiSockets)
 	if err := cmd.Run(); err != nil {
+		// pkill exits with 1 if no process matched, e.g. without live VMs
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("failed to kill the firecracker processes: %v", err)
+		}
+	}
+
+	// every VM is gone, so are their leases
+	if err := old.ipam.ReleaseAll(); err != nil {
 		return nil, err
 	}
 
-	s.manager = NewManager(s.manager.config, s.manager.vmCtx)
+	manager, err := NewManager(old.config, old.vmCtx, old.runs)
+	if err != nil {
+		return nil, err
+	}
+	s.manager.Store(manager)
 
 	return &proto.CleanupVmResponse{}, nil
 }
+
+func (s *serviceImpl) Pause(_ context.Context, req *proto.PauseVmRequest) (*proto.PauseVmResponse, error) {
+	if err := s.Manager().PauseVM(req.Ip); err != nil {
+		return nil, err
+	}
+
+	return &proto.PauseVmResponse{}, nil
+}
+
+func (s *serviceImpl) Resume(_ context.Context, req *proto.ResumeVmRequest) (*proto.ResumeVmResponse, error) {
+	if err := s.Manager().ResumeVM(req.Ip); err != nil {
+		return nil, err
+	}
+
+	return &proto.ResumeVmResponse{}, nil
+}
+
+func (s *serviceImpl) CreateSnapshot(_ context.Context, req *proto.CreateSnapshotVmRequest) (*proto.CreateSnapshotVmResponse, error) {
+	snapshotType := SnapshotTypeFull
+	if req.Type == proto.SnapshotType_SNAPSHOT_TYPE_DIFF {
+		snapshotType = SnapshotTypeDiff
+	}
+
+	snap, err := s.Manager().CreateSnapshot(req.Ip, snapshotType, req.KeepPaused)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.CreateSnapshotVmResponse{Snapshot: snapshotToProto(snap)}, nil
+}
+
+func (s *serviceImpl) RestoreFromSnapshot(_ context.Context, req *proto.RestoreFromSnapshotVmRequest) (*proto.RestoreFromSnapshotVmResponse, error) {
+	vm, err := s.Manager().RestoreFromSnapshot(req.SnapshotId, req.Resume)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.RestoreFromSnapshotVmResponse{Vm: vmToProto(vm)}, nil
+}
+
+func (s *serviceImpl) ListVms(_ context.Context, req *proto.ListVmsRequest) (*proto.ListVmsResponse, error) {
+	vms := s.Manager().ListVMs()
+
+	res := &proto.ListVmsResponse{Vms: make([]*proto.Vm, 0, len(vms))}
+	for _, vm := range vms {
+		res.Vms = append(res.Vms, vmToProto(vm))
+	}
+
+	return res, nil
+}
+
+func (s *serviceImpl) GetVm(_ context.Context, req *proto.GetVmRequest) (*proto.GetVmResponse, error) {
+	vm, err := s.Manager().GetVM(req.Ip)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.GetVmResponse{Vm: vmToProto(vm)}, nil
+}
+
+func (s *serviceImpl) DeleteVm(_ context.Context, req *proto.DeleteVmRequest) (*proto.DeleteVmResponse, error) {
+	if err := s.Manager().DeleteVM(req.Ip); err != nil {
+		return nil, err
+	}
+
+	return &proto.DeleteVmResponse{}, nil
+}
+
+func (s *serviceImpl) GetVmMetrics(_ context.Context, req *proto.GetVmMetricsRequest) (*proto.GetVmMetricsResponse, error) {
+	vm, err := s.Manager().GetVM(req.Ip)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to time.Time
+	if req.From != 0 {
+		from = time.UnixMilli(req.From)
+	}
+	if req.To != 0 {
+		to = time.UnixMilli(req.To)
+	}
+	metrics, err := vm.Metrics(from, to, req.Prefixes, req.Series)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &proto.GetVmMetricsResponse{
+		From:    unixMilli(metrics.From),
+		To:      unixMilli(metrics.To),
+		Delta:   metrics.Delta,
+		Samples: make([]*proto.MetricsSample, 0, len(metrics.Samples)),
+	}
+	for _, sample := range metrics.Samples {
+		res.Samples = append(res.Samples, &proto.MetricsSample{Time: sample.Time.UnixMilli(), Values: sample.Values})
+	}
+	return res, nil
+}
+
+var vmStates = map[State]proto.VmState{
+	StateCreating: proto.VmState_VM_STATE_CREATING,
+	StateRunning:  proto.VmState_VM_STATE_RUNNING,
+	StatePaused:   proto.VmState_VM_STATE_PAUSED,
+	StateStopping: proto.VmState_VM_STATE_STOPPING,
+	StateStopped:  proto.VmState_VM_STATE_STOPPED,
+	StateFailed:   proto.VmState_VM_STATE_FAILED,
+}
+
+func vmToProto(vm *SimplifiedVM) *proto.Vm {
+	status := vm.Status()
+	return &proto.Vm{
+		Ip:           vm.IP,
+		PrefixLength: int32(vm.PrefixLength),
+		KernelPath:   vm.KernelPath,
+		RootfsPath:   vm.RootfsPath,
+		VcpuCount:    vm.MachineConfig.VcpuCount,
+		MemSizeMib:   vm.MachineConfig.MemSizeMib,
+		State:        vmStates[status.State],
+		Error:        status.Error,
+		Pid:          int64(status.PID),
+		SocketPath:   vm.SocketPath,
+		VsockPath:    vm.VsockPath,
+		VsockCid:     vm.VsockCID,
+		TapName:      vm.TapName,
+		MacAddress:   vm.MacAddress,
+		GatewayIP:    vm.GatewayIP,
+		CreatedAt:    unixMilli(status.CreatedAt),
+		StartedAt:    unixMilli(status.StartedAt),
+		StoppedAt:    unixMilli(status.StoppedAt),
+
+		NetworkRxRateLimiter: rateLimiterToProto(vm.MachineConfig.NetworkRxRateLimiter),
+		NetworkTxRateLimiter: rateLimiterToProto(vm.MachineConfig.NetworkTxRateLimiter),
+		DriveRateLimiter:     rateLimiterToProto(vm.MachineConfig.DriveRateLimiter),
+	}
+}
+
+func rateLimiterFromProto(limiter *proto.RateLimiter) *hypervisor.RateLimiter {
+	if limiter == nil {
+		return nil
+	}
+	return &hypervisor.RateLimiter{
+		Bandwidth: tokenBucketFromProto(limiter.Bandwidth),
+		Ops:       tokenBucketFromProto(limiter.Ops),
+	}
+}
+
+func tokenBucketFromProto(bucket *proto.TokenBucket) *hypervisor.TokenBucket {
+	if bucket == nil {
+		return nil
+	}
+	return &hypervisor.TokenBucket{
+		Size:         bucket.Size,
+		OneTimeBurst: bucket.OneTimeBurst,
+		RefillTimeMs: bucket.RefillTimeMs,
+	}
+}
+
+func rateLimiterToProto(limiter *hypervisor.RateLimiter) *proto.RateLimiter {
+	if limiter == nil {
+		return nil
+	}
+	return &proto.RateLimiter{
+		Bandwidth: tokenBucketToProto(limiter.Bandwidth),
+		Ops:       tokenBucketToProto(limiter.Ops),
+	}
+}
+
+func tokenBucketToProto(bucket *hypervisor.TokenBucket) *proto.TokenBucket {
+	if bucket == nil {
+		return nil
+	}
+	return &proto.TokenBucket{
+		Size:         bucket.Size,
+		OneTimeBurst: bucket.OneTimeBurst,
+		RefillTimeMs: bucket.RefillTimeMs,
+	}
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func snapshotToProto(snap *Snapshot) *proto.Snapshot {
+	snapshotType := proto.SnapshotType_SNAPSHOT_TYPE_FULL
+	if snap.Type == SnapshotTypeDiff {
+		snapshotType = proto.SnapshotType_SNAPSHOT_TYPE_DIFF
+	}
+
+	return &proto.Snapshot{
+		Id:           snap.ID,
+		Ip:           snap.IP,
+		Type:         snapshotType,
+		ParentId:     snap.ParentID,
+		MemFilePath:  snap.MemFilePath,
+		SnapshotPath: snap.SnapshotPath,
+		CreatedAt:    snap.CreatedAt.UnixMilli(),
+	}
+}
+
+func jobToProto(j job.Job) *proto.Job {
+	res := &proto.Job{
+		Id:        j.ID,
+		Ip:        j.Target,
+		Command:   j.Command,
+		Server:    j.Server,
+		Pid:       int64(j.Pid),
+		TimeoutMs: j.Timeout.Milliseconds(),
+		LogPath:   j.LogPath,
+		State:     proto.JobState(j.State),
+		StartedAt: unixMilli(j.StartedAt),
+		EndedAt:   unixMilli(j.EndedAt),
+	}
+	if !j.EndedAt.IsZero() {
+		res.Exit = protoconv.CommandExit[*proto.CommandExit](j.Result)
+	}
+	return res
+}
+
+func fileToProto(f agent.File) *proto.FileInfo {
+	return &proto.FileInfo{Path: f.Path, Mode: uint32(f.Mode), Size: f.Size, Sha256: f.SHA256}
+}
+
+func traceSessionToProto(session tracer.Session) *proto.TraceSession {
+	targets := make([]*proto.TraceTarget, 0, len(session.Targets))
+	for _, target := range session.Targets {
+		targets = append(targets, &proto.TraceTarget{Ip: target.Name, Pid: int64(target.PID)})
+	}
+
+	return &proto.TraceSession{
+		Id:        session.ID,
+		Targets:   targets,
+		Syscalls:  session.Syscalls,
+		Latency:   session.Latency,
+		StartedAt: unixMilli(session.StartedAt),
+		StoppedAt: unixMilli(session.StoppedAt),
+	}
+}
@@ -0,0 +1,557 @@
+package vm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/agent"
+	"github.com/bookpanda/firecracker-runner-node/internal/hypervisor"
+	"github.com/bookpanda/firecracker-runner-node/internal/run"
+	proto "github.com/bookpanda/firecracker-runner-node/proto/vm/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, m *Manager) proto.VmServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	proto.RegisterVmServiceServer(server, NewService(m, zap.NewNop()))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return proto.NewVmServiceClient(conn)
+}
+
+func TestServiceVMLifecycle(t *testing.T) {
+	m, _ := newTestManager(t)
+	client := newTestClient(t, m)
+	ctx := context.Background()
+	ip := "192.168.103.2"
+
+	created, err := client.Create(ctx, &proto.CreateVmRequest{
+		Ip:         ip,
+		KernelPath: "vmlinux",
+		RootfsPath: "rootfs.ext4",
+		GatewayIP:  "192.168.103.1",
+		VcpuCount:  2,
+		MemSizeMib: 1024,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := created.Vm
+	if vm.State != proto.VmState_VM_STATE_RUNNING || vm.Pid == 0 || vm.TapName != "tap0" || vm.VsockCid != 3 {
+		t.Fatalf("unexpected created VM: %v", vm)
+	}
+	if vm.VcpuCount != 2 || vm.MemSizeMib != 1024 {
+		t.Fatalf("got %d vCPUs and %d MiB, want 2 and 1024", vm.VcpuCount, vm.MemSizeMib)
+	}
+
+	if _, err := client.Create(ctx, &proto.CreateVmRequest{Ip: "192.168.103.3", VcpuCount: 3, Smt: true}); err == nil {
+		t.Fatal("create with an odd vCPU count and SMT succeeded")
+	}
+
+	list, err := client.ListVms(ctx, &proto.ListVmsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Vms) != 1 || list.Vms[0].Ip != ip {
+		t.Fatalf("ListVms returned %v, want only %s", list.Vms, ip)
+	}
+
+	if _, err := client.Pause(ctx, &proto.PauseVmRequest{Ip: ip}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetVm(ctx, &proto.GetVmRequest{Ip: ip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Vm.State != proto.VmState_VM_STATE_PAUSED {
+		t.Fatalf("VM is %s after Pause", got.Vm.State)
+	}
+
+	snap, err := client.CreateSnapshot(ctx, &proto.CreateSnapshotVmRequest{Ip: ip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(snap.Snapshot.MemFilePath); err != nil {
+		t.Fatalf("snapshot memory file missing: %v", err)
+	}
+
+	if _, err := client.Resume(ctx, &proto.ResumeVmRequest{Ip: ip}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DeleteVm(ctx, &proto.DeleteVmRequest{Ip: ip}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetVm(ctx, &proto.GetVmRequest{Ip: ip}); err == nil {
+		t.Fatal("GetVm succeeded for a deleted VM")
+	}
+
+	restored, err := client.RestoreFromSnapshot(ctx, &proto.RestoreFromSnapshotVmRequest{SnapshotId: snap.Snapshot.Id, Resume: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Vm.State != proto.VmState_VM_STATE_RUNNING || restored.Vm.MacAddress != vm.MacAddress {
+		t.Fatalf("unexpected restored VM: %v", restored.Vm)
+	}
+}
+
+func TestServiceSendClientCommand(t *testing.T) {
+	m, _ := newTestManager(t)
+	client := newTestClient(t, m)
+	ctx := context.Background()
+	ip := "192.168.103.4"
+
+	if _, err := client.Create(ctx, &proto.CreateVmRequest{Ip: ip, GatewayIP: "192.168.103.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := client.SendClientCommand(ctx, &proto.SendClientCommandVmRequest{Ip: ip, Command: "iperf3 -c 192.168.103.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, exit := recvCommand(t, stream)
+	if len(lines) != 1 || lines[0].Output != "iperf3 -c 192.168.103.1" || lines[0].Stream != proto.OutputStream_OUTPUT_STREAM_STDOUT {
+		t.Fatalf("got output %v, want the echoed command on stdout", lines)
+	}
+	if exit.ExitCode != 0 || exit.Killed || exit.Error != "" {
+		t.Fatalf("got exit %v, want code 0", exit)
+	}
+
+	// the fake guest echoes the command into the log of the job
+	jobs := m.ListJobs(ip)
+	if len(jobs) != 1 {
+		t.Fatalf("got jobs %+v, want the client command", jobs)
+	}
+	out, err := os.ReadFile(filepath.Join(m.runs.Dir(run.VMTestDir), "vm-"+ip+"-"+jobs[0].ID+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(out), "[OUTPUT] iperf3 -c 192.168.103.1\n"; !strings.Contains(got, want) {
+		t.Fatalf("command log is %q, want it to contain %q", got, want)
+	}
+	if cmds := m.runs.Current().Commands; len(cmds) != 1 || cmds[0].Target != ip || cmds[0].Server {
+		t.Fatalf("got run manifest commands %+v, want the client command", cmds)
+	}
+}
+
+func TestServiceSendClientCommandExitCode(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.SetVsockHandler(hypervisor.CommandHandler(func(command string, out io.Writer) error {
+		fmt.Fprintf(out, "error: connection refused\n")
+		fmt.Fprintf(out, "done\n")
+		return errors.New("exit status 3")
+	}))
+	client := newTestClient(t, m)
+	ctx := context.Background()
+	ip := "192.168.103.5"
+
+	if _, err := client.Create(ctx, &proto.CreateVmRequest{Ip: ip, GatewayIP: "192.168.103.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := client.SendClientCommand(ctx, &proto.SendClientCommandVmRequest{Ip: ip, Command: "iperf3 -c 192.168.103.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, exit := recvCommand(t, stream)
+	// only the last line is the status of the command
+	if len(lines) != 2 || lines[0].Output != "error: connection refused" || lines[1].Output != "done" {
+		t.Fatalf("got output %v, want both lines the command printed", lines)
+	}
+	if exit.ExitCode != 3 || exit.Killed {
+		t.Fatalf("got exit %v, want code 3", exit)
+	}
+}
+
+func TestServiceSendClientCommandErrorOutput(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.SetVsockHandler(hypervisor.CommandHandler(func(command string, out io.Writer) error {
+		// looks like a status line but is output
+		fmt.Fprintf(out, "error: connection refused\n")
+		return nil
+	}))
+	client := newTestClient(t, m)
+	ctx := context.Background()
+	ip := "192.168.103.6"
+
+	if _, err := client.Create(ctx, &proto.CreateVmRequest{Ip: ip, GatewayIP: "192.168.103.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := client.SendClientCommand(ctx, &proto.SendClientCommandVmRequest{Ip: ip, Command: "iperf3 -c 192.168.103.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, exit := recvCommand(t, stream)
+	if len(lines) != 1 || lines[0].Output != "error: connection refused" {
+		t.Fatalf("got output %v, want the line the command printed", lines)
+	}
+	if exit.ExitCode != 0 || exit.Killed || exit.Error != "" {
+		t.Fatalf("got exit %v, want code 0", exit)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		line   string
+		code   int
+		signal string
+		ok     bool
+	}{
+		{line: "error: exit status 1", code: 1, ok: true},
+		{line: "error: exit status 255", code: 255, ok: true},
+		{line: "error: signal: killed", code: -1, signal: "killed", ok: true},
+		{line: "error: signal: segmentation fault (core dumped)", code: -1, signal: "segmentation fault (core dumped)", ok: true},
+		{line: "error: connection refused"},
+		{line: "error: exit status 1 and more"},
+		{line: "error: exit status 0"},
+		{line: "error: exit status -1"},
+		{line: "error: signal: "},
+		{line: "exit status 1"},
+		{line: "Error: exit status 1"},
+	}
+
+	for _, tt := range tests {
+		code, signal, ok := parseStatus(tt.line)
+		if ok != tt.ok || ok && (code != tt.code || signal != tt.signal) {
+			t.Errorf("parseStatus(%q) = %d, %q, %v, want %d, %q, %v", tt.line, code, signal, ok, tt.code, tt.signal, tt.ok)
+		}
+	}
+}
+
+// recvCommand reads the output of a client command up to its exit.
+func recvCommand(t *testing.T, stream grpc.ServerStreamingClient[proto.SendClientCommandVmResponse]) ([]*proto.SendClientCommandVmResponse, *proto.CommandExit) {
+	t.Helper()
+
+	var lines []*proto.SendClientCommandVmResponse
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("stream ended before the exit: %v", err)
+		}
+		if res.Exit != nil {
+			if _, err := stream.Recv(); err != io.EOF {
+				t.Fatalf("got %v after the exit, want EOF", err)
+			}
+			return lines, res.Exit
+		}
+		lines = append(lines, res)
+	}
+}
+
+func TestServiceExec(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.SetVsockHandler(func(_ uint32, conn net.Conn) { agent.ServeConn(conn) })
+	client := newTestClient(t, m)
+	ctx := context.Background()
+	ip := "192.168.103.6"
+
+	if _, err := client.Create(ctx, &proto.CreateVmRequest{Ip: ip, GatewayIP: "192.168.103.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := client.Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&proto.ExecVmRequest{Start: &proto.ExecVmStart{
+		Ip:    ip,
+		Argv:  []string{"sh", "-c", "read name; echo hi $name; echo bye >&2"},
+		Stdin: true,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&proto.ExecVmRequest{Stdin: []byte("bob\n"), CloseStdin: true}); err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr, exit := recvExec(t, stream)
+	if stdout != "hi bob\n" || stderr != "bye\n" || exit.ExitCode != 0 {
+		t.Fatalf("got stdout %q, stderr %q and exit %v", stdout, stderr, exit)
+	}
+
+	// a signal stops the command
+	stream, err = client.Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&proto.ExecVmRequest{Start: &proto.ExecVmStart{Ip: ip, Argv: []string{"sleep", "60"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&proto.ExecVmRequest{Signal: int32(syscall.SIGTERM)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, exit := recvExec(t, stream); !exit.Killed || exit.Signal != "terminated" {
+		t.Fatalf("got exit %v, want a command killed by SIGTERM", exit)
+	}
+}
+
+func TestServiceExecNeedsFramedAgent(t *testing.T) {
+	m, _ := newTestManager(t)
+	client := newTestClient(t, m)
+	ctx := context.Background()
+	ip := "192.168.103.7"
+
+	if _, err := client.Create(ctx, &proto.CreateVmRequest{Ip: ip, GatewayIP: "192.168.103.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the fake guest only speaks the line protocol
+	stream, err := client.Exec(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send(&proto.ExecVmRequest{Start: &proto.ExecVmStart{Ip: ip, Argv: []string{"true"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Fatal("exec through an agent speaking the line protocol succeeded")
+	}
+}
+
+func TestServicePutGetFile(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.SetVsockHandler(func(_ uint32, conn net.Conn) { agent.ServeConn(conn) })
+	client := newTestClient(t, m)
+	ctx := context.Background()
+	ip := "192.168.103.8"
+
+	if _, err := client.Create(ctx, &proto.CreateVmRequest{Ip: ip, GatewayIP: "192.168.103.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the fake guest is this host
+	path := filepath.Join(t.TempDir(), "bin", "tool")
+	content := []byte("#!/bin/sh\necho hi\n")
+	sum := sha256.Sum256(content)
+	put, err := client.PutFile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = put.Send(&proto.PutFileVmRequest{Start: &proto.PutFileVmStart{
+		Ip:     ip,
+		Path:   path,
+		Mode:   0o755,
+		Size:   int64(len(content)),
+		Sha256: sum[:],
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := put.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Offset != 0 {
+		t.Fatalf("upload starts at %d", res.Offset)
+	}
+	for _, req := range []*proto.PutFileVmRequest{{Offset: 0, Data: content[:5]}, {Offset: 5, Data: content[5:]}} {
+		if err := put.Send(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := put.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	res, err = put.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.File.GetMode() != 0o755 || res.File.GetSize() != int64(len(content)) {
+		t.Fatalf("stored %v", res.File)
+	}
+
+	get, err := client.GetFile(ctx, &proto.GetFileVmRequest{Ip: ip, Path: path, Offset: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := get.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(head.File.GetSha256(), sum[:]) || head.File.GetMode() != 0o755 {
+		t.Fatalf("got %v, want the stored file", head.File)
+	}
+	var got []byte
+	for {
+		res, err := get.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, res.Data...)
+	}
+	if !bytes.Equal(got, content[3:]) {
+		t.Fatalf("got %q, want %q", got, content[3:])
+	}
+}
+
+func TestServiceJobs(t *testing.T) {
+	m, fake := newTestManager(t)
+	fake.SetVsockHandler(func(_ uint32, conn net.Conn) { agent.ServeConn(conn) })
+	client := newTestClient(t, m)
+	ctx := context.Background()
+	ip := "192.168.103.9"
+
+	if _, err := client.Create(ctx, &proto.CreateVmRequest{Ip: ip, GatewayIP: "192.168.103.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := client.SendServerCommand(ctx, &proto.SendServerCommandVmRequest{Ip: ip, Command: "sleep 60"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	timed, err := client.SendServerCommand(ctx, &proto.SendServerCommandVmRequest{Ip: ip, Command: "sleep 60", TimeoutMs: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream, err := client.SendClientCommand(ctx, &proto.SendClientCommandVmRequest{Ip: ip, Command: "echo hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientJob := res.JobId
+	recvCommand(t, stream)
+
+	list, err := client.ListJobs(ctx, &proto.ListJobsVmRequest{Ip: ip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, j := range list.Jobs {
+		ids = append(ids, j.Id)
+	}
+	if want := []string{server.JobId, timed.JobId, clientJob}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("got jobs %v, want %v", ids, want)
+	}
+	if j := list.Jobs[2]; j.State != proto.JobState_JOB_STATE_EXITED || j.Exit.GetExitCode() != 0 || j.Server {
+		t.Fatalf("got %v, want the exited client command", j)
+	}
+
+	cancelled, err := client.CancelJob(ctx, &proto.CancelJobVmRequest{Id: server.JobId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j := cancelled.Job; j.State != proto.JobState_JOB_STATE_CANCELLED || !j.Exit.GetKilled() || j.EndedAt == 0 {
+		t.Fatalf("got %v, want a cancelled job", j)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		res, err := client.GetJob(ctx, &proto.GetJobVmRequest{Id: timed.JobId})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Job.State == proto.JobState_JOB_STATE_TIMED_OUT {
+			break
+		}
+		if res.Job.State != proto.JobState_JOB_STATE_RUNNING || time.Now().After(deadline) {
+			t.Fatalf("got %v, want a job that times out", res.Job)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if _, err := client.GetJob(ctx, &proto.GetJobVmRequest{Id: "job-0"}); err == nil {
+		t.Fatal("got an unknown job")
+	}
+}
+
+// recvExec reads the PID, the output and the exit of an exec.
+func recvExec(t *testing.T, stream grpc.BidiStreamingClient[proto.ExecVmRequest, proto.ExecVmResponse]) (stdout, stderr string, exit *proto.CommandExit) {
+	t.Helper()
+
+	res, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pid <= 0 {
+		t.Fatalf("first response %v has no PID", res)
+	}
+
+	var out, errOut strings.Builder
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("stream ended before the exit: %v", err)
+		}
+		if res.Exit != nil {
+			return out.String(), errOut.String(), res.Exit
+		}
+		if res.Stream == proto.OutputStream_OUTPUT_STREAM_STDERR {
+			errOut.Write(res.Data)
+		} else {
+			out.Write(res.Data)
+		}
+	}
+}
+
+// fakeSudo puts a sudo on PATH that exits with code, like pkill would.
+func fakeSudo(t *testing.T, code int) {
+	t.Helper()
+
+	bin := t.TempDir()
+	script := fmt.Sprintf("#!/bin/sh\nexit %d\n", code)
+	if err := os.WriteFile(filepath.Join(bin, "sudo"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestServiceCleanupWithoutVMs(t *testing.T) {
+	m, _ := newTestManager(t)
+	s := NewService(m, zap.NewNop()).(*serviceImpl)
+	if _, err := createTestVM(m, "192.168.103.10"); err != nil {
+		t.Fatal(err)
+	}
+
+	// pkill matched nothing, which is no failure
+	fakeSudo(t, 1)
+	if _, err := s.Cleanup(context.Background(), &proto.CleanupVmRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if s.Manager() == m {
+		t.Fatal("Cleanup kept the old manager")
+	}
+	if leases := s.Manager().ipam.Leases(); len(leases) != 0 {
+		t.Fatalf("got leases %v after Cleanup", leases)
+	}
+
+	fakeSudo(t, 2)
+	if _, err := s.Cleanup(context.Background(), &proto.CleanupVmRequest{}); err == nil {
+		t.Fatal("Cleanup succeeded although pkill failed")
+	}
+}
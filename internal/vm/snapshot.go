@@ -0,0 +1,291 @@
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	SnapshotTypeFull = "full"
+	SnapshotTypeDiff = "diff"
+
+	snapshotMetadataFile = "snapshot.json"
+)
+
+// Snapshot is a snapshot stored under the manager's snapshots directory.
+// Besides the file paths it records the identity of the source VM, since
+// the tap, vsock and CID are baked into the snapshot state.
+type Snapshot struct {
+	ID            string        `json:"id"`
+	Type          string        `json:"type"`
+	ParentID      string        `json:"parentId,omitempty"`
+	MemFilePath   string        `json:"memFilePath"`
+	SnapshotPath  string        `json:"snapshotPath"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	IP            string        `json:"ip"`
+	PrefixLength  int           `json:"prefixLength,omitempty"`
+	GatewayIP     string        `json:"gatewayIP"`
+	KernelPath    string        `json:"kernelPath"`
+	RootfsPath    string        `json:"rootfsPath"`
+	TapName       string        `json:"tapName"`
+	MacAddress    string        `json:"macAddress"`
+	VsockCID      uint32        `json:"vsockCID"`
+	VMID          int           `json:"vmid"`
+	MachineConfig MachineConfig `json:"machineConfig"`
+}
+
+func (m *Manager) PauseVM(ip string) error {
+	vm, err := m.getVM(ip)
+	if err != nil {
+		return err
+	}
+
+	vm.opMu.Lock()
+	defer vm.opMu.Unlock()
+	return vm.Pause(m.vmCtx)
+}
+
+func (m *Manager) ResumeVM(ip string) error {
+	vm, err := m.getVM(ip)
+	if err != nil {
+		return err
+	}
+
+	vm.opMu.Lock()
+	defer vm.opMu.Unlock()
+	return vm.Resume(m.vmCtx)
+}
+
+// CreateSnapshot pauses the VM, snapshots it into its own directory under
+// snapshotsDir and resumes it again unless keepPaused is set. A VM that was
+// already paused stays paused. If the VM fails to resume, the snapshot is
+// kept and returned with the error.
+func (m *Manager) CreateSnapshot(ip, snapshotType string, keepPaused bool) (*Snapshot, error) {
+	vm, err := m.getVM(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	vm.opMu.Lock()
+	defer vm.opMu.Unlock()
+
+	if err := vm.requireState(StateRunning, StatePaused); err != nil {
+		return nil, err
+	}
+
+	diff := snapshotType == SnapshotTypeDiff
+	var parent *Snapshot
+	if diff {
+		if !vm.MachineConfig.TrackDirtyPages {
+			return nil, fmt.Errorf("vm %s was not created with dirty page tracking", ip)
+		}
+		parent = m.getSnapshot(vm.lastSnapshotID)
+		if parent == nil {
+			return nil, fmt.Errorf("diff snapshot of vm %s requires a previous snapshot", ip)
+		}
+	}
+
+	id := fmt.Sprintf("%s-%d", vm.IP, time.Now().UnixNano())
+	dir := filepath.Join(m.snapshotsDir, id)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %v", err)
+	}
+
+	snap := &Snapshot{
+		ID:            id,
+		Type:          snapshotType,
+		MemFilePath:   filepath.Join(dir, "mem"),
+		SnapshotPath:  filepath.Join(dir, "vmstate"),
+		CreatedAt:     time.Now(),
+		IP:            vm.IP,
+		PrefixLength:  vm.PrefixLength,
+		GatewayIP:     vm.GatewayIP,
+		KernelPath:    vm.KernelPath,
+		RootfsPath:    vm.RootfsPath,
+		TapName:       vm.TapName,
+		MacAddress:    vm.MacAddress,
+		VsockCID:      vm.VsockCID,
+		VMID:          vm.VMID,
+		MachineConfig: vm.MachineConfig,
+	}
+
+	wasRunning := vm.State() == StateRunning
+	if wasRunning {
+		if err := vm.Pause(m.vmCtx); err != nil {
+			os.RemoveAll(dir)
+			return nil, err
+		}
+	}
+
+	memFilePath := snap.MemFilePath
+	if diff {
+		snap.ParentID = parent.ID
+		memFilePath = filepath.Join(dir, "mem.diff")
+	}
+
+	if err := vm.CreateSnapshot(m.vmCtx, memFilePath, snap.SnapshotPath, diff); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	// materialize the diff on top of its parent so every stored snapshot
+	// can be restored on its own
+	if diff {
+		if err := mergeDiffMemFile(parent.MemFilePath, memFilePath, snap.MemFilePath); err != nil {
+			os.RemoveAll(dir)
+			return nil, err
+		}
+		os.Remove(memFilePath)
+	}
+
+	if err := writeSnapshotMetadata(dir, snap); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	vm.lastSnapshotID = snap.ID
+	m.mu.Lock()
+	m.snapshots[snap.ID] = snap
+	m.mu.Unlock()
+	log.Printf("Created %s snapshot %s of VM %d", snap.Type, snap.ID, vm.VMID)
+
+	// the snapshot is good even if the VM does not run again
+	if wasRunning && !keepPaused {
+		if err := vm.Resume(m.vmCtx); err != nil {
+			return snap, fmt.Errorf("created snapshot %s, but %w", snap.ID, err)
+		}
+	}
+
+	return snap, nil
+}
+
+// RestoreFromSnapshot starts a new VM from a stored snapshot. The restored VM
+// takes over the IP, tap and CID of the VM the snapshot was taken from.
+func (m *Manager) RestoreFromSnapshot(id string, resume bool) (*SimplifiedVM, error) {
+	snap := m.getSnapshot(id)
+	if snap == nil {
+		return nil, fmt.Errorf("snapshot %s not found", id)
+	}
+
+	// the snapshot's tap and CID belong to its slot, so restore into that slot
+	vm, err := m.addVM(snap.IP, snap.MachineConfig, snap.VMID, func(int, string) (*SimplifiedVM, error) {
+		return RestoreVM(m.vmCtx, m.hypervisor, m.host(), snap, snap.MemFilePath, resume)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot restore snapshot %s: %v", id, err)
+	}
+	m.recordVM(vm, snap.ID)
+
+	if err := vm.Start(m.vmCtx); err != nil {
+		return nil, fmt.Errorf("failed to restore VM %d from snapshot %s: %v", snap.VMID, id, err)
+	}
+	log.Printf("VM %d restored from snapshot %s. Socket: %s", vm.VMID, id, vm.SocketPath)
+
+	return vm, nil
+}
+
+func (m *Manager) getSnapshot(id string) *Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshots[id]
+}
+
+func (m *Manager) loadSnapshots() {
+	entries, err := os.ReadDir(m.snapshotsDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("failed to read snapshots directory: %v", err)
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(m.snapshotsDir, entry.Name(), snapshotMetadataFile))
+		if err != nil {
+			continue
+		}
+
+		snap := &Snapshot{}
+		if err := json.Unmarshal(data, snap); err != nil {
+			log.Printf("failed to parse snapshot %s: %v", entry.Name(), err)
+			continue
+		}
+		m.snapshots[snap.ID] = snap
+	}
+}
+
+func writeSnapshotMetadata(dir string, snap *Snapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot metadata: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, snapshotMetadataFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot metadata: %v", err)
+	}
+
+	return nil
+}
+
+// mergeDiffMemFile copies basePath to outPath and writes the data regions of
+// the sparse diff file over it. Firecracker writes only dirty pages into a
+// diff memory file and leaves holes everywhere else.
+func mergeDiffMemFile(basePath, diffPath, outPath string) error {
+	base, err := os.Open(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to open base memory file: %v", err)
+	}
+	defer base.Close()
+
+	diff, err := os.Open(diffPath)
+	if err != nil {
+		return fmt.Errorf("failed to open diff memory file: %v", err)
+	}
+	defer diff.Close()
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create memory file: %v", err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, base); err != nil {
+		return fmt.Errorf("failed to copy base memory file: %v", err)
+	}
+
+	fd := int(diff.Fd())
+	var offset int64
+	for {
+		start, err := unix.Seek(fd, offset, unix.SEEK_DATA)
+		if errors.Is(err, unix.ENXIO) {
+			// no data past offset
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seek diff memory file: %v", err)
+		}
+
+		end, err := unix.Seek(fd, start, unix.SEEK_HOLE)
+		if err != nil {
+			return fmt.Errorf("failed to seek diff memory file: %v", err)
+		}
+
+		section := io.NewSectionReader(diff, start, end-start)
+		if _, err := io.Copy(io.NewOffsetWriter(out, start), section); err != nil {
+			return fmt.Errorf("failed to merge diff memory file: %v", err)
+		}
+		offset = end
+	}
+}
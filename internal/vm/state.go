@@ -0,0 +1,100 @@
+package vm
+
+import (
+	"fmt"
+	"time"
+)
+
+type State string
+
+const (
+	StateCreating State = "creating"
+	StateRunning  State = "running"
+	StatePaused   State = "paused"
+	StateStopping State = "stopping"
+	StateStopped  State = "stopped"
+	StateFailed   State = "failed"
+)
+
+// transitions lists the states a VM may move to from each state.
+// Any state may move to failed.
+var transitions = map[State][]State{
+	StateCreating: {StateRunning, StatePaused},
+	StateRunning:  {StatePaused, StateStopping, StateStopped},
+	StatePaused:   {StateRunning, StateStopping, StateStopped},
+	StateStopping: {StateStopped},
+	StateStopped:  {},
+	StateFailed:   {StateStopping, StateStopped},
+}
+
+// Status is a point-in-time copy of a VM's lifecycle fields.
+type Status struct {
+	State     State
+	PID       int
+	Error     string
+	CreatedAt time.Time
+	StartedAt time.Time
+	StoppedAt time.Time
+}
+
+func (v *SimplifiedVM) Status() Status {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.status
+}
+
+func (v *SimplifiedVM) State() State {
+	return v.Status().State
+}
+
+// setState moves the VM to the given state if the transition is allowed.
+func (v *SimplifiedVM) setState(to State) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	from := v.status.State
+	if to != StateFailed && !canTransition(from, to) {
+		return fmt.Errorf("vm %s cannot go from %s to %s", v.IP, from, to)
+	}
+
+	v.status.State = to
+	switch to {
+	case StateRunning:
+		if v.status.StartedAt.IsZero() {
+			v.status.StartedAt = time.Now()
+		}
+	case StateStopped, StateFailed:
+		v.status.StoppedAt = time.Now()
+	}
+
+	return nil
+}
+
+func (v *SimplifiedVM) setFailed(err error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.status.State = StateFailed
+	v.status.Error = err.Error()
+	v.status.StoppedAt = time.Now()
+}
+
+// requireState returns an error unless the VM is in one of the given states.
+func (v *SimplifiedVM) requireState(states ...State) error {
+	current := v.State()
+	for _, s := range states {
+		if current == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("vm %s is %s", v.IP, current)
+}
+
+func canTransition(from, to State) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
@@ -1,105 +1,93 @@
 package vm
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"log"
-	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
-)
 
-func (m *Manager) TrackSyscalls() error {
-	tracePath, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %v", err)
-	}
-	tracePath = filepath.Join(tracePath, "trace_syscalls.sh")
+	"github.com/bookpanda/firecracker-runner-node/internal/run"
+	"github.com/bookpanda/firecracker-runner-node/internal/tracer"
+)
 
-	for _, vm := range m.vms {
-		pid, err := vm.Machine.PID()
-		if err != nil {
-			return fmt.Errorf("failed to get vm %s PID: %v", vm.IP, err)
+// TrackSyscalls starts a tracing session of the VMs with the given IPs, or of
+// every running or paused VM if ips is empty. syscalls is an allowlist of
+// syscall names and families, such as "network", empty to count every
+// syscall. latency also builds latency histograms of the syscalls. The
+// session logs to vm-syscalls/<session>/vm-<ip>.log in the current run
+// every tracer.DefaultInterval until it is stopped.
+func (m *Manager) TrackSyscalls(ips, syscalls []string, latency bool) (tracer.Session, error) {
+	var vms []*SimplifiedVM
+	if len(ips) == 0 {
+		for _, vm := range m.ListVMs() {
+			switch vm.State() {
+			case StateRunning, StatePaused:
+				vms = append(vms, vm)
+			}
 		}
-
-		command := fmt.Sprintf("sudo %s %d", tracePath, pid)
-		logPath := filepath.Join(m.syscallsDir, fmt.Sprintf("vm-%s.log", vm.IP))
-		if err := captureCommandOutput(m.traceCtx, vm.IP, command, logPath); err != nil {
-			return fmt.Errorf("failed to track syscalls of vm %s: %v", vm.IP, err)
+		if len(vms) == 0 {
+			return tracer.Session{}, fmt.Errorf("no running VMs to trace")
 		}
 	}
-	return nil
-}
-
-func (m *Manager) StopSyscalls() error {
-	m.cancelTrace()
-	return nil
-}
-
-func captureCommandOutput(ctx context.Context, vmIP, command, logPath string) error {
-	logFile, err := os.Create(logPath)
-	if err != nil {
-		return fmt.Errorf("failed to create log file %s: %v", logPath, err)
-	}
-
-	go func() {
-		defer logFile.Close()
-
-		var cmd *exec.Cmd
-		// Split the command properly for exec
-		args := strings.Fields(command)
-		if len(args) == 0 {
-			log.Printf("Empty trace command for %s", vmIP)
-			return
-		}
-		cmd = exec.CommandContext(ctx, args[0], args[1:]...)
-
-		stdout, err := cmd.StdoutPipe()
+	for _, ip := range ips {
+		vm, err := m.GetVM(ip)
 		if err != nil {
-			log.Printf("Failed to create stdout pipe for %s: %v", vmIP, err)
-			return
+			return tracer.Session{}, err
+		}
+		switch state := vm.State(); state {
+		case StateRunning, StatePaused:
+		default:
+			return tracer.Session{}, fmt.Errorf("vm %s is %s", ip, state)
 		}
+		vms = append(vms, vm)
+	}
 
-		stderr, err := cmd.StderrPipe()
+	targets := make([]tracer.Target, 0, len(vms))
+	for _, vm := range vms {
+		pid, err := vm.Machine.PID()
 		if err != nil {
-			log.Printf("Failed to create stderr pipe for %s: %v", vmIP, err)
-			return
+			return tracer.Session{}, fmt.Errorf("failed to get vm %s PID: %v", vm.IP, err)
 		}
+		targets = append(targets, tracer.Target{Name: vm.IP, PID: pid})
+	}
 
-		if err := cmd.Start(); err != nil {
-			log.Printf("Failed to start command on %s: %v", vmIP, err)
-			return
-		}
+	session, err := m.syscalls.Start(targets, syscalls, latency)
+	if err != nil {
+		return tracer.Session{}, fmt.Errorf("failed to track syscalls: %v", err)
+	}
+	return session, nil
+}
 
-		// Track trace processes for proper cleanup
-		// if isTrace {
-		// 	e.traceProcsMux.Lock()
-		// 	e.traceProcs = append(e.traceProcs, cmd)
-		// 	e.traceProcsMux.Unlock()
-		// }
+// StopSyscalls stops the tracing session with the given ID, or every session
+// if id is empty.
+func (m *Manager) StopSyscalls(id string) error {
+	if id == "" {
+		m.syscalls.StopAll()
+		return nil
+	}
+	return m.syscalls.Stop(id)
+}
 
-		go func() {
-			scanner := bufio.NewScanner(stdout)
-			for scanner.Scan() {
-				logFile.WriteString(fmt.Sprintf("[STDOUT] %s\n", scanner.Text()))
-			}
-		}()
+func (m *Manager) ListTraceSessions() []tracer.Session {
+	return m.syscalls.List()
+}
 
-		go func() {
-			scanner := bufio.NewScanner(stderr)
-			for scanner.Scan() {
-				logFile.WriteString(fmt.Sprintf("[STDERR] %s\n", scanner.Text()))
-			}
-		}()
+// SyscallStats returns the latest syscall counts of the VM with the given IP,
+// or of every VM if ip is empty, in the tracing session with the given ID, or
+// in the latest session if id is empty. The counts of a stopped session stay
+// available.
+func (m *Manager) SyscallStats(id, ip string) ([]tracer.TargetStats, error) {
+	return m.syscalls.Stats(id, ip)
+}
 
-		// for trace: stop when traceCtx is canceled
-		<-ctx.Done()
-		cmd.Process.Kill() // kill ONLY server process
-		cmd.Wait()         // wait for stdout/stderr to be closed
-		log.Printf("Server on %s stopped, logs saved to %s", vmIP, logPath)
-	}()
+// WatchSyscalls calls send with the counts of the VM with the given IP, or of
+// every VM if ip is empty, at the end of every interval of the tracing
+// session with the given ID, or of the latest session if id is empty. It
+// returns once ctx is done, send fails, or the session has stopped and the
+// final counts are sent.
+func (m *Manager) WatchSyscalls(ctx context.Context, id, ip string, send func(tracer.TargetStats) error) error {
+	return m.syscalls.Watch(ctx, id, ip, send)
+}
 
-	return nil
+func (m *Manager) syscallsLogPath(session string, target tracer.Target) string {
+	return filepath.Join(m.runs.Dir(run.VMSyscallsDir), session, fmt.Sprintf("vm-%s.log", target.Name))
 }
@@ -2,6 +2,7 @@ package vm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -10,15 +11,26 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/firecracker-microvm/firecracker-go-sdk"
-	"github.com/firecracker-microvm/firecracker-go-sdk/client/models"
+	"github.com/bookpanda/firecracker-runner-node/internal/hypervisor"
 )
 
+// Host is where the host side of a VM lives.
+type Host struct {
+	LogDir    string // firecracker logs, metrics and output
+	SocketDir string // API and vsock sockets
+	TapPrefix string
+	// StopTimeout is how long Stop waits for the guest to shut down before
+	// it kills firecracker
+	StopTimeout time.Duration
+}
+
 type SimplifiedVM struct {
-	Machine    *firecracker.Machine
+	Machine    hypervisor.Machine
 	KernelPath string
 	RootfsPath string
 	SocketPath string
@@ -28,14 +40,69 @@ type SimplifiedVM struct {
 	Stderr     chan string
 	VMID       int
 	TapName    string
+	MacAddress string
 	IP         string
+	GatewayIP  string
+	// PrefixLength is the length of the guest subnet's prefix, e.g. 24
+	PrefixLength int
+
+	MachineConfig MachineConfig
+
+	mu     sync.Mutex // guards status
+	status Status
+	// opMu serializes lifecycle operations (pause, resume, snapshot, stop)
+	opMu sync.Mutex
+	// startPaused keeps a restored VM paused after Start
+	startPaused bool
+	stopTimeout time.Duration
+
+	// lastSnapshotID is the snapshot that dirty page tracking is relative to,
+	// i.e. the parent of the next diff snapshot
+	lastSnapshotID string
+
+	metrics *metricsSeries
+
+	// legacyAgent is set once the guest agent turned out to speak only the
+	// line protocol. Such an agent runs the HELLO probe as a shell command,
+	// so it is probed once per VM.
+	legacyAgent atomic.Bool
 }
 
 func (v *SimplifiedVM) Start(ctx context.Context) error {
 	if err := v.Machine.Start(ctx); err != nil {
-		return fmt.Errorf("failed to start machine: %v", err)
+		err = fmt.Errorf("failed to start machine: %v", err)
+		v.setFailed(err)
+		return err
 	}
 
+	pid, err := v.Machine.PID()
+	if err != nil {
+		log.Printf("Failed to get PID of VM %d: %v", v.VMID, err)
+	}
+	v.mu.Lock()
+	v.status.PID = pid
+	v.mu.Unlock()
+
+	started := StateRunning
+	if v.startPaused {
+		started = StatePaused
+	}
+	if err := v.setState(started); err != nil {
+		return err
+	}
+
+	// notice firecracker exiting behind our back
+	go func() {
+		err := v.Machine.Wait(context.Background())
+		switch v.State() {
+		case StateRunning, StatePaused:
+			log.Printf("VM %d exited unexpectedly: %v", v.VMID, err)
+			v.setFailed(fmt.Errorf("firecracker exited unexpectedly: %v", err))
+		}
+	}()
+
+	go v.collectMetrics(ctx)
+
 	go func() {
 		for {
 			select {
@@ -59,21 +126,67 @@ func (v *SimplifiedVM) Start(ctx context.Context) error {
 }
 
 func (v *SimplifiedVM) Stop(ctx context.Context) error {
+	if v.State() == StateStopped {
+		return nil
+	}
+	if err := v.setState(StateStopping); err != nil {
+		return err
+	}
+
 	if err := v.Machine.Shutdown(ctx); err != nil {
 		log.Printf("Graceful shutdown failed for VM %d: %v", v.VMID, err)
 	}
 
 	// wait a bit for graceful shutdown to complete
-	time.Sleep(2 * time.Second)
+	waitCtx, cancel := context.WithTimeout(ctx, v.stopTimeout)
+	err := v.Machine.Wait(waitCtx)
+	cancel()
 
-	if err := v.killFirecrackerProcess(); err != nil {
-		log.Printf("Failed to kill Firecracker process for VM %d: %v", v.VMID, err)
+	if errors.Is(err, context.DeadlineExceeded) {
+		if err := v.Machine.StopVMM(); err != nil {
+			log.Printf("Failed to stop VMM for VM %d: %v", v.VMID, err)
+		}
+		if err := v.killFirecrackerProcess(); err != nil {
+			log.Printf("Failed to kill Firecracker process for VM %d: %v", v.VMID, err)
+		}
 	}
 
-	// clean up socket file
+	// clean up socket files
 	if err := os.Remove(v.SocketPath); err != nil && !os.IsNotExist(err) {
 		log.Printf("failed to remove socket file: %v", err)
 	}
+	if err := os.Remove(v.VsockPath); err != nil && !os.IsNotExist(err) {
+		log.Printf("failed to remove vsock file: %v", err)
+	}
+	return v.setState(StateStopped)
+}
+
+func (v *SimplifiedVM) Pause(ctx context.Context) error {
+	if err := v.requireState(StateRunning); err != nil {
+		return err
+	}
+	if err := v.Machine.PauseVM(ctx); err != nil {
+		return fmt.Errorf("failed to pause VM %d: %v", v.VMID, err)
+	}
+	return v.setState(StatePaused)
+}
+
+func (v *SimplifiedVM) Resume(ctx context.Context) error {
+	if err := v.requireState(StatePaused); err != nil {
+		return err
+	}
+	if err := v.Machine.ResumeVM(ctx); err != nil {
+		return fmt.Errorf("failed to resume VM %d: %v", v.VMID, err)
+	}
+	return v.setState(StateRunning)
+}
+
+// CreateSnapshot writes the guest memory and VM state of a paused VM to disk.
+// A diff snapshot only contains the pages dirtied since the previous snapshot.
+func (v *SimplifiedVM) CreateSnapshot(ctx context.Context, memFilePath, snapshotPath string, diff bool) error {
+	if err := v.Machine.CreateSnapshot(ctx, memFilePath, snapshotPath, diff); err != nil {
+		return fmt.Errorf("failed to create snapshot of VM %d: %v", v.VMID, err)
+	}
 	return nil
 }
 
@@ -120,85 +233,45 @@ func (v *SimplifiedVM) killFirecrackerProcess() error {
 	return nil
 }
 
-func CreateVM(ctx context.Context, ip, kernelPath, rootfsPath, gatewayIP string, vmIndex int) (*SimplifiedVM, error) {
-	socketPath := filepath.Join(os.TempDir(), fmt.Sprintf("vm-%s.sock", ip))
-	vsockPath := filepath.Join(os.TempDir(), fmt.Sprintf("vsock-%s.sock", ip))
-	cid := uint32(vmIndex + 3)
+func CreateVM(ctx context.Context, hv hypervisor.Hypervisor, host Host, ip, kernelPath, rootfsPath, gatewayIP string, prefixLength, slot int, machineCfg MachineConfig) (*SimplifiedVM, error) {
+	socketPath := filepath.Join(host.SocketDir, fmt.Sprintf("vm-%s.sock", ip))
+	vsockPath := filepath.Join(host.SocketDir, fmt.Sprintf("vsock-%s.sock", ip))
+	cid := slotCID(slot)
 
 	stdout := make(chan string, 100)
 	stderr := make(chan string, 100)
 
-	macAddr := fmt.Sprintf("AA:FC:00:00:00:%02X", vmIndex+1)
-	tapName := fmt.Sprintf("tap%d", vmIndex)
-
-	logDir := "./vm-logs"
-
-	cfg := firecracker.Config{
-		SocketPath:      socketPath, // host-FC process communication
-		KernelImagePath: kernelPath,
-		KernelArgs:      "console=ttyS0 noapic reboot=k panic=1 pci=off rw",
-		VsockDevices: []firecracker.VsockDevice{ // host-guest(vm) communication
-			{
-				ID:   fmt.Sprintf("vsock-%d", vmIndex),
-				Path: vsockPath,
-				CID:  cid,
-			},
-		},
-		Drives: []models.Drive{
-			{
-				DriveID:      firecracker.String("1"),
-				PathOnHost:   firecracker.String(rootfsPath),
-				IsRootDevice: firecracker.Bool(true),
-				IsReadOnly:   firecracker.Bool(true),
-			},
-		},
-		NetworkInterfaces: []firecracker.NetworkInterface{
-			{
-				StaticConfiguration: &firecracker.StaticNetworkConfiguration{
-					MacAddress:  macAddr,
-					HostDevName: tapName,
-					IPConfiguration: &firecracker.IPConfiguration{
-						IPAddr: net.IPNet{
-							IP:   net.ParseIP(ip),
-							Mask: net.CIDRMask(24, 32),
-						},
-						Gateway:     net.ParseIP(gatewayIP),
-						Nameservers: []string{"8.8.8.8", "8.8.4.4"},
-					},
-				},
-			},
-		},
-		MachineCfg: models.MachineConfiguration{
-			VcpuCount:  firecracker.Int64(1),
-			MemSizeMib: firecracker.Int64(512),
-		},
-		ForwardSignals: []os.Signal{},
-		LogLevel:       "Debug",
-		LogPath:        filepath.Join(logDir, fmt.Sprintf("vm-%s.log", ip)),
-		MetricsPath:    filepath.Join(logDir, fmt.Sprintf("vm-%s-metrics", ip)),
-	}
+	macAddr := slotMacAddress(slot)
+	tapName := slotTapName(host.TapPrefix, slot)
 
-	stdoutFile, err := os.Create(filepath.Join(logDir, fmt.Sprintf("vm-%s.stdout", ip)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout file: %v", err)
+	cfg := machineFiles(host.LogDir, ip)
+	cfg.SocketPath = socketPath
+	cfg.KernelPath = kernelPath
+	cfg.KernelArgs = machineCfg.bootArgs()
+	cfg.RootfsPath = rootfsPath
+	cfg.RootfsRateLimiter = machineCfg.DriveRateLimiter
+	cfg.Vsock = hypervisor.VsockConfig{
+		ID:   fmt.Sprintf("vsock-%d", slot),
+		Path: vsockPath,
+		CID:  cid,
 	}
-
-	stderrFile, err := os.Create(filepath.Join(logDir, fmt.Sprintf("vm-%s.stderr", ip)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create stderr file: %v", err)
+	cfg.Network = hypervisor.NetworkConfig{
+		TapName:    tapName,
+		MacAddress: macAddr,
+		IP: net.IPNet{
+			IP:   net.ParseIP(ip),
+			Mask: net.CIDRMask(prefixLength, 32),
+		},
+		Gateway:       net.ParseIP(gatewayIP),
+		Nameservers:   machineCfg.Nameservers,
+		RxRateLimiter: machineCfg.NetworkRxRateLimiter,
+		TxRateLimiter: machineCfg.NetworkTxRateLimiter,
 	}
+	cfg.Machine = machineCfg.hypervisorConfig()
 
-	cmd := firecracker.VMCommandBuilder{}.
-		WithBin("firecracker").
-		WithSocketPath(socketPath).
-		WithStdin(os.Stdin).
-		WithStdout(stdoutFile).
-		WithStderr(stderrFile).
-		Build(ctx)
-
-	machine, err := firecracker.NewMachine(ctx, cfg, firecracker.WithProcessRunner(cmd))
+	machine, err := hv.NewMachine(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create machine: %v", err)
+		return nil, err
 	}
 
 	return &SimplifiedVM{
@@ -210,8 +283,83 @@ func CreateVM(ctx context.Context, ip, kernelPath, rootfsPath, gatewayIP string,
 		VsockCID:   cid,
 		Stdout:     stdout,
 		Stderr:     stderr,
-		VMID:       vmIndex,
+		VMID:       slot,
 		TapName:    tapName,
+		MacAddress: macAddr,
 		IP:         ip,
+		GatewayIP:  gatewayIP,
+
+		PrefixLength: prefixLength,
+
+		MachineConfig: machineCfg,
+		status:        Status{State: StateCreating, CreatedAt: time.Now()},
+		stopTimeout:   host.StopTimeout,
+		metrics:       newMetricsSeries(cfg.MetricsPath),
+	}, nil
+}
+
+// RestoreVM creates a VM that boots from a snapshot instead of a kernel.
+// Devices (drives, tap, vsock) are restored from the snapshot state, so the
+// source VM must no longer be running.
+func RestoreVM(ctx context.Context, hv hypervisor.Hypervisor, host Host, snap *Snapshot, memFilePath string, resume bool) (*SimplifiedVM, error) {
+	socketPath := filepath.Join(host.SocketDir, fmt.Sprintf("vm-%s.sock", snap.IP))
+	vsockPath := filepath.Join(host.SocketDir, fmt.Sprintf("vsock-%s.sock", snap.IP))
+
+	// firecracker binds the vsock path recorded in the snapshot
+	if err := os.Remove(vsockPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale vsock file: %v", err)
+	}
+
+	cfg := machineFiles(host.LogDir, snap.IP)
+	cfg.SocketPath = socketPath
+	cfg.Vsock = hypervisor.VsockConfig{Path: vsockPath, CID: snap.VsockCID}
+	cfg.Network = hypervisor.NetworkConfig{TapName: snap.TapName, MacAddress: snap.MacAddress}
+	cfg.Machine = snap.MachineConfig.hypervisorConfig()
+	cfg.Snapshot = &hypervisor.SnapshotConfig{
+		MemFilePath:         memFilePath,
+		SnapshotPath:        snap.SnapshotPath,
+		Resume:              resume,
+		EnableDiffSnapshots: snap.MachineConfig.TrackDirtyPages,
+	}
+
+	machine, err := hv.NewMachine(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SimplifiedVM{
+		Machine:    machine,
+		KernelPath: snap.KernelPath,
+		RootfsPath: snap.RootfsPath,
+		SocketPath: socketPath,
+		VsockPath:  vsockPath,
+		VsockCID:   snap.VsockCID,
+		Stdout:     make(chan string, 100),
+		Stderr:     make(chan string, 100),
+		VMID:       snap.VMID,
+		TapName:    snap.TapName,
+		MacAddress: snap.MacAddress,
+		IP:         snap.IP,
+		GatewayIP:  snap.GatewayIP,
+
+		PrefixLength: snap.PrefixLength,
+
+		MachineConfig:  snap.MachineConfig,
+		status:         Status{State: StateCreating, CreatedAt: time.Now()},
+		startPaused:    !resume,
+		stopTimeout:    host.StopTimeout,
+		lastSnapshotID: snap.ID,
+		metrics:        newMetricsSeries(cfg.MetricsPath),
 	}, nil
 }
+
+// machineFiles returns a config with the log, metrics and output files of the
+// VM in logDir.
+func machineFiles(logDir, ip string) hypervisor.Config {
+	return hypervisor.Config{
+		LogPath:     filepath.Join(logDir, fmt.Sprintf("vm-%s.log", ip)),
+		MetricsPath: filepath.Join(logDir, fmt.Sprintf("vm-%s-metrics", ip)),
+		StdoutPath:  filepath.Join(logDir, fmt.Sprintf("vm-%s.stdout", ip)),
+		StderrPath:  filepath.Join(logDir, fmt.Sprintf("vm-%s.stderr", ip)),
+	}
+}
@@ -3,88 +3,259 @@ package vm
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bookpanda/firecracker-runner-node/internal/agent"
+	"github.com/bookpanda/firecracker-runner-node/internal/job"
 )
 
-func streamCommandVsock(ctx context.Context, sockPath string, port uint32, cmd string, outputWriter func(string)) error {
+// statusPrefix starts the last line an agent speaking the line protocol
+// writes if a command fails, "error: exit status N" or "error: signal: X".
+// Every other line is output, including other lines starting with
+// statusPrefix; the agent merges stdout and stderr.
+const statusPrefix = "error: "
+
+// streamCommandVsock runs cmd with sh -c through the guest agent of vm and
+// calls output with every line it prints until it exits or ctx is done. The
+// error is set if the agent could not be reached; how the command ended is in
+// the result. Agents that only speak the line protocol are still supported.
+func streamCommandVsock(ctx context.Context, vm *SimplifiedVM, port uint32, cmd string, output func(job.Output)) (job.Result, error) {
+	client, err := dialAgent(vm, port)
+	if errors.Is(err, agent.ErrLegacyAgent) {
+		return streamLineCommandVsock(ctx, vm.VsockPath, port, cmd, output)
+	}
+	if err != nil {
+		return job.Result{}, err
+	}
+	defer client.Close()
+
+	if err := client.Start(job.Spec{Argv: []string{"sh", "-c", cmd}}); err != nil {
+		return job.Result{}, err
+	}
+
+	// the agent answers the kill with the exit, and hanging up kills the
+	// command too if the agent does not answer
+	stop := context.AfterFunc(ctx, func() {
+		if err := client.Signal(syscall.SIGKILL); err != nil {
+			client.Close()
+		}
+	})
+	defer stop()
+
+	lines := job.NewLines(output)
+	result, err := client.Wait(lines.Write)
+	lines.Flush()
+	if ctx.Err() != nil {
+		return job.Result{ExitCode: -1, Duration: result.Duration, Killed: true, Error: context.Cause(ctx).Error()}, nil
+	}
+	if err != nil {
+		return job.Result{ExitCode: -1, Error: err.Error()}, nil
+	}
+	return result, nil
+}
+
+// dialAgent connects to the guest agent of vm on port. It returns
+// agent.ErrLegacyAgent if the agent only speaks the line protocol, without
+// connecting if an earlier call found out.
+func dialAgent(vm *SimplifiedVM, port uint32) (*agent.Client, error) {
+	if vm.legacyAgent.Load() {
+		return nil, agent.ErrLegacyAgent
+	}
+
+	conn, reader, err := connectVsock(vm.VsockPath, port)
+	if err != nil {
+		return nil, err
+	}
+	client, err := agent.NewClient(conn, reader, func() bool { return vm.State() == StatePaused })
+	if err != nil {
+		conn.Close()
+		if errors.Is(err, agent.ErrLegacyAgent) {
+			log.Printf("VM %s: guest agent only speaks the line protocol", vm.IP)
+			vm.legacyAgent.Store(true)
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+// connectVsock connects to port of the guest through the vsock socket of
+// firecracker at sockPath. The reader reads the connection.
+func connectVsock(sockPath string, port uint32) (net.Conn, *bufio.Reader, error) {
 	conn, err := net.Dial("unix", sockPath)
 	if err != nil {
-		return fmt.Errorf("failed to connect to %s: %v", sockPath, err)
+		return nil, nil, fmt.Errorf("failed to connect to %s: %v", sockPath, err)
 	}
-	defer conn.Close()
 
 	_, err = fmt.Fprintf(conn, "CONNECT %d\n", port)
 	if err != nil {
-		return fmt.Errorf("failed to send CONNECT: %v", err)
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to send CONNECT: %v", err)
+	}
+
+	// firecracker acknowledges with "OK <host port>"
+	reader := bufio.NewReader(conn)
+	ack, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to read CONNECT ack: %v", err)
+	}
+	if !strings.HasPrefix(ack, "OK ") {
+		conn.Close()
+		return nil, nil, fmt.Errorf("unexpected CONNECT ack: %q", strings.TrimSpace(ack))
+	}
+	return conn, reader, nil
+}
+
+// streamLineCommandVsock is streamCommandVsock for agents that only speak the
+// line protocol: the command is sent as a line and its merged output is read
+// until the agent hangs up.
+func streamLineCommandVsock(ctx context.Context, sockPath string, port uint32, cmd string, output func(job.Output)) (job.Result, error) {
+	start := time.Now()
+	conn, reader, err := connectVsock(sockPath, port)
+	if err != nil {
+		return job.Result{}, err
 	}
+	defer conn.Close()
 
 	// send the actual command
 	_, err = fmt.Fprintf(conn, "%s\n", cmd)
 	if err != nil {
-		return fmt.Errorf("failed to send command: %v", err)
+		return job.Result{}, fmt.Errorf("failed to send command: %v", err)
 	}
 
-	reader := bufio.NewReader(conn)
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			line, err := reader.ReadString('\n')
-			if len(line) > 0 {
-				outputWriter(line)
-			}
-			if err != nil {
-				// EOF or connection closed
-				return nil
-			}
+	// closing the connection ends the read below
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// a status line is only known to be one once it turns out to be the last
+	var status string
+	var readErr error
+	for readErr == nil {
+		var line string
+		line, readErr = reader.ReadString('\n')
+		if line == "" {
+			continue
 		}
+		if status != "" {
+			output(job.Output{Stream: job.Stdout, Line: status})
+			status = ""
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if _, _, ok := parseStatus(line); ok {
+			status = line
+			continue
+		}
+		output(job.Output{Stream: job.Stdout, Line: line})
 	}
+
+	result := job.Result{ExitCode: -1, Duration: time.Since(start)}
+	switch {
+	case ctx.Err() != nil:
+		if status != "" {
+			output(job.Output{Stream: job.Stdout, Line: status})
+		}
+		result.Killed = true
+		result.Error = context.Cause(ctx).Error()
+	case !errors.Is(readErr, io.EOF):
+		result.Error = fmt.Sprintf("lost connection to the guest agent: %v", readErr)
+	case status != "":
+		code, signal, _ := parseStatus(status)
+		result.ExitCode = code
+		if signal != "" {
+			result.Killed = true
+			result.Signal = signal
+			result.Error = strings.TrimPrefix(status, statusPrefix)
+		}
+	default:
+		result.ExitCode = 0
+	}
+	return result, nil
 }
 
-func (m *Manager) captureCommandOutputVsock(sockPath string, port uint32, command, logPath string, wait bool) error {
+// parseStatus parses the status line of a failed command, which is the
+// error the agent got running it. The exit code is -1 for a signal. ok is
+// false if line is not a status line.
+func parseStatus(line string) (exitCode int, signal string, ok bool) {
+	status, ok := strings.CutPrefix(line, statusPrefix)
+	if !ok {
+		return 0, "", false
+	}
+	if code, ok := strings.CutPrefix(status, "exit status "); ok {
+		n, err := strconv.Atoi(code)
+		if err != nil || n <= 0 || strconv.Itoa(n) != code {
+			return 0, "", false
+		}
+		return n, "", true
+	}
+	if signal, ok := strings.CutPrefix(status, "signal: "); ok && signal != "" {
+		return -1, signal, true
+	}
+	return 0, "", false
+}
+
+// runCommandVsock runs command through the guest agent until it exits or ctx
+// is done, writing its output to a new log file at logPath and passing it to
+// output, which may be nil.
+func runCommandVsock(ctx context.Context, vm *SimplifiedVM, port uint32, command, logPath string, output func(job.Output)) (job.Result, error) {
 	logFile, err := os.Create(logPath)
 	if err != nil {
-		return fmt.Errorf("failed to create log file %s: %v", logPath, err)
+		return job.Result{}, fmt.Errorf("failed to create log file %s: %v", logPath, err)
 	}
+	defer logFile.Close()
 
-	if wait {
-		m.wg.Add(1)
+	result, err := streamCommandVsock(ctx, vm, port, command, func(out job.Output) {
+		tag := "OUTPUT"
+		if out.Stream == job.Stderr {
+			tag = "STDERR"
+		}
+		logFile.WriteString(fmt.Sprintf("[%s] %s\n", tag, out.Line))
+		if output != nil {
+			output(out)
+		}
+	})
+	if err != nil {
+		logFile.WriteString(fmt.Sprintf("Error: %v\n", err))
+		return job.Result{}, err
 	}
 
-	go func() {
-		if wait {
-			defer m.wg.Done()
-		}
-		defer logFile.Close()
+	logFile.WriteString(fmt.Sprintf("[EXIT] code %d after %s, killed %v\n", result.ExitCode, result.Duration, result.Killed))
+	if result.Error != "" {
+		logFile.WriteString(fmt.Sprintf("Error: %s\n", result.Error))
+	}
+	return result, nil
+}
 
-		// Create a writer function that writes to the log file
-		outputWriter := func(line string) {
-			logFile.WriteString(fmt.Sprintf("[OUTPUT] %s", line))
-		}
+// startCommandVsock runs command through the guest agent in the background
+// until it exits or ctx is done, then calls done with how it ended.
+func startCommandVsock(ctx context.Context, vm *SimplifiedVM, port uint32, command, logPath string, done func(job.Result)) error {
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to create log file %s: %v", logPath, err)
+	}
+	logFile.Close()
 
-		if wait {
-			// For commands that should complete (like clients)
-			err := streamCommandVsock(m.vmCtx, sockPath, port, command, outputWriter)
-			if err != nil {
-				logFile.WriteString(fmt.Sprintf("Error: %v\n", err))
-				log.Printf("VM %s %d: command failed: %v", sockPath, port, err)
-			} else {
-				log.Printf("VM %s %d: command completed, output saved to %s", sockPath, port, logPath)
-			}
-		} else {
-			// For long-running commands (like servers)
-			err := streamCommandVsock(m.vmCtx, sockPath, port, command, outputWriter)
-			if err != nil && err != context.Canceled {
-				logFile.WriteString(fmt.Sprintf("Error: %v\n", err))
-				log.Printf("VM %s %d: command failed: %v", sockPath, port, err)
-			} else {
-				log.Printf("VM %s %d: server stopped, logs saved to %s", sockPath, port, logPath)
-			}
+	go func() {
+		result, err := runCommandVsock(ctx, vm, port, command, logPath, nil)
+		switch {
+		case err != nil:
+			log.Printf("VM %s: command failed: %v", vm.IP, err)
+			result = job.Result{ExitCode: -1, Error: err.Error()}
+		case result.Killed && ctx.Err() != nil:
+			log.Printf("VM %s: server stopped, logs saved to %s", vm.IP, logPath)
+		default:
+			log.Printf("VM %s: server exited with code %d, logs saved to %s", vm.IP, result.ExitCode, logPath)
 		}
+		done(result)
 	}()
 
 	return nil
@@ -21,8 +21,78 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type LogKind int32
+
+const (
+	LogKind_LOG_KIND_UNSPECIFIED   LogKind = 0
+	LogKind_LOG_KIND_FIRECRACKER   LogKind = 1 // vm-logs/vm-<ip>.log
+	LogKind_LOG_KIND_METRICS       LogKind = 2 // vm-logs/vm-<ip>-metrics
+	LogKind_LOG_KIND_STDOUT        LogKind = 3 // vm-logs/vm-<ip>.stdout
+	LogKind_LOG_KIND_STDERR        LogKind = 4 // vm-logs/vm-<ip>.stderr
+	LogKind_LOG_KIND_TEST          LogKind = 5 // vm-test/vm-<ip>-<job ID>.log
+	LogKind_LOG_KIND_SYSCALLS      LogKind = 6 // vm-syscalls/<session>/vm-<ip>.log
+	LogKind_LOG_KIND_NODE          LogKind = 7 // node-logs/node-server-<job ID>.log and node-client-<job ID>.log
+	LogKind_LOG_KIND_NODE_SYSCALLS LogKind = 8 // node-logs/<session>/node-syscalls-<pid>.log
+)
+
+// Enum value maps for LogKind.
+var (
+	LogKind_name = map[int32]string{
+		0: "LOG_KIND_UNSPECIFIED",
+		1: "LOG_KIND_FIRECRACKER",
+		2: "LOG_KIND_METRICS",
+		3: "LOG_KIND_STDOUT",
+		4: "LOG_KIND_STDERR",
+		5: "LOG_KIND_TEST",
+		6: "LOG_KIND_SYSCALLS",
+		7: "LOG_KIND_NODE",
+		8: "LOG_KIND_NODE_SYSCALLS",
+	}
+	LogKind_value = map[string]int32{
+		"LOG_KIND_UNSPECIFIED":   0,
+		"LOG_KIND_FIRECRACKER":   1,
+		"LOG_KIND_METRICS":       2,
+		"LOG_KIND_STDOUT":        3,
+		"LOG_KIND_STDERR":        4,
+		"LOG_KIND_TEST":          5,
+		"LOG_KIND_SYSCALLS":      6,
+		"LOG_KIND_NODE":          7,
+		"LOG_KIND_NODE_SYSCALLS": 8,
+	}
+)
+
+func (x LogKind) Enum() *LogKind {
+	p := new(LogKind)
+	*p = x
+	return p
+}
+
+func (x LogKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LogKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_filesystem_proto_enumTypes[0].Descriptor()
+}
+
+func (LogKind) Type() protoreflect.EnumType {
+	return &file_proto_filesystem_proto_enumTypes[0]
+}
+
+func (x LogKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LogKind.Descriptor instead.
+func (LogKind) EnumDescriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{0}
+}
+
+// CleanupFileSystemRequest removes the runner's temporary files and starts
+// a new run. The artifacts of earlier runs are kept.
 type CleanupFileSystemRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=runId,proto3" json:"runId,omitempty"` // generated if empty
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -57,8 +127,16 @@ func (*CleanupFileSystemRequest) Descriptor() ([]byte, []int) {
 	return file_proto_filesystem_proto_rawDescGZIP(), []int{0}
 }
 
+func (x *CleanupFileSystemRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
 type CleanupFileSystemResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Run           *Run                   `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -93,16 +171,595 @@ func (*CleanupFileSystemResponse) Descriptor() ([]byte, []int) {
 	return file_proto_filesystem_proto_rawDescGZIP(), []int{1}
 }
 
-type GetLogsFileSystemRequest struct {
+func (x *CleanupFileSystemResponse) GetRun() *Run {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+// Image is a kernel or rootfs image as it was when a VM booted from it.
+type Image struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime       int64                  `protobuf:"varint,3,opt,name=modTime,proto3" json:"modTime,omitempty"` // unix millis
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Image) Reset() {
+	*x = Image{}
+	mi := &file_proto_filesystem_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Image) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Image) ProtoMessage() {}
+
+func (x *Image) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filesystem_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Image.ProtoReflect.Descriptor instead.
+func (*Image) Descriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Image) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Image) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Image) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+type RunVm struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Kernel        *Image                 `protobuf:"bytes,2,opt,name=kernel,proto3" json:"kernel,omitempty"`
+	Rootfs        *Image                 `protobuf:"bytes,3,opt,name=rootfs,proto3" json:"rootfs,omitempty"`
+	VcpuCount     int64                  `protobuf:"varint,4,opt,name=vcpuCount,proto3" json:"vcpuCount,omitempty"`
+	MemSizeMib    int64                  `protobuf:"varint,5,opt,name=memSizeMib,proto3" json:"memSizeMib,omitempty"`
+	KernelArgs    string                 `protobuf:"bytes,6,opt,name=kernelArgs,proto3" json:"kernelArgs,omitempty"`
+	SnapshotId    string                 `protobuf:"bytes,7,opt,name=snapshotId,proto3" json:"snapshotId,omitempty"` // the snapshot the VM was restored from, if any
+	CreatedAt     int64                  `protobuf:"varint,8,opt,name=createdAt,proto3" json:"createdAt,omitempty"`  // unix millis
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunVm) Reset() {
+	*x = RunVm{}
+	mi := &file_proto_filesystem_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunVm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunVm) ProtoMessage() {}
+
+func (x *RunVm) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filesystem_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunVm.ProtoReflect.Descriptor instead.
+func (*RunVm) Descriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RunVm) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *RunVm) GetKernel() *Image {
+	if x != nil {
+		return x.Kernel
+	}
+	return nil
+}
+
+func (x *RunVm) GetRootfs() *Image {
+	if x != nil {
+		return x.Rootfs
+	}
+	return nil
+}
+
+func (x *RunVm) GetVcpuCount() int64 {
+	if x != nil {
+		return x.VcpuCount
+	}
+	return 0
+}
+
+func (x *RunVm) GetMemSizeMib() int64 {
+	if x != nil {
+		return x.MemSizeMib
+	}
+	return 0
+}
+
+func (x *RunVm) GetKernelArgs() string {
+	if x != nil {
+		return x.KernelArgs
+	}
+	return ""
+}
+
+func (x *RunVm) GetSnapshotId() string {
+	if x != nil {
+		return x.SnapshotId
+	}
+	return ""
+}
+
+func (x *RunVm) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type RunCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        string                 `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"` // the IP of the VM, or "node"
+	Command       string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	Server        bool                   `protobuf:"varint,3,opt,name=server,proto3" json:"server,omitempty"`       // runs in the background, a client runs to completion
+	StartedAt     int64                  `protobuf:"varint,4,opt,name=startedAt,proto3" json:"startedAt,omitempty"` // unix millis
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunCommand) Reset() {
+	*x = RunCommand{}
+	mi := &file_proto_filesystem_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunCommand) ProtoMessage() {}
+
+func (x *RunCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filesystem_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunCommand.ProtoReflect.Descriptor instead.
+func (*RunCommand) Descriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RunCommand) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *RunCommand) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *RunCommand) GetServer() bool {
+	if x != nil {
+		return x.Server
+	}
+	return false
+}
+
+func (x *RunCommand) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+// Run is the manifest of an experiment run. Its artifacts are kept in their
+// own directory, laid out like vm-logs, vm-test, vm-syscalls and node-logs.
+type Run struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	StartedAt     int64                  `protobuf:"varint,2,opt,name=startedAt,proto3" json:"startedAt,omitempty"` // unix millis
+	Vms           []*RunVm               `protobuf:"bytes,3,rep,name=vms,proto3" json:"vms,omitempty"`
+	Commands      []*RunCommand          `protobuf:"bytes,4,rep,name=commands,proto3" json:"commands,omitempty"`
+	Current       bool                   `protobuf:"varint,5,opt,name=current,proto3" json:"current,omitempty"` // the run new artifacts go to
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Run) Reset() {
+	*x = Run{}
+	mi := &file_proto_filesystem_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Run) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Run) ProtoMessage() {}
+
+func (x *Run) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filesystem_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Run.ProtoReflect.Descriptor instead.
+func (*Run) Descriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Run) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Run) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *Run) GetVms() []*RunVm {
+	if x != nil {
+		return x.Vms
+	}
+	return nil
+}
+
+func (x *Run) GetCommands() []*RunCommand {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+func (x *Run) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+// StartRunFileSystemRequest ends the current run and starts a new one.
+type StartRunFileSystemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // generated if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRunFileSystemRequest) Reset() {
+	*x = StartRunFileSystemRequest{}
+	mi := &file_proto_filesystem_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRunFileSystemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRunFileSystemRequest) ProtoMessage() {}
+
+func (x *StartRunFileSystemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filesystem_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRunFileSystemRequest.ProtoReflect.Descriptor instead.
+func (*StartRunFileSystemRequest) Descriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StartRunFileSystemRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type StartRunFileSystemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Run           *Run                   `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRunFileSystemResponse) Reset() {
+	*x = StartRunFileSystemResponse{}
+	mi := &file_proto_filesystem_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRunFileSystemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRunFileSystemResponse) ProtoMessage() {}
+
+func (x *StartRunFileSystemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filesystem_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRunFileSystemResponse.ProtoReflect.Descriptor instead.
+func (*StartRunFileSystemResponse) Descriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StartRunFileSystemResponse) GetRun() *Run {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+type ListRunsFileSystemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRunsFileSystemRequest) Reset() {
+	*x = ListRunsFileSystemRequest{}
+	mi := &file_proto_filesystem_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRunsFileSystemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunsFileSystemRequest) ProtoMessage() {}
+
+func (x *ListRunsFileSystemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filesystem_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunsFileSystemRequest.ProtoReflect.Descriptor instead.
+func (*ListRunsFileSystemRequest) Descriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{8}
+}
+
+type ListRunsFileSystemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Runs          []*Run                 `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRunsFileSystemResponse) Reset() {
+	*x = ListRunsFileSystemResponse{}
+	mi := &file_proto_filesystem_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRunsFileSystemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRunsFileSystemResponse) ProtoMessage() {}
+
+func (x *ListRunsFileSystemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filesystem_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRunsFileSystemResponse.ProtoReflect.Descriptor instead.
+func (*ListRunsFileSystemResponse) Descriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListRunsFileSystemResponse) GetRuns() []*Run {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+// ExportRunFileSystemRequest streams the directory of a run as a tar.gz
+// archive, whose paths start with the run's ID.
+type ExportRunFileSystemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // the current run if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRunFileSystemRequest) Reset() {
+	*x = ExportRunFileSystemRequest{}
+	mi := &file_proto_filesystem_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRunFileSystemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRunFileSystemRequest) ProtoMessage() {}
+
+func (x *ExportRunFileSystemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filesystem_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRunFileSystemRequest.ProtoReflect.Descriptor instead.
+func (*ExportRunFileSystemRequest) Descriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExportRunFileSystemRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ExportRunFileSystemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"` // the next chunk of the archive
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRunFileSystemResponse) Reset() {
+	*x = ExportRunFileSystemResponse{}
+	mi := &file_proto_filesystem_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRunFileSystemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRunFileSystemResponse) ProtoMessage() {}
+
+func (x *ExportRunFileSystemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_filesystem_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRunFileSystemResponse.ProtoReflect.Descriptor instead.
+func (*ExportRunFileSystemResponse) Descriptor() ([]byte, []int) {
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExportRunFileSystemResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// GetLogsFileSystemRequest streams the log files of a run one after
+// another, in chunks.
+type GetLogsFileSystemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`                                                // the VM whose logs to send, only node logs if empty
+	Kinds         []LogKind              `protobuf:"varint,2,rep,packed,name=kinds,proto3,enum=proto.filesystem.v1.LogKind" json:"kinds,omitempty"` // every kind if empty
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`                                       // skip the first offset bytes of every file
+	Tail          int64                  `protobuf:"varint,4,opt,name=tail,proto3" json:"tail,omitempty"`                                           // only send the last tail lines of every file, instead of offset
+	RunId         string                 `protobuf:"bytes,5,opt,name=runId,proto3" json:"runId,omitempty"`                                          // the current run if empty
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetLogsFileSystemRequest) Reset() {
 	*x = GetLogsFileSystemRequest{}
-	mi := &file_proto_filesystem_proto_msgTypes[2]
+	mi := &file_proto_filesystem_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -114,7 +771,7 @@ func (x *GetLogsFileSystemRequest) String() string {
 func (*GetLogsFileSystemRequest) ProtoMessage() {}
 
 func (x *GetLogsFileSystemRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_filesystem_proto_msgTypes[2]
+	mi := &file_proto_filesystem_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -127,7 +784,7 @@ func (x *GetLogsFileSystemRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetLogsFileSystemRequest.ProtoReflect.Descriptor instead.
 func (*GetLogsFileSystemRequest) Descriptor() ([]byte, []int) {
-	return file_proto_filesystem_proto_rawDescGZIP(), []int{2}
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetLogsFileSystemRequest) GetIp() string {
@@ -137,15 +794,48 @@ func (x *GetLogsFileSystemRequest) GetIp() string {
 	return ""
 }
 
+func (x *GetLogsFileSystemRequest) GetKinds() []LogKind {
+	if x != nil {
+		return x.Kinds
+	}
+	return nil
+}
+
+func (x *GetLogsFileSystemRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetLogsFileSystemRequest) GetTail() int64 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+func (x *GetLogsFileSystemRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
 type GetLogsFileSystemResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // relative to the run's directory
+	Kind          LogKind                `protobuf:"varint,2,opt,name=kind,proto3,enum=proto.filesystem.v1.LogKind" json:"kind,omitempty"`
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"` // of data in the file
+	Data          []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Eof           bool                   `protobuf:"varint,5,opt,name=eof,proto3" json:"eof,omitempty"` // the last chunk of the file
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetLogsFileSystemResponse) Reset() {
 	*x = GetLogsFileSystemResponse{}
-	mi := &file_proto_filesystem_proto_msgTypes[3]
+	mi := &file_proto_filesystem_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -157,7 +847,7 @@ func (x *GetLogsFileSystemResponse) String() string {
 func (*GetLogsFileSystemResponse) ProtoMessage() {}
 
 func (x *GetLogsFileSystemResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_filesystem_proto_msgTypes[3]
+	mi := &file_proto_filesystem_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -170,22 +860,123 @@ func (x *GetLogsFileSystemResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetLogsFileSystemResponse.ProtoReflect.Descriptor instead.
 func (*GetLogsFileSystemResponse) Descriptor() ([]byte, []int) {
-	return file_proto_filesystem_proto_rawDescGZIP(), []int{3}
+	return file_proto_filesystem_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetLogsFileSystemResponse) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetLogsFileSystemResponse) GetKind() LogKind {
+	if x != nil {
+		return x.Kind
+	}
+	return LogKind_LOG_KIND_UNSPECIFIED
+}
+
+func (x *GetLogsFileSystemResponse) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetLogsFileSystemResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *GetLogsFileSystemResponse) GetEof() bool {
+	if x != nil {
+		return x.Eof
+	}
+	return false
 }
 
 var File_proto_filesystem_proto protoreflect.FileDescriptor
 
 const file_proto_filesystem_proto_rawDesc = "" +
 	"\n" +
-	"\x16proto/filesystem.proto\x12\x13proto.filesystem.v1\"\x1a\n" +
-	"\x18CleanupFileSystemRequest\"\x1b\n" +
-	"\x19CleanupFileSystemResponse\"*\n" +
+	"\x16proto/filesystem.proto\x12\x13proto.filesystem.v1\"0\n" +
+	"\x18CleanupFileSystemRequest\x12\x14\n" +
+	"\x05runId\x18\x01 \x01(\tR\x05runId\"G\n" +
+	"\x19CleanupFileSystemResponse\x12*\n" +
+	"\x03run\x18\x01 \x01(\v2\x18.proto.filesystem.v1.RunR\x03run\"I\n" +
+	"\x05Image\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x18\n" +
+	"\amodTime\x18\x03 \x01(\x03R\amodTime\"\x9b\x02\n" +
+	"\x05RunVm\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x122\n" +
+	"\x06kernel\x18\x02 \x01(\v2\x1a.proto.filesystem.v1.ImageR\x06kernel\x122\n" +
+	"\x06rootfs\x18\x03 \x01(\v2\x1a.proto.filesystem.v1.ImageR\x06rootfs\x12\x1c\n" +
+	"\tvcpuCount\x18\x04 \x01(\x03R\tvcpuCount\x12\x1e\n" +
+	"\n" +
+	"memSizeMib\x18\x05 \x01(\x03R\n" +
+	"memSizeMib\x12\x1e\n" +
+	"\n" +
+	"kernelArgs\x18\x06 \x01(\tR\n" +
+	"kernelArgs\x12\x1e\n" +
+	"\n" +
+	"snapshotId\x18\a \x01(\tR\n" +
+	"snapshotId\x12\x1c\n" +
+	"\tcreatedAt\x18\b \x01(\x03R\tcreatedAt\"t\n" +
+	"\n" +
+	"RunCommand\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\tR\x06target\x12\x18\n" +
+	"\acommand\x18\x02 \x01(\tR\acommand\x12\x16\n" +
+	"\x06server\x18\x03 \x01(\bR\x06server\x12\x1c\n" +
+	"\tstartedAt\x18\x04 \x01(\x03R\tstartedAt\"\xb8\x01\n" +
+	"\x03Run\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tstartedAt\x18\x02 \x01(\x03R\tstartedAt\x12,\n" +
+	"\x03vms\x18\x03 \x03(\v2\x1a.proto.filesystem.v1.RunVmR\x03vms\x12;\n" +
+	"\bcommands\x18\x04 \x03(\v2\x1f.proto.filesystem.v1.RunCommandR\bcommands\x12\x18\n" +
+	"\acurrent\x18\x05 \x01(\bR\acurrent\"+\n" +
+	"\x19StartRunFileSystemRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"H\n" +
+	"\x1aStartRunFileSystemResponse\x12*\n" +
+	"\x03run\x18\x01 \x01(\v2\x18.proto.filesystem.v1.RunR\x03run\"\x1b\n" +
+	"\x19ListRunsFileSystemRequest\"J\n" +
+	"\x1aListRunsFileSystemResponse\x12,\n" +
+	"\x04runs\x18\x01 \x03(\v2\x18.proto.filesystem.v1.RunR\x04runs\",\n" +
+	"\x1aExportRunFileSystemRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"1\n" +
+	"\x1bExportRunFileSystemResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"\xa0\x01\n" +
 	"\x18GetLogsFileSystemRequest\x12\x0e\n" +
-	"\x02ip\x18\x01 \x01(\tR\x02ip\"\x1b\n" +
-	"\x19GetLogsFileSystemResponse2\xeb\x01\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x122\n" +
+	"\x05kinds\x18\x02 \x03(\x0e2\x1c.proto.filesystem.v1.LogKindR\x05kinds\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04tail\x18\x04 \x01(\x03R\x04tail\x12\x14\n" +
+	"\x05runId\x18\x05 \x01(\tR\x05runId\"\x9f\x01\n" +
+	"\x19GetLogsFileSystemResponse\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x120\n" +
+	"\x04kind\x18\x02 \x01(\x0e2\x1c.proto.filesystem.v1.LogKindR\x04kind\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\x12\x10\n" +
+	"\x03eof\x18\x05 \x01(\bR\x03eof*\xd6\x01\n" +
+	"\aLogKind\x12\x18\n" +
+	"\x14LOG_KIND_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14LOG_KIND_FIRECRACKER\x10\x01\x12\x14\n" +
+	"\x10LOG_KIND_METRICS\x10\x02\x12\x13\n" +
+	"\x0fLOG_KIND_STDOUT\x10\x03\x12\x13\n" +
+	"\x0fLOG_KIND_STDERR\x10\x04\x12\x11\n" +
+	"\rLOG_KIND_TEST\x10\x05\x12\x15\n" +
+	"\x11LOG_KIND_SYSCALLS\x10\x06\x12\x11\n" +
+	"\rLOG_KIND_NODE\x10\a\x12\x1a\n" +
+	"\x16LOG_KIND_NODE_SYSCALLS\x10\b2\xbf\x04\n" +
 	"\x11FileSystemService\x12j\n" +
-	"\aCleanup\x12-.proto.filesystem.v1.CleanupFileSystemRequest\x1a..proto.filesystem.v1.CleanupFileSystemResponse\"\x00\x12j\n" +
-	"\aGetLogs\x12-.proto.filesystem.v1.GetLogsFileSystemRequest\x1a..proto.filesystem.v1.GetLogsFileSystemResponse\"\x00B\x15Z\x13proto/filesystem/v1b\x06proto3"
+	"\aCleanup\x12-.proto.filesystem.v1.CleanupFileSystemRequest\x1a..proto.filesystem.v1.CleanupFileSystemResponse\"\x00\x12l\n" +
+	"\aGetLogs\x12-.proto.filesystem.v1.GetLogsFileSystemRequest\x1a..proto.filesystem.v1.GetLogsFileSystemResponse\"\x000\x01\x12m\n" +
+	"\bStartRun\x12..proto.filesystem.v1.StartRunFileSystemRequest\x1a/.proto.filesystem.v1.StartRunFileSystemResponse\"\x00\x12m\n" +
+	"\bListRuns\x12..proto.filesystem.v1.ListRunsFileSystemRequest\x1a/.proto.filesystem.v1.ListRunsFileSystemResponse\"\x00\x12r\n" +
+	"\tExportRun\x12/.proto.filesystem.v1.ExportRunFileSystemRequest\x1a0.proto.filesystem.v1.ExportRunFileSystemResponse\"\x000\x01B\x15Z\x13proto/filesystem/v1b\x06proto3"
 
 var (
 	file_proto_filesystem_proto_rawDescOnce sync.Once
@@ -199,23 +990,50 @@ func file_proto_filesystem_proto_rawDescGZIP() []byte {
 	return file_proto_filesystem_proto_rawDescData
 }
 
-var file_proto_filesystem_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_proto_filesystem_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_filesystem_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_proto_filesystem_proto_goTypes = []any{
-	(*CleanupFileSystemRequest)(nil),  // 0: proto.filesystem.v1.CleanupFileSystemRequest
-	(*CleanupFileSystemResponse)(nil), // 1: proto.filesystem.v1.CleanupFileSystemResponse
-	(*GetLogsFileSystemRequest)(nil),  // 2: proto.filesystem.v1.GetLogsFileSystemRequest
-	(*GetLogsFileSystemResponse)(nil), // 3: proto.filesystem.v1.GetLogsFileSystemResponse
+	(LogKind)(0),                        // 0: proto.filesystem.v1.LogKind
+	(*CleanupFileSystemRequest)(nil),    // 1: proto.filesystem.v1.CleanupFileSystemRequest
+	(*CleanupFileSystemResponse)(nil),   // 2: proto.filesystem.v1.CleanupFileSystemResponse
+	(*Image)(nil),                       // 3: proto.filesystem.v1.Image
+	(*RunVm)(nil),                       // 4: proto.filesystem.v1.RunVm
+	(*RunCommand)(nil),                  // 5: proto.filesystem.v1.RunCommand
+	(*Run)(nil),                         // 6: proto.filesystem.v1.Run
+	(*StartRunFileSystemRequest)(nil),   // 7: proto.filesystem.v1.StartRunFileSystemRequest
+	(*StartRunFileSystemResponse)(nil),  // 8: proto.filesystem.v1.StartRunFileSystemResponse
+	(*ListRunsFileSystemRequest)(nil),   // 9: proto.filesystem.v1.ListRunsFileSystemRequest
+	(*ListRunsFileSystemResponse)(nil),  // 10: proto.filesystem.v1.ListRunsFileSystemResponse
+	(*ExportRunFileSystemRequest)(nil),  // 11: proto.filesystem.v1.ExportRunFileSystemRequest
+	(*ExportRunFileSystemResponse)(nil), // 12: proto.filesystem.v1.ExportRunFileSystemResponse
+	(*GetLogsFileSystemRequest)(nil),    // 13: proto.filesystem.v1.GetLogsFileSystemRequest
+	(*GetLogsFileSystemResponse)(nil),   // 14: proto.filesystem.v1.GetLogsFileSystemResponse
 }
 var file_proto_filesystem_proto_depIdxs = []int32{
-	0, // 0: proto.filesystem.v1.FileSystemService.Cleanup:input_type -> proto.filesystem.v1.CleanupFileSystemRequest
-	2, // 1: proto.filesystem.v1.FileSystemService.GetLogs:input_type -> proto.filesystem.v1.GetLogsFileSystemRequest
-	1, // 2: proto.filesystem.v1.FileSystemService.Cleanup:output_type -> proto.filesystem.v1.CleanupFileSystemResponse
-	3, // 3: proto.filesystem.v1.FileSystemService.GetLogs:output_type -> proto.filesystem.v1.GetLogsFileSystemResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	6,  // 0: proto.filesystem.v1.CleanupFileSystemResponse.run:type_name -> proto.filesystem.v1.Run
+	3,  // 1: proto.filesystem.v1.RunVm.kernel:type_name -> proto.filesystem.v1.Image
+	3,  // 2: proto.filesystem.v1.RunVm.rootfs:type_name -> proto.filesystem.v1.Image
+	4,  // 3: proto.filesystem.v1.Run.vms:type_name -> proto.filesystem.v1.RunVm
+	5,  // 4: proto.filesystem.v1.Run.commands:type_name -> proto.filesystem.v1.RunCommand
+	6,  // 5: proto.filesystem.v1.StartRunFileSystemResponse.run:type_name -> proto.filesystem.v1.Run
+	6,  // 6: proto.filesystem.v1.ListRunsFileSystemResponse.runs:type_name -> proto.filesystem.v1.Run
+	0,  // 7: proto.filesystem.v1.GetLogsFileSystemRequest.kinds:type_name -> proto.filesystem.v1.LogKind
+	0,  // 8: proto.filesystem.v1.GetLogsFileSystemResponse.kind:type_name -> proto.filesystem.v1.LogKind
+	1,  // 9: proto.filesystem.v1.FileSystemService.Cleanup:input_type -> proto.filesystem.v1.CleanupFileSystemRequest
+	13, // 10: proto.filesystem.v1.FileSystemService.GetLogs:input_type -> proto.filesystem.v1.GetLogsFileSystemRequest
+	7,  // 11: proto.filesystem.v1.FileSystemService.StartRun:input_type -> proto.filesystem.v1.StartRunFileSystemRequest
+	9,  // 12: proto.filesystem.v1.FileSystemService.ListRuns:input_type -> proto.filesystem.v1.ListRunsFileSystemRequest
+	11, // 13: proto.filesystem.v1.FileSystemService.ExportRun:input_type -> proto.filesystem.v1.ExportRunFileSystemRequest
+	2,  // 14: proto.filesystem.v1.FileSystemService.Cleanup:output_type -> proto.filesystem.v1.CleanupFileSystemResponse
+	14, // 15: proto.filesystem.v1.FileSystemService.GetLogs:output_type -> proto.filesystem.v1.GetLogsFileSystemResponse
+	8,  // 16: proto.filesystem.v1.FileSystemService.StartRun:output_type -> proto.filesystem.v1.StartRunFileSystemResponse
+	10, // 17: proto.filesystem.v1.FileSystemService.ListRuns:output_type -> proto.filesystem.v1.ListRunsFileSystemResponse
+	12, // 18: proto.filesystem.v1.FileSystemService.ExportRun:output_type -> proto.filesystem.v1.ExportRunFileSystemResponse
+	14, // [14:19] is the sub-list for method output_type
+	9,  // [9:14] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_proto_filesystem_proto_init() }
@@ -228,13 +1046,14 @@ func file_proto_filesystem_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_filesystem_proto_rawDesc), len(file_proto_filesystem_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_filesystem_proto_goTypes,
 		DependencyIndexes: file_proto_filesystem_proto_depIdxs,
+		EnumInfos:         file_proto_filesystem_proto_enumTypes,
 		MessageInfos:      file_proto_filesystem_proto_msgTypes,
 	}.Build()
 	File_proto_filesystem_proto = out.File
@@ -19,8 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FileSystemService_Cleanup_FullMethodName = "/proto.filesystem.v1.FileSystemService/Cleanup"
-	FileSystemService_GetLogs_FullMethodName = "/proto.filesystem.v1.FileSystemService/GetLogs"
+	FileSystemService_Cleanup_FullMethodName   = "/proto.filesystem.v1.FileSystemService/Cleanup"
+	FileSystemService_GetLogs_FullMethodName   = "/proto.filesystem.v1.FileSystemService/GetLogs"
+	FileSystemService_StartRun_FullMethodName  = "/proto.filesystem.v1.FileSystemService/StartRun"
+	FileSystemService_ListRuns_FullMethodName  = "/proto.filesystem.v1.FileSystemService/ListRuns"
+	FileSystemService_ExportRun_FullMethodName = "/proto.filesystem.v1.FileSystemService/ExportRun"
 )
 
 // FileSystemServiceClient is the client API for FileSystemService service.
@@ -28,7 +31,10 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type FileSystemServiceClient interface {
 	Cleanup(ctx context.Context, in *CleanupFileSystemRequest, opts ...grpc.CallOption) (*CleanupFileSystemResponse, error)
-	GetLogs(ctx context.Context, in *GetLogsFileSystemRequest, opts ...grpc.CallOption) (*GetLogsFileSystemResponse, error)
+	GetLogs(ctx context.Context, in *GetLogsFileSystemRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetLogsFileSystemResponse], error)
+	StartRun(ctx context.Context, in *StartRunFileSystemRequest, opts ...grpc.CallOption) (*StartRunFileSystemResponse, error)
+	ListRuns(ctx context.Context, in *ListRunsFileSystemRequest, opts ...grpc.CallOption) (*ListRunsFileSystemResponse, error)
+	ExportRun(ctx context.Context, in *ExportRunFileSystemRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportRunFileSystemResponse], error)
 }
 
 type fileSystemServiceClient struct {
@@ -49,22 +55,73 @@ func (c *fileSystemServiceClient) Cleanup(ctx context.Context, in *CleanupFileSy
 	return out, nil
 }
 
-func (c *fileSystemServiceClient) GetLogs(ctx context.Context, in *GetLogsFileSystemRequest, opts ...grpc.CallOption) (*GetLogsFileSystemResponse, error) {
+func (c *fileSystemServiceClient) GetLogs(ctx context.Context, in *GetLogsFileSystemRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetLogsFileSystemResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(GetLogsFileSystemResponse)
-	err := c.cc.Invoke(ctx, FileSystemService_GetLogs_FullMethodName, in, out, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &FileSystemService_ServiceDesc.Streams[0], FileSystemService_GetLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetLogsFileSystemRequest, GetLogsFileSystemResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileSystemService_GetLogsClient = grpc.ServerStreamingClient[GetLogsFileSystemResponse]
+
+func (c *fileSystemServiceClient) StartRun(ctx context.Context, in *StartRunFileSystemRequest, opts ...grpc.CallOption) (*StartRunFileSystemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartRunFileSystemResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_StartRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileSystemServiceClient) ListRuns(ctx context.Context, in *ListRunsFileSystemRequest, opts ...grpc.CallOption) (*ListRunsFileSystemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRunsFileSystemResponse)
+	err := c.cc.Invoke(ctx, FileSystemService_ListRuns_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
+func (c *fileSystemServiceClient) ExportRun(ctx context.Context, in *ExportRunFileSystemRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportRunFileSystemResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileSystemService_ServiceDesc.Streams[1], FileSystemService_ExportRun_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportRunFileSystemRequest, ExportRunFileSystemResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileSystemService_ExportRunClient = grpc.ServerStreamingClient[ExportRunFileSystemResponse]
+
 // FileSystemServiceServer is the server API for FileSystemService service.
 // All implementations must embed UnimplementedFileSystemServiceServer
 // for forward compatibility.
 type FileSystemServiceServer interface {
 	Cleanup(context.Context, *CleanupFileSystemRequest) (*CleanupFileSystemResponse, error)
-	GetLogs(context.Context, *GetLogsFileSystemRequest) (*GetLogsFileSystemResponse, error)
+	GetLogs(*GetLogsFileSystemRequest, grpc.ServerStreamingServer[GetLogsFileSystemResponse]) error
+	StartRun(context.Context, *StartRunFileSystemRequest) (*StartRunFileSystemResponse, error)
+	ListRuns(context.Context, *ListRunsFileSystemRequest) (*ListRunsFileSystemResponse, error)
+	ExportRun(*ExportRunFileSystemRequest, grpc.ServerStreamingServer[ExportRunFileSystemResponse]) error
 	mustEmbedUnimplementedFileSystemServiceServer()
 }
 
@@ -78,8 +135,17 @@ type UnimplementedFileSystemServiceServer struct{}
 func (UnimplementedFileSystemServiceServer) Cleanup(context.Context, *CleanupFileSystemRequest) (*CleanupFileSystemResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Cleanup not implemented")
 }
-func (UnimplementedFileSystemServiceServer) GetLogs(context.Context, *GetLogsFileSystemRequest) (*GetLogsFileSystemResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetLogs not implemented")
+func (UnimplementedFileSystemServiceServer) GetLogs(*GetLogsFileSystemRequest, grpc.ServerStreamingServer[GetLogsFileSystemResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method GetLogs not implemented")
+}
+func (UnimplementedFileSystemServiceServer) StartRun(context.Context, *StartRunFileSystemRequest) (*StartRunFileSystemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartRun not implemented")
+}
+func (UnimplementedFileSystemServiceServer) ListRuns(context.Context, *ListRunsFileSystemRequest) (*ListRunsFileSystemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRuns not implemented")
+}
+func (UnimplementedFileSystemServiceServer) ExportRun(*ExportRunFileSystemRequest, grpc.ServerStreamingServer[ExportRunFileSystemResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportRun not implemented")
 }
 func (UnimplementedFileSystemServiceServer) mustEmbedUnimplementedFileSystemServiceServer() {}
 func (UnimplementedFileSystemServiceServer) testEmbeddedByValue()                           {}
@@ -120,24 +186,64 @@ func _FileSystemService_Cleanup_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
-func _FileSystemService_GetLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetLogsFileSystemRequest)
+func _FileSystemService_GetLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetLogsFileSystemRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileSystemServiceServer).GetLogs(m, &grpc.GenericServerStream[GetLogsFileSystemRequest, GetLogsFileSystemResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileSystemService_GetLogsServer = grpc.ServerStreamingServer[GetLogsFileSystemResponse]
+
+func _FileSystemService_StartRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRunFileSystemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileSystemServiceServer).StartRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileSystemService_StartRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileSystemServiceServer).StartRun(ctx, req.(*StartRunFileSystemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileSystemService_ListRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRunsFileSystemRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FileSystemServiceServer).GetLogs(ctx, in)
+		return srv.(FileSystemServiceServer).ListRuns(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: FileSystemService_GetLogs_FullMethodName,
+		FullMethod: FileSystemService_ListRuns_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FileSystemServiceServer).GetLogs(ctx, req.(*GetLogsFileSystemRequest))
+		return srv.(FileSystemServiceServer).ListRuns(ctx, req.(*ListRunsFileSystemRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileSystemService_ExportRun_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRunFileSystemRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileSystemServiceServer).ExportRun(m, &grpc.GenericServerStream[ExportRunFileSystemRequest, ExportRunFileSystemResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileSystemService_ExportRunServer = grpc.ServerStreamingServer[ExportRunFileSystemResponse]
+
 // FileSystemService_ServiceDesc is the grpc.ServiceDesc for FileSystemService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -150,10 +256,25 @@ var FileSystemService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _FileSystemService_Cleanup_Handler,
 		},
 		{
-			MethodName: "GetLogs",
-			Handler:    _FileSystemService_GetLogs_Handler,
+			MethodName: "StartRun",
+			Handler:    _FileSystemService_StartRun_Handler,
+		},
+		{
+			MethodName: "ListRuns",
+			Handler:    _FileSystemService_ListRuns_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetLogs",
+			Handler:       _FileSystemService_GetLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportRun",
+			Handler:       _FileSystemService_ExportRun_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/filesystem.proto",
 }
@@ -21,6 +21,152 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type RouteMode int32
+
+const (
+	RouteMode_ROUTE_MODE_STATIC RouteMode = 0 // host route via the remote node
+	RouteMode_ROUTE_MODE_VXLAN  RouteMode = 1 // VXLAN tunnel attached to the bridge
+)
+
+// Enum value maps for RouteMode.
+var (
+	RouteMode_name = map[int32]string{
+		0: "ROUTE_MODE_STATIC",
+		1: "ROUTE_MODE_VXLAN",
+	}
+	RouteMode_value = map[string]int32{
+		"ROUTE_MODE_STATIC": 0,
+		"ROUTE_MODE_VXLAN":  1,
+	}
+)
+
+func (x RouteMode) Enum() *RouteMode {
+	p := new(RouteMode)
+	*p = x
+	return p
+}
+
+func (x RouteMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RouteMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_network_proto_enumTypes[0].Descriptor()
+}
+
+func (RouteMode) Type() protoreflect.EnumType {
+	return &file_proto_network_proto_enumTypes[0]
+}
+
+func (x RouteMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RouteMode.Descriptor instead.
+func (RouteMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{0}
+}
+
+type CrossNodeRoute struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RemoteSubnet  string                 `protobuf:"bytes,1,opt,name=remoteSubnet,proto3" json:"remoteSubnet,omitempty"`
+	RemoteNodeIP  string                 `protobuf:"bytes,2,opt,name=remoteNodeIP,proto3" json:"remoteNodeIP,omitempty"`
+	LocalBridgeIP string                 `protobuf:"bytes,3,opt,name=localBridgeIP,proto3" json:"localBridgeIP,omitempty"`
+	Mode          RouteMode              `protobuf:"varint,4,opt,name=mode,proto3,enum=proto.network.v1.RouteMode" json:"mode,omitempty"`
+	Vni           uint32                 `protobuf:"varint,5,opt,name=vni,proto3" json:"vni,omitempty"`
+	Port          int32                  `protobuf:"varint,6,opt,name=port,proto3" json:"port,omitempty"`
+	LocalNodeIP   string                 `protobuf:"bytes,7,opt,name=localNodeIP,proto3" json:"localNodeIP,omitempty"`
+	Device        string                 `protobuf:"bytes,8,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrossNodeRoute) Reset() {
+	*x = CrossNodeRoute{}
+	mi := &file_proto_network_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrossNodeRoute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrossNodeRoute) ProtoMessage() {}
+
+func (x *CrossNodeRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrossNodeRoute.ProtoReflect.Descriptor instead.
+func (*CrossNodeRoute) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CrossNodeRoute) GetRemoteSubnet() string {
+	if x != nil {
+		return x.RemoteSubnet
+	}
+	return ""
+}
+
+func (x *CrossNodeRoute) GetRemoteNodeIP() string {
+	if x != nil {
+		return x.RemoteNodeIP
+	}
+	return ""
+}
+
+func (x *CrossNodeRoute) GetLocalBridgeIP() string {
+	if x != nil {
+		return x.LocalBridgeIP
+	}
+	return ""
+}
+
+func (x *CrossNodeRoute) GetMode() RouteMode {
+	if x != nil {
+		return x.Mode
+	}
+	return RouteMode_ROUTE_MODE_STATIC
+}
+
+func (x *CrossNodeRoute) GetVni() uint32 {
+	if x != nil {
+		return x.Vni
+	}
+	return 0
+}
+
+func (x *CrossNodeRoute) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *CrossNodeRoute) GetLocalNodeIP() string {
+	if x != nil {
+		return x.LocalNodeIP
+	}
+	return ""
+}
+
+func (x *CrossNodeRoute) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
 type SetupNetworkRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BridgeIP      string                 `protobuf:"bytes,1,opt,name=bridgeIP,proto3" json:"bridgeIP,omitempty"`
@@ -31,7 +177,7 @@ type SetupNetworkRequest struct {
 
 func (x *SetupNetworkRequest) Reset() {
 	*x = SetupNetworkRequest{}
-	mi := &file_proto_network_proto_msgTypes[0]
+	mi := &file_proto_network_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -43,7 +189,7 @@ func (x *SetupNetworkRequest) String() string {
 func (*SetupNetworkRequest) ProtoMessage() {}
 
 func (x *SetupNetworkRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_network_proto_msgTypes[0]
+	mi := &file_proto_network_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -56,7 +202,7 @@ func (x *SetupNetworkRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetupNetworkRequest.ProtoReflect.Descriptor instead.
 func (*SetupNetworkRequest) Descriptor() ([]byte, []int) {
-	return file_proto_network_proto_rawDescGZIP(), []int{0}
+	return file_proto_network_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *SetupNetworkRequest) GetBridgeIP() string {
@@ -81,7 +227,7 @@ type SetupNetworkResponse struct {
 
 func (x *SetupNetworkResponse) Reset() {
 	*x = SetupNetworkResponse{}
-	mi := &file_proto_network_proto_msgTypes[1]
+	mi := &file_proto_network_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -93,7 +239,7 @@ func (x *SetupNetworkResponse) String() string {
 func (*SetupNetworkResponse) ProtoMessage() {}
 
 func (x *SetupNetworkResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_network_proto_msgTypes[1]
+	mi := &file_proto_network_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -106,19 +252,20 @@ func (x *SetupNetworkResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetupNetworkResponse.ProtoReflect.Descriptor instead.
 func (*SetupNetworkResponse) Descriptor() ([]byte, []int) {
-	return file_proto_network_proto_rawDescGZIP(), []int{1}
+	return file_proto_network_proto_rawDescGZIP(), []int{2}
 }
 
 type CleanupNetworkRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	NumVMs        int32                  `protobuf:"varint,1,opt,name=numVMs,proto3" json:"numVMs,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deprecated: ignored. Cleanup removes everything the runner created.
+	NumVMs        int32 `protobuf:"varint,1,opt,name=numVMs,proto3" json:"numVMs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CleanupNetworkRequest) Reset() {
 	*x = CleanupNetworkRequest{}
-	mi := &file_proto_network_proto_msgTypes[2]
+	mi := &file_proto_network_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -130,7 +277,7 @@ func (x *CleanupNetworkRequest) String() string {
 func (*CleanupNetworkRequest) ProtoMessage() {}
 
 func (x *CleanupNetworkRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_network_proto_msgTypes[2]
+	mi := &file_proto_network_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -143,7 +290,7 @@ func (x *CleanupNetworkRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CleanupNetworkRequest.ProtoReflect.Descriptor instead.
 func (*CleanupNetworkRequest) Descriptor() ([]byte, []int) {
-	return file_proto_network_proto_rawDescGZIP(), []int{2}
+	return file_proto_network_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CleanupNetworkRequest) GetNumVMs() int32 {
@@ -161,7 +308,7 @@ type CleanupNetworkResponse struct {
 
 func (x *CleanupNetworkResponse) Reset() {
 	*x = CleanupNetworkResponse{}
-	mi := &file_proto_network_proto_msgTypes[3]
+	mi := &file_proto_network_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -173,7 +320,7 @@ func (x *CleanupNetworkResponse) String() string {
 func (*CleanupNetworkResponse) ProtoMessage() {}
 
 func (x *CleanupNetworkResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_network_proto_msgTypes[3]
+	mi := &file_proto_network_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -186,7 +333,7 @@ func (x *CleanupNetworkResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CleanupNetworkResponse.ProtoReflect.Descriptor instead.
 func (*CleanupNetworkResponse) Descriptor() ([]byte, []int) {
-	return file_proto_network_proto_rawDescGZIP(), []int{3}
+	return file_proto_network_proto_rawDescGZIP(), []int{4}
 }
 
 type SetupCrossNodeRouteRequest struct {
@@ -194,13 +341,17 @@ type SetupCrossNodeRouteRequest struct {
 	RemoteSubnet  string                 `protobuf:"bytes,1,opt,name=remoteSubnet,proto3" json:"remoteSubnet,omitempty"`
 	RemoteNodeIP  string                 `protobuf:"bytes,2,opt,name=remoteNodeIP,proto3" json:"remoteNodeIP,omitempty"`
 	LocalBridgeIP string                 `protobuf:"bytes,3,opt,name=localBridgeIP,proto3" json:"localBridgeIP,omitempty"`
+	Mode          RouteMode              `protobuf:"varint,4,opt,name=mode,proto3,enum=proto.network.v1.RouteMode" json:"mode,omitempty"`
+	Vni           uint32                 `protobuf:"varint,5,opt,name=vni,proto3" json:"vni,omitempty"`                // vxlan mode only
+	Port          int32                  `protobuf:"varint,6,opt,name=port,proto3" json:"port,omitempty"`              // vxlan mode only, default 4789
+	LocalNodeIP   string                 `protobuf:"bytes,7,opt,name=localNodeIP,proto3" json:"localNodeIP,omitempty"` // vxlan mode only, source address of the tunnel
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SetupCrossNodeRouteRequest) Reset() {
 	*x = SetupCrossNodeRouteRequest{}
-	mi := &file_proto_network_proto_msgTypes[4]
+	mi := &file_proto_network_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -212,7 +363,7 @@ func (x *SetupCrossNodeRouteRequest) String() string {
 func (*SetupCrossNodeRouteRequest) ProtoMessage() {}
 
 func (x *SetupCrossNodeRouteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_network_proto_msgTypes[4]
+	mi := &file_proto_network_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -225,7 +376,7 @@ func (x *SetupCrossNodeRouteRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetupCrossNodeRouteRequest.ProtoReflect.Descriptor instead.
 func (*SetupCrossNodeRouteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_network_proto_rawDescGZIP(), []int{4}
+	return file_proto_network_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SetupCrossNodeRouteRequest) GetRemoteSubnet() string {
@@ -249,15 +400,44 @@ func (x *SetupCrossNodeRouteRequest) GetLocalBridgeIP() string {
 	return ""
 }
 
+func (x *SetupCrossNodeRouteRequest) GetMode() RouteMode {
+	if x != nil {
+		return x.Mode
+	}
+	return RouteMode_ROUTE_MODE_STATIC
+}
+
+func (x *SetupCrossNodeRouteRequest) GetVni() uint32 {
+	if x != nil {
+		return x.Vni
+	}
+	return 0
+}
+
+func (x *SetupCrossNodeRouteRequest) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *SetupCrossNodeRouteRequest) GetLocalNodeIP() string {
+	if x != nil {
+		return x.LocalNodeIP
+	}
+	return ""
+}
+
 type SetupCrossNodeRouteResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Route         *CrossNodeRoute        `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SetupCrossNodeRouteResponse) Reset() {
 	*x = SetupCrossNodeRouteResponse{}
-	mi := &file_proto_network_proto_msgTypes[5]
+	mi := &file_proto_network_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -269,7 +449,7 @@ func (x *SetupCrossNodeRouteResponse) String() string {
 func (*SetupCrossNodeRouteResponse) ProtoMessage() {}
 
 func (x *SetupCrossNodeRouteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_network_proto_msgTypes[5]
+	mi := &file_proto_network_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -282,30 +462,762 @@ func (x *SetupCrossNodeRouteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SetupCrossNodeRouteResponse.ProtoReflect.Descriptor instead.
 func (*SetupCrossNodeRouteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_network_proto_rawDescGZIP(), []int{5}
+	return file_proto_network_proto_rawDescGZIP(), []int{6}
 }
 
-var File_proto_network_proto protoreflect.FileDescriptor
+func (x *SetupCrossNodeRouteResponse) GetRoute() *CrossNodeRoute {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
 
-const file_proto_network_proto_rawDesc = "" +
-	"\n" +
-	"\x13proto/network.proto\x12\x10proto.network.v1\"I\n" +
-	"\x13SetupNetworkRequest\x12\x1a\n" +
-	"\bbridgeIP\x18\x01 \x01(\tR\bbridgeIP\x12\x16\n" +
-	"\x06numVMs\x18\x02 \x01(\x05R\x06numVMs\"\x16\n" +
-	"\x14SetupNetworkResponse\"/\n" +
-	"\x15CleanupNetworkRequest\x12\x16\n" +
-	"\x06numVMs\x18\x01 \x01(\x05R\x06numVMs\"\x18\n" +
-	"\x16CleanupNetworkResponse\"\x8a\x01\n" +
-	"\x1aSetupCrossNodeRouteRequest\x12\"\n" +
-	"\fremoteSubnet\x18\x01 \x01(\tR\fremoteSubnet\x12\"\n" +
-	"\fremoteNodeIP\x18\x02 \x01(\tR\fremoteNodeIP\x12$\n" +
-	"\rlocalBridgeIP\x18\x03 \x01(\tR\rlocalBridgeIP\"\x1d\n" +
-	"\x1bSetupCrossNodeRouteResponse2\xc0\x02\n" +
+type TeardownCrossNodeRouteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RemoteSubnet  string                 `protobuf:"bytes,1,opt,name=remoteSubnet,proto3" json:"remoteSubnet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeardownCrossNodeRouteRequest) Reset() {
+	*x = TeardownCrossNodeRouteRequest{}
+	mi := &file_proto_network_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeardownCrossNodeRouteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeardownCrossNodeRouteRequest) ProtoMessage() {}
+
+func (x *TeardownCrossNodeRouteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeardownCrossNodeRouteRequest.ProtoReflect.Descriptor instead.
+func (*TeardownCrossNodeRouteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TeardownCrossNodeRouteRequest) GetRemoteSubnet() string {
+	if x != nil {
+		return x.RemoteSubnet
+	}
+	return ""
+}
+
+type TeardownCrossNodeRouteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeardownCrossNodeRouteResponse) Reset() {
+	*x = TeardownCrossNodeRouteResponse{}
+	mi := &file_proto_network_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeardownCrossNodeRouteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeardownCrossNodeRouteResponse) ProtoMessage() {}
+
+func (x *TeardownCrossNodeRouteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeardownCrossNodeRouteResponse.ProtoReflect.Descriptor instead.
+func (*TeardownCrossNodeRouteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{8}
+}
+
+type ListCrossNodeRoutesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCrossNodeRoutesRequest) Reset() {
+	*x = ListCrossNodeRoutesRequest{}
+	mi := &file_proto_network_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCrossNodeRoutesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCrossNodeRoutesRequest) ProtoMessage() {}
+
+func (x *ListCrossNodeRoutesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCrossNodeRoutesRequest.ProtoReflect.Descriptor instead.
+func (*ListCrossNodeRoutesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{9}
+}
+
+type ListCrossNodeRoutesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Routes        []*CrossNodeRoute      `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCrossNodeRoutesResponse) Reset() {
+	*x = ListCrossNodeRoutesResponse{}
+	mi := &file_proto_network_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCrossNodeRoutesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCrossNodeRoutesResponse) ProtoMessage() {}
+
+func (x *ListCrossNodeRoutesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCrossNodeRoutesResponse.ProtoReflect.Descriptor instead.
+func (*ListCrossNodeRoutesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListCrossNodeRoutesResponse) GetRoutes() []*CrossNodeRoute {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+// Netem emulates a WAN link. Percentages are 0 to 100.
+type Netem struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	DelayMs          uint32                 `protobuf:"varint,1,opt,name=delayMs,proto3" json:"delayMs,omitempty"`
+	JitterMs         uint32                 `protobuf:"varint,2,opt,name=jitterMs,proto3" json:"jitterMs,omitempty"` // requires a delay
+	LossPercent      float32                `protobuf:"fixed32,3,opt,name=lossPercent,proto3" json:"lossPercent,omitempty"`
+	DuplicatePercent float32                `protobuf:"fixed32,4,opt,name=duplicatePercent,proto3" json:"duplicatePercent,omitempty"`
+	ReorderPercent   float32                `protobuf:"fixed32,5,opt,name=reorderPercent,proto3" json:"reorderPercent,omitempty"` // requires a delay
+	CorruptPercent   float32                `protobuf:"fixed32,6,opt,name=corruptPercent,proto3" json:"corruptPercent,omitempty"`
+	Limit            uint32                 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"` // queue length in packets, default 1000
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Netem) Reset() {
+	*x = Netem{}
+	mi := &file_proto_network_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Netem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Netem) ProtoMessage() {}
+
+func (x *Netem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Netem.ProtoReflect.Descriptor instead.
+func (*Netem) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Netem) GetDelayMs() uint32 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
+func (x *Netem) GetJitterMs() uint32 {
+	if x != nil {
+		return x.JitterMs
+	}
+	return 0
+}
+
+func (x *Netem) GetLossPercent() float32 {
+	if x != nil {
+		return x.LossPercent
+	}
+	return 0
+}
+
+func (x *Netem) GetDuplicatePercent() float32 {
+	if x != nil {
+		return x.DuplicatePercent
+	}
+	return 0
+}
+
+func (x *Netem) GetReorderPercent() float32 {
+	if x != nil {
+		return x.ReorderPercent
+	}
+	return 0
+}
+
+func (x *Netem) GetCorruptPercent() float32 {
+	if x != nil {
+		return x.CorruptPercent
+	}
+	return 0
+}
+
+func (x *Netem) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Tbf caps the bandwidth with a token bucket filter.
+type Tbf struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RateKbit      uint64                 `protobuf:"varint,1,opt,name=rateKbit,proto3" json:"rateKbit,omitempty"`
+	BurstBytes    uint32                 `protobuf:"varint,2,opt,name=burstBytes,proto3" json:"burstBytes,omitempty"` // default 32KiB
+	LatencyMs     uint32                 `protobuf:"varint,3,opt,name=latencyMs,proto3" json:"latencyMs,omitempty"`   // max queueing delay, default 50ms
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tbf) Reset() {
+	*x = Tbf{}
+	mi := &file_proto_network_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tbf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tbf) ProtoMessage() {}
+
+func (x *Tbf) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tbf.ProtoReflect.Descriptor instead.
+func (*Tbf) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Tbf) GetRateKbit() uint64 {
+	if x != nil {
+		return x.RateKbit
+	}
+	return 0
+}
+
+func (x *Tbf) GetBurstBytes() uint32 {
+	if x != nil {
+		return x.BurstBytes
+	}
+	return 0
+}
+
+func (x *Tbf) GetLatencyMs() uint32 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+// TrafficShape applies to the egress of the VM's tap, i.e. to traffic towards
+// the guest. Use the VM's networkTxRateLimiter to cap what the guest sends.
+type TrafficShape struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	TapName       string                 `protobuf:"bytes,2,opt,name=tapName,proto3" json:"tapName,omitempty"`
+	Netem         *Netem                 `protobuf:"bytes,3,opt,name=netem,proto3" json:"netem,omitempty"`
+	Tbf           *Tbf                   `protobuf:"bytes,4,opt,name=tbf,proto3" json:"tbf,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrafficShape) Reset() {
+	*x = TrafficShape{}
+	mi := &file_proto_network_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrafficShape) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrafficShape) ProtoMessage() {}
+
+func (x *TrafficShape) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrafficShape.ProtoReflect.Descriptor instead.
+func (*TrafficShape) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TrafficShape) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *TrafficShape) GetTapName() string {
+	if x != nil {
+		return x.TapName
+	}
+	return ""
+}
+
+func (x *TrafficShape) GetNetem() *Netem {
+	if x != nil {
+		return x.Netem
+	}
+	return nil
+}
+
+func (x *TrafficShape) GetTbf() *Tbf {
+	if x != nil {
+		return x.Tbf
+	}
+	return nil
+}
+
+type ShapeTrafficRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`           // the VM to shape
+	TapName       string                 `protobuf:"bytes,2,opt,name=tapName,proto3" json:"tapName,omitempty"` // used if ip is empty
+	Netem         *Netem                 `protobuf:"bytes,3,opt,name=netem,proto3" json:"netem,omitempty"`
+	Tbf           *Tbf                   `protobuf:"bytes,4,opt,name=tbf,proto3" json:"tbf,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShapeTrafficRequest) Reset() {
+	*x = ShapeTrafficRequest{}
+	mi := &file_proto_network_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShapeTrafficRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShapeTrafficRequest) ProtoMessage() {}
+
+func (x *ShapeTrafficRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShapeTrafficRequest.ProtoReflect.Descriptor instead.
+func (*ShapeTrafficRequest) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ShapeTrafficRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *ShapeTrafficRequest) GetTapName() string {
+	if x != nil {
+		return x.TapName
+	}
+	return ""
+}
+
+func (x *ShapeTrafficRequest) GetNetem() *Netem {
+	if x != nil {
+		return x.Netem
+	}
+	return nil
+}
+
+func (x *ShapeTrafficRequest) GetTbf() *Tbf {
+	if x != nil {
+		return x.Tbf
+	}
+	return nil
+}
+
+type ShapeTrafficResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shape         *TrafficShape          `protobuf:"bytes,1,opt,name=shape,proto3" json:"shape,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShapeTrafficResponse) Reset() {
+	*x = ShapeTrafficResponse{}
+	mi := &file_proto_network_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShapeTrafficResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShapeTrafficResponse) ProtoMessage() {}
+
+func (x *ShapeTrafficResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShapeTrafficResponse.ProtoReflect.Descriptor instead.
+func (*ShapeTrafficResponse) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ShapeTrafficResponse) GetShape() *TrafficShape {
+	if x != nil {
+		return x.Shape
+	}
+	return nil
+}
+
+type ClearTrafficShapingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	TapName       string                 `protobuf:"bytes,2,opt,name=tapName,proto3" json:"tapName,omitempty"` // used if ip is empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearTrafficShapingRequest) Reset() {
+	*x = ClearTrafficShapingRequest{}
+	mi := &file_proto_network_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearTrafficShapingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearTrafficShapingRequest) ProtoMessage() {}
+
+func (x *ClearTrafficShapingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearTrafficShapingRequest.ProtoReflect.Descriptor instead.
+func (*ClearTrafficShapingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ClearTrafficShapingRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *ClearTrafficShapingRequest) GetTapName() string {
+	if x != nil {
+		return x.TapName
+	}
+	return ""
+}
+
+type ClearTrafficShapingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearTrafficShapingResponse) Reset() {
+	*x = ClearTrafficShapingResponse{}
+	mi := &file_proto_network_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearTrafficShapingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearTrafficShapingResponse) ProtoMessage() {}
+
+func (x *ClearTrafficShapingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearTrafficShapingResponse.ProtoReflect.Descriptor instead.
+func (*ClearTrafficShapingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{17}
+}
+
+type ListTrafficShapingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrafficShapingRequest) Reset() {
+	*x = ListTrafficShapingRequest{}
+	mi := &file_proto_network_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrafficShapingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrafficShapingRequest) ProtoMessage() {}
+
+func (x *ListTrafficShapingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrafficShapingRequest.ProtoReflect.Descriptor instead.
+func (*ListTrafficShapingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{18}
+}
+
+type ListTrafficShapingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shapes        []*TrafficShape        `protobuf:"bytes,1,rep,name=shapes,proto3" json:"shapes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrafficShapingResponse) Reset() {
+	*x = ListTrafficShapingResponse{}
+	mi := &file_proto_network_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrafficShapingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrafficShapingResponse) ProtoMessage() {}
+
+func (x *ListTrafficShapingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_network_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrafficShapingResponse.ProtoReflect.Descriptor instead.
+func (*ListTrafficShapingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_network_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListTrafficShapingResponse) GetShapes() []*TrafficShape {
+	if x != nil {
+		return x.Shapes
+	}
+	return nil
+}
+
+var File_proto_network_proto protoreflect.FileDescriptor
+
+const file_proto_network_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/network.proto\x12\x10proto.network.v1\"\x8f\x02\n" +
+	"\x0eCrossNodeRoute\x12\"\n" +
+	"\fremoteSubnet\x18\x01 \x01(\tR\fremoteSubnet\x12\"\n" +
+	"\fremoteNodeIP\x18\x02 \x01(\tR\fremoteNodeIP\x12$\n" +
+	"\rlocalBridgeIP\x18\x03 \x01(\tR\rlocalBridgeIP\x12/\n" +
+	"\x04mode\x18\x04 \x01(\x0e2\x1b.proto.network.v1.RouteModeR\x04mode\x12\x10\n" +
+	"\x03vni\x18\x05 \x01(\rR\x03vni\x12\x12\n" +
+	"\x04port\x18\x06 \x01(\x05R\x04port\x12 \n" +
+	"\vlocalNodeIP\x18\a \x01(\tR\vlocalNodeIP\x12\x16\n" +
+	"\x06device\x18\b \x01(\tR\x06device\"I\n" +
+	"\x13SetupNetworkRequest\x12\x1a\n" +
+	"\bbridgeIP\x18\x01 \x01(\tR\bbridgeIP\x12\x16\n" +
+	"\x06numVMs\x18\x02 \x01(\x05R\x06numVMs\"\x16\n" +
+	"\x14SetupNetworkResponse\"/\n" +
+	"\x15CleanupNetworkRequest\x12\x16\n" +
+	"\x06numVMs\x18\x01 \x01(\x05R\x06numVMs\"\x18\n" +
+	"\x16CleanupNetworkResponse\"\x83\x02\n" +
+	"\x1aSetupCrossNodeRouteRequest\x12\"\n" +
+	"\fremoteSubnet\x18\x01 \x01(\tR\fremoteSubnet\x12\"\n" +
+	"\fremoteNodeIP\x18\x02 \x01(\tR\fremoteNodeIP\x12$\n" +
+	"\rlocalBridgeIP\x18\x03 \x01(\tR\rlocalBridgeIP\x12/\n" +
+	"\x04mode\x18\x04 \x01(\x0e2\x1b.proto.network.v1.RouteModeR\x04mode\x12\x10\n" +
+	"\x03vni\x18\x05 \x01(\rR\x03vni\x12\x12\n" +
+	"\x04port\x18\x06 \x01(\x05R\x04port\x12 \n" +
+	"\vlocalNodeIP\x18\a \x01(\tR\vlocalNodeIP\"U\n" +
+	"\x1bSetupCrossNodeRouteResponse\x126\n" +
+	"\x05route\x18\x01 \x01(\v2 .proto.network.v1.CrossNodeRouteR\x05route\"C\n" +
+	"\x1dTeardownCrossNodeRouteRequest\x12\"\n" +
+	"\fremoteSubnet\x18\x01 \x01(\tR\fremoteSubnet\" \n" +
+	"\x1eTeardownCrossNodeRouteResponse\"\x1c\n" +
+	"\x1aListCrossNodeRoutesRequest\"W\n" +
+	"\x1bListCrossNodeRoutesResponse\x128\n" +
+	"\x06routes\x18\x01 \x03(\v2 .proto.network.v1.CrossNodeRouteR\x06routes\"\xf1\x01\n" +
+	"\x05Netem\x12\x18\n" +
+	"\adelayMs\x18\x01 \x01(\rR\adelayMs\x12\x1a\n" +
+	"\bjitterMs\x18\x02 \x01(\rR\bjitterMs\x12 \n" +
+	"\vlossPercent\x18\x03 \x01(\x02R\vlossPercent\x12*\n" +
+	"\x10duplicatePercent\x18\x04 \x01(\x02R\x10duplicatePercent\x12&\n" +
+	"\x0ereorderPercent\x18\x05 \x01(\x02R\x0ereorderPercent\x12&\n" +
+	"\x0ecorruptPercent\x18\x06 \x01(\x02R\x0ecorruptPercent\x12\x14\n" +
+	"\x05limit\x18\a \x01(\rR\x05limit\"_\n" +
+	"\x03Tbf\x12\x1a\n" +
+	"\brateKbit\x18\x01 \x01(\x04R\brateKbit\x12\x1e\n" +
+	"\n" +
+	"burstBytes\x18\x02 \x01(\rR\n" +
+	"burstBytes\x12\x1c\n" +
+	"\tlatencyMs\x18\x03 \x01(\rR\tlatencyMs\"\x90\x01\n" +
+	"\fTrafficShape\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x18\n" +
+	"\atapName\x18\x02 \x01(\tR\atapName\x12-\n" +
+	"\x05netem\x18\x03 \x01(\v2\x17.proto.network.v1.NetemR\x05netem\x12'\n" +
+	"\x03tbf\x18\x04 \x01(\v2\x15.proto.network.v1.TbfR\x03tbf\"\x97\x01\n" +
+	"\x13ShapeTrafficRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x18\n" +
+	"\atapName\x18\x02 \x01(\tR\atapName\x12-\n" +
+	"\x05netem\x18\x03 \x01(\v2\x17.proto.network.v1.NetemR\x05netem\x12'\n" +
+	"\x03tbf\x18\x04 \x01(\v2\x15.proto.network.v1.TbfR\x03tbf\"L\n" +
+	"\x14ShapeTrafficResponse\x124\n" +
+	"\x05shape\x18\x01 \x01(\v2\x1e.proto.network.v1.TrafficShapeR\x05shape\"F\n" +
+	"\x1aClearTrafficShapingRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x18\n" +
+	"\atapName\x18\x02 \x01(\tR\atapName\"\x1d\n" +
+	"\x1bClearTrafficShapingResponse\"\x1b\n" +
+	"\x19ListTrafficShapingRequest\"T\n" +
+	"\x1aListTrafficShapingResponse\x126\n" +
+	"\x06shapes\x18\x01 \x03(\v2\x1e.proto.network.v1.TrafficShapeR\x06shapes*8\n" +
+	"\tRouteMode\x12\x15\n" +
+	"\x11ROUTE_MODE_STATIC\x10\x00\x12\x14\n" +
+	"\x10ROUTE_MODE_VXLAN\x10\x012\xff\x06\n" +
 	"\x0eNetworkService\x12X\n" +
 	"\x05Setup\x12%.proto.network.v1.SetupNetworkRequest\x1a&.proto.network.v1.SetupNetworkResponse\"\x00\x12^\n" +
 	"\aCleanup\x12'.proto.network.v1.CleanupNetworkRequest\x1a(.proto.network.v1.CleanupNetworkResponse\"\x00\x12t\n" +
-	"\x13SetupCrossNodeRoute\x12,.proto.network.v1.SetupCrossNodeRouteRequest\x1a-.proto.network.v1.SetupCrossNodeRouteResponse\"\x00B\x12Z\x10proto/network/v1b\x06proto3"
+	"\x13SetupCrossNodeRoute\x12,.proto.network.v1.SetupCrossNodeRouteRequest\x1a-.proto.network.v1.SetupCrossNodeRouteResponse\"\x00\x12}\n" +
+	"\x16TeardownCrossNodeRoute\x12/.proto.network.v1.TeardownCrossNodeRouteRequest\x1a0.proto.network.v1.TeardownCrossNodeRouteResponse\"\x00\x12t\n" +
+	"\x13ListCrossNodeRoutes\x12,.proto.network.v1.ListCrossNodeRoutesRequest\x1a-.proto.network.v1.ListCrossNodeRoutesResponse\"\x00\x12_\n" +
+	"\fShapeTraffic\x12%.proto.network.v1.ShapeTrafficRequest\x1a&.proto.network.v1.ShapeTrafficResponse\"\x00\x12t\n" +
+	"\x13ClearTrafficShaping\x12,.proto.network.v1.ClearTrafficShapingRequest\x1a-.proto.network.v1.ClearTrafficShapingResponse\"\x00\x12q\n" +
+	"\x12ListTrafficShaping\x12+.proto.network.v1.ListTrafficShapingRequest\x1a,.proto.network.v1.ListTrafficShapingResponse\"\x00B\x12Z\x10proto/network/v1b\x06proto3"
 
 var (
 	file_proto_network_proto_rawDescOnce sync.Once
@@ -319,27 +1231,63 @@ func file_proto_network_proto_rawDescGZIP() []byte {
 	return file_proto_network_proto_rawDescData
 }
 
-var file_proto_network_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_network_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_network_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_proto_network_proto_goTypes = []any{
-	(*SetupNetworkRequest)(nil),         // 0: proto.network.v1.SetupNetworkRequest
-	(*SetupNetworkResponse)(nil),        // 1: proto.network.v1.SetupNetworkResponse
-	(*CleanupNetworkRequest)(nil),       // 2: proto.network.v1.CleanupNetworkRequest
-	(*CleanupNetworkResponse)(nil),      // 3: proto.network.v1.CleanupNetworkResponse
-	(*SetupCrossNodeRouteRequest)(nil),  // 4: proto.network.v1.SetupCrossNodeRouteRequest
-	(*SetupCrossNodeRouteResponse)(nil), // 5: proto.network.v1.SetupCrossNodeRouteResponse
+	(RouteMode)(0),                         // 0: proto.network.v1.RouteMode
+	(*CrossNodeRoute)(nil),                 // 1: proto.network.v1.CrossNodeRoute
+	(*SetupNetworkRequest)(nil),            // 2: proto.network.v1.SetupNetworkRequest
+	(*SetupNetworkResponse)(nil),           // 3: proto.network.v1.SetupNetworkResponse
+	(*CleanupNetworkRequest)(nil),          // 4: proto.network.v1.CleanupNetworkRequest
+	(*CleanupNetworkResponse)(nil),         // 5: proto.network.v1.CleanupNetworkResponse
+	(*SetupCrossNodeRouteRequest)(nil),     // 6: proto.network.v1.SetupCrossNodeRouteRequest
+	(*SetupCrossNodeRouteResponse)(nil),    // 7: proto.network.v1.SetupCrossNodeRouteResponse
+	(*TeardownCrossNodeRouteRequest)(nil),  // 8: proto.network.v1.TeardownCrossNodeRouteRequest
+	(*TeardownCrossNodeRouteResponse)(nil), // 9: proto.network.v1.TeardownCrossNodeRouteResponse
+	(*ListCrossNodeRoutesRequest)(nil),     // 10: proto.network.v1.ListCrossNodeRoutesRequest
+	(*ListCrossNodeRoutesResponse)(nil),    // 11: proto.network.v1.ListCrossNodeRoutesResponse
+	(*Netem)(nil),                          // 12: proto.network.v1.Netem
+	(*Tbf)(nil),                            // 13: proto.network.v1.Tbf
+	(*TrafficShape)(nil),                   // 14: proto.network.v1.TrafficShape
+	(*ShapeTrafficRequest)(nil),            // 15: proto.network.v1.ShapeTrafficRequest
+	(*ShapeTrafficResponse)(nil),           // 16: proto.network.v1.ShapeTrafficResponse
+	(*ClearTrafficShapingRequest)(nil),     // 17: proto.network.v1.ClearTrafficShapingRequest
+	(*ClearTrafficShapingResponse)(nil),    // 18: proto.network.v1.ClearTrafficShapingResponse
+	(*ListTrafficShapingRequest)(nil),      // 19: proto.network.v1.ListTrafficShapingRequest
+	(*ListTrafficShapingResponse)(nil),     // 20: proto.network.v1.ListTrafficShapingResponse
 }
 var file_proto_network_proto_depIdxs = []int32{
-	0, // 0: proto.network.v1.NetworkService.Setup:input_type -> proto.network.v1.SetupNetworkRequest
-	2, // 1: proto.network.v1.NetworkService.Cleanup:input_type -> proto.network.v1.CleanupNetworkRequest
-	4, // 2: proto.network.v1.NetworkService.SetupCrossNodeRoute:input_type -> proto.network.v1.SetupCrossNodeRouteRequest
-	1, // 3: proto.network.v1.NetworkService.Setup:output_type -> proto.network.v1.SetupNetworkResponse
-	3, // 4: proto.network.v1.NetworkService.Cleanup:output_type -> proto.network.v1.CleanupNetworkResponse
-	5, // 5: proto.network.v1.NetworkService.SetupCrossNodeRoute:output_type -> proto.network.v1.SetupCrossNodeRouteResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: proto.network.v1.CrossNodeRoute.mode:type_name -> proto.network.v1.RouteMode
+	0,  // 1: proto.network.v1.SetupCrossNodeRouteRequest.mode:type_name -> proto.network.v1.RouteMode
+	1,  // 2: proto.network.v1.SetupCrossNodeRouteResponse.route:type_name -> proto.network.v1.CrossNodeRoute
+	1,  // 3: proto.network.v1.ListCrossNodeRoutesResponse.routes:type_name -> proto.network.v1.CrossNodeRoute
+	12, // 4: proto.network.v1.TrafficShape.netem:type_name -> proto.network.v1.Netem
+	13, // 5: proto.network.v1.TrafficShape.tbf:type_name -> proto.network.v1.Tbf
+	12, // 6: proto.network.v1.ShapeTrafficRequest.netem:type_name -> proto.network.v1.Netem
+	13, // 7: proto.network.v1.ShapeTrafficRequest.tbf:type_name -> proto.network.v1.Tbf
+	14, // 8: proto.network.v1.ShapeTrafficResponse.shape:type_name -> proto.network.v1.TrafficShape
+	14, // 9: proto.network.v1.ListTrafficShapingResponse.shapes:type_name -> proto.network.v1.TrafficShape
+	2,  // 10: proto.network.v1.NetworkService.Setup:input_type -> proto.network.v1.SetupNetworkRequest
+	4,  // 11: proto.network.v1.NetworkService.Cleanup:input_type -> proto.network.v1.CleanupNetworkRequest
+	6,  // 12: proto.network.v1.NetworkService.SetupCrossNodeRoute:input_type -> proto.network.v1.SetupCrossNodeRouteRequest
+	8,  // 13: proto.network.v1.NetworkService.TeardownCrossNodeRoute:input_type -> proto.network.v1.TeardownCrossNodeRouteRequest
+	10, // 14: proto.network.v1.NetworkService.ListCrossNodeRoutes:input_type -> proto.network.v1.ListCrossNodeRoutesRequest
+	15, // 15: proto.network.v1.NetworkService.ShapeTraffic:input_type -> proto.network.v1.ShapeTrafficRequest
+	17, // 16: proto.network.v1.NetworkService.ClearTrafficShaping:input_type -> proto.network.v1.ClearTrafficShapingRequest
+	19, // 17: proto.network.v1.NetworkService.ListTrafficShaping:input_type -> proto.network.v1.ListTrafficShapingRequest
+	3,  // 18: proto.network.v1.NetworkService.Setup:output_type -> proto.network.v1.SetupNetworkResponse
+	5,  // 19: proto.network.v1.NetworkService.Cleanup:output_type -> proto.network.v1.CleanupNetworkResponse
+	7,  // 20: proto.network.v1.NetworkService.SetupCrossNodeRoute:output_type -> proto.network.v1.SetupCrossNodeRouteResponse
+	9,  // 21: proto.network.v1.NetworkService.TeardownCrossNodeRoute:output_type -> proto.network.v1.TeardownCrossNodeRouteResponse
+	11, // 22: proto.network.v1.NetworkService.ListCrossNodeRoutes:output_type -> proto.network.v1.ListCrossNodeRoutesResponse
+	16, // 23: proto.network.v1.NetworkService.ShapeTraffic:output_type -> proto.network.v1.ShapeTrafficResponse
+	18, // 24: proto.network.v1.NetworkService.ClearTrafficShaping:output_type -> proto.network.v1.ClearTrafficShapingResponse
+	20, // 25: proto.network.v1.NetworkService.ListTrafficShaping:output_type -> proto.network.v1.ListTrafficShapingResponse
+	18, // [18:26] is the sub-list for method output_type
+	10, // [10:18] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_proto_network_proto_init() }
@@ -352,13 +1300,14 @@ func file_proto_network_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_network_proto_rawDesc), len(file_proto_network_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      1,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_network_proto_goTypes,
 		DependencyIndexes: file_proto_network_proto_depIdxs,
+		EnumInfos:         file_proto_network_proto_enumTypes,
 		MessageInfos:      file_proto_network_proto_msgTypes,
 	}.Build()
 	File_proto_network_proto = out.File
@@ -19,9 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	NetworkService_Setup_FullMethodName               = "/proto.network.v1.NetworkService/Setup"
-	NetworkService_Cleanup_FullMethodName             = "/proto.network.v1.NetworkService/Cleanup"
-	NetworkService_SetupCrossNodeRoute_FullMethodName = "/proto.network.v1.NetworkService/SetupCrossNodeRoute"
+	NetworkService_Setup_FullMethodName                  = "/proto.network.v1.NetworkService/Setup"
+	NetworkService_Cleanup_FullMethodName                = "/proto.network.v1.NetworkService/Cleanup"
+	NetworkService_SetupCrossNodeRoute_FullMethodName    = "/proto.network.v1.NetworkService/SetupCrossNodeRoute"
+	NetworkService_TeardownCrossNodeRoute_FullMethodName = "/proto.network.v1.NetworkService/TeardownCrossNodeRoute"
+	NetworkService_ListCrossNodeRoutes_FullMethodName    = "/proto.network.v1.NetworkService/ListCrossNodeRoutes"
+	NetworkService_ShapeTraffic_FullMethodName           = "/proto.network.v1.NetworkService/ShapeTraffic"
+	NetworkService_ClearTrafficShaping_FullMethodName    = "/proto.network.v1.NetworkService/ClearTrafficShaping"
+	NetworkService_ListTrafficShaping_FullMethodName     = "/proto.network.v1.NetworkService/ListTrafficShaping"
 )
 
 // NetworkServiceClient is the client API for NetworkService service.
@@ -31,6 +36,11 @@ type NetworkServiceClient interface {
 	Setup(ctx context.Context, in *SetupNetworkRequest, opts ...grpc.CallOption) (*SetupNetworkResponse, error)
 	Cleanup(ctx context.Context, in *CleanupNetworkRequest, opts ...grpc.CallOption) (*CleanupNetworkResponse, error)
 	SetupCrossNodeRoute(ctx context.Context, in *SetupCrossNodeRouteRequest, opts ...grpc.CallOption) (*SetupCrossNodeRouteResponse, error)
+	TeardownCrossNodeRoute(ctx context.Context, in *TeardownCrossNodeRouteRequest, opts ...grpc.CallOption) (*TeardownCrossNodeRouteResponse, error)
+	ListCrossNodeRoutes(ctx context.Context, in *ListCrossNodeRoutesRequest, opts ...grpc.CallOption) (*ListCrossNodeRoutesResponse, error)
+	ShapeTraffic(ctx context.Context, in *ShapeTrafficRequest, opts ...grpc.CallOption) (*ShapeTrafficResponse, error)
+	ClearTrafficShaping(ctx context.Context, in *ClearTrafficShapingRequest, opts ...grpc.CallOption) (*ClearTrafficShapingResponse, error)
+	ListTrafficShaping(ctx context.Context, in *ListTrafficShapingRequest, opts ...grpc.CallOption) (*ListTrafficShapingResponse, error)
 }
 
 type networkServiceClient struct {
@@ -71,6 +81,56 @@ func (c *networkServiceClient) SetupCrossNodeRoute(ctx context.Context, in *Setu
 	return out, nil
 }
 
+func (c *networkServiceClient) TeardownCrossNodeRoute(ctx context.Context, in *TeardownCrossNodeRouteRequest, opts ...grpc.CallOption) (*TeardownCrossNodeRouteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TeardownCrossNodeRouteResponse)
+	err := c.cc.Invoke(ctx, NetworkService_TeardownCrossNodeRoute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkServiceClient) ListCrossNodeRoutes(ctx context.Context, in *ListCrossNodeRoutesRequest, opts ...grpc.CallOption) (*ListCrossNodeRoutesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCrossNodeRoutesResponse)
+	err := c.cc.Invoke(ctx, NetworkService_ListCrossNodeRoutes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkServiceClient) ShapeTraffic(ctx context.Context, in *ShapeTrafficRequest, opts ...grpc.CallOption) (*ShapeTrafficResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShapeTrafficResponse)
+	err := c.cc.Invoke(ctx, NetworkService_ShapeTraffic_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkServiceClient) ClearTrafficShaping(ctx context.Context, in *ClearTrafficShapingRequest, opts ...grpc.CallOption) (*ClearTrafficShapingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearTrafficShapingResponse)
+	err := c.cc.Invoke(ctx, NetworkService_ClearTrafficShaping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkServiceClient) ListTrafficShaping(ctx context.Context, in *ListTrafficShapingRequest, opts ...grpc.CallOption) (*ListTrafficShapingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrafficShapingResponse)
+	err := c.cc.Invoke(ctx, NetworkService_ListTrafficShaping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NetworkServiceServer is the server API for NetworkService service.
 // All implementations must embed UnimplementedNetworkServiceServer
 // for forward compatibility.
@@ -78,6 +138,11 @@ type NetworkServiceServer interface {
 	Setup(context.Context, *SetupNetworkRequest) (*SetupNetworkResponse, error)
 	Cleanup(context.Context, *CleanupNetworkRequest) (*CleanupNetworkResponse, error)
 	SetupCrossNodeRoute(context.Context, *SetupCrossNodeRouteRequest) (*SetupCrossNodeRouteResponse, error)
+	TeardownCrossNodeRoute(context.Context, *TeardownCrossNodeRouteRequest) (*TeardownCrossNodeRouteResponse, error)
+	ListCrossNodeRoutes(context.Context, *ListCrossNodeRoutesRequest) (*ListCrossNodeRoutesResponse, error)
+	ShapeTraffic(context.Context, *ShapeTrafficRequest) (*ShapeTrafficResponse, error)
+	ClearTrafficShaping(context.Context, *ClearTrafficShapingRequest) (*ClearTrafficShapingResponse, error)
+	ListTrafficShaping(context.Context, *ListTrafficShapingRequest) (*ListTrafficShapingResponse, error)
 	mustEmbedUnimplementedNetworkServiceServer()
 }
 
@@ -97,6 +162,21 @@ func (UnimplementedNetworkServiceServer) Cleanup(context.Context, *CleanupNetwor
 func (UnimplementedNetworkServiceServer) SetupCrossNodeRoute(context.Context, *SetupCrossNodeRouteRequest) (*SetupCrossNodeRouteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetupCrossNodeRoute not implemented")
 }
+func (UnimplementedNetworkServiceServer) TeardownCrossNodeRoute(context.Context, *TeardownCrossNodeRouteRequest) (*TeardownCrossNodeRouteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TeardownCrossNodeRoute not implemented")
+}
+func (UnimplementedNetworkServiceServer) ListCrossNodeRoutes(context.Context, *ListCrossNodeRoutesRequest) (*ListCrossNodeRoutesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCrossNodeRoutes not implemented")
+}
+func (UnimplementedNetworkServiceServer) ShapeTraffic(context.Context, *ShapeTrafficRequest) (*ShapeTrafficResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShapeTraffic not implemented")
+}
+func (UnimplementedNetworkServiceServer) ClearTrafficShaping(context.Context, *ClearTrafficShapingRequest) (*ClearTrafficShapingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearTrafficShaping not implemented")
+}
+func (UnimplementedNetworkServiceServer) ListTrafficShaping(context.Context, *ListTrafficShapingRequest) (*ListTrafficShapingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrafficShaping not implemented")
+}
 func (UnimplementedNetworkServiceServer) mustEmbedUnimplementedNetworkServiceServer() {}
 func (UnimplementedNetworkServiceServer) testEmbeddedByValue()                        {}
 
@@ -172,6 +252,96 @@ func _NetworkService_SetupCrossNodeRoute_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NetworkService_TeardownCrossNodeRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TeardownCrossNodeRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).TeardownCrossNodeRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetworkService_TeardownCrossNodeRoute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).TeardownCrossNodeRoute(ctx, req.(*TeardownCrossNodeRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetworkService_ListCrossNodeRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCrossNodeRoutesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).ListCrossNodeRoutes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetworkService_ListCrossNodeRoutes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).ListCrossNodeRoutes(ctx, req.(*ListCrossNodeRoutesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetworkService_ShapeTraffic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShapeTrafficRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).ShapeTraffic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetworkService_ShapeTraffic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).ShapeTraffic(ctx, req.(*ShapeTrafficRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetworkService_ClearTrafficShaping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearTrafficShapingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).ClearTrafficShaping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetworkService_ClearTrafficShaping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).ClearTrafficShaping(ctx, req.(*ClearTrafficShapingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetworkService_ListTrafficShaping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrafficShapingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).ListTrafficShaping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NetworkService_ListTrafficShaping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).ListTrafficShaping(ctx, req.(*ListTrafficShapingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NetworkService_ServiceDesc is the grpc.ServiceDesc for NetworkService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,6 +361,26 @@ var NetworkService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetupCrossNodeRoute",
 			Handler:    _NetworkService_SetupCrossNodeRoute_Handler,
 		},
+		{
+			MethodName: "TeardownCrossNodeRoute",
+			Handler:    _NetworkService_TeardownCrossNodeRoute_Handler,
+		},
+		{
+			MethodName: "ListCrossNodeRoutes",
+			Handler:    _NetworkService_ListCrossNodeRoutes_Handler,
+		},
+		{
+			MethodName: "ShapeTraffic",
+			Handler:    _NetworkService_ShapeTraffic_Handler,
+		},
+		{
+			MethodName: "ClearTrafficShaping",
+			Handler:    _NetworkService_ClearTrafficShaping_Handler,
+		},
+		{
+			MethodName: "ListTrafficShaping",
+			Handler:    _NetworkService_ListTrafficShaping_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/network.proto",
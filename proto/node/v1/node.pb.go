@@ -21,9 +21,114 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type OutputStream int32
+
+const (
+	OutputStream_OUTPUT_STREAM_UNSPECIFIED OutputStream = 0
+	OutputStream_OUTPUT_STREAM_STDOUT      OutputStream = 1
+	OutputStream_OUTPUT_STREAM_STDERR      OutputStream = 2
+)
+
+// Enum value maps for OutputStream.
+var (
+	OutputStream_name = map[int32]string{
+		0: "OUTPUT_STREAM_UNSPECIFIED",
+		1: "OUTPUT_STREAM_STDOUT",
+		2: "OUTPUT_STREAM_STDERR",
+	}
+	OutputStream_value = map[string]int32{
+		"OUTPUT_STREAM_UNSPECIFIED": 0,
+		"OUTPUT_STREAM_STDOUT":      1,
+		"OUTPUT_STREAM_STDERR":      2,
+	}
+)
+
+func (x OutputStream) Enum() *OutputStream {
+	p := new(OutputStream)
+	*p = x
+	return p
+}
+
+func (x OutputStream) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputStream) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_node_proto_enumTypes[0].Descriptor()
+}
+
+func (OutputStream) Type() protoreflect.EnumType {
+	return &file_proto_node_proto_enumTypes[0]
+}
+
+func (x OutputStream) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputStream.Descriptor instead.
+func (OutputStream) EnumDescriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{0}
+}
+
+type JobState int32
+
+const (
+	JobState_JOB_STATE_UNSPECIFIED JobState = 0
+	JobState_JOB_STATE_RUNNING     JobState = 1
+	JobState_JOB_STATE_EXITED      JobState = 2 // on its own, whatever its exit code
+	JobState_JOB_STATE_CANCELLED   JobState = 3 // by CancelJob, Cleanup or StopSyscalls without a session
+	JobState_JOB_STATE_TIMED_OUT   JobState = 4
+)
+
+// Enum value maps for JobState.
+var (
+	JobState_name = map[int32]string{
+		0: "JOB_STATE_UNSPECIFIED",
+		1: "JOB_STATE_RUNNING",
+		2: "JOB_STATE_EXITED",
+		3: "JOB_STATE_CANCELLED",
+		4: "JOB_STATE_TIMED_OUT",
+	}
+	JobState_value = map[string]int32{
+		"JOB_STATE_UNSPECIFIED": 0,
+		"JOB_STATE_RUNNING":     1,
+		"JOB_STATE_EXITED":      2,
+		"JOB_STATE_CANCELLED":   3,
+		"JOB_STATE_TIMED_OUT":   4,
+	}
+)
+
+func (x JobState) Enum() *JobState {
+	p := new(JobState)
+	*p = x
+	return p
+}
+
+func (x JobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_node_proto_enumTypes[1].Descriptor()
+}
+
+func (JobState) Type() protoreflect.EnumType {
+	return &file_proto_node_proto_enumTypes[1]
+}
+
+func (x JobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobState.Descriptor instead.
+func (JobState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{1}
+}
+
 type SendServerCommandNodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Command       string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	TimeoutMs     int64                  `protobuf:"varint,2,opt,name=timeoutMs,proto3" json:"timeoutMs,omitempty"` // optional, the command is killed after it
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,9 +170,19 @@ func (x *SendServerCommandNodeRequest) GetCommand() string {
 	return ""
 }
 
+func (x *SendServerCommandNodeRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
 type SendServerCommandNodeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Output        string                 `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	Pid           int64                  `protobuf:"varint,2,opt,name=pid,proto3" json:"pid,omitempty"`
+	SessionId     string                 `protobuf:"bytes,3,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // the tracing session of the command's syscalls
+	JobId         string                 `protobuf:"bytes,4,opt,name=jobId,proto3" json:"jobId,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -109,9 +224,31 @@ func (x *SendServerCommandNodeResponse) GetOutput() string {
 	return ""
 }
 
+func (x *SendServerCommandNodeResponse) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *SendServerCommandNodeResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SendServerCommandNodeResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
 type SendClientCommandNodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Command       string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	TimeoutMs     int64                  `protobuf:"varint,2,opt,name=timeoutMs,proto3" json:"timeoutMs,omitempty"` // optional, the command is killed after it
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -153,9 +290,22 @@ func (x *SendClientCommandNodeRequest) GetCommand() string {
 	return ""
 }
 
+func (x *SendClientCommandNodeRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+// Every message but the last is a line of output; the last one only carries
+// the exit.
 type SendClientCommandNodeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Output        string                 `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	Output        string                 `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`       // without the newline
+	SessionId     string                 `protobuf:"bytes,2,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // the tracing session of the command's syscalls
+	Stream        OutputStream           `protobuf:"varint,3,opt,name=stream,proto3,enum=proto.node.v1.OutputStream" json:"stream,omitempty"`
+	Exit          *CommandExit           `protobuf:"bytes,4,opt,name=exit,proto3" json:"exit,omitempty"`
+	JobId         string                 `protobuf:"bytes,5,opt,name=jobId,proto3" json:"jobId,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -197,26 +347,59 @@ func (x *SendClientCommandNodeResponse) GetOutput() string {
 	return ""
 }
 
-type StopSyscallsNodeRequest struct {
+func (x *SendClientCommandNodeResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SendClientCommandNodeResponse) GetStream() OutputStream {
+	if x != nil {
+		return x.Stream
+	}
+	return OutputStream_OUTPUT_STREAM_UNSPECIFIED
+}
+
+func (x *SendClientCommandNodeResponse) GetExit() *CommandExit {
+	if x != nil {
+		return x.Exit
+	}
+	return nil
+}
+
+func (x *SendClientCommandNodeResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type CommandExit struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExitCode      int32                  `protobuf:"varint,1,opt,name=exitCode,proto3" json:"exitCode,omitempty"` // -1 if the command was killed or could not run
+	DurationMs    int64                  `protobuf:"varint,2,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	Killed        bool                   `protobuf:"varint,3,opt,name=killed,proto3" json:"killed,omitempty"` // stopped by a signal, or cancelled before it exited
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`    // why the command did not run to completion, if it did not
+	Signal        string                 `protobuf:"bytes,5,opt,name=signal,proto3" json:"signal,omitempty"`  // the signal that killed the command, e.g. "killed"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StopSyscallsNodeRequest) Reset() {
-	*x = StopSyscallsNodeRequest{}
+func (x *CommandExit) Reset() {
+	*x = CommandExit{}
 	mi := &file_proto_node_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StopSyscallsNodeRequest) String() string {
+func (x *CommandExit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopSyscallsNodeRequest) ProtoMessage() {}
+func (*CommandExit) ProtoMessage() {}
 
-func (x *StopSyscallsNodeRequest) ProtoReflect() protoreflect.Message {
+func (x *CommandExit) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_node_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -228,31 +411,74 @@ func (x *StopSyscallsNodeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopSyscallsNodeRequest.ProtoReflect.Descriptor instead.
-func (*StopSyscallsNodeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CommandExit.ProtoReflect.Descriptor instead.
+func (*CommandExit) Descriptor() ([]byte, []int) {
 	return file_proto_node_proto_rawDescGZIP(), []int{4}
 }
 
-type StopSyscallsNodeResponse struct {
+func (x *CommandExit) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *CommandExit) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *CommandExit) GetKilled() bool {
+	if x != nil {
+		return x.Killed
+	}
+	return false
+}
+
+func (x *CommandExit) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CommandExit) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+// The first request starts the command; later ones write to its stdin,
+// resize its terminal or signal it. Ending the call kills the command, but
+// closing the sending side does not.
+type ExecNodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *ExecNodeStart         `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Stdin         []byte                 `protobuf:"bytes,2,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	CloseStdin    bool                   `protobuf:"varint,3,opt,name=closeStdin,proto3" json:"closeStdin,omitempty"` // after stdin; types ^D in a terminal
+	Resize        *TerminalSize          `protobuf:"bytes,4,opt,name=resize,proto3" json:"resize,omitempty"`
+	Signal        int32                  `protobuf:"varint,5,opt,name=signal,proto3" json:"signal,omitempty"` // a Linux signal number, e.g. 15 for SIGTERM
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StopSyscallsNodeResponse) Reset() {
-	*x = StopSyscallsNodeResponse{}
+func (x *ExecNodeRequest) Reset() {
+	*x = ExecNodeRequest{}
 	mi := &file_proto_node_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StopSyscallsNodeResponse) String() string {
+func (x *ExecNodeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopSyscallsNodeResponse) ProtoMessage() {}
+func (*ExecNodeRequest) ProtoMessage() {}
 
-func (x *StopSyscallsNodeResponse) ProtoReflect() protoreflect.Message {
+func (x *ExecNodeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_node_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -264,31 +490,73 @@ func (x *StopSyscallsNodeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopSyscallsNodeResponse.ProtoReflect.Descriptor instead.
-func (*StopSyscallsNodeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ExecNodeRequest.ProtoReflect.Descriptor instead.
+func (*ExecNodeRequest) Descriptor() ([]byte, []int) {
 	return file_proto_node_proto_rawDescGZIP(), []int{5}
 }
 
-type CleanupNodeRequest struct {
+func (x *ExecNodeRequest) GetStart() *ExecNodeStart {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *ExecNodeRequest) GetStdin() []byte {
+	if x != nil {
+		return x.Stdin
+	}
+	return nil
+}
+
+func (x *ExecNodeRequest) GetCloseStdin() bool {
+	if x != nil {
+		return x.CloseStdin
+	}
+	return false
+}
+
+func (x *ExecNodeRequest) GetResize() *TerminalSize {
+	if x != nil {
+		return x.Resize
+	}
+	return nil
+}
+
+func (x *ExecNodeRequest) GetSignal() int32 {
+	if x != nil {
+		return x.Signal
+	}
+	return 0
+}
+
+type ExecNodeStart struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Argv          []string               `protobuf:"bytes,1,rep,name=argv,proto3" json:"argv,omitempty"`
+	Env           []string               `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty"`              // KEY=value, added to the environment of the runner
+	Cwd           string                 `protobuf:"bytes,3,opt,name=cwd,proto3" json:"cwd,omitempty"`              // optional, the directory of the runner if empty
+	Tty           bool                   `protobuf:"varint,4,opt,name=tty,proto3" json:"tty,omitempty"`             // runs the command in a terminal, which merges stderr into stdout
+	Size          *TerminalSize          `protobuf:"bytes,5,opt,name=size,proto3" json:"size,omitempty"`            // the initial size of the terminal
+	Stdin         bool                   `protobuf:"varint,6,opt,name=stdin,proto3" json:"stdin,omitempty"`         // keeps stdin open for the stdin of later requests
+	TimeoutMs     int64                  `protobuf:"varint,7,opt,name=timeoutMs,proto3" json:"timeoutMs,omitempty"` // optional, the command is killed after it
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CleanupNodeRequest) Reset() {
-	*x = CleanupNodeRequest{}
+func (x *ExecNodeStart) Reset() {
+	*x = ExecNodeStart{}
 	mi := &file_proto_node_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CleanupNodeRequest) String() string {
+func (x *ExecNodeStart) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanupNodeRequest) ProtoMessage() {}
+func (*ExecNodeStart) ProtoMessage() {}
 
-func (x *CleanupNodeRequest) ProtoReflect() protoreflect.Message {
+func (x *ExecNodeStart) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_node_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -300,31 +568,82 @@ func (x *CleanupNodeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanupNodeRequest.ProtoReflect.Descriptor instead.
-func (*CleanupNodeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ExecNodeStart.ProtoReflect.Descriptor instead.
+func (*ExecNodeStart) Descriptor() ([]byte, []int) {
 	return file_proto_node_proto_rawDescGZIP(), []int{6}
 }
 
-type CleanupNodeResponse struct {
+func (x *ExecNodeStart) GetArgv() []string {
+	if x != nil {
+		return x.Argv
+	}
+	return nil
+}
+
+func (x *ExecNodeStart) GetEnv() []string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ExecNodeStart) GetCwd() string {
+	if x != nil {
+		return x.Cwd
+	}
+	return ""
+}
+
+func (x *ExecNodeStart) GetTty() bool {
+	if x != nil {
+		return x.Tty
+	}
+	return false
+}
+
+func (x *ExecNodeStart) GetSize() *TerminalSize {
+	if x != nil {
+		return x.Size
+	}
+	return nil
+}
+
+func (x *ExecNodeStart) GetStdin() bool {
+	if x != nil {
+		return x.Stdin
+	}
+	return false
+}
+
+func (x *ExecNodeStart) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type TerminalSize struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          uint32                 `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	Cols          uint32                 `protobuf:"varint,2,opt,name=cols,proto3" json:"cols,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CleanupNodeResponse) Reset() {
-	*x = CleanupNodeResponse{}
+func (x *TerminalSize) Reset() {
+	*x = TerminalSize{}
 	mi := &file_proto_node_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CleanupNodeResponse) String() string {
+func (x *TerminalSize) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanupNodeResponse) ProtoMessage() {}
+func (*TerminalSize) ProtoMessage() {}
 
-func (x *CleanupNodeResponse) ProtoReflect() protoreflect.Message {
+func (x *TerminalSize) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_node_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -336,33 +655,1575 @@ func (x *CleanupNodeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanupNodeResponse.ProtoReflect.Descriptor instead.
-func (*CleanupNodeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use TerminalSize.ProtoReflect.Descriptor instead.
+func (*TerminalSize) Descriptor() ([]byte, []int) {
 	return file_proto_node_proto_rawDescGZIP(), []int{7}
 }
 
-var File_proto_node_proto protoreflect.FileDescriptor
+func (x *TerminalSize) GetRows() uint32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
 
-const file_proto_node_proto_rawDesc = "" +
-	"\n" +
-	"\x10proto/node.proto\x12\rproto.node.v1\"8\n" +
-	"\x1cSendServerCommandNodeRequest\x12\x18\n" +
-	"\acommand\x18\x01 \x01(\tR\acommand\"7\n" +
-	"\x1dSendServerCommandNodeResponse\x12\x16\n" +
-	"\x06output\x18\x01 \x01(\tR\x06output\"8\n" +
-	"\x1cSendClientCommandNodeRequest\x12\x18\n" +
-	"\acommand\x18\x01 \x01(\tR\acommand\"7\n" +
-	"\x1dSendClientCommandNodeResponse\x12\x16\n" +
-	"\x06output\x18\x01 \x01(\tR\x06output\"\x19\n" +
-	"\x17StopSyscallsNodeRequest\"\x1a\n" +
-	"\x18StopSyscallsNodeResponse\"\x14\n" +
+func (x *TerminalSize) GetCols() uint32 {
+	if x != nil {
+		return x.Cols
+	}
+	return 0
+}
+
+// The first response carries the PID and the job ID, the following ones the
+// output as it is written, and the last one the exit.
+type ExecNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pid           int64                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	Stream        OutputStream           `protobuf:"varint,2,opt,name=stream,proto3,enum=proto.node.v1.OutputStream" json:"stream,omitempty"`
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Exit          *CommandExit           `protobuf:"bytes,4,opt,name=exit,proto3" json:"exit,omitempty"`
+	JobId         string                 `protobuf:"bytes,5,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecNodeResponse) Reset() {
+	*x = ExecNodeResponse{}
+	mi := &file_proto_node_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecNodeResponse) ProtoMessage() {}
+
+func (x *ExecNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecNodeResponse.ProtoReflect.Descriptor instead.
+func (*ExecNodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ExecNodeResponse) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *ExecNodeResponse) GetStream() OutputStream {
+	if x != nil {
+		return x.Stream
+	}
+	return OutputStream_OUTPUT_STREAM_UNSPECIFIED
+}
+
+func (x *ExecNodeResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExecNodeResponse) GetExit() *CommandExit {
+	if x != nil {
+		return x.Exit
+	}
+	return nil
+}
+
+func (x *ExecNodeResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// A command started by SendServerCommand, SendClientCommand or Exec.
+type Job struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Command       string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	Server        bool                   `protobuf:"varint,3,opt,name=server,proto3" json:"server,omitempty"` // started by SendServerCommand
+	Pid           int64                  `protobuf:"varint,4,opt,name=pid,proto3" json:"pid,omitempty"`
+	TimeoutMs     int64                  `protobuf:"varint,5,opt,name=timeoutMs,proto3" json:"timeoutMs,omitempty"` // 0 if the command may run forever
+	LogPath       string                 `protobuf:"bytes,6,opt,name=logPath,proto3" json:"logPath,omitempty"`      // empty for Exec
+	State         JobState               `protobuf:"varint,7,opt,name=state,proto3,enum=proto.node.v1.JobState" json:"state,omitempty"`
+	StartedAt     int64                  `protobuf:"varint,8,opt,name=startedAt,proto3" json:"startedAt,omitempty"` // unix millis
+	EndedAt       int64                  `protobuf:"varint,9,opt,name=endedAt,proto3" json:"endedAt,omitempty"`     // unix millis, 0 while running
+	Exit          *CommandExit           `protobuf:"bytes,10,opt,name=exit,proto3" json:"exit,omitempty"`           // once the command ended
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_proto_node_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *Job) GetServer() bool {
+	if x != nil {
+		return x.Server
+	}
+	return false
+}
+
+func (x *Job) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Job) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *Job) GetLogPath() string {
+	if x != nil {
+		return x.LogPath
+	}
+	return ""
+}
+
+func (x *Job) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *Job) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *Job) GetEndedAt() int64 {
+	if x != nil {
+		return x.EndedAt
+	}
+	return 0
+}
+
+func (x *Job) GetExit() *CommandExit {
+	if x != nil {
+		return x.Exit
+	}
+	return nil
+}
+
+type ListJobsNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsNodeRequest) Reset() {
+	*x = ListJobsNodeRequest{}
+	mi := &file_proto_node_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsNodeRequest) ProtoMessage() {}
+
+func (x *ListJobsNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsNodeRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsNodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{10}
+}
+
+type ListJobsNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*Job                 `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsNodeResponse) Reset() {
+	*x = ListJobsNodeResponse{}
+	mi := &file_proto_node_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsNodeResponse) ProtoMessage() {}
+
+func (x *ListJobsNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsNodeResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsNodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListJobsNodeResponse) GetJobs() []*Job {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type GetJobNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobNodeRequest) Reset() {
+	*x = GetJobNodeRequest{}
+	mi := &file_proto_node_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobNodeRequest) ProtoMessage() {}
+
+func (x *GetJobNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobNodeRequest.ProtoReflect.Descriptor instead.
+func (*GetJobNodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetJobNodeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetJobNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *Job                   `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobNodeResponse) Reset() {
+	*x = GetJobNodeResponse{}
+	mi := &file_proto_node_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobNodeResponse) ProtoMessage() {}
+
+func (x *GetJobNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobNodeResponse.ProtoReflect.Descriptor instead.
+func (*GetJobNodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetJobNodeResponse) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+// Cancelling kills the command of the job and returns once it ended.
+// Cancelling an ended job returns it.
+type CancelJobNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobNodeRequest) Reset() {
+	*x = CancelJobNodeRequest{}
+	mi := &file_proto_node_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobNodeRequest) ProtoMessage() {}
+
+func (x *CancelJobNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobNodeRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobNodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CancelJobNodeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelJobNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *Job                   `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobNodeResponse) Reset() {
+	*x = CancelJobNodeResponse{}
+	mi := &file_proto_node_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobNodeResponse) ProtoMessage() {}
+
+func (x *CancelJobNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobNodeResponse.ProtoReflect.Descriptor instead.
+func (*CancelJobNodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CancelJobNodeResponse) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type TrackSyscallsNodeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pids  []int64                `protobuf:"varint,1,rep,packed,name=pids,proto3" json:"pids,omitempty"`
+	// optional allowlist of syscall names and the families "network",
+	// "polling", "file" and "memory", every syscall if empty
+	Syscalls []string `protobuf:"bytes,2,rep,name=syscalls,proto3" json:"syscalls,omitempty"`
+	// also pair every syscall's enter and exit to build latency histograms
+	Latency       bool `protobuf:"varint,3,opt,name=latency,proto3" json:"latency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackSyscallsNodeRequest) Reset() {
+	*x = TrackSyscallsNodeRequest{}
+	mi := &file_proto_node_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackSyscallsNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackSyscallsNodeRequest) ProtoMessage() {}
+
+func (x *TrackSyscallsNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackSyscallsNodeRequest.ProtoReflect.Descriptor instead.
+func (*TrackSyscallsNodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TrackSyscallsNodeRequest) GetPids() []int64 {
+	if x != nil {
+		return x.Pids
+	}
+	return nil
+}
+
+func (x *TrackSyscallsNodeRequest) GetSyscalls() []string {
+	if x != nil {
+		return x.Syscalls
+	}
+	return nil
+}
+
+func (x *TrackSyscallsNodeRequest) GetLatency() bool {
+	if x != nil {
+		return x.Latency
+	}
+	return false
+}
+
+type TrackSyscallsNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *TraceSession          `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackSyscallsNodeResponse) Reset() {
+	*x = TrackSyscallsNodeResponse{}
+	mi := &file_proto_node_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackSyscallsNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackSyscallsNodeResponse) ProtoMessage() {}
+
+func (x *TrackSyscallsNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackSyscallsNodeResponse.ProtoReflect.Descriptor instead.
+func (*TrackSyscallsNodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TrackSyscallsNodeResponse) GetSession() *TraceSession {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+// StopSyscallsNodeRequest without a session stops every session and kills
+// the server commands.
+type StopSyscallsNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopSyscallsNodeRequest) Reset() {
+	*x = StopSyscallsNodeRequest{}
+	mi := &file_proto_node_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopSyscallsNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopSyscallsNodeRequest) ProtoMessage() {}
+
+func (x *StopSyscallsNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopSyscallsNodeRequest.ProtoReflect.Descriptor instead.
+func (*StopSyscallsNodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StopSyscallsNodeRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type StopSyscallsNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopSyscallsNodeResponse) Reset() {
+	*x = StopSyscallsNodeResponse{}
+	mi := &file_proto_node_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopSyscallsNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopSyscallsNodeResponse) ProtoMessage() {}
+
+func (x *StopSyscallsNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopSyscallsNodeResponse.ProtoReflect.Descriptor instead.
+func (*StopSyscallsNodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{19}
+}
+
+// TraceSession traces the syscalls of a set of processes until it is stopped.
+type TraceSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Pids          []int64                `protobuf:"varint,2,rep,packed,name=pids,proto3" json:"pids,omitempty"`
+	Syscalls      []string               `protobuf:"bytes,3,rep,name=syscalls,proto3" json:"syscalls,omitempty"`    // the allowlist, empty for every syscall
+	StartedAt     int64                  `protobuf:"varint,4,opt,name=startedAt,proto3" json:"startedAt,omitempty"` // unix millis
+	StoppedAt     int64                  `protobuf:"varint,5,opt,name=stoppedAt,proto3" json:"stoppedAt,omitempty"` // unix millis, 0 while running
+	Latency       bool                   `protobuf:"varint,6,opt,name=latency,proto3" json:"latency,omitempty"`     // syscalls are timed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceSession) Reset() {
+	*x = TraceSession{}
+	mi := &file_proto_node_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceSession) ProtoMessage() {}
+
+func (x *TraceSession) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceSession.ProtoReflect.Descriptor instead.
+func (*TraceSession) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TraceSession) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TraceSession) GetPids() []int64 {
+	if x != nil {
+		return x.Pids
+	}
+	return nil
+}
+
+func (x *TraceSession) GetSyscalls() []string {
+	if x != nil {
+		return x.Syscalls
+	}
+	return nil
+}
+
+func (x *TraceSession) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *TraceSession) GetStoppedAt() int64 {
+	if x != nil {
+		return x.StoppedAt
+	}
+	return 0
+}
+
+func (x *TraceSession) GetLatency() bool {
+	if x != nil {
+		return x.Latency
+	}
+	return false
+}
+
+type ListTraceSessionsNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTraceSessionsNodeRequest) Reset() {
+	*x = ListTraceSessionsNodeRequest{}
+	mi := &file_proto_node_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTraceSessionsNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTraceSessionsNodeRequest) ProtoMessage() {}
+
+func (x *ListTraceSessionsNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTraceSessionsNodeRequest.ProtoReflect.Descriptor instead.
+func (*ListTraceSessionsNodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{21}
+}
+
+type ListTraceSessionsNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*TraceSession        `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTraceSessionsNodeResponse) Reset() {
+	*x = ListTraceSessionsNodeResponse{}
+	mi := &file_proto_node_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTraceSessionsNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTraceSessionsNodeResponse) ProtoMessage() {}
+
+func (x *ListTraceSessionsNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTraceSessionsNodeResponse.ProtoReflect.Descriptor instead.
+func (*ListTraceSessionsNodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListTraceSessionsNodeResponse) GetSessions() []*TraceSession {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// SyscallCount is how often the threads named comm called syscall.
+type SyscallCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comm          string                 `protobuf:"bytes,1,opt,name=comm,proto3" json:"comm,omitempty"`
+	Syscall       string                 `protobuf:"bytes,2,opt,name=syscall,proto3" json:"syscall,omitempty"`
+	Count         uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyscallCount) Reset() {
+	*x = SyscallCount{}
+	mi := &file_proto_node_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyscallCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyscallCount) ProtoMessage() {}
+
+func (x *SyscallCount) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyscallCount.ProtoReflect.Descriptor instead.
+func (*SyscallCount) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SyscallCount) GetComm() string {
+	if x != nil {
+		return x.Comm
+	}
+	return ""
+}
+
+func (x *SyscallCount) GetSyscall() string {
+	if x != nil {
+		return x.Syscall
+	}
+	return ""
+}
+
+func (x *SyscallCount) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// SyscallLatency is the log2 latency histogram of syscall, from enter to
+// exit, in the thread tid named comm.
+type SyscallLatency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comm          string                 `protobuf:"bytes,1,opt,name=comm,proto3" json:"comm,omitempty"`
+	Tid           int64                  `protobuf:"varint,2,opt,name=tid,proto3" json:"tid,omitempty"`
+	Syscall       string                 `protobuf:"bytes,3,opt,name=syscall,proto3" json:"syscall,omitempty"`
+	Count         uint64                 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	TotalNs       uint64                 `protobuf:"varint,5,opt,name=totalNs,proto3" json:"totalNs,omitempty"`
+	Buckets       []*LatencyBucket       `protobuf:"bytes,6,rep,name=buckets,proto3" json:"buckets,omitempty"` // the buckets that are not empty, fastest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyscallLatency) Reset() {
+	*x = SyscallLatency{}
+	mi := &file_proto_node_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyscallLatency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyscallLatency) ProtoMessage() {}
+
+func (x *SyscallLatency) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyscallLatency.ProtoReflect.Descriptor instead.
+func (*SyscallLatency) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SyscallLatency) GetComm() string {
+	if x != nil {
+		return x.Comm
+	}
+	return ""
+}
+
+func (x *SyscallLatency) GetTid() int64 {
+	if x != nil {
+		return x.Tid
+	}
+	return 0
+}
+
+func (x *SyscallLatency) GetSyscall() string {
+	if x != nil {
+		return x.Syscall
+	}
+	return ""
+}
+
+func (x *SyscallLatency) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SyscallLatency) GetTotalNs() uint64 {
+	if x != nil {
+		return x.TotalNs
+	}
+	return 0
+}
+
+func (x *SyscallLatency) GetBuckets() []*LatencyBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+// LatencyBucket counts the calls that took minNs to maxNs nanoseconds.
+type LatencyBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinNs         uint64                 `protobuf:"varint,1,opt,name=minNs,proto3" json:"minNs,omitempty"`
+	MaxNs         uint64                 `protobuf:"varint,2,opt,name=maxNs,proto3" json:"maxNs,omitempty"`
+	Count         uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencyBucket) Reset() {
+	*x = LatencyBucket{}
+	mi := &file_proto_node_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencyBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyBucket) ProtoMessage() {}
+
+func (x *LatencyBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencyBucket.ProtoReflect.Descriptor instead.
+func (*LatencyBucket) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *LatencyBucket) GetMinNs() uint64 {
+	if x != nil {
+		return x.MinNs
+	}
+	return 0
+}
+
+func (x *LatencyBucket) GetMaxNs() uint64 {
+	if x != nil {
+		return x.MaxNs
+	}
+	return 0
+}
+
+func (x *LatencyBucket) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// SyscallStats is the latest snapshot of the syscall trace of a process.
+type SyscallStats struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Pid        int64                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	Time       int64                  `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"` // unix millis
+	IntervalMs int64                  `protobuf:"varint,3,opt,name=intervalMs,proto3" json:"intervalMs,omitempty"`
+	Interval   []*SyscallCount        `protobuf:"bytes,4,rep,name=interval,proto3" json:"interval,omitempty"` // during the last interval, most called first
+	Total      []*SyscallCount        `protobuf:"bytes,5,rep,name=total,proto3" json:"total,omitempty"`       // since tracing started, most called first
+	Final      bool                   `protobuf:"varint,6,opt,name=final,proto3" json:"final,omitempty"`      // tracing has stopped
+	SessionId  string                 `protobuf:"bytes,7,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	// only if the session times syscalls
+	IntervalLatency []*SyscallLatency `protobuf:"bytes,8,rep,name=intervalLatency,proto3" json:"intervalLatency,omitempty"` // during the last interval, most time spent first
+	TotalLatency    []*SyscallLatency `protobuf:"bytes,9,rep,name=totalLatency,proto3" json:"totalLatency,omitempty"`       // since tracing started, most time spent first
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SyscallStats) Reset() {
+	*x = SyscallStats{}
+	mi := &file_proto_node_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyscallStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyscallStats) ProtoMessage() {}
+
+func (x *SyscallStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyscallStats.ProtoReflect.Descriptor instead.
+func (*SyscallStats) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SyscallStats) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *SyscallStats) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *SyscallStats) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+func (x *SyscallStats) GetInterval() []*SyscallCount {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *SyscallStats) GetTotal() []*SyscallCount {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *SyscallStats) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+func (x *SyscallStats) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SyscallStats) GetIntervalLatency() []*SyscallLatency {
+	if x != nil {
+		return x.IntervalLatency
+	}
+	return nil
+}
+
+func (x *SyscallStats) GetTotalLatency() []*SyscallLatency {
+	if x != nil {
+		return x.TotalLatency
+	}
+	return nil
+}
+
+type GetSyscallStatsNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pid           int64                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`            // optional, every process of the session if 0
+	SessionId     string                 `protobuf:"bytes,2,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // optional, the latest session if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSyscallStatsNodeRequest) Reset() {
+	*x = GetSyscallStatsNodeRequest{}
+	mi := &file_proto_node_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSyscallStatsNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSyscallStatsNodeRequest) ProtoMessage() {}
+
+func (x *GetSyscallStatsNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSyscallStatsNodeRequest.ProtoReflect.Descriptor instead.
+func (*GetSyscallStatsNodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetSyscallStatsNodeRequest) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *GetSyscallStatsNodeRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type GetSyscallStatsNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*SyscallStats        `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSyscallStatsNodeResponse) Reset() {
+	*x = GetSyscallStatsNodeResponse{}
+	mi := &file_proto_node_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSyscallStatsNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSyscallStatsNodeResponse) ProtoMessage() {}
+
+func (x *GetSyscallStatsNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSyscallStatsNodeResponse.ProtoReflect.Descriptor instead.
+func (*GetSyscallStatsNodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetSyscallStatsNodeResponse) GetStats() []*SyscallStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// WatchSyscallsNodeRequest streams the counts of every tracing interval until
+// the session is stopped.
+type WatchSyscallsNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pid           int64                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`            // optional, every process of the session if 0
+	SessionId     string                 `protobuf:"bytes,2,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // optional, the latest session if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSyscallsNodeRequest) Reset() {
+	*x = WatchSyscallsNodeRequest{}
+	mi := &file_proto_node_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSyscallsNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSyscallsNodeRequest) ProtoMessage() {}
+
+func (x *WatchSyscallsNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSyscallsNodeRequest.ProtoReflect.Descriptor instead.
+func (*WatchSyscallsNodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *WatchSyscallsNodeRequest) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *WatchSyscallsNodeRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type WatchSyscallsNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *SyscallStats          `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSyscallsNodeResponse) Reset() {
+	*x = WatchSyscallsNodeResponse{}
+	mi := &file_proto_node_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSyscallsNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSyscallsNodeResponse) ProtoMessage() {}
+
+func (x *WatchSyscallsNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSyscallsNodeResponse.ProtoReflect.Descriptor instead.
+func (*WatchSyscallsNodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *WatchSyscallsNodeResponse) GetStats() *SyscallStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type CleanupNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CleanupNodeRequest) Reset() {
+	*x = CleanupNodeRequest{}
+	mi := &file_proto_node_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CleanupNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanupNodeRequest) ProtoMessage() {}
+
+func (x *CleanupNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanupNodeRequest.ProtoReflect.Descriptor instead.
+func (*CleanupNodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{31}
+}
+
+type CleanupNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CleanupNodeResponse) Reset() {
+	*x = CleanupNodeResponse{}
+	mi := &file_proto_node_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CleanupNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanupNodeResponse) ProtoMessage() {}
+
+func (x *CleanupNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_node_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanupNodeResponse.ProtoReflect.Descriptor instead.
+func (*CleanupNodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_node_proto_rawDescGZIP(), []int{32}
+}
+
+var File_proto_node_proto protoreflect.FileDescriptor
+
+const file_proto_node_proto_rawDesc = "" +
+	"\n" +
+	"\x10proto/node.proto\x12\rproto.node.v1\"V\n" +
+	"\x1cSendServerCommandNodeRequest\x12\x18\n" +
+	"\acommand\x18\x01 \x01(\tR\acommand\x12\x1c\n" +
+	"\ttimeoutMs\x18\x02 \x01(\x03R\ttimeoutMs\"}\n" +
+	"\x1dSendServerCommandNodeResponse\x12\x16\n" +
+	"\x06output\x18\x01 \x01(\tR\x06output\x12\x10\n" +
+	"\x03pid\x18\x02 \x01(\x03R\x03pid\x12\x1c\n" +
+	"\tsessionId\x18\x03 \x01(\tR\tsessionId\x12\x14\n" +
+	"\x05jobId\x18\x04 \x01(\tR\x05jobId\"V\n" +
+	"\x1cSendClientCommandNodeRequest\x12\x18\n" +
+	"\acommand\x18\x01 \x01(\tR\acommand\x12\x1c\n" +
+	"\ttimeoutMs\x18\x02 \x01(\x03R\ttimeoutMs\"\xd0\x01\n" +
+	"\x1dSendClientCommandNodeResponse\x12\x16\n" +
+	"\x06output\x18\x01 \x01(\tR\x06output\x12\x1c\n" +
+	"\tsessionId\x18\x02 \x01(\tR\tsessionId\x123\n" +
+	"\x06stream\x18\x03 \x01(\x0e2\x1b.proto.node.v1.OutputStreamR\x06stream\x12.\n" +
+	"\x04exit\x18\x04 \x01(\v2\x1a.proto.node.v1.CommandExitR\x04exit\x12\x14\n" +
+	"\x05jobId\x18\x05 \x01(\tR\x05jobId\"\x8f\x01\n" +
+	"\vCommandExit\x12\x1a\n" +
+	"\bexitCode\x18\x01 \x01(\x05R\bexitCode\x12\x1e\n" +
+	"\n" +
+	"durationMs\x18\x02 \x01(\x03R\n" +
+	"durationMs\x12\x16\n" +
+	"\x06killed\x18\x03 \x01(\bR\x06killed\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x16\n" +
+	"\x06signal\x18\x05 \x01(\tR\x06signal\"\xc8\x01\n" +
+	"\x0fExecNodeRequest\x122\n" +
+	"\x05start\x18\x01 \x01(\v2\x1c.proto.node.v1.ExecNodeStartR\x05start\x12\x14\n" +
+	"\x05stdin\x18\x02 \x01(\fR\x05stdin\x12\x1e\n" +
+	"\n" +
+	"closeStdin\x18\x03 \x01(\bR\n" +
+	"closeStdin\x123\n" +
+	"\x06resize\x18\x04 \x01(\v2\x1b.proto.node.v1.TerminalSizeR\x06resize\x12\x16\n" +
+	"\x06signal\x18\x05 \x01(\x05R\x06signal\"\xbe\x01\n" +
+	"\rExecNodeStart\x12\x12\n" +
+	"\x04argv\x18\x01 \x03(\tR\x04argv\x12\x10\n" +
+	"\x03env\x18\x02 \x03(\tR\x03env\x12\x10\n" +
+	"\x03cwd\x18\x03 \x01(\tR\x03cwd\x12\x10\n" +
+	"\x03tty\x18\x04 \x01(\bR\x03tty\x12/\n" +
+	"\x04size\x18\x05 \x01(\v2\x1b.proto.node.v1.TerminalSizeR\x04size\x12\x14\n" +
+	"\x05stdin\x18\x06 \x01(\bR\x05stdin\x12\x1c\n" +
+	"\ttimeoutMs\x18\a \x01(\x03R\ttimeoutMs\"6\n" +
+	"\fTerminalSize\x12\x12\n" +
+	"\x04rows\x18\x01 \x01(\rR\x04rows\x12\x12\n" +
+	"\x04cols\x18\x02 \x01(\rR\x04cols\"\xb3\x01\n" +
+	"\x10ExecNodeResponse\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\x03R\x03pid\x123\n" +
+	"\x06stream\x18\x02 \x01(\x0e2\x1b.proto.node.v1.OutputStreamR\x06stream\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\x12.\n" +
+	"\x04exit\x18\x04 \x01(\v2\x1a.proto.node.v1.CommandExitR\x04exit\x12\x14\n" +
+	"\x05jobId\x18\x05 \x01(\tR\x05jobId\"\xa8\x02\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\acommand\x18\x02 \x01(\tR\acommand\x12\x16\n" +
+	"\x06server\x18\x03 \x01(\bR\x06server\x12\x10\n" +
+	"\x03pid\x18\x04 \x01(\x03R\x03pid\x12\x1c\n" +
+	"\ttimeoutMs\x18\x05 \x01(\x03R\ttimeoutMs\x12\x18\n" +
+	"\alogPath\x18\x06 \x01(\tR\alogPath\x12-\n" +
+	"\x05state\x18\a \x01(\x0e2\x17.proto.node.v1.JobStateR\x05state\x12\x1c\n" +
+	"\tstartedAt\x18\b \x01(\x03R\tstartedAt\x12\x18\n" +
+	"\aendedAt\x18\t \x01(\x03R\aendedAt\x12.\n" +
+	"\x04exit\x18\n" +
+	" \x01(\v2\x1a.proto.node.v1.CommandExitR\x04exit\"\x15\n" +
+	"\x13ListJobsNodeRequest\">\n" +
+	"\x14ListJobsNodeResponse\x12&\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x12.proto.node.v1.JobR\x04jobs\"#\n" +
+	"\x11GetJobNodeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\":\n" +
+	"\x12GetJobNodeResponse\x12$\n" +
+	"\x03job\x18\x01 \x01(\v2\x12.proto.node.v1.JobR\x03job\"&\n" +
+	"\x14CancelJobNodeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"=\n" +
+	"\x15CancelJobNodeResponse\x12$\n" +
+	"\x03job\x18\x01 \x01(\v2\x12.proto.node.v1.JobR\x03job\"d\n" +
+	"\x18TrackSyscallsNodeRequest\x12\x12\n" +
+	"\x04pids\x18\x01 \x03(\x03R\x04pids\x12\x1a\n" +
+	"\bsyscalls\x18\x02 \x03(\tR\bsyscalls\x12\x18\n" +
+	"\alatency\x18\x03 \x01(\bR\alatency\"R\n" +
+	"\x19TrackSyscallsNodeResponse\x125\n" +
+	"\asession\x18\x01 \x01(\v2\x1b.proto.node.v1.TraceSessionR\asession\"7\n" +
+	"\x17StopSyscallsNodeRequest\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\"\x1a\n" +
+	"\x18StopSyscallsNodeResponse\"\xa4\x01\n" +
+	"\fTraceSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04pids\x18\x02 \x03(\x03R\x04pids\x12\x1a\n" +
+	"\bsyscalls\x18\x03 \x03(\tR\bsyscalls\x12\x1c\n" +
+	"\tstartedAt\x18\x04 \x01(\x03R\tstartedAt\x12\x1c\n" +
+	"\tstoppedAt\x18\x05 \x01(\x03R\tstoppedAt\x12\x18\n" +
+	"\alatency\x18\x06 \x01(\bR\alatency\"\x1e\n" +
+	"\x1cListTraceSessionsNodeRequest\"X\n" +
+	"\x1dListTraceSessionsNodeResponse\x127\n" +
+	"\bsessions\x18\x01 \x03(\v2\x1b.proto.node.v1.TraceSessionR\bsessions\"R\n" +
+	"\fSyscallCount\x12\x12\n" +
+	"\x04comm\x18\x01 \x01(\tR\x04comm\x12\x18\n" +
+	"\asyscall\x18\x02 \x01(\tR\asyscall\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x04R\x05count\"\xb8\x01\n" +
+	"\x0eSyscallLatency\x12\x12\n" +
+	"\x04comm\x18\x01 \x01(\tR\x04comm\x12\x10\n" +
+	"\x03tid\x18\x02 \x01(\x03R\x03tid\x12\x18\n" +
+	"\asyscall\x18\x03 \x01(\tR\asyscall\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x04R\x05count\x12\x18\n" +
+	"\atotalNs\x18\x05 \x01(\x04R\atotalNs\x126\n" +
+	"\abuckets\x18\x06 \x03(\v2\x1c.proto.node.v1.LatencyBucketR\abuckets\"Q\n" +
+	"\rLatencyBucket\x12\x14\n" +
+	"\x05minNs\x18\x01 \x01(\x04R\x05minNs\x12\x14\n" +
+	"\x05maxNs\x18\x02 \x01(\x04R\x05maxNs\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x04R\x05count\"\x80\x03\n" +
+	"\fSyscallStats\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\x03R\x03pid\x12\x12\n" +
+	"\x04time\x18\x02 \x01(\x03R\x04time\x12\x1e\n" +
+	"\n" +
+	"intervalMs\x18\x03 \x01(\x03R\n" +
+	"intervalMs\x127\n" +
+	"\binterval\x18\x04 \x03(\v2\x1b.proto.node.v1.SyscallCountR\binterval\x121\n" +
+	"\x05total\x18\x05 \x03(\v2\x1b.proto.node.v1.SyscallCountR\x05total\x12\x14\n" +
+	"\x05final\x18\x06 \x01(\bR\x05final\x12\x1c\n" +
+	"\tsessionId\x18\a \x01(\tR\tsessionId\x12G\n" +
+	"\x0fintervalLatency\x18\b \x03(\v2\x1d.proto.node.v1.SyscallLatencyR\x0fintervalLatency\x12A\n" +
+	"\ftotalLatency\x18\t \x03(\v2\x1d.proto.node.v1.SyscallLatencyR\ftotalLatency\"L\n" +
+	"\x1aGetSyscallStatsNodeRequest\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\x03R\x03pid\x12\x1c\n" +
+	"\tsessionId\x18\x02 \x01(\tR\tsessionId\"P\n" +
+	"\x1bGetSyscallStatsNodeResponse\x121\n" +
+	"\x05stats\x18\x01 \x03(\v2\x1b.proto.node.v1.SyscallStatsR\x05stats\"J\n" +
+	"\x18WatchSyscallsNodeRequest\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\x03R\x03pid\x12\x1c\n" +
+	"\tsessionId\x18\x02 \x01(\tR\tsessionId\"N\n" +
+	"\x19WatchSyscallsNodeResponse\x121\n" +
+	"\x05stats\x18\x01 \x01(\v2\x1b.proto.node.v1.SyscallStatsR\x05stats\"\x14\n" +
 	"\x12CleanupNodeRequest\"\x15\n" +
-	"\x13CleanupNodeResponse2\xaa\x03\n" +
+	"\x13CleanupNodeResponse*a\n" +
+	"\fOutputStream\x12\x1d\n" +
+	"\x19OUTPUT_STREAM_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14OUTPUT_STREAM_STDOUT\x10\x01\x12\x18\n" +
+	"\x14OUTPUT_STREAM_STDERR\x10\x02*\x84\x01\n" +
+	"\bJobState\x12\x19\n" +
+	"\x15JOB_STATE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11JOB_STATE_RUNNING\x10\x01\x12\x14\n" +
+	"\x10JOB_STATE_EXITED\x10\x02\x12\x17\n" +
+	"\x13JOB_STATE_CANCELLED\x10\x03\x12\x17\n" +
+	"\x13JOB_STATE_TIMED_OUT\x10\x042\xa7\t\n" +
 	"\vNodeService\x12p\n" +
 	"\x11SendServerCommand\x12+.proto.node.v1.SendServerCommandNodeRequest\x1a,.proto.node.v1.SendServerCommandNodeResponse\"\x00\x12r\n" +
-	"\x11SendClientCommand\x12+.proto.node.v1.SendClientCommandNodeRequest\x1a,.proto.node.v1.SendClientCommandNodeResponse\"\x000\x01\x12a\n" +
-	"\fStopSyscalls\x12&.proto.node.v1.StopSyscallsNodeRequest\x1a'.proto.node.v1.StopSyscallsNodeResponse\"\x00\x12R\n" +
-	"\aCleanup\x12!.proto.node.v1.CleanupNodeRequest\x1a\".proto.node.v1.CleanupNodeResponse\"\x00B\x0fZ\rproto/node/v1b\x06proto3"
+	"\x11SendClientCommand\x12+.proto.node.v1.SendClientCommandNodeRequest\x1a,.proto.node.v1.SendClientCommandNodeResponse\"\x000\x01\x12d\n" +
+	"\rTrackSyscalls\x12'.proto.node.v1.TrackSyscallsNodeRequest\x1a(.proto.node.v1.TrackSyscallsNodeResponse\"\x00\x12a\n" +
+	"\fStopSyscalls\x12&.proto.node.v1.StopSyscallsNodeRequest\x1a'.proto.node.v1.StopSyscallsNodeResponse\"\x00\x12p\n" +
+	"\x11ListTraceSessions\x12+.proto.node.v1.ListTraceSessionsNodeRequest\x1a,.proto.node.v1.ListTraceSessionsNodeResponse\"\x00\x12j\n" +
+	"\x0fGetSyscallStats\x12).proto.node.v1.GetSyscallStatsNodeRequest\x1a*.proto.node.v1.GetSyscallStatsNodeResponse\"\x00\x12f\n" +
+	"\rWatchSyscalls\x12'.proto.node.v1.WatchSyscallsNodeRequest\x1a(.proto.node.v1.WatchSyscallsNodeResponse\"\x000\x01\x12R\n" +
+	"\aCleanup\x12!.proto.node.v1.CleanupNodeRequest\x1a\".proto.node.v1.CleanupNodeResponse\"\x00\x12M\n" +
+	"\x04Exec\x12\x1e.proto.node.v1.ExecNodeRequest\x1a\x1f.proto.node.v1.ExecNodeResponse\"\x00(\x010\x01\x12U\n" +
+	"\bListJobs\x12\".proto.node.v1.ListJobsNodeRequest\x1a#.proto.node.v1.ListJobsNodeResponse\"\x00\x12O\n" +
+	"\x06GetJob\x12 .proto.node.v1.GetJobNodeRequest\x1a!.proto.node.v1.GetJobNodeResponse\"\x00\x12X\n" +
+	"\tCancelJob\x12#.proto.node.v1.CancelJobNodeRequest\x1a$.proto.node.v1.CancelJobNodeResponse\"\x00B\x0fZ\rproto/node/v1b\x06proto3"
 
 var (
 	file_proto_node_proto_rawDescOnce sync.Once
@@ -376,31 +2237,96 @@ func file_proto_node_proto_rawDescGZIP() []byte {
 	return file_proto_node_proto_rawDescData
 }
 
-var file_proto_node_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_node_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_node_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_proto_node_proto_goTypes = []any{
-	(*SendServerCommandNodeRequest)(nil),  // 0: proto.node.v1.SendServerCommandNodeRequest
-	(*SendServerCommandNodeResponse)(nil), // 1: proto.node.v1.SendServerCommandNodeResponse
-	(*SendClientCommandNodeRequest)(nil),  // 2: proto.node.v1.SendClientCommandNodeRequest
-	(*SendClientCommandNodeResponse)(nil), // 3: proto.node.v1.SendClientCommandNodeResponse
-	(*StopSyscallsNodeRequest)(nil),       // 4: proto.node.v1.StopSyscallsNodeRequest
-	(*StopSyscallsNodeResponse)(nil),      // 5: proto.node.v1.StopSyscallsNodeResponse
-	(*CleanupNodeRequest)(nil),            // 6: proto.node.v1.CleanupNodeRequest
-	(*CleanupNodeResponse)(nil),           // 7: proto.node.v1.CleanupNodeResponse
+	(OutputStream)(0),                     // 0: proto.node.v1.OutputStream
+	(JobState)(0),                         // 1: proto.node.v1.JobState
+	(*SendServerCommandNodeRequest)(nil),  // 2: proto.node.v1.SendServerCommandNodeRequest
+	(*SendServerCommandNodeResponse)(nil), // 3: proto.node.v1.SendServerCommandNodeResponse
+	(*SendClientCommandNodeRequest)(nil),  // 4: proto.node.v1.SendClientCommandNodeRequest
+	(*SendClientCommandNodeResponse)(nil), // 5: proto.node.v1.SendClientCommandNodeResponse
+	(*CommandExit)(nil),                   // 6: proto.node.v1.CommandExit
+	(*ExecNodeRequest)(nil),               // 7: proto.node.v1.ExecNodeRequest
+	(*ExecNodeStart)(nil),                 // 8: proto.node.v1.ExecNodeStart
+	(*TerminalSize)(nil),                  // 9: proto.node.v1.TerminalSize
+	(*ExecNodeResponse)(nil),              // 10: proto.node.v1.ExecNodeResponse
+	(*Job)(nil),                           // 11: proto.node.v1.Job
+	(*ListJobsNodeRequest)(nil),           // 12: proto.node.v1.ListJobsNodeRequest
+	(*ListJobsNodeResponse)(nil),          // 13: proto.node.v1.ListJobsNodeResponse
+	(*GetJobNodeRequest)(nil),             // 14: proto.node.v1.GetJobNodeRequest
+	(*GetJobNodeResponse)(nil),            // 15: proto.node.v1.GetJobNodeResponse
+	(*CancelJobNodeRequest)(nil),          // 16: proto.node.v1.CancelJobNodeRequest
+	(*CancelJobNodeResponse)(nil),         // 17: proto.node.v1.CancelJobNodeResponse
+	(*TrackSyscallsNodeRequest)(nil),      // 18: proto.node.v1.TrackSyscallsNodeRequest
+	(*TrackSyscallsNodeResponse)(nil),     // 19: proto.node.v1.TrackSyscallsNodeResponse
+	(*StopSyscallsNodeRequest)(nil),       // 20: proto.node.v1.StopSyscallsNodeRequest
+	(*StopSyscallsNodeResponse)(nil),      // 21: proto.node.v1.StopSyscallsNodeResponse
+	(*TraceSession)(nil),                  // 22: proto.node.v1.TraceSession
+	(*ListTraceSessionsNodeRequest)(nil),  // 23: proto.node.v1.ListTraceSessionsNodeRequest
+	(*ListTraceSessionsNodeResponse)(nil), // 24: proto.node.v1.ListTraceSessionsNodeResponse
+	(*SyscallCount)(nil),                  // 25: proto.node.v1.SyscallCount
+	(*SyscallLatency)(nil),                // 26: proto.node.v1.SyscallLatency
+	(*LatencyBucket)(nil),                 // 27: proto.node.v1.LatencyBucket
+	(*SyscallStats)(nil),                  // 28: proto.node.v1.SyscallStats
+	(*GetSyscallStatsNodeRequest)(nil),    // 29: proto.node.v1.GetSyscallStatsNodeRequest
+	(*GetSyscallStatsNodeResponse)(nil),   // 30: proto.node.v1.GetSyscallStatsNodeResponse
+	(*WatchSyscallsNodeRequest)(nil),      // 31: proto.node.v1.WatchSyscallsNodeRequest
+	(*WatchSyscallsNodeResponse)(nil),     // 32: proto.node.v1.WatchSyscallsNodeResponse
+	(*CleanupNodeRequest)(nil),            // 33: proto.node.v1.CleanupNodeRequest
+	(*CleanupNodeResponse)(nil),           // 34: proto.node.v1.CleanupNodeResponse
 }
 var file_proto_node_proto_depIdxs = []int32{
-	0, // 0: proto.node.v1.NodeService.SendServerCommand:input_type -> proto.node.v1.SendServerCommandNodeRequest
-	2, // 1: proto.node.v1.NodeService.SendClientCommand:input_type -> proto.node.v1.SendClientCommandNodeRequest
-	4, // 2: proto.node.v1.NodeService.StopSyscalls:input_type -> proto.node.v1.StopSyscallsNodeRequest
-	6, // 3: proto.node.v1.NodeService.Cleanup:input_type -> proto.node.v1.CleanupNodeRequest
-	1, // 4: proto.node.v1.NodeService.SendServerCommand:output_type -> proto.node.v1.SendServerCommandNodeResponse
-	3, // 5: proto.node.v1.NodeService.SendClientCommand:output_type -> proto.node.v1.SendClientCommandNodeResponse
-	5, // 6: proto.node.v1.NodeService.StopSyscalls:output_type -> proto.node.v1.StopSyscallsNodeResponse
-	7, // 7: proto.node.v1.NodeService.Cleanup:output_type -> proto.node.v1.CleanupNodeResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: proto.node.v1.SendClientCommandNodeResponse.stream:type_name -> proto.node.v1.OutputStream
+	6,  // 1: proto.node.v1.SendClientCommandNodeResponse.exit:type_name -> proto.node.v1.CommandExit
+	8,  // 2: proto.node.v1.ExecNodeRequest.start:type_name -> proto.node.v1.ExecNodeStart
+	9,  // 3: proto.node.v1.ExecNodeRequest.resize:type_name -> proto.node.v1.TerminalSize
+	9,  // 4: proto.node.v1.ExecNodeStart.size:type_name -> proto.node.v1.TerminalSize
+	0,  // 5: proto.node.v1.ExecNodeResponse.stream:type_name -> proto.node.v1.OutputStream
+	6,  // 6: proto.node.v1.ExecNodeResponse.exit:type_name -> proto.node.v1.CommandExit
+	1,  // 7: proto.node.v1.Job.state:type_name -> proto.node.v1.JobState
+	6,  // 8: proto.node.v1.Job.exit:type_name -> proto.node.v1.CommandExit
+	11, // 9: proto.node.v1.ListJobsNodeResponse.jobs:type_name -> proto.node.v1.Job
+	11, // 10: proto.node.v1.GetJobNodeResponse.job:type_name -> proto.node.v1.Job
+	11, // 11: proto.node.v1.CancelJobNodeResponse.job:type_name -> proto.node.v1.Job
+	22, // 12: proto.node.v1.TrackSyscallsNodeResponse.session:type_name -> proto.node.v1.TraceSession
+	22, // 13: proto.node.v1.ListTraceSessionsNodeResponse.sessions:type_name -> proto.node.v1.TraceSession
+	27, // 14: proto.node.v1.SyscallLatency.buckets:type_name -> proto.node.v1.LatencyBucket
+	25, // 15: proto.node.v1.SyscallStats.interval:type_name -> proto.node.v1.SyscallCount
+	25, // 16: proto.node.v1.SyscallStats.total:type_name -> proto.node.v1.SyscallCount
+	26, // 17: proto.node.v1.SyscallStats.intervalLatency:type_name -> proto.node.v1.SyscallLatency
+	26, // 18: proto.node.v1.SyscallStats.totalLatency:type_name -> proto.node.v1.SyscallLatency
+	28, // 19: proto.node.v1.GetSyscallStatsNodeResponse.stats:type_name -> proto.node.v1.SyscallStats
+	28, // 20: proto.node.v1.WatchSyscallsNodeResponse.stats:type_name -> proto.node.v1.SyscallStats
+	2,  // 21: proto.node.v1.NodeService.SendServerCommand:input_type -> proto.node.v1.SendServerCommandNodeRequest
+	4,  // 22: proto.node.v1.NodeService.SendClientCommand:input_type -> proto.node.v1.SendClientCommandNodeRequest
+	18, // 23: proto.node.v1.NodeService.TrackSyscalls:input_type -> proto.node.v1.TrackSyscallsNodeRequest
+	20, // 24: proto.node.v1.NodeService.StopSyscalls:input_type -> proto.node.v1.StopSyscallsNodeRequest
+	23, // 25: proto.node.v1.NodeService.ListTraceSessions:input_type -> proto.node.v1.ListTraceSessionsNodeRequest
+	29, // 26: proto.node.v1.NodeService.GetSyscallStats:input_type -> proto.node.v1.GetSyscallStatsNodeRequest
+	31, // 27: proto.node.v1.NodeService.WatchSyscalls:input_type -> proto.node.v1.WatchSyscallsNodeRequest
+	33, // 28: proto.node.v1.NodeService.Cleanup:input_type -> proto.node.v1.CleanupNodeRequest
+	7,  // 29: proto.node.v1.NodeService.Exec:input_type -> proto.node.v1.ExecNodeRequest
+	12, // 30: proto.node.v1.NodeService.ListJobs:input_type -> proto.node.v1.ListJobsNodeRequest
+	14, // 31: proto.node.v1.NodeService.GetJob:input_type -> proto.node.v1.GetJobNodeRequest
+	16, // 32: proto.node.v1.NodeService.CancelJob:input_type -> proto.node.v1.CancelJobNodeRequest
+	3,  // 33: proto.node.v1.NodeService.SendServerCommand:output_type -> proto.node.v1.SendServerCommandNodeResponse
+	5,  // 34: proto.node.v1.NodeService.SendClientCommand:output_type -> proto.node.v1.SendClientCommandNodeResponse
+	19, // 35: proto.node.v1.NodeService.TrackSyscalls:output_type -> proto.node.v1.TrackSyscallsNodeResponse
+	21, // 36: proto.node.v1.NodeService.StopSyscalls:output_type -> proto.node.v1.StopSyscallsNodeResponse
+	24, // 37: proto.node.v1.NodeService.ListTraceSessions:output_type -> proto.node.v1.ListTraceSessionsNodeResponse
+	30, // 38: proto.node.v1.NodeService.GetSyscallStats:output_type -> proto.node.v1.GetSyscallStatsNodeResponse
+	32, // 39: proto.node.v1.NodeService.WatchSyscalls:output_type -> proto.node.v1.WatchSyscallsNodeResponse
+	34, // 40: proto.node.v1.NodeService.Cleanup:output_type -> proto.node.v1.CleanupNodeResponse
+	10, // 41: proto.node.v1.NodeService.Exec:output_type -> proto.node.v1.ExecNodeResponse
+	13, // 42: proto.node.v1.NodeService.ListJobs:output_type -> proto.node.v1.ListJobsNodeResponse
+	15, // 43: proto.node.v1.NodeService.GetJob:output_type -> proto.node.v1.GetJobNodeResponse
+	17, // 44: proto.node.v1.NodeService.CancelJob:output_type -> proto.node.v1.CancelJobNodeResponse
+	33, // [33:45] is the sub-list for method output_type
+	21, // [21:33] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_proto_node_proto_init() }
@@ -413,13 +2339,14 @@ func file_proto_node_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_node_proto_rawDesc), len(file_proto_node_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      2,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_node_proto_goTypes,
 		DependencyIndexes: file_proto_node_proto_depIdxs,
+		EnumInfos:         file_proto_node_proto_enumTypes,
 		MessageInfos:      file_proto_node_proto_msgTypes,
 	}.Build()
 	File_proto_node_proto = out.File
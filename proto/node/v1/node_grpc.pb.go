@@ -21,8 +21,16 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	NodeService_SendServerCommand_FullMethodName = "/proto.node.v1.NodeService/SendServerCommand"
 	NodeService_SendClientCommand_FullMethodName = "/proto.node.v1.NodeService/SendClientCommand"
+	NodeService_TrackSyscalls_FullMethodName     = "/proto.node.v1.NodeService/TrackSyscalls"
 	NodeService_StopSyscalls_FullMethodName      = "/proto.node.v1.NodeService/StopSyscalls"
+	NodeService_ListTraceSessions_FullMethodName = "/proto.node.v1.NodeService/ListTraceSessions"
+	NodeService_GetSyscallStats_FullMethodName   = "/proto.node.v1.NodeService/GetSyscallStats"
+	NodeService_WatchSyscalls_FullMethodName     = "/proto.node.v1.NodeService/WatchSyscalls"
 	NodeService_Cleanup_FullMethodName           = "/proto.node.v1.NodeService/Cleanup"
+	NodeService_Exec_FullMethodName              = "/proto.node.v1.NodeService/Exec"
+	NodeService_ListJobs_FullMethodName          = "/proto.node.v1.NodeService/ListJobs"
+	NodeService_GetJob_FullMethodName            = "/proto.node.v1.NodeService/GetJob"
+	NodeService_CancelJob_FullMethodName         = "/proto.node.v1.NodeService/CancelJob"
 )
 
 // NodeServiceClient is the client API for NodeService service.
@@ -31,8 +39,16 @@ const (
 type NodeServiceClient interface {
 	SendServerCommand(ctx context.Context, in *SendServerCommandNodeRequest, opts ...grpc.CallOption) (*SendServerCommandNodeResponse, error)
 	SendClientCommand(ctx context.Context, in *SendClientCommandNodeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SendClientCommandNodeResponse], error)
+	TrackSyscalls(ctx context.Context, in *TrackSyscallsNodeRequest, opts ...grpc.CallOption) (*TrackSyscallsNodeResponse, error)
 	StopSyscalls(ctx context.Context, in *StopSyscallsNodeRequest, opts ...grpc.CallOption) (*StopSyscallsNodeResponse, error)
+	ListTraceSessions(ctx context.Context, in *ListTraceSessionsNodeRequest, opts ...grpc.CallOption) (*ListTraceSessionsNodeResponse, error)
+	GetSyscallStats(ctx context.Context, in *GetSyscallStatsNodeRequest, opts ...grpc.CallOption) (*GetSyscallStatsNodeResponse, error)
+	WatchSyscalls(ctx context.Context, in *WatchSyscallsNodeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchSyscallsNodeResponse], error)
 	Cleanup(ctx context.Context, in *CleanupNodeRequest, opts ...grpc.CallOption) (*CleanupNodeResponse, error)
+	Exec(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ExecNodeRequest, ExecNodeResponse], error)
+	ListJobs(ctx context.Context, in *ListJobsNodeRequest, opts ...grpc.CallOption) (*ListJobsNodeResponse, error)
+	GetJob(ctx context.Context, in *GetJobNodeRequest, opts ...grpc.CallOption) (*GetJobNodeResponse, error)
+	CancelJob(ctx context.Context, in *CancelJobNodeRequest, opts ...grpc.CallOption) (*CancelJobNodeResponse, error)
 }
 
 type nodeServiceClient struct {
@@ -72,6 +88,16 @@ func (c *nodeServiceClient) SendClientCommand(ctx context.Context, in *SendClien
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type NodeService_SendClientCommandClient = grpc.ServerStreamingClient[SendClientCommandNodeResponse]
 
+func (c *nodeServiceClient) TrackSyscalls(ctx context.Context, in *TrackSyscallsNodeRequest, opts ...grpc.CallOption) (*TrackSyscallsNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrackSyscallsNodeResponse)
+	err := c.cc.Invoke(ctx, NodeService_TrackSyscalls_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *nodeServiceClient) StopSyscalls(ctx context.Context, in *StopSyscallsNodeRequest, opts ...grpc.CallOption) (*StopSyscallsNodeResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(StopSyscallsNodeResponse)
@@ -82,6 +108,45 @@ func (c *nodeServiceClient) StopSyscalls(ctx context.Context, in *StopSyscallsNo
 	return out, nil
 }
 
+func (c *nodeServiceClient) ListTraceSessions(ctx context.Context, in *ListTraceSessionsNodeRequest, opts ...grpc.CallOption) (*ListTraceSessionsNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTraceSessionsNodeResponse)
+	err := c.cc.Invoke(ctx, NodeService_ListTraceSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetSyscallStats(ctx context.Context, in *GetSyscallStatsNodeRequest, opts ...grpc.CallOption) (*GetSyscallStatsNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSyscallStatsNodeResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetSyscallStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) WatchSyscalls(ctx context.Context, in *WatchSyscallsNodeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchSyscallsNodeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[1], NodeService_WatchSyscalls_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchSyscallsNodeRequest, WatchSyscallsNodeResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_WatchSyscallsClient = grpc.ServerStreamingClient[WatchSyscallsNodeResponse]
+
 func (c *nodeServiceClient) Cleanup(ctx context.Context, in *CleanupNodeRequest, opts ...grpc.CallOption) (*CleanupNodeResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CleanupNodeResponse)
@@ -92,14 +157,65 @@ func (c *nodeServiceClient) Cleanup(ctx context.Context, in *CleanupNodeRequest,
 	return out, nil
 }
 
+func (c *nodeServiceClient) Exec(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ExecNodeRequest, ExecNodeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NodeService_ServiceDesc.Streams[2], NodeService_Exec_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecNodeRequest, ExecNodeResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_ExecClient = grpc.BidiStreamingClient[ExecNodeRequest, ExecNodeResponse]
+
+func (c *nodeServiceClient) ListJobs(ctx context.Context, in *ListJobsNodeRequest, opts ...grpc.CallOption) (*ListJobsNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobsNodeResponse)
+	err := c.cc.Invoke(ctx, NodeService_ListJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) GetJob(ctx context.Context, in *GetJobNodeRequest, opts ...grpc.CallOption) (*GetJobNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobNodeResponse)
+	err := c.cc.Invoke(ctx, NodeService_GetJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nodeServiceClient) CancelJob(ctx context.Context, in *CancelJobNodeRequest, opts ...grpc.CallOption) (*CancelJobNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelJobNodeResponse)
+	err := c.cc.Invoke(ctx, NodeService_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NodeServiceServer is the server API for NodeService service.
 // All implementations must embed UnimplementedNodeServiceServer
 // for forward compatibility.
 type NodeServiceServer interface {
 	SendServerCommand(context.Context, *SendServerCommandNodeRequest) (*SendServerCommandNodeResponse, error)
 	SendClientCommand(*SendClientCommandNodeRequest, grpc.ServerStreamingServer[SendClientCommandNodeResponse]) error
+	TrackSyscalls(context.Context, *TrackSyscallsNodeRequest) (*TrackSyscallsNodeResponse, error)
 	StopSyscalls(context.Context, *StopSyscallsNodeRequest) (*StopSyscallsNodeResponse, error)
+	ListTraceSessions(context.Context, *ListTraceSessionsNodeRequest) (*ListTraceSessionsNodeResponse, error)
+	GetSyscallStats(context.Context, *GetSyscallStatsNodeRequest) (*GetSyscallStatsNodeResponse, error)
+	WatchSyscalls(*WatchSyscallsNodeRequest, grpc.ServerStreamingServer[WatchSyscallsNodeResponse]) error
 	Cleanup(context.Context, *CleanupNodeRequest) (*CleanupNodeResponse, error)
+	Exec(grpc.BidiStreamingServer[ExecNodeRequest, ExecNodeResponse]) error
+	ListJobs(context.Context, *ListJobsNodeRequest) (*ListJobsNodeResponse, error)
+	GetJob(context.Context, *GetJobNodeRequest) (*GetJobNodeResponse, error)
+	CancelJob(context.Context, *CancelJobNodeRequest) (*CancelJobNodeResponse, error)
 	mustEmbedUnimplementedNodeServiceServer()
 }
 
@@ -116,12 +232,36 @@ func (UnimplementedNodeServiceServer) SendServerCommand(context.Context, *SendSe
 func (UnimplementedNodeServiceServer) SendClientCommand(*SendClientCommandNodeRequest, grpc.ServerStreamingServer[SendClientCommandNodeResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method SendClientCommand not implemented")
 }
+func (UnimplementedNodeServiceServer) TrackSyscalls(context.Context, *TrackSyscallsNodeRequest) (*TrackSyscallsNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TrackSyscalls not implemented")
+}
 func (UnimplementedNodeServiceServer) StopSyscalls(context.Context, *StopSyscallsNodeRequest) (*StopSyscallsNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopSyscalls not implemented")
 }
+func (UnimplementedNodeServiceServer) ListTraceSessions(context.Context, *ListTraceSessionsNodeRequest) (*ListTraceSessionsNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTraceSessions not implemented")
+}
+func (UnimplementedNodeServiceServer) GetSyscallStats(context.Context, *GetSyscallStatsNodeRequest) (*GetSyscallStatsNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSyscallStats not implemented")
+}
+func (UnimplementedNodeServiceServer) WatchSyscalls(*WatchSyscallsNodeRequest, grpc.ServerStreamingServer[WatchSyscallsNodeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSyscalls not implemented")
+}
 func (UnimplementedNodeServiceServer) Cleanup(context.Context, *CleanupNodeRequest) (*CleanupNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Cleanup not implemented")
 }
+func (UnimplementedNodeServiceServer) Exec(grpc.BidiStreamingServer[ExecNodeRequest, ExecNodeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Exec not implemented")
+}
+func (UnimplementedNodeServiceServer) ListJobs(context.Context, *ListJobsNodeRequest) (*ListJobsNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedNodeServiceServer) GetJob(context.Context, *GetJobNodeRequest) (*GetJobNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedNodeServiceServer) CancelJob(context.Context, *CancelJobNodeRequest) (*CancelJobNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
 func (UnimplementedNodeServiceServer) mustEmbedUnimplementedNodeServiceServer() {}
 func (UnimplementedNodeServiceServer) testEmbeddedByValue()                     {}
 
@@ -172,6 +312,24 @@ func _NodeService_SendClientCommand_Handler(srv interface{}, stream grpc.ServerS
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type NodeService_SendClientCommandServer = grpc.ServerStreamingServer[SendClientCommandNodeResponse]
 
+func _NodeService_TrackSyscalls_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrackSyscallsNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).TrackSyscalls(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_TrackSyscalls_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).TrackSyscalls(ctx, req.(*TrackSyscallsNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NodeService_StopSyscalls_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StopSyscallsNodeRequest)
 	if err := dec(in); err != nil {
@@ -190,6 +348,53 @@ func _NodeService_StopSyscalls_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_ListTraceSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTraceSessionsNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).ListTraceSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_ListTraceSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).ListTraceSessions(ctx, req.(*ListTraceSessionsNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetSyscallStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSyscallStatsNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetSyscallStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetSyscallStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetSyscallStats(ctx, req.(*GetSyscallStatsNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_WatchSyscalls_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSyscallsNodeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NodeServiceServer).WatchSyscalls(m, &grpc.GenericServerStream[WatchSyscallsNodeRequest, WatchSyscallsNodeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_WatchSyscallsServer = grpc.ServerStreamingServer[WatchSyscallsNodeResponse]
+
 func _NodeService_Cleanup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CleanupNodeRequest)
 	if err := dec(in); err != nil {
@@ -208,6 +413,67 @@ func _NodeService_Cleanup_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NodeService_Exec_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NodeServiceServer).Exec(&grpc.GenericServerStream[ExecNodeRequest, ExecNodeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NodeService_ExecServer = grpc.BidiStreamingServer[ExecNodeRequest, ExecNodeResponse]
+
+func _NodeService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).ListJobs(ctx, req.(*ListJobsNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).GetJob(ctx, req.(*GetJobNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NodeService_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NodeServiceServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NodeService_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeServiceServer).CancelJob(ctx, req.(*CancelJobNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NodeService_ServiceDesc is the grpc.ServiceDesc for NodeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -219,14 +485,38 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendServerCommand",
 			Handler:    _NodeService_SendServerCommand_Handler,
 		},
+		{
+			MethodName: "TrackSyscalls",
+			Handler:    _NodeService_TrackSyscalls_Handler,
+		},
 		{
 			MethodName: "StopSyscalls",
 			Handler:    _NodeService_StopSyscalls_Handler,
 		},
+		{
+			MethodName: "ListTraceSessions",
+			Handler:    _NodeService_ListTraceSessions_Handler,
+		},
+		{
+			MethodName: "GetSyscallStats",
+			Handler:    _NodeService_GetSyscallStats_Handler,
+		},
 		{
 			MethodName: "Cleanup",
 			Handler:    _NodeService_Cleanup_Handler,
 		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _NodeService_ListJobs_Handler,
+		},
+		{
+			MethodName: "GetJob",
+			Handler:    _NodeService_GetJob_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _NodeService_CancelJob_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -234,6 +524,17 @@ var NodeService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _NodeService_SendClientCommand_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchSyscalls",
+			Handler:       _NodeService_WatchSyscalls_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Exec",
+			Handler:       _NodeService_Exec_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/node.proto",
 }
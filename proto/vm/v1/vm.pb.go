@@ -21,13 +21,242 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type VmState int32
+
+const (
+	VmState_VM_STATE_UNSPECIFIED VmState = 0
+	VmState_VM_STATE_CREATING    VmState = 1
+	VmState_VM_STATE_RUNNING     VmState = 2
+	VmState_VM_STATE_PAUSED      VmState = 3
+	VmState_VM_STATE_STOPPING    VmState = 4
+	VmState_VM_STATE_STOPPED     VmState = 5
+	VmState_VM_STATE_FAILED      VmState = 6
+)
+
+// Enum value maps for VmState.
+var (
+	VmState_name = map[int32]string{
+		0: "VM_STATE_UNSPECIFIED",
+		1: "VM_STATE_CREATING",
+		2: "VM_STATE_RUNNING",
+		3: "VM_STATE_PAUSED",
+		4: "VM_STATE_STOPPING",
+		5: "VM_STATE_STOPPED",
+		6: "VM_STATE_FAILED",
+	}
+	VmState_value = map[string]int32{
+		"VM_STATE_UNSPECIFIED": 0,
+		"VM_STATE_CREATING":    1,
+		"VM_STATE_RUNNING":     2,
+		"VM_STATE_PAUSED":      3,
+		"VM_STATE_STOPPING":    4,
+		"VM_STATE_STOPPED":     5,
+		"VM_STATE_FAILED":      6,
+	}
+)
+
+func (x VmState) Enum() *VmState {
+	p := new(VmState)
+	*p = x
+	return p
+}
+
+func (x VmState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VmState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vm_proto_enumTypes[0].Descriptor()
+}
+
+func (VmState) Type() protoreflect.EnumType {
+	return &file_proto_vm_proto_enumTypes[0]
+}
+
+func (x VmState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VmState.Descriptor instead.
+func (VmState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{0}
+}
+
+type OutputStream int32
+
+const (
+	OutputStream_OUTPUT_STREAM_UNSPECIFIED OutputStream = 0
+	OutputStream_OUTPUT_STREAM_STDOUT      OutputStream = 1
+	OutputStream_OUTPUT_STREAM_STDERR      OutputStream = 2
+)
+
+// Enum value maps for OutputStream.
+var (
+	OutputStream_name = map[int32]string{
+		0: "OUTPUT_STREAM_UNSPECIFIED",
+		1: "OUTPUT_STREAM_STDOUT",
+		2: "OUTPUT_STREAM_STDERR",
+	}
+	OutputStream_value = map[string]int32{
+		"OUTPUT_STREAM_UNSPECIFIED": 0,
+		"OUTPUT_STREAM_STDOUT":      1,
+		"OUTPUT_STREAM_STDERR":      2,
+	}
+)
+
+func (x OutputStream) Enum() *OutputStream {
+	p := new(OutputStream)
+	*p = x
+	return p
+}
+
+func (x OutputStream) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputStream) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vm_proto_enumTypes[1].Descriptor()
+}
+
+func (OutputStream) Type() protoreflect.EnumType {
+	return &file_proto_vm_proto_enumTypes[1]
+}
+
+func (x OutputStream) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputStream.Descriptor instead.
+func (OutputStream) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{1}
+}
+
+type JobState int32
+
+const (
+	JobState_JOB_STATE_UNSPECIFIED JobState = 0
+	JobState_JOB_STATE_RUNNING     JobState = 1
+	JobState_JOB_STATE_EXITED      JobState = 2 // on its own, whatever its exit code
+	JobState_JOB_STATE_CANCELLED   JobState = 3 // by CancelJob, Cleanup or when the runner stops
+	JobState_JOB_STATE_TIMED_OUT   JobState = 4
+)
+
+// Enum value maps for JobState.
+var (
+	JobState_name = map[int32]string{
+		0: "JOB_STATE_UNSPECIFIED",
+		1: "JOB_STATE_RUNNING",
+		2: "JOB_STATE_EXITED",
+		3: "JOB_STATE_CANCELLED",
+		4: "JOB_STATE_TIMED_OUT",
+	}
+	JobState_value = map[string]int32{
+		"JOB_STATE_UNSPECIFIED": 0,
+		"JOB_STATE_RUNNING":     1,
+		"JOB_STATE_EXITED":      2,
+		"JOB_STATE_CANCELLED":   3,
+		"JOB_STATE_TIMED_OUT":   4,
+	}
+)
+
+func (x JobState) Enum() *JobState {
+	p := new(JobState)
+	*p = x
+	return p
+}
+
+func (x JobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vm_proto_enumTypes[2].Descriptor()
+}
+
+func (JobState) Type() protoreflect.EnumType {
+	return &file_proto_vm_proto_enumTypes[2]
+}
+
+func (x JobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobState.Descriptor instead.
+func (JobState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{2}
+}
+
+type SnapshotType int32
+
+const (
+	SnapshotType_SNAPSHOT_TYPE_FULL SnapshotType = 0
+	SnapshotType_SNAPSHOT_TYPE_DIFF SnapshotType = 1 // only the pages dirtied since the VM's previous snapshot
+)
+
+// Enum value maps for SnapshotType.
+var (
+	SnapshotType_name = map[int32]string{
+		0: "SNAPSHOT_TYPE_FULL",
+		1: "SNAPSHOT_TYPE_DIFF",
+	}
+	SnapshotType_value = map[string]int32{
+		"SNAPSHOT_TYPE_FULL": 0,
+		"SNAPSHOT_TYPE_DIFF": 1,
+	}
+)
+
+func (x SnapshotType) Enum() *SnapshotType {
+	p := new(SnapshotType)
+	*p = x
+	return p
+}
+
+func (x SnapshotType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SnapshotType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vm_proto_enumTypes[3].Descriptor()
+}
+
+func (SnapshotType) Type() protoreflect.EnumType {
+	return &file_proto_vm_proto_enumTypes[3]
+}
+
+func (x SnapshotType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SnapshotType.Descriptor instead.
+func (SnapshotType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{3}
+}
+
 type Vm struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
-	KernelPath    string                 `protobuf:"bytes,3,opt,name=kernelPath,proto3" json:"kernelPath,omitempty"`
-	RootfsPath    string                 `protobuf:"bytes,4,opt,name=rootfsPath,proto3" json:"rootfsPath,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Ip                   string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	KernelPath           string                 `protobuf:"bytes,3,opt,name=kernelPath,proto3" json:"kernelPath,omitempty"`
+	RootfsPath           string                 `protobuf:"bytes,4,opt,name=rootfsPath,proto3" json:"rootfsPath,omitempty"`
+	VcpuCount            int64                  `protobuf:"varint,5,opt,name=vcpuCount,proto3" json:"vcpuCount,omitempty"`
+	MemSizeMib           int64                  `protobuf:"varint,6,opt,name=memSizeMib,proto3" json:"memSizeMib,omitempty"`
+	State                VmState                `protobuf:"varint,7,opt,name=state,proto3,enum=proto.vm.v1.VmState" json:"state,omitempty"`
+	Error                string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"` // why the VM failed
+	Pid                  int64                  `protobuf:"varint,9,opt,name=pid,proto3" json:"pid,omitempty"`    // firecracker process
+	SocketPath           string                 `protobuf:"bytes,10,opt,name=socketPath,proto3" json:"socketPath,omitempty"`
+	VsockPath            string                 `protobuf:"bytes,11,opt,name=vsockPath,proto3" json:"vsockPath,omitempty"`
+	VsockCid             uint32                 `protobuf:"varint,12,opt,name=vsockCid,proto3" json:"vsockCid,omitempty"`
+	TapName              string                 `protobuf:"bytes,13,opt,name=tapName,proto3" json:"tapName,omitempty"`
+	MacAddress           string                 `protobuf:"bytes,14,opt,name=macAddress,proto3" json:"macAddress,omitempty"`
+	GatewayIP            string                 `protobuf:"bytes,15,opt,name=gatewayIP,proto3" json:"gatewayIP,omitempty"`
+	CreatedAt            int64                  `protobuf:"varint,16,opt,name=createdAt,proto3" json:"createdAt,omitempty"`       // unix millis
+	StartedAt            int64                  `protobuf:"varint,17,opt,name=startedAt,proto3" json:"startedAt,omitempty"`       // unix millis, 0 if never started
+	StoppedAt            int64                  `protobuf:"varint,18,opt,name=stoppedAt,proto3" json:"stoppedAt,omitempty"`       // unix millis, 0 if not stopped
+	PrefixLength         int32                  `protobuf:"varint,19,opt,name=prefixLength,proto3" json:"prefixLength,omitempty"` // of the guest subnet, e.g. 24 for 192.168.100.0/24
+	NetworkRxRateLimiter *RateLimiter           `protobuf:"bytes,20,opt,name=networkRxRateLimiter,proto3" json:"networkRxRateLimiter,omitempty"`
+	NetworkTxRateLimiter *RateLimiter           `protobuf:"bytes,21,opt,name=networkTxRateLimiter,proto3" json:"networkTxRateLimiter,omitempty"`
+	DriveRateLimiter     *RateLimiter           `protobuf:"bytes,22,opt,name=driveRateLimiter,proto3" json:"driveRateLimiter,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *Vm) Reset() {
@@ -81,30 +310,157 @@ func (x *Vm) GetRootfsPath() string {
 	return ""
 }
 
-type CreateVmRequest struct {
+func (x *Vm) GetVcpuCount() int64 {
+	if x != nil {
+		return x.VcpuCount
+	}
+	return 0
+}
+
+func (x *Vm) GetMemSizeMib() int64 {
+	if x != nil {
+		return x.MemSizeMib
+	}
+	return 0
+}
+
+func (x *Vm) GetState() VmState {
+	if x != nil {
+		return x.State
+	}
+	return VmState_VM_STATE_UNSPECIFIED
+}
+
+func (x *Vm) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Vm) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Vm) GetSocketPath() string {
+	if x != nil {
+		return x.SocketPath
+	}
+	return ""
+}
+
+func (x *Vm) GetVsockPath() string {
+	if x != nil {
+		return x.VsockPath
+	}
+	return ""
+}
+
+func (x *Vm) GetVsockCid() uint32 {
+	if x != nil {
+		return x.VsockCid
+	}
+	return 0
+}
+
+func (x *Vm) GetTapName() string {
+	if x != nil {
+		return x.TapName
+	}
+	return ""
+}
+
+func (x *Vm) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *Vm) GetGatewayIP() string {
+	if x != nil {
+		return x.GatewayIP
+	}
+	return ""
+}
+
+func (x *Vm) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Vm) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *Vm) GetStoppedAt() int64 {
+	if x != nil {
+		return x.StoppedAt
+	}
+	return 0
+}
+
+func (x *Vm) GetPrefixLength() int32 {
+	if x != nil {
+		return x.PrefixLength
+	}
+	return 0
+}
+
+func (x *Vm) GetNetworkRxRateLimiter() *RateLimiter {
+	if x != nil {
+		return x.NetworkRxRateLimiter
+	}
+	return nil
+}
+
+func (x *Vm) GetNetworkTxRateLimiter() *RateLimiter {
+	if x != nil {
+		return x.NetworkTxRateLimiter
+	}
+	return nil
+}
+
+func (x *Vm) GetDriveRateLimiter() *RateLimiter {
+	if x != nil {
+		return x.DriveRateLimiter
+	}
+	return nil
+}
+
+// TokenBucket holds size tokens (bytes or operations) and refills completely
+// every refillTimeMs.
+type TokenBucket struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
-	KernelPath    string                 `protobuf:"bytes,2,opt,name=kernelPath,proto3" json:"kernelPath,omitempty"`
-	RootfsPath    string                 `protobuf:"bytes,3,opt,name=rootfsPath,proto3" json:"rootfsPath,omitempty"`
-	GatewayIP     string                 `protobuf:"bytes,4,opt,name=gatewayIP,proto3" json:"gatewayIP,omitempty"`
+	Size          int64                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	OneTimeBurst  int64                  `protobuf:"varint,2,opt,name=oneTimeBurst,proto3" json:"oneTimeBurst,omitempty"` // extra initial allowance
+	RefillTimeMs  int64                  `protobuf:"varint,3,opt,name=refillTimeMs,proto3" json:"refillTimeMs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateVmRequest) Reset() {
-	*x = CreateVmRequest{}
+func (x *TokenBucket) Reset() {
+	*x = TokenBucket{}
 	mi := &file_proto_vm_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateVmRequest) String() string {
+func (x *TokenBucket) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateVmRequest) ProtoMessage() {}
+func (*TokenBucket) ProtoMessage() {}
 
-func (x *CreateVmRequest) ProtoReflect() protoreflect.Message {
+func (x *TokenBucket) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_vm_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -116,61 +472,3021 @@ func (x *CreateVmRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateVmRequest.ProtoReflect.Descriptor instead.
-func (*CreateVmRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use TokenBucket.ProtoReflect.Descriptor instead.
+func (*TokenBucket) Descriptor() ([]byte, []int) {
 	return file_proto_vm_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *CreateVmRequest) GetIp() string {
-	if x != nil {
-		return x.Ip
-	}
-	return ""
-}
-
-func (x *CreateVmRequest) GetKernelPath() string {
+func (x *TokenBucket) GetSize() int64 {
 	if x != nil {
-		return x.KernelPath
+		return x.Size
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateVmRequest) GetRootfsPath() string {
+func (x *TokenBucket) GetOneTimeBurst() int64 {
 	if x != nil {
-		return x.RootfsPath
+		return x.OneTimeBurst
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateVmRequest) GetGatewayIP() string {
+func (x *TokenBucket) GetRefillTimeMs() int64 {
 	if x != nil {
-		return x.GatewayIP
+		return x.RefillTimeMs
 	}
-	return ""
+	return 0
 }
 
-type CreateVmResponse struct {
+// RateLimiter is a firecracker device rate limiter. An unset bucket means no limit.
+type RateLimiter struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Vm            *Vm                    `protobuf:"bytes,1,opt,name=vm,proto3" json:"vm,omitempty"`
+	Bandwidth     *TokenBucket           `protobuf:"bytes,1,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"` // bytes
+	Ops           *TokenBucket           `protobuf:"bytes,2,opt,name=ops,proto3" json:"ops,omitempty"`             // packets or I/O operations
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateVmResponse) Reset() {
-	*x = CreateVmResponse{}
+func (x *RateLimiter) Reset() {
+	*x = RateLimiter{}
+	mi := &file_proto_vm_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimiter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimiter) ProtoMessage() {}
+
+func (x *RateLimiter) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_vm_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimiter.ProtoReflect.Descriptor instead.
+func (*RateLimiter) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RateLimiter) GetBandwidth() *TokenBucket {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return nil
+}
+
+func (x *RateLimiter) GetOps() *TokenBucket {
+	if x != nil {
+		return x.Ops
+	}
+	return nil
+}
+
+type CreateVmRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Ip                   string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"` // optional, the next free address of the node's subnet if empty
+	KernelPath           string                 `protobuf:"bytes,2,opt,name=kernelPath,proto3" json:"kernelPath,omitempty"`
+	RootfsPath           string                 `protobuf:"bytes,3,opt,name=rootfsPath,proto3" json:"rootfsPath,omitempty"`
+	GatewayIP            string                 `protobuf:"bytes,4,opt,name=gatewayIP,proto3" json:"gatewayIP,omitempty"`              // default the first address of the node's subnet
+	TrackDirtyPages      bool                   `protobuf:"varint,5,opt,name=trackDirtyPages,proto3" json:"trackDirtyPages,omitempty"` // required for diff snapshots
+	VcpuCount            int64                  `protobuf:"varint,6,opt,name=vcpuCount,proto3" json:"vcpuCount,omitempty"`             // default 1
+	MemSizeMib           int64                  `protobuf:"varint,7,opt,name=memSizeMib,proto3" json:"memSizeMib,omitempty"`           // default 512
+	Smt                  bool                   `protobuf:"varint,8,opt,name=smt,proto3" json:"smt,omitempty"`
+	CpuTemplate          string                 `protobuf:"bytes,9,opt,name=cpuTemplate,proto3" json:"cpuTemplate,omitempty"`                    // "C3" or "T2", empty for none
+	KernelArgs           string                 `protobuf:"bytes,10,opt,name=kernelArgs,proto3" json:"kernelArgs,omitempty"`                     // appended to the default boot args
+	Nameservers          []string               `protobuf:"bytes,11,rep,name=nameservers,proto3" json:"nameservers,omitempty"`                   // default 8.8.8.8, 8.8.4.4
+	NetworkRxRateLimiter *RateLimiter           `protobuf:"bytes,12,opt,name=networkRxRateLimiter,proto3" json:"networkRxRateLimiter,omitempty"` // traffic to the guest
+	NetworkTxRateLimiter *RateLimiter           `protobuf:"bytes,13,opt,name=networkTxRateLimiter,proto3" json:"networkTxRateLimiter,omitempty"` // traffic from the guest
+	DriveRateLimiter     *RateLimiter           `protobuf:"bytes,14,opt,name=driveRateLimiter,proto3" json:"driveRateLimiter,omitempty"`         // the rootfs drive
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *CreateVmRequest) Reset() {
+	*x = CreateVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVmRequest) ProtoMessage() {}
+
+func (x *CreateVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVmRequest.ProtoReflect.Descriptor instead.
+func (*CreateVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *CreateVmRequest) GetKernelPath() string {
+	if x != nil {
+		return x.KernelPath
+	}
+	return ""
+}
+
+func (x *CreateVmRequest) GetRootfsPath() string {
+	if x != nil {
+		return x.RootfsPath
+	}
+	return ""
+}
+
+func (x *CreateVmRequest) GetGatewayIP() string {
+	if x != nil {
+		return x.GatewayIP
+	}
+	return ""
+}
+
+func (x *CreateVmRequest) GetTrackDirtyPages() bool {
+	if x != nil {
+		return x.TrackDirtyPages
+	}
+	return false
+}
+
+func (x *CreateVmRequest) GetVcpuCount() int64 {
+	if x != nil {
+		return x.VcpuCount
+	}
+	return 0
+}
+
+func (x *CreateVmRequest) GetMemSizeMib() int64 {
+	if x != nil {
+		return x.MemSizeMib
+	}
+	return 0
+}
+
+func (x *CreateVmRequest) GetSmt() bool {
+	if x != nil {
+		return x.Smt
+	}
+	return false
+}
+
+func (x *CreateVmRequest) GetCpuTemplate() string {
+	if x != nil {
+		return x.CpuTemplate
+	}
+	return ""
+}
+
+func (x *CreateVmRequest) GetKernelArgs() string {
+	if x != nil {
+		return x.KernelArgs
+	}
+	return ""
+}
+
+func (x *CreateVmRequest) GetNameservers() []string {
+	if x != nil {
+		return x.Nameservers
+	}
+	return nil
+}
+
+func (x *CreateVmRequest) GetNetworkRxRateLimiter() *RateLimiter {
+	if x != nil {
+		return x.NetworkRxRateLimiter
+	}
+	return nil
+}
+
+func (x *CreateVmRequest) GetNetworkTxRateLimiter() *RateLimiter {
+	if x != nil {
+		return x.NetworkTxRateLimiter
+	}
+	return nil
+}
+
+func (x *CreateVmRequest) GetDriveRateLimiter() *RateLimiter {
+	if x != nil {
+		return x.DriveRateLimiter
+	}
+	return nil
+}
+
+type CreateVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vm            *Vm                    `protobuf:"bytes,1,opt,name=vm,proto3" json:"vm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVmResponse) Reset() {
+	*x = CreateVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVmResponse) ProtoMessage() {}
+
+func (x *CreateVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVmResponse.ProtoReflect.Descriptor instead.
+func (*CreateVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateVmResponse) GetVm() *Vm {
+	if x != nil {
+		return x.Vm
+	}
+	return nil
+}
+
+type SendServerCommandVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Command       string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	Wait          bool                   `protobuf:"varint,3,opt,name=wait,proto3" json:"wait,omitempty"`
+	TimeoutMs     int64                  `protobuf:"varint,4,opt,name=timeoutMs,proto3" json:"timeoutMs,omitempty"` // optional, the command is killed after it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendServerCommandVmRequest) Reset() {
+	*x = SendServerCommandVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendServerCommandVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendServerCommandVmRequest) ProtoMessage() {}
+
+func (x *SendServerCommandVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendServerCommandVmRequest.ProtoReflect.Descriptor instead.
+func (*SendServerCommandVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SendServerCommandVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *SendServerCommandVmRequest) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *SendServerCommandVmRequest) GetWait() bool {
+	if x != nil {
+		return x.Wait
+	}
+	return false
+}
+
+func (x *SendServerCommandVmRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type SendServerCommandVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Output        string                 `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	JobId         string                 `protobuf:"bytes,2,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendServerCommandVmResponse) Reset() {
+	*x = SendServerCommandVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendServerCommandVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendServerCommandVmResponse) ProtoMessage() {}
+
+func (x *SendServerCommandVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendServerCommandVmResponse.ProtoReflect.Descriptor instead.
+func (*SendServerCommandVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SendServerCommandVmResponse) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *SendServerCommandVmResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type SendClientCommandVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Command       string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	TimeoutMs     int64                  `protobuf:"varint,3,opt,name=timeoutMs,proto3" json:"timeoutMs,omitempty"` // optional, the command is killed after it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendClientCommandVmRequest) Reset() {
+	*x = SendClientCommandVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendClientCommandVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendClientCommandVmRequest) ProtoMessage() {}
+
+func (x *SendClientCommandVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendClientCommandVmRequest.ProtoReflect.Descriptor instead.
+func (*SendClientCommandVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SendClientCommandVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *SendClientCommandVmRequest) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *SendClientCommandVmRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+// Every message but the last is a line of output; the last one only carries
+// the exit.
+type SendClientCommandVmResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Output string                 `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"` // without the newline
+	// always stdout for guest agents that only speak the line protocol, which
+	// merge stderr into it
+	Stream        OutputStream `protobuf:"varint,2,opt,name=stream,proto3,enum=proto.vm.v1.OutputStream" json:"stream,omitempty"`
+	Exit          *CommandExit `protobuf:"bytes,3,opt,name=exit,proto3" json:"exit,omitempty"`
+	JobId         string       `protobuf:"bytes,4,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendClientCommandVmResponse) Reset() {
+	*x = SendClientCommandVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendClientCommandVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendClientCommandVmResponse) ProtoMessage() {}
+
+func (x *SendClientCommandVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendClientCommandVmResponse.ProtoReflect.Descriptor instead.
+func (*SendClientCommandVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SendClientCommandVmResponse) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *SendClientCommandVmResponse) GetStream() OutputStream {
+	if x != nil {
+		return x.Stream
+	}
+	return OutputStream_OUTPUT_STREAM_UNSPECIFIED
+}
+
+func (x *SendClientCommandVmResponse) GetExit() *CommandExit {
+	if x != nil {
+		return x.Exit
+	}
+	return nil
+}
+
+func (x *SendClientCommandVmResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type CommandExit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExitCode      int32                  `protobuf:"varint,1,opt,name=exitCode,proto3" json:"exitCode,omitempty"` // -1 if the command was killed or could not run
+	DurationMs    int64                  `protobuf:"varint,2,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	Killed        bool                   `protobuf:"varint,3,opt,name=killed,proto3" json:"killed,omitempty"` // stopped by a signal, or cancelled before it exited
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`    // why the command did not run to completion, if it did not
+	Signal        string                 `protobuf:"bytes,5,opt,name=signal,proto3" json:"signal,omitempty"`  // the signal that killed the command, e.g. "killed"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandExit) Reset() {
+	*x = CommandExit{}
+	mi := &file_proto_vm_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandExit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandExit) ProtoMessage() {}
+
+func (x *CommandExit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandExit.ProtoReflect.Descriptor instead.
+func (*CommandExit) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CommandExit) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *CommandExit) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *CommandExit) GetKilled() bool {
+	if x != nil {
+		return x.Killed
+	}
+	return false
+}
+
+func (x *CommandExit) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CommandExit) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+// The first request starts the command; later ones write to its stdin,
+// resize its terminal or signal it. Ending the call kills the command, but
+// closing the sending side does not.
+type ExecVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *ExecVmStart           `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Stdin         []byte                 `protobuf:"bytes,2,opt,name=stdin,proto3" json:"stdin,omitempty"`
+	CloseStdin    bool                   `protobuf:"varint,3,opt,name=closeStdin,proto3" json:"closeStdin,omitempty"` // after stdin; types ^D in a terminal
+	Resize        *TerminalSize          `protobuf:"bytes,4,opt,name=resize,proto3" json:"resize,omitempty"`
+	Signal        int32                  `protobuf:"varint,5,opt,name=signal,proto3" json:"signal,omitempty"` // a Linux signal number, e.g. 15 for SIGTERM
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecVmRequest) Reset() {
+	*x = ExecVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecVmRequest) ProtoMessage() {}
+
+func (x *ExecVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecVmRequest.ProtoReflect.Descriptor instead.
+func (*ExecVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExecVmRequest) GetStart() *ExecVmStart {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *ExecVmRequest) GetStdin() []byte {
+	if x != nil {
+		return x.Stdin
+	}
+	return nil
+}
+
+func (x *ExecVmRequest) GetCloseStdin() bool {
+	if x != nil {
+		return x.CloseStdin
+	}
+	return false
+}
+
+func (x *ExecVmRequest) GetResize() *TerminalSize {
+	if x != nil {
+		return x.Resize
+	}
+	return nil
+}
+
+func (x *ExecVmRequest) GetSignal() int32 {
+	if x != nil {
+		return x.Signal
+	}
+	return 0
+}
+
+type ExecVmStart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Argv          []string               `protobuf:"bytes,2,rep,name=argv,proto3" json:"argv,omitempty"`
+	Env           []string               `protobuf:"bytes,3,rep,name=env,proto3" json:"env,omitempty"`              // KEY=value, added to the environment of the guest agent
+	Cwd           string                 `protobuf:"bytes,4,opt,name=cwd,proto3" json:"cwd,omitempty"`              // optional, the directory of the guest agent if empty
+	Tty           bool                   `protobuf:"varint,5,opt,name=tty,proto3" json:"tty,omitempty"`             // runs the command in a terminal, which merges stderr into stdout
+	Size          *TerminalSize          `protobuf:"bytes,6,opt,name=size,proto3" json:"size,omitempty"`            // the initial size of the terminal
+	Stdin         bool                   `protobuf:"varint,7,opt,name=stdin,proto3" json:"stdin,omitempty"`         // keeps stdin open for the stdin of later requests
+	TimeoutMs     int64                  `protobuf:"varint,8,opt,name=timeoutMs,proto3" json:"timeoutMs,omitempty"` // optional, the command is killed after it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecVmStart) Reset() {
+	*x = ExecVmStart{}
+	mi := &file_proto_vm_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecVmStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecVmStart) ProtoMessage() {}
+
+func (x *ExecVmStart) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecVmStart.ProtoReflect.Descriptor instead.
+func (*ExecVmStart) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExecVmStart) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *ExecVmStart) GetArgv() []string {
+	if x != nil {
+		return x.Argv
+	}
+	return nil
+}
+
+func (x *ExecVmStart) GetEnv() []string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ExecVmStart) GetCwd() string {
+	if x != nil {
+		return x.Cwd
+	}
+	return ""
+}
+
+func (x *ExecVmStart) GetTty() bool {
+	if x != nil {
+		return x.Tty
+	}
+	return false
+}
+
+func (x *ExecVmStart) GetSize() *TerminalSize {
+	if x != nil {
+		return x.Size
+	}
+	return nil
+}
+
+func (x *ExecVmStart) GetStdin() bool {
+	if x != nil {
+		return x.Stdin
+	}
+	return false
+}
+
+func (x *ExecVmStart) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type TerminalSize struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          uint32                 `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	Cols          uint32                 `protobuf:"varint,2,opt,name=cols,proto3" json:"cols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TerminalSize) Reset() {
+	*x = TerminalSize{}
+	mi := &file_proto_vm_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TerminalSize) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TerminalSize) ProtoMessage() {}
+
+func (x *TerminalSize) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TerminalSize.ProtoReflect.Descriptor instead.
+func (*TerminalSize) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TerminalSize) GetRows() uint32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *TerminalSize) GetCols() uint32 {
+	if x != nil {
+		return x.Cols
+	}
+	return 0
+}
+
+// The first response carries the PID and the job ID, the following ones the
+// output as it is written, and the last one the exit.
+type ExecVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pid           int64                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	Stream        OutputStream           `protobuf:"varint,2,opt,name=stream,proto3,enum=proto.vm.v1.OutputStream" json:"stream,omitempty"`
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Exit          *CommandExit           `protobuf:"bytes,4,opt,name=exit,proto3" json:"exit,omitempty"`
+	JobId         string                 `protobuf:"bytes,5,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecVmResponse) Reset() {
+	*x = ExecVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecVmResponse) ProtoMessage() {}
+
+func (x *ExecVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecVmResponse.ProtoReflect.Descriptor instead.
+func (*ExecVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ExecVmResponse) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *ExecVmResponse) GetStream() OutputStream {
+	if x != nil {
+		return x.Stream
+	}
+	return OutputStream_OUTPUT_STREAM_UNSPECIFIED
+}
+
+func (x *ExecVmResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExecVmResponse) GetExit() *CommandExit {
+	if x != nil {
+		return x.Exit
+	}
+	return nil
+}
+
+func (x *ExecVmResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// A command started by SendServerCommand, SendClientCommand or Exec.
+type Job struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Ip            string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"` // of the VM the command runs on
+	Command       string                 `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	Server        bool                   `protobuf:"varint,4,opt,name=server,proto3" json:"server,omitempty"`       // started by SendServerCommand
+	Pid           int64                  `protobuf:"varint,5,opt,name=pid,proto3" json:"pid,omitempty"`             // 0 if unknown
+	TimeoutMs     int64                  `protobuf:"varint,6,opt,name=timeoutMs,proto3" json:"timeoutMs,omitempty"` // 0 if the command may run forever
+	LogPath       string                 `protobuf:"bytes,7,opt,name=logPath,proto3" json:"logPath,omitempty"`      // empty for Exec
+	State         JobState               `protobuf:"varint,8,opt,name=state,proto3,enum=proto.vm.v1.JobState" json:"state,omitempty"`
+	StartedAt     int64                  `protobuf:"varint,9,opt,name=startedAt,proto3" json:"startedAt,omitempty"` // unix millis
+	EndedAt       int64                  `protobuf:"varint,10,opt,name=endedAt,proto3" json:"endedAt,omitempty"`    // unix millis, 0 while running
+	Exit          *CommandExit           `protobuf:"bytes,11,opt,name=exit,proto3" json:"exit,omitempty"`           // once the command ended
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_proto_vm_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Job) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *Job) GetServer() bool {
+	if x != nil {
+		return x.Server
+	}
+	return false
+}
+
+func (x *Job) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Job) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *Job) GetLogPath() string {
+	if x != nil {
+		return x.LogPath
+	}
+	return ""
+}
+
+func (x *Job) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *Job) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *Job) GetEndedAt() int64 {
+	if x != nil {
+		return x.EndedAt
+	}
+	return 0
+}
+
+func (x *Job) GetExit() *CommandExit {
+	if x != nil {
+		return x.Exit
+	}
+	return nil
+}
+
+type ListJobsVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"` // optional, the jobs of every VM if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsVmRequest) Reset() {
+	*x = ListJobsVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsVmRequest) ProtoMessage() {}
+
+func (x *ListJobsVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsVmRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListJobsVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type ListJobsVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*Job                 `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsVmResponse) Reset() {
+	*x = ListJobsVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsVmResponse) ProtoMessage() {}
+
+func (x *ListJobsVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsVmResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListJobsVmResponse) GetJobs() []*Job {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type GetJobVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobVmRequest) Reset() {
+	*x = GetJobVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobVmRequest) ProtoMessage() {}
+
+func (x *GetJobVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobVmRequest.ProtoReflect.Descriptor instead.
+func (*GetJobVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetJobVmRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetJobVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *Job                   `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobVmResponse) Reset() {
+	*x = GetJobVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobVmResponse) ProtoMessage() {}
+
+func (x *GetJobVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobVmResponse.ProtoReflect.Descriptor instead.
+func (*GetJobVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetJobVmResponse) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+// Cancelling kills the command of the job and returns once it ended.
+// Cancelling an ended job returns it.
+type CancelJobVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobVmRequest) Reset() {
+	*x = CancelJobVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobVmRequest) ProtoMessage() {}
+
+func (x *CancelJobVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobVmRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CancelJobVmRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelJobVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *Job                   `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobVmResponse) Reset() {
+	*x = CancelJobVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobVmResponse) ProtoMessage() {}
+
+func (x *CancelJobVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobVmResponse.ProtoReflect.Descriptor instead.
+func (*CancelJobVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CancelJobVmResponse) GetJob() *Job {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+// The first request names the file; later ones carry its content in order,
+// from the offset of the first response. Closing the sending side ends the
+// content, ending the call keeps what the guest received for a later call
+// with the same file to resume.
+type PutFileVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *PutFileVmStart        `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // of data in the file
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutFileVmRequest) Reset() {
+	*x = PutFileVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutFileVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutFileVmRequest) ProtoMessage() {}
+
+func (x *PutFileVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutFileVmRequest.ProtoReflect.Descriptor instead.
+func (*PutFileVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PutFileVmRequest) GetStart() *PutFileVmStart {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *PutFileVmRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *PutFileVmRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type PutFileVmStart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`  // absolute path in the guest, missing directories are created
+	Mode          uint32                 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"` // permission bits, e.g. 0644
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256        []byte                 `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"` // of the whole content, checked before the file is moved into place
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutFileVmStart) Reset() {
+	*x = PutFileVmStart{}
+	mi := &file_proto_vm_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutFileVmStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutFileVmStart) ProtoMessage() {}
+
+func (x *PutFileVmStart) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutFileVmStart.ProtoReflect.Descriptor instead.
+func (*PutFileVmStart) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PutFileVmStart) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *PutFileVmStart) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PutFileVmStart) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *PutFileVmStart) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PutFileVmStart) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+// The first response carries the offset to send the content from, the last
+// one the file as it was stored.
+type PutFileVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Offset        int64                  `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	File          *FileInfo              `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutFileVmResponse) Reset() {
+	*x = PutFileVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutFileVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutFileVmResponse) ProtoMessage() {}
+
+func (x *PutFileVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutFileVmResponse.ProtoReflect.Descriptor instead.
+func (*PutFileVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PutFileVmResponse) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *PutFileVmResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+type FileInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Mode          uint32                 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"` // permission bits
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256        []byte                 `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"` // of the whole content
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileInfo) Reset() {
+	*x = FileInfo{}
+	mi := &file_proto_vm_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileInfo) ProtoMessage() {}
+
+func (x *FileInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileInfo.ProtoReflect.Descriptor instead.
+func (*FileInfo) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *FileInfo) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileInfo) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *FileInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileInfo) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+type GetFileVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"` // optional, resumes an earlier call from there
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFileVmRequest) Reset() {
+	*x = GetFileVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFileVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileVmRequest) ProtoMessage() {}
+
+func (x *GetFileVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileVmRequest.ProtoReflect.Descriptor instead.
+func (*GetFileVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetFileVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *GetFileVmRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetFileVmRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// The first response describes the whole file, the following ones carry its
+// content from the requested offset in order. The content is complete once
+// the call ends without an error; check its checksum if it was resumed.
+type GetFileVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileInfo              `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // of data in the file
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFileVmResponse) Reset() {
+	*x = GetFileVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFileVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileVmResponse) ProtoMessage() {}
+
+func (x *GetFileVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileVmResponse.ProtoReflect.Descriptor instead.
+func (*GetFileVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetFileVmResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+func (x *GetFileVmResponse) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetFileVmResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type TrackSyscallsVmRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ips   []string               `protobuf:"bytes,1,rep,name=ips,proto3" json:"ips,omitempty"` // optional, every running or paused VM if empty
+	// optional allowlist of syscall names and the families "network",
+	// "polling", "file" and "memory", every syscall if empty
+	Syscalls []string `protobuf:"bytes,2,rep,name=syscalls,proto3" json:"syscalls,omitempty"`
+	// also pair every syscall's enter and exit to build latency histograms
+	Latency       bool `protobuf:"varint,3,opt,name=latency,proto3" json:"latency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackSyscallsVmRequest) Reset() {
+	*x = TrackSyscallsVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackSyscallsVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackSyscallsVmRequest) ProtoMessage() {}
+
+func (x *TrackSyscallsVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackSyscallsVmRequest.ProtoReflect.Descriptor instead.
+func (*TrackSyscallsVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TrackSyscallsVmRequest) GetIps() []string {
+	if x != nil {
+		return x.Ips
+	}
+	return nil
+}
+
+func (x *TrackSyscallsVmRequest) GetSyscalls() []string {
+	if x != nil {
+		return x.Syscalls
+	}
+	return nil
+}
+
+func (x *TrackSyscallsVmRequest) GetLatency() bool {
+	if x != nil {
+		return x.Latency
+	}
+	return false
+}
+
+type TrackSyscallsVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *TraceSession          `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackSyscallsVmResponse) Reset() {
+	*x = TrackSyscallsVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackSyscallsVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackSyscallsVmResponse) ProtoMessage() {}
+
+func (x *TrackSyscallsVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackSyscallsVmResponse.ProtoReflect.Descriptor instead.
+func (*TrackSyscallsVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TrackSyscallsVmResponse) GetSession() *TraceSession {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+type StopSyscallsVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // optional, every session if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopSyscallsVmRequest) Reset() {
+	*x = StopSyscallsVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopSyscallsVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopSyscallsVmRequest) ProtoMessage() {}
+
+func (x *StopSyscallsVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopSyscallsVmRequest.ProtoReflect.Descriptor instead.
+func (*StopSyscallsVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *StopSyscallsVmRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type StopSyscallsVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopSyscallsVmResponse) Reset() {
+	*x = StopSyscallsVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopSyscallsVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopSyscallsVmResponse) ProtoMessage() {}
+
+func (x *StopSyscallsVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopSyscallsVmResponse.ProtoReflect.Descriptor instead.
+func (*StopSyscallsVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{30}
+}
+
+type TraceTarget struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Pid           int64                  `protobuf:"varint,2,opt,name=pid,proto3" json:"pid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceTarget) Reset() {
+	*x = TraceTarget{}
+	mi := &file_proto_vm_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceTarget) ProtoMessage() {}
+
+func (x *TraceTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceTarget.ProtoReflect.Descriptor instead.
+func (*TraceTarget) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TraceTarget) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *TraceTarget) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+// TraceSession traces the syscalls of a set of VMs until it is stopped.
+type TraceSession struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Targets       []*TraceTarget         `protobuf:"bytes,2,rep,name=targets,proto3" json:"targets,omitempty"`
+	Syscalls      []string               `protobuf:"bytes,3,rep,name=syscalls,proto3" json:"syscalls,omitempty"`    // the allowlist, empty for every syscall
+	StartedAt     int64                  `protobuf:"varint,4,opt,name=startedAt,proto3" json:"startedAt,omitempty"` // unix millis
+	StoppedAt     int64                  `protobuf:"varint,5,opt,name=stoppedAt,proto3" json:"stoppedAt,omitempty"` // unix millis, 0 while running
+	Latency       bool                   `protobuf:"varint,6,opt,name=latency,proto3" json:"latency,omitempty"`     // syscalls are timed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceSession) Reset() {
+	*x = TraceSession{}
+	mi := &file_proto_vm_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceSession) ProtoMessage() {}
+
+func (x *TraceSession) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceSession.ProtoReflect.Descriptor instead.
+func (*TraceSession) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *TraceSession) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TraceSession) GetTargets() []*TraceTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *TraceSession) GetSyscalls() []string {
+	if x != nil {
+		return x.Syscalls
+	}
+	return nil
+}
+
+func (x *TraceSession) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *TraceSession) GetStoppedAt() int64 {
+	if x != nil {
+		return x.StoppedAt
+	}
+	return 0
+}
+
+func (x *TraceSession) GetLatency() bool {
+	if x != nil {
+		return x.Latency
+	}
+	return false
+}
+
+type ListTraceSessionsVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTraceSessionsVmRequest) Reset() {
+	*x = ListTraceSessionsVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTraceSessionsVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTraceSessionsVmRequest) ProtoMessage() {}
+
+func (x *ListTraceSessionsVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTraceSessionsVmRequest.ProtoReflect.Descriptor instead.
+func (*ListTraceSessionsVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{33}
+}
+
+type ListTraceSessionsVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*TraceSession        `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTraceSessionsVmResponse) Reset() {
+	*x = ListTraceSessionsVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTraceSessionsVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTraceSessionsVmResponse) ProtoMessage() {}
+
+func (x *ListTraceSessionsVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTraceSessionsVmResponse.ProtoReflect.Descriptor instead.
+func (*ListTraceSessionsVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListTraceSessionsVmResponse) GetSessions() []*TraceSession {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// SyscallCount is how often the threads named comm called syscall.
+type SyscallCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comm          string                 `protobuf:"bytes,1,opt,name=comm,proto3" json:"comm,omitempty"`
+	Syscall       string                 `protobuf:"bytes,2,opt,name=syscall,proto3" json:"syscall,omitempty"`
+	Count         uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyscallCount) Reset() {
+	*x = SyscallCount{}
+	mi := &file_proto_vm_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyscallCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyscallCount) ProtoMessage() {}
+
+func (x *SyscallCount) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyscallCount.ProtoReflect.Descriptor instead.
+func (*SyscallCount) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SyscallCount) GetComm() string {
+	if x != nil {
+		return x.Comm
+	}
+	return ""
+}
+
+func (x *SyscallCount) GetSyscall() string {
+	if x != nil {
+		return x.Syscall
+	}
+	return ""
+}
+
+func (x *SyscallCount) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// SyscallLatency is the log2 latency histogram of syscall, from enter to
+// exit, in the thread tid named comm.
+type SyscallLatency struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comm          string                 `protobuf:"bytes,1,opt,name=comm,proto3" json:"comm,omitempty"`
+	Tid           int64                  `protobuf:"varint,2,opt,name=tid,proto3" json:"tid,omitempty"`
+	Syscall       string                 `protobuf:"bytes,3,opt,name=syscall,proto3" json:"syscall,omitempty"`
+	Count         uint64                 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	TotalNs       uint64                 `protobuf:"varint,5,opt,name=totalNs,proto3" json:"totalNs,omitempty"`
+	Buckets       []*LatencyBucket       `protobuf:"bytes,6,rep,name=buckets,proto3" json:"buckets,omitempty"` // the buckets that are not empty, fastest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyscallLatency) Reset() {
+	*x = SyscallLatency{}
+	mi := &file_proto_vm_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyscallLatency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyscallLatency) ProtoMessage() {}
+
+func (x *SyscallLatency) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyscallLatency.ProtoReflect.Descriptor instead.
+func (*SyscallLatency) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SyscallLatency) GetComm() string {
+	if x != nil {
+		return x.Comm
+	}
+	return ""
+}
+
+func (x *SyscallLatency) GetTid() int64 {
+	if x != nil {
+		return x.Tid
+	}
+	return 0
+}
+
+func (x *SyscallLatency) GetSyscall() string {
+	if x != nil {
+		return x.Syscall
+	}
+	return ""
+}
+
+func (x *SyscallLatency) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SyscallLatency) GetTotalNs() uint64 {
+	if x != nil {
+		return x.TotalNs
+	}
+	return 0
+}
+
+func (x *SyscallLatency) GetBuckets() []*LatencyBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+// LatencyBucket counts the calls that took minNs to maxNs nanoseconds.
+type LatencyBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinNs         uint64                 `protobuf:"varint,1,opt,name=minNs,proto3" json:"minNs,omitempty"`
+	MaxNs         uint64                 `protobuf:"varint,2,opt,name=maxNs,proto3" json:"maxNs,omitempty"`
+	Count         uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatencyBucket) Reset() {
+	*x = LatencyBucket{}
+	mi := &file_proto_vm_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatencyBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyBucket) ProtoMessage() {}
+
+func (x *LatencyBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencyBucket.ProtoReflect.Descriptor instead.
+func (*LatencyBucket) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *LatencyBucket) GetMinNs() uint64 {
+	if x != nil {
+		return x.MinNs
+	}
+	return 0
+}
+
+func (x *LatencyBucket) GetMaxNs() uint64 {
+	if x != nil {
+		return x.MaxNs
+	}
+	return 0
+}
+
+func (x *LatencyBucket) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// SyscallStats is the latest snapshot of the syscall trace of a VM's
+// firecracker process.
+type SyscallStats struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Ip         string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Pid        int64                  `protobuf:"varint,2,opt,name=pid,proto3" json:"pid,omitempty"`
+	Time       int64                  `protobuf:"varint,3,opt,name=time,proto3" json:"time,omitempty"` // unix millis
+	IntervalMs int64                  `protobuf:"varint,4,opt,name=intervalMs,proto3" json:"intervalMs,omitempty"`
+	Interval   []*SyscallCount        `protobuf:"bytes,5,rep,name=interval,proto3" json:"interval,omitempty"` // during the last interval, most called first
+	Total      []*SyscallCount        `protobuf:"bytes,6,rep,name=total,proto3" json:"total,omitempty"`       // since tracing started, most called first
+	Final      bool                   `protobuf:"varint,7,opt,name=final,proto3" json:"final,omitempty"`      // tracing has stopped
+	SessionId  string                 `protobuf:"bytes,8,opt,name=sessionId,proto3" json:"sessionId,omitempty"`
+	// only if the session times syscalls
+	IntervalLatency []*SyscallLatency `protobuf:"bytes,9,rep,name=intervalLatency,proto3" json:"intervalLatency,omitempty"` // during the last interval, most time spent first
+	TotalLatency    []*SyscallLatency `protobuf:"bytes,10,rep,name=totalLatency,proto3" json:"totalLatency,omitempty"`      // since tracing started, most time spent first
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SyscallStats) Reset() {
+	*x = SyscallStats{}
+	mi := &file_proto_vm_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyscallStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyscallStats) ProtoMessage() {}
+
+func (x *SyscallStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyscallStats.ProtoReflect.Descriptor instead.
+func (*SyscallStats) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SyscallStats) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *SyscallStats) GetPid() int64 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *SyscallStats) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *SyscallStats) GetIntervalMs() int64 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+func (x *SyscallStats) GetInterval() []*SyscallCount {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *SyscallStats) GetTotal() []*SyscallCount {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *SyscallStats) GetFinal() bool {
+	if x != nil {
+		return x.Final
+	}
+	return false
+}
+
+func (x *SyscallStats) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SyscallStats) GetIntervalLatency() []*SyscallLatency {
+	if x != nil {
+		return x.IntervalLatency
+	}
+	return nil
+}
+
+func (x *SyscallStats) GetTotalLatency() []*SyscallLatency {
+	if x != nil {
+		return x.TotalLatency
+	}
+	return nil
+}
+
+type GetSyscallStatsVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`               // optional, every VM of the session if empty
+	SessionId     string                 `protobuf:"bytes,2,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // optional, the latest session if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSyscallStatsVmRequest) Reset() {
+	*x = GetSyscallStatsVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSyscallStatsVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSyscallStatsVmRequest) ProtoMessage() {}
+
+func (x *GetSyscallStatsVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSyscallStatsVmRequest.ProtoReflect.Descriptor instead.
+func (*GetSyscallStatsVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetSyscallStatsVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *GetSyscallStatsVmRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type GetSyscallStatsVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*SyscallStats        `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSyscallStatsVmResponse) Reset() {
+	*x = GetSyscallStatsVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSyscallStatsVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSyscallStatsVmResponse) ProtoMessage() {}
+
+func (x *GetSyscallStatsVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSyscallStatsVmResponse.ProtoReflect.Descriptor instead.
+func (*GetSyscallStatsVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetSyscallStatsVmResponse) GetStats() []*SyscallStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// WatchSyscallsVmRequest streams the counts of every tracing interval until
+// the session is stopped.
+type WatchSyscallsVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`               // optional, every VM of the session if empty
+	SessionId     string                 `protobuf:"bytes,2,opt,name=sessionId,proto3" json:"sessionId,omitempty"` // optional, the latest session if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSyscallsVmRequest) Reset() {
+	*x = WatchSyscallsVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSyscallsVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSyscallsVmRequest) ProtoMessage() {}
+
+func (x *WatchSyscallsVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSyscallsVmRequest.ProtoReflect.Descriptor instead.
+func (*WatchSyscallsVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *WatchSyscallsVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *WatchSyscallsVmRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type WatchSyscallsVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *SyscallStats          `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSyscallsVmResponse) Reset() {
+	*x = WatchSyscallsVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSyscallsVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSyscallsVmResponse) ProtoMessage() {}
+
+func (x *WatchSyscallsVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSyscallsVmResponse.ProtoReflect.Descriptor instead.
+func (*WatchSyscallsVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *WatchSyscallsVmResponse) GetStats() *SyscallStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type CleanupVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CleanupVmRequest) Reset() {
+	*x = CleanupVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CleanupVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanupVmRequest) ProtoMessage() {}
+
+func (x *CleanupVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanupVmRequest.ProtoReflect.Descriptor instead.
+func (*CleanupVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{43}
+}
+
+type CleanupVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CleanupVmResponse) Reset() {
+	*x = CleanupVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CleanupVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanupVmResponse) ProtoMessage() {}
+
+func (x *CleanupVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanupVmResponse.ProtoReflect.Descriptor instead.
+func (*CleanupVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{44}
+}
+
+type PauseVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseVmRequest) Reset() {
+	*x = PauseVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseVmRequest) ProtoMessage() {}
+
+func (x *PauseVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseVmRequest.ProtoReflect.Descriptor instead.
+func (*PauseVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *PauseVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type PauseVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseVmResponse) Reset() {
+	*x = PauseVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseVmResponse) ProtoMessage() {}
+
+func (x *PauseVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseVmResponse.ProtoReflect.Descriptor instead.
+func (*PauseVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{46}
+}
+
+type ResumeVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeVmRequest) Reset() {
+	*x = ResumeVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeVmRequest) ProtoMessage() {}
+
+func (x *ResumeVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeVmRequest.ProtoReflect.Descriptor instead.
+func (*ResumeVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ResumeVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type ResumeVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeVmResponse) Reset() {
+	*x = ResumeVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeVmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeVmResponse) ProtoMessage() {}
+
+func (x *ResumeVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeVmResponse.ProtoReflect.Descriptor instead.
+func (*ResumeVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{48}
+}
+
+type Snapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Ip            string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	Type          SnapshotType           `protobuf:"varint,3,opt,name=type,proto3,enum=proto.vm.v1.SnapshotType" json:"type,omitempty"`
+	ParentId      string                 `protobuf:"bytes,4,opt,name=parentId,proto3" json:"parentId,omitempty"`
+	MemFilePath   string                 `protobuf:"bytes,5,opt,name=memFilePath,proto3" json:"memFilePath,omitempty"`
+	SnapshotPath  string                 `protobuf:"bytes,6,opt,name=snapshotPath,proto3" json:"snapshotPath,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=createdAt,proto3" json:"createdAt,omitempty"` // unix millis
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	mi := &file_proto_vm_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *Snapshot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Snapshot) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Snapshot) GetType() SnapshotType {
+	if x != nil {
+		return x.Type
+	}
+	return SnapshotType_SNAPSHOT_TYPE_FULL
+}
+
+func (x *Snapshot) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Snapshot) GetMemFilePath() string {
+	if x != nil {
+		return x.MemFilePath
+	}
+	return ""
+}
+
+func (x *Snapshot) GetSnapshotPath() string {
+	if x != nil {
+		return x.SnapshotPath
+	}
+	return ""
+}
+
+func (x *Snapshot) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type CreateSnapshotVmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Type          SnapshotType           `protobuf:"varint,2,opt,name=type,proto3,enum=proto.vm.v1.SnapshotType" json:"type,omitempty"`
+	KeepPaused    bool                   `protobuf:"varint,3,opt,name=keepPaused,proto3" json:"keepPaused,omitempty"` // leave the VM paused after the snapshot is taken
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSnapshotVmRequest) Reset() {
+	*x = CreateSnapshotVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSnapshotVmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSnapshotVmRequest) ProtoMessage() {}
+
+func (x *CreateSnapshotVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSnapshotVmRequest.ProtoReflect.Descriptor instead.
+func (*CreateSnapshotVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *CreateSnapshotVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *CreateSnapshotVmRequest) GetType() SnapshotType {
+	if x != nil {
+		return x.Type
+	}
+	return SnapshotType_SNAPSHOT_TYPE_FULL
+}
+
+func (x *CreateSnapshotVmRequest) GetKeepPaused() bool {
+	if x != nil {
+		return x.KeepPaused
+	}
+	return false
+}
+
+type CreateSnapshotVmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshot      *Snapshot              `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSnapshotVmResponse) Reset() {
+	*x = CreateSnapshotVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateVmResponse) String() string {
+func (x *CreateSnapshotVmResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateVmResponse) ProtoMessage() {}
+func (*CreateSnapshotVmResponse) ProtoMessage() {}
 
-func (x *CreateVmResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[2]
+func (x *CreateSnapshotVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -181,42 +3497,41 @@ func (x *CreateVmResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateVmResponse.ProtoReflect.Descriptor instead.
-func (*CreateVmResponse) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use CreateSnapshotVmResponse.ProtoReflect.Descriptor instead.
+func (*CreateSnapshotVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *CreateVmResponse) GetVm() *Vm {
+func (x *CreateSnapshotVmResponse) GetSnapshot() *Snapshot {
 	if x != nil {
-		return x.Vm
+		return x.Snapshot
 	}
 	return nil
 }
 
-type SendServerCommandVmRequest struct {
+type RestoreFromSnapshotVmRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
-	Command       string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
-	Wait          bool                   `protobuf:"varint,3,opt,name=wait,proto3" json:"wait,omitempty"`
+	SnapshotId    string                 `protobuf:"bytes,1,opt,name=snapshotId,proto3" json:"snapshotId,omitempty"`
+	Resume        bool                   `protobuf:"varint,2,opt,name=resume,proto3" json:"resume,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendServerCommandVmRequest) Reset() {
-	*x = SendServerCommandVmRequest{}
-	mi := &file_proto_vm_proto_msgTypes[3]
+func (x *RestoreFromSnapshotVmRequest) Reset() {
+	*x = RestoreFromSnapshotVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendServerCommandVmRequest) String() string {
+func (x *RestoreFromSnapshotVmRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendServerCommandVmRequest) ProtoMessage() {}
+func (*RestoreFromSnapshotVmRequest) ProtoMessage() {}
 
-func (x *SendServerCommandVmRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[3]
+func (x *RestoreFromSnapshotVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -227,54 +3542,47 @@ func (x *SendServerCommandVmRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendServerCommandVmRequest.ProtoReflect.Descriptor instead.
-func (*SendServerCommandVmRequest) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *SendServerCommandVmRequest) GetIp() string {
-	if x != nil {
-		return x.Ip
-	}
-	return ""
+// Deprecated: Use RestoreFromSnapshotVmRequest.ProtoReflect.Descriptor instead.
+func (*RestoreFromSnapshotVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *SendServerCommandVmRequest) GetCommand() string {
+func (x *RestoreFromSnapshotVmRequest) GetSnapshotId() string {
 	if x != nil {
-		return x.Command
+		return x.SnapshotId
 	}
 	return ""
 }
 
-func (x *SendServerCommandVmRequest) GetWait() bool {
+func (x *RestoreFromSnapshotVmRequest) GetResume() bool {
 	if x != nil {
-		return x.Wait
+		return x.Resume
 	}
 	return false
 }
 
-type SendServerCommandVmResponse struct {
+type RestoreFromSnapshotVmResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Output        string                 `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	Vm            *Vm                    `protobuf:"bytes,1,opt,name=vm,proto3" json:"vm,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendServerCommandVmResponse) Reset() {
-	*x = SendServerCommandVmResponse{}
-	mi := &file_proto_vm_proto_msgTypes[4]
+func (x *RestoreFromSnapshotVmResponse) Reset() {
+	*x = RestoreFromSnapshotVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendServerCommandVmResponse) String() string {
+func (x *RestoreFromSnapshotVmResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendServerCommandVmResponse) ProtoMessage() {}
+func (*RestoreFromSnapshotVmResponse) ProtoMessage() {}
 
-func (x *SendServerCommandVmResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[4]
+func (x *RestoreFromSnapshotVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -285,41 +3593,39 @@ func (x *SendServerCommandVmResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendServerCommandVmResponse.ProtoReflect.Descriptor instead.
-func (*SendServerCommandVmResponse) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use RestoreFromSnapshotVmResponse.ProtoReflect.Descriptor instead.
+func (*RestoreFromSnapshotVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *SendServerCommandVmResponse) GetOutput() string {
+func (x *RestoreFromSnapshotVmResponse) GetVm() *Vm {
 	if x != nil {
-		return x.Output
+		return x.Vm
 	}
-	return ""
+	return nil
 }
 
-type SendClientCommandVmRequest struct {
+type ListVmsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
-	Command       string                 `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendClientCommandVmRequest) Reset() {
-	*x = SendClientCommandVmRequest{}
-	mi := &file_proto_vm_proto_msgTypes[5]
+func (x *ListVmsRequest) Reset() {
+	*x = ListVmsRequest{}
+	mi := &file_proto_vm_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendClientCommandVmRequest) String() string {
+func (x *ListVmsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendClientCommandVmRequest) ProtoMessage() {}
+func (*ListVmsRequest) ProtoMessage() {}
 
-func (x *SendClientCommandVmRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[5]
+func (x *ListVmsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -330,47 +3636,77 @@ func (x *SendClientCommandVmRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendClientCommandVmRequest.ProtoReflect.Descriptor instead.
-func (*SendClientCommandVmRequest) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ListVmsRequest.ProtoReflect.Descriptor instead.
+func (*ListVmsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *SendClientCommandVmRequest) GetIp() string {
+type ListVmsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vms           []*Vm                  `protobuf:"bytes,1,rep,name=vms,proto3" json:"vms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVmsResponse) Reset() {
+	*x = ListVmsResponse{}
+	mi := &file_proto_vm_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVmsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVmsResponse) ProtoMessage() {}
+
+func (x *ListVmsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[55]
 	if x != nil {
-		return x.Ip
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *SendClientCommandVmRequest) GetCommand() string {
+// Deprecated: Use ListVmsResponse.ProtoReflect.Descriptor instead.
+func (*ListVmsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ListVmsResponse) GetVms() []*Vm {
 	if x != nil {
-		return x.Command
+		return x.Vms
 	}
-	return ""
+	return nil
 }
 
-type SendClientCommandVmResponse struct {
+type GetVmRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Output        string                 `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendClientCommandVmResponse) Reset() {
-	*x = SendClientCommandVmResponse{}
-	mi := &file_proto_vm_proto_msgTypes[6]
+func (x *GetVmRequest) Reset() {
+	*x = GetVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendClientCommandVmResponse) String() string {
+func (x *GetVmRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendClientCommandVmResponse) ProtoMessage() {}
+func (*GetVmRequest) ProtoMessage() {}
 
-func (x *SendClientCommandVmResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[6]
+func (x *GetVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -381,39 +3717,40 @@ func (x *SendClientCommandVmResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendClientCommandVmResponse.ProtoReflect.Descriptor instead.
-func (*SendClientCommandVmResponse) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use GetVmRequest.ProtoReflect.Descriptor instead.
+func (*GetVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *SendClientCommandVmResponse) GetOutput() string {
+func (x *GetVmRequest) GetIp() string {
 	if x != nil {
-		return x.Output
+		return x.Ip
 	}
 	return ""
 }
 
-type TrackSyscallsVmRequest struct {
+type GetVmResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vm            *Vm                    `protobuf:"bytes,1,opt,name=vm,proto3" json:"vm,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TrackSyscallsVmRequest) Reset() {
-	*x = TrackSyscallsVmRequest{}
-	mi := &file_proto_vm_proto_msgTypes[7]
+func (x *GetVmResponse) Reset() {
+	*x = GetVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TrackSyscallsVmRequest) String() string {
+func (x *GetVmResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TrackSyscallsVmRequest) ProtoMessage() {}
+func (*GetVmResponse) ProtoMessage() {}
 
-func (x *TrackSyscallsVmRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[7]
+func (x *GetVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -424,32 +3761,40 @@ func (x *TrackSyscallsVmRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TrackSyscallsVmRequest.ProtoReflect.Descriptor instead.
-func (*TrackSyscallsVmRequest) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use GetVmResponse.ProtoReflect.Descriptor instead.
+func (*GetVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{57}
 }
 
-type TrackSyscallsVmResponse struct {
+func (x *GetVmResponse) GetVm() *Vm {
+	if x != nil {
+		return x.Vm
+	}
+	return nil
+}
+
+type DeleteVmRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TrackSyscallsVmResponse) Reset() {
-	*x = TrackSyscallsVmResponse{}
-	mi := &file_proto_vm_proto_msgTypes[8]
+func (x *DeleteVmRequest) Reset() {
+	*x = DeleteVmRequest{}
+	mi := &file_proto_vm_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TrackSyscallsVmResponse) String() string {
+func (x *DeleteVmRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TrackSyscallsVmResponse) ProtoMessage() {}
+func (*DeleteVmRequest) ProtoMessage() {}
 
-func (x *TrackSyscallsVmResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[8]
+func (x *DeleteVmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -460,32 +3805,39 @@ func (x *TrackSyscallsVmResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TrackSyscallsVmResponse.ProtoReflect.Descriptor instead.
-func (*TrackSyscallsVmResponse) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use DeleteVmRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVmRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{58}
 }
 
-type StopSyscallsVmRequest struct {
+func (x *DeleteVmRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type DeleteVmResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StopSyscallsVmRequest) Reset() {
-	*x = StopSyscallsVmRequest{}
-	mi := &file_proto_vm_proto_msgTypes[9]
+func (x *DeleteVmResponse) Reset() {
+	*x = DeleteVmResponse{}
+	mi := &file_proto_vm_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StopSyscallsVmRequest) String() string {
+func (x *DeleteVmResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopSyscallsVmRequest) ProtoMessage() {}
+func (*DeleteVmResponse) ProtoMessage() {}
 
-func (x *StopSyscallsVmRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[9]
+func (x *DeleteVmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -496,32 +3848,37 @@ func (x *StopSyscallsVmRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopSyscallsVmRequest.ProtoReflect.Descriptor instead.
-func (*StopSyscallsVmRequest) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use DeleteVmResponse.ProtoReflect.Descriptor instead.
+func (*DeleteVmResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{59}
 }
 
-type StopSyscallsVmResponse struct {
+type GetVmMetricsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	From          int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`        // unix millis, exclusive, 0 for the first sample
+	To            int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`            // unix millis, inclusive, 0 for the latest sample
+	Prefixes      []string               `protobuf:"bytes,4,rep,name=prefixes,proto3" json:"prefixes,omitempty"` // e.g. "net_eth0." or "vcpu.", empty for every metric
+	Series        bool                   `protobuf:"varint,5,opt,name=series,proto3" json:"series,omitempty"`    // also return every sample
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StopSyscallsVmResponse) Reset() {
-	*x = StopSyscallsVmResponse{}
-	mi := &file_proto_vm_proto_msgTypes[10]
+func (x *GetVmMetricsRequest) Reset() {
+	*x = GetVmMetricsRequest{}
+	mi := &file_proto_vm_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StopSyscallsVmResponse) String() string {
+func (x *GetVmMetricsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopSyscallsVmResponse) ProtoMessage() {}
+func (*GetVmMetricsRequest) ProtoMessage() {}
 
-func (x *StopSyscallsVmResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[10]
+func (x *GetVmMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -532,32 +3889,69 @@ func (x *StopSyscallsVmResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopSyscallsVmResponse.ProtoReflect.Descriptor instead.
-func (*StopSyscallsVmResponse) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use GetVmMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetVmMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{60}
 }
 
-type CleanupVmRequest struct {
+func (x *GetVmMetricsRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *GetVmMetricsRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetVmMetricsRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetVmMetricsRequest) GetPrefixes() []string {
+	if x != nil {
+		return x.Prefixes
+	}
+	return nil
+}
+
+func (x *GetVmMetricsRequest) GetSeries() bool {
+	if x != nil {
+		return x.Series
+	}
+	return false
+}
+
+type MetricsSample struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          int64                  `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"` // unix millis
+	Values        map[string]uint64      `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CleanupVmRequest) Reset() {
-	*x = CleanupVmRequest{}
-	mi := &file_proto_vm_proto_msgTypes[11]
+func (x *MetricsSample) Reset() {
+	*x = MetricsSample{}
+	mi := &file_proto_vm_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CleanupVmRequest) String() string {
+func (x *MetricsSample) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanupVmRequest) ProtoMessage() {}
+func (*MetricsSample) ProtoMessage() {}
 
-func (x *CleanupVmRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[11]
+func (x *MetricsSample) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -568,32 +3962,51 @@ func (x *CleanupVmRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanupVmRequest.ProtoReflect.Descriptor instead.
-func (*CleanupVmRequest) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use MetricsSample.ProtoReflect.Descriptor instead.
+func (*MetricsSample) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{61}
 }
 
-type CleanupVmResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+func (x *MetricsSample) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *MetricsSample) GetValues() map[string]uint64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type GetVmMetricsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	From  int64                  `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"` // unix millis of the first sample
+	To    int64                  `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`     // unix millis of the last sample
+	// counters summed over the samples; timings (*_us) hold their latest value
+	Delta         map[string]uint64 `protobuf:"bytes,3,rep,name=delta,proto3" json:"delta,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Samples       []*MetricsSample  `protobuf:"bytes,4,rep,name=samples,proto3" json:"samples,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CleanupVmResponse) Reset() {
-	*x = CleanupVmResponse{}
-	mi := &file_proto_vm_proto_msgTypes[12]
+func (x *GetVmMetricsResponse) Reset() {
+	*x = GetVmMetricsResponse{}
+	mi := &file_proto_vm_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CleanupVmResponse) String() string {
+func (x *GetVmMetricsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanupVmResponse) ProtoMessage() {}
+func (*GetVmMetricsResponse) ProtoMessage() {}
 
-func (x *CleanupVmResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vm_proto_msgTypes[12]
+func (x *GetVmMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vm_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -604,16 +4017,44 @@ func (x *CleanupVmResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanupVmResponse.ProtoReflect.Descriptor instead.
-func (*CleanupVmResponse) Descriptor() ([]byte, []int) {
-	return file_proto_vm_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use GetVmMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetVmMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vm_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetVmMetricsResponse) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetVmMetricsResponse) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetVmMetricsResponse) GetDelta() map[string]uint64 {
+	if x != nil {
+		return x.Delta
+	}
+	return nil
+}
+
+func (x *GetVmMetricsResponse) GetSamples() []*MetricsSample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
 }
 
 var File_proto_vm_proto protoreflect.FileDescriptor
 
 const file_proto_vm_proto_rawDesc = "" +
 	"\n" +
-	"\x0eproto/vm.proto\x12\vproto.vm.v1\"T\n" +
+	"\x0eproto/vm.proto\x12\vproto.vm.v1\"\xf8\x05\n" +
 	"\x02Vm\x12\x0e\n" +
 	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x1e\n" +
 	"\n" +
@@ -621,7 +4062,39 @@ const file_proto_vm_proto_rawDesc = "" +
 	"kernelPath\x12\x1e\n" +
 	"\n" +
 	"rootfsPath\x18\x04 \x01(\tR\n" +
-	"rootfsPath\"\x7f\n" +
+	"rootfsPath\x12\x1c\n" +
+	"\tvcpuCount\x18\x05 \x01(\x03R\tvcpuCount\x12\x1e\n" +
+	"\n" +
+	"memSizeMib\x18\x06 \x01(\x03R\n" +
+	"memSizeMib\x12*\n" +
+	"\x05state\x18\a \x01(\x0e2\x14.proto.vm.v1.VmStateR\x05state\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x10\n" +
+	"\x03pid\x18\t \x01(\x03R\x03pid\x12\x1e\n" +
+	"\n" +
+	"socketPath\x18\n" +
+	" \x01(\tR\n" +
+	"socketPath\x12\x1c\n" +
+	"\tvsockPath\x18\v \x01(\tR\tvsockPath\x12\x1a\n" +
+	"\bvsockCid\x18\f \x01(\rR\bvsockCid\x12\x18\n" +
+	"\atapName\x18\r \x01(\tR\atapName\x12\x1e\n" +
+	"\n" +
+	"macAddress\x18\x0e \x01(\tR\n" +
+	"macAddress\x12\x1c\n" +
+	"\tgatewayIP\x18\x0f \x01(\tR\tgatewayIP\x12\x1c\n" +
+	"\tcreatedAt\x18\x10 \x01(\x03R\tcreatedAt\x12\x1c\n" +
+	"\tstartedAt\x18\x11 \x01(\x03R\tstartedAt\x12\x1c\n" +
+	"\tstoppedAt\x18\x12 \x01(\x03R\tstoppedAt\x12\"\n" +
+	"\fprefixLength\x18\x13 \x01(\x05R\fprefixLength\x12L\n" +
+	"\x14networkRxRateLimiter\x18\x14 \x01(\v2\x18.proto.vm.v1.RateLimiterR\x14networkRxRateLimiter\x12L\n" +
+	"\x14networkTxRateLimiter\x18\x15 \x01(\v2\x18.proto.vm.v1.RateLimiterR\x14networkTxRateLimiter\x12D\n" +
+	"\x10driveRateLimiter\x18\x16 \x01(\v2\x18.proto.vm.v1.RateLimiterR\x10driveRateLimiter\"i\n" +
+	"\vTokenBucket\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\x12\"\n" +
+	"\foneTimeBurst\x18\x02 \x01(\x03R\foneTimeBurst\x12\"\n" +
+	"\frefillTimeMs\x18\x03 \x01(\x03R\frefillTimeMs\"q\n" +
+	"\vRateLimiter\x126\n" +
+	"\tbandwidth\x18\x01 \x01(\v2\x18.proto.vm.v1.TokenBucketR\tbandwidth\x12*\n" +
+	"\x03ops\x18\x02 \x01(\v2\x18.proto.vm.v1.TokenBucketR\x03ops\"\xbf\x04\n" +
 	"\x0fCreateVmRequest\x12\x0e\n" +
 	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x1e\n" +
 	"\n" +
@@ -630,33 +4103,294 @@ const file_proto_vm_proto_rawDesc = "" +
 	"\n" +
 	"rootfsPath\x18\x03 \x01(\tR\n" +
 	"rootfsPath\x12\x1c\n" +
-	"\tgatewayIP\x18\x04 \x01(\tR\tgatewayIP\"3\n" +
+	"\tgatewayIP\x18\x04 \x01(\tR\tgatewayIP\x12(\n" +
+	"\x0ftrackDirtyPages\x18\x05 \x01(\bR\x0ftrackDirtyPages\x12\x1c\n" +
+	"\tvcpuCount\x18\x06 \x01(\x03R\tvcpuCount\x12\x1e\n" +
+	"\n" +
+	"memSizeMib\x18\a \x01(\x03R\n" +
+	"memSizeMib\x12\x10\n" +
+	"\x03smt\x18\b \x01(\bR\x03smt\x12 \n" +
+	"\vcpuTemplate\x18\t \x01(\tR\vcpuTemplate\x12\x1e\n" +
+	"\n" +
+	"kernelArgs\x18\n" +
+	" \x01(\tR\n" +
+	"kernelArgs\x12 \n" +
+	"\vnameservers\x18\v \x03(\tR\vnameservers\x12L\n" +
+	"\x14networkRxRateLimiter\x18\f \x01(\v2\x18.proto.vm.v1.RateLimiterR\x14networkRxRateLimiter\x12L\n" +
+	"\x14networkTxRateLimiter\x18\r \x01(\v2\x18.proto.vm.v1.RateLimiterR\x14networkTxRateLimiter\x12D\n" +
+	"\x10driveRateLimiter\x18\x0e \x01(\v2\x18.proto.vm.v1.RateLimiterR\x10driveRateLimiter\"3\n" +
 	"\x10CreateVmResponse\x12\x1f\n" +
-	"\x02vm\x18\x01 \x01(\v2\x0f.proto.vm.v1.VmR\x02vm\"Z\n" +
+	"\x02vm\x18\x01 \x01(\v2\x0f.proto.vm.v1.VmR\x02vm\"x\n" +
 	"\x1aSendServerCommandVmRequest\x12\x0e\n" +
 	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x18\n" +
 	"\acommand\x18\x02 \x01(\tR\acommand\x12\x12\n" +
-	"\x04wait\x18\x03 \x01(\bR\x04wait\"5\n" +
+	"\x04wait\x18\x03 \x01(\bR\x04wait\x12\x1c\n" +
+	"\ttimeoutMs\x18\x04 \x01(\x03R\ttimeoutMs\"K\n" +
 	"\x1bSendServerCommandVmResponse\x12\x16\n" +
-	"\x06output\x18\x01 \x01(\tR\x06output\"F\n" +
+	"\x06output\x18\x01 \x01(\tR\x06output\x12\x14\n" +
+	"\x05jobId\x18\x02 \x01(\tR\x05jobId\"d\n" +
 	"\x1aSendClientCommandVmRequest\x12\x0e\n" +
 	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x18\n" +
-	"\acommand\x18\x02 \x01(\tR\acommand\"5\n" +
+	"\acommand\x18\x02 \x01(\tR\acommand\x12\x1c\n" +
+	"\ttimeoutMs\x18\x03 \x01(\x03R\ttimeoutMs\"\xac\x01\n" +
 	"\x1bSendClientCommandVmResponse\x12\x16\n" +
-	"\x06output\x18\x01 \x01(\tR\x06output\"\x18\n" +
-	"\x16TrackSyscallsVmRequest\"\x19\n" +
-	"\x17TrackSyscallsVmResponse\"\x17\n" +
-	"\x15StopSyscallsVmRequest\"\x18\n" +
-	"\x16StopSyscallsVmResponse\"\x12\n" +
+	"\x06output\x18\x01 \x01(\tR\x06output\x121\n" +
+	"\x06stream\x18\x02 \x01(\x0e2\x19.proto.vm.v1.OutputStreamR\x06stream\x12,\n" +
+	"\x04exit\x18\x03 \x01(\v2\x18.proto.vm.v1.CommandExitR\x04exit\x12\x14\n" +
+	"\x05jobId\x18\x04 \x01(\tR\x05jobId\"\x8f\x01\n" +
+	"\vCommandExit\x12\x1a\n" +
+	"\bexitCode\x18\x01 \x01(\x05R\bexitCode\x12\x1e\n" +
+	"\n" +
+	"durationMs\x18\x02 \x01(\x03R\n" +
+	"durationMs\x12\x16\n" +
+	"\x06killed\x18\x03 \x01(\bR\x06killed\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x16\n" +
+	"\x06signal\x18\x05 \x01(\tR\x06signal\"\xc0\x01\n" +
+	"\rExecVmRequest\x12.\n" +
+	"\x05start\x18\x01 \x01(\v2\x18.proto.vm.v1.ExecVmStartR\x05start\x12\x14\n" +
+	"\x05stdin\x18\x02 \x01(\fR\x05stdin\x12\x1e\n" +
+	"\n" +
+	"closeStdin\x18\x03 \x01(\bR\n" +
+	"closeStdin\x121\n" +
+	"\x06resize\x18\x04 \x01(\v2\x19.proto.vm.v1.TerminalSizeR\x06resize\x12\x16\n" +
+	"\x06signal\x18\x05 \x01(\x05R\x06signal\"\xca\x01\n" +
+	"\vExecVmStart\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x12\n" +
+	"\x04argv\x18\x02 \x03(\tR\x04argv\x12\x10\n" +
+	"\x03env\x18\x03 \x03(\tR\x03env\x12\x10\n" +
+	"\x03cwd\x18\x04 \x01(\tR\x03cwd\x12\x10\n" +
+	"\x03tty\x18\x05 \x01(\bR\x03tty\x12-\n" +
+	"\x04size\x18\x06 \x01(\v2\x19.proto.vm.v1.TerminalSizeR\x04size\x12\x14\n" +
+	"\x05stdin\x18\a \x01(\bR\x05stdin\x12\x1c\n" +
+	"\ttimeoutMs\x18\b \x01(\x03R\ttimeoutMs\"6\n" +
+	"\fTerminalSize\x12\x12\n" +
+	"\x04rows\x18\x01 \x01(\rR\x04rows\x12\x12\n" +
+	"\x04cols\x18\x02 \x01(\rR\x04cols\"\xad\x01\n" +
+	"\x0eExecVmResponse\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\x03R\x03pid\x121\n" +
+	"\x06stream\x18\x02 \x01(\x0e2\x19.proto.vm.v1.OutputStreamR\x06stream\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\x12,\n" +
+	"\x04exit\x18\x04 \x01(\v2\x18.proto.vm.v1.CommandExitR\x04exit\x12\x14\n" +
+	"\x05jobId\x18\x05 \x01(\tR\x05jobId\"\xb4\x02\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x0e\n" +
+	"\x02ip\x18\x02 \x01(\tR\x02ip\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x16\n" +
+	"\x06server\x18\x04 \x01(\bR\x06server\x12\x10\n" +
+	"\x03pid\x18\x05 \x01(\x03R\x03pid\x12\x1c\n" +
+	"\ttimeoutMs\x18\x06 \x01(\x03R\ttimeoutMs\x12\x18\n" +
+	"\alogPath\x18\a \x01(\tR\alogPath\x12+\n" +
+	"\x05state\x18\b \x01(\x0e2\x15.proto.vm.v1.JobStateR\x05state\x12\x1c\n" +
+	"\tstartedAt\x18\t \x01(\x03R\tstartedAt\x12\x18\n" +
+	"\aendedAt\x18\n" +
+	" \x01(\x03R\aendedAt\x12,\n" +
+	"\x04exit\x18\v \x01(\v2\x18.proto.vm.v1.CommandExitR\x04exit\"#\n" +
+	"\x11ListJobsVmRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\":\n" +
+	"\x12ListJobsVmResponse\x12$\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x10.proto.vm.v1.JobR\x04jobs\"!\n" +
+	"\x0fGetJobVmRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"6\n" +
+	"\x10GetJobVmResponse\x12\"\n" +
+	"\x03job\x18\x01 \x01(\v2\x10.proto.vm.v1.JobR\x03job\"$\n" +
+	"\x12CancelJobVmRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"9\n" +
+	"\x13CancelJobVmResponse\x12\"\n" +
+	"\x03job\x18\x01 \x01(\v2\x10.proto.vm.v1.JobR\x03job\"q\n" +
+	"\x10PutFileVmRequest\x121\n" +
+	"\x05start\x18\x01 \x01(\v2\x1b.proto.vm.v1.PutFileVmStartR\x05start\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"t\n" +
+	"\x0ePutFileVmStart\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\rR\x04mode\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x05 \x01(\fR\x06sha256\"V\n" +
+	"\x11PutFileVmResponse\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x03R\x06offset\x12)\n" +
+	"\x04file\x18\x02 \x01(\v2\x15.proto.vm.v1.FileInfoR\x04file\"^\n" +
+	"\bFileInfo\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\rR\x04mode\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x04 \x01(\fR\x06sha256\"N\n" +
+	"\x10GetFileVmRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\"j\n" +
+	"\x11GetFileVmResponse\x12)\n" +
+	"\x04file\x18\x01 \x01(\v2\x15.proto.vm.v1.FileInfoR\x04file\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"`\n" +
+	"\x16TrackSyscallsVmRequest\x12\x10\n" +
+	"\x03ips\x18\x01 \x03(\tR\x03ips\x12\x1a\n" +
+	"\bsyscalls\x18\x02 \x03(\tR\bsyscalls\x12\x18\n" +
+	"\alatency\x18\x03 \x01(\bR\alatency\"N\n" +
+	"\x17TrackSyscallsVmResponse\x123\n" +
+	"\asession\x18\x01 \x01(\v2\x19.proto.vm.v1.TraceSessionR\asession\"5\n" +
+	"\x15StopSyscallsVmRequest\x12\x1c\n" +
+	"\tsessionId\x18\x01 \x01(\tR\tsessionId\"\x18\n" +
+	"\x16StopSyscallsVmResponse\"/\n" +
+	"\vTraceTarget\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x10\n" +
+	"\x03pid\x18\x02 \x01(\x03R\x03pid\"\xc4\x01\n" +
+	"\fTraceSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x122\n" +
+	"\atargets\x18\x02 \x03(\v2\x18.proto.vm.v1.TraceTargetR\atargets\x12\x1a\n" +
+	"\bsyscalls\x18\x03 \x03(\tR\bsyscalls\x12\x1c\n" +
+	"\tstartedAt\x18\x04 \x01(\x03R\tstartedAt\x12\x1c\n" +
+	"\tstoppedAt\x18\x05 \x01(\x03R\tstoppedAt\x12\x18\n" +
+	"\alatency\x18\x06 \x01(\bR\alatency\"\x1c\n" +
+	"\x1aListTraceSessionsVmRequest\"T\n" +
+	"\x1bListTraceSessionsVmResponse\x125\n" +
+	"\bsessions\x18\x01 \x03(\v2\x19.proto.vm.v1.TraceSessionR\bsessions\"R\n" +
+	"\fSyscallCount\x12\x12\n" +
+	"\x04comm\x18\x01 \x01(\tR\x04comm\x12\x18\n" +
+	"\asyscall\x18\x02 \x01(\tR\asyscall\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x04R\x05count\"\xb6\x01\n" +
+	"\x0eSyscallLatency\x12\x12\n" +
+	"\x04comm\x18\x01 \x01(\tR\x04comm\x12\x10\n" +
+	"\x03tid\x18\x02 \x01(\x03R\x03tid\x12\x18\n" +
+	"\asyscall\x18\x03 \x01(\tR\asyscall\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x04R\x05count\x12\x18\n" +
+	"\atotalNs\x18\x05 \x01(\x04R\atotalNs\x124\n" +
+	"\abuckets\x18\x06 \x03(\v2\x1a.proto.vm.v1.LatencyBucketR\abuckets\"Q\n" +
+	"\rLatencyBucket\x12\x14\n" +
+	"\x05minNs\x18\x01 \x01(\x04R\x05minNs\x12\x14\n" +
+	"\x05maxNs\x18\x02 \x01(\x04R\x05maxNs\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x04R\x05count\"\x88\x03\n" +
+	"\fSyscallStats\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x10\n" +
+	"\x03pid\x18\x02 \x01(\x03R\x03pid\x12\x12\n" +
+	"\x04time\x18\x03 \x01(\x03R\x04time\x12\x1e\n" +
+	"\n" +
+	"intervalMs\x18\x04 \x01(\x03R\n" +
+	"intervalMs\x125\n" +
+	"\binterval\x18\x05 \x03(\v2\x19.proto.vm.v1.SyscallCountR\binterval\x12/\n" +
+	"\x05total\x18\x06 \x03(\v2\x19.proto.vm.v1.SyscallCountR\x05total\x12\x14\n" +
+	"\x05final\x18\a \x01(\bR\x05final\x12\x1c\n" +
+	"\tsessionId\x18\b \x01(\tR\tsessionId\x12E\n" +
+	"\x0fintervalLatency\x18\t \x03(\v2\x1b.proto.vm.v1.SyscallLatencyR\x0fintervalLatency\x12?\n" +
+	"\ftotalLatency\x18\n" +
+	" \x03(\v2\x1b.proto.vm.v1.SyscallLatencyR\ftotalLatency\"H\n" +
+	"\x18GetSyscallStatsVmRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x1c\n" +
+	"\tsessionId\x18\x02 \x01(\tR\tsessionId\"L\n" +
+	"\x19GetSyscallStatsVmResponse\x12/\n" +
+	"\x05stats\x18\x01 \x03(\v2\x19.proto.vm.v1.SyscallStatsR\x05stats\"F\n" +
+	"\x16WatchSyscallsVmRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x1c\n" +
+	"\tsessionId\x18\x02 \x01(\tR\tsessionId\"J\n" +
+	"\x17WatchSyscallsVmResponse\x12/\n" +
+	"\x05stats\x18\x01 \x01(\v2\x19.proto.vm.v1.SyscallStatsR\x05stats\"\x12\n" +
 	"\x10CleanupVmRequest\"\x13\n" +
-	"\x11CleanupVmResponse2\xaf\x04\n" +
+	"\x11CleanupVmResponse\" \n" +
+	"\x0ePauseVmRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\"\x11\n" +
+	"\x0fPauseVmResponse\"!\n" +
+	"\x0fResumeVmRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\"\x12\n" +
+	"\x10ResumeVmResponse\"\xd9\x01\n" +
+	"\bSnapshot\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x0e\n" +
+	"\x02ip\x18\x02 \x01(\tR\x02ip\x12-\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x19.proto.vm.v1.SnapshotTypeR\x04type\x12\x1a\n" +
+	"\bparentId\x18\x04 \x01(\tR\bparentId\x12 \n" +
+	"\vmemFilePath\x18\x05 \x01(\tR\vmemFilePath\x12\"\n" +
+	"\fsnapshotPath\x18\x06 \x01(\tR\fsnapshotPath\x12\x1c\n" +
+	"\tcreatedAt\x18\a \x01(\x03R\tcreatedAt\"x\n" +
+	"\x17CreateSnapshotVmRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12-\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x19.proto.vm.v1.SnapshotTypeR\x04type\x12\x1e\n" +
+	"\n" +
+	"keepPaused\x18\x03 \x01(\bR\n" +
+	"keepPaused\"M\n" +
+	"\x18CreateSnapshotVmResponse\x121\n" +
+	"\bsnapshot\x18\x01 \x01(\v2\x15.proto.vm.v1.SnapshotR\bsnapshot\"V\n" +
+	"\x1cRestoreFromSnapshotVmRequest\x12\x1e\n" +
+	"\n" +
+	"snapshotId\x18\x01 \x01(\tR\n" +
+	"snapshotId\x12\x16\n" +
+	"\x06resume\x18\x02 \x01(\bR\x06resume\"@\n" +
+	"\x1dRestoreFromSnapshotVmResponse\x12\x1f\n" +
+	"\x02vm\x18\x01 \x01(\v2\x0f.proto.vm.v1.VmR\x02vm\"\x10\n" +
+	"\x0eListVmsRequest\"4\n" +
+	"\x0fListVmsResponse\x12!\n" +
+	"\x03vms\x18\x01 \x03(\v2\x0f.proto.vm.v1.VmR\x03vms\"\x1e\n" +
+	"\fGetVmRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\"0\n" +
+	"\rGetVmResponse\x12\x1f\n" +
+	"\x02vm\x18\x01 \x01(\v2\x0f.proto.vm.v1.VmR\x02vm\"!\n" +
+	"\x0fDeleteVmRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\"\x12\n" +
+	"\x10DeleteVmResponse\"}\n" +
+	"\x13GetVmMetricsRequest\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x1a\n" +
+	"\bprefixes\x18\x04 \x03(\tR\bprefixes\x12\x16\n" +
+	"\x06series\x18\x05 \x01(\bR\x06series\"\x9e\x01\n" +
+	"\rMetricsSample\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\x03R\x04time\x12>\n" +
+	"\x06values\x18\x02 \x03(\v2&.proto.vm.v1.MetricsSample.ValuesEntryR\x06values\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x04R\x05value:\x028\x01\"\xee\x01\n" +
+	"\x14GetVmMetricsResponse\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\x03R\x02to\x12B\n" +
+	"\x05delta\x18\x03 \x03(\v2,.proto.vm.v1.GetVmMetricsResponse.DeltaEntryR\x05delta\x124\n" +
+	"\asamples\x18\x04 \x03(\v2\x1a.proto.vm.v1.MetricsSampleR\asamples\x1a8\n" +
+	"\n" +
+	"DeltaEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x04R\x05value:\x028\x01*\xa7\x01\n" +
+	"\aVmState\x12\x18\n" +
+	"\x14VM_STATE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11VM_STATE_CREATING\x10\x01\x12\x14\n" +
+	"\x10VM_STATE_RUNNING\x10\x02\x12\x13\n" +
+	"\x0fVM_STATE_PAUSED\x10\x03\x12\x15\n" +
+	"\x11VM_STATE_STOPPING\x10\x04\x12\x14\n" +
+	"\x10VM_STATE_STOPPED\x10\x05\x12\x13\n" +
+	"\x0fVM_STATE_FAILED\x10\x06*a\n" +
+	"\fOutputStream\x12\x1d\n" +
+	"\x19OUTPUT_STREAM_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14OUTPUT_STREAM_STDOUT\x10\x01\x12\x18\n" +
+	"\x14OUTPUT_STREAM_STDERR\x10\x02*\x84\x01\n" +
+	"\bJobState\x12\x19\n" +
+	"\x15JOB_STATE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11JOB_STATE_RUNNING\x10\x01\x12\x14\n" +
+	"\x10JOB_STATE_EXITED\x10\x02\x12\x17\n" +
+	"\x13JOB_STATE_CANCELLED\x10\x03\x12\x17\n" +
+	"\x13JOB_STATE_TIMED_OUT\x10\x04*>\n" +
+	"\fSnapshotType\x12\x16\n" +
+	"\x12SNAPSHOT_TYPE_FULL\x10\x00\x12\x16\n" +
+	"\x12SNAPSHOT_TYPE_DIFF\x10\x012\xb8\x0f\n" +
 	"\tVmService\x12G\n" +
 	"\x06Create\x12\x1c.proto.vm.v1.CreateVmRequest\x1a\x1d.proto.vm.v1.CreateVmResponse\"\x00\x12h\n" +
 	"\x11SendServerCommand\x12'.proto.vm.v1.SendServerCommandVmRequest\x1a(.proto.vm.v1.SendServerCommandVmResponse\"\x00\x12j\n" +
 	"\x11SendClientCommand\x12'.proto.vm.v1.SendClientCommandVmRequest\x1a(.proto.vm.v1.SendClientCommandVmResponse\"\x000\x01\x12\\\n" +
 	"\rTrackSyscalls\x12#.proto.vm.v1.TrackSyscallsVmRequest\x1a$.proto.vm.v1.TrackSyscallsVmResponse\"\x00\x12Y\n" +
-	"\fStopSyscalls\x12\".proto.vm.v1.StopSyscallsVmRequest\x1a#.proto.vm.v1.StopSyscallsVmResponse\"\x00\x12J\n" +
-	"\aCleanup\x12\x1d.proto.vm.v1.CleanupVmRequest\x1a\x1e.proto.vm.v1.CleanupVmResponse\"\x00B\rZ\vproto/vm/v1b\x06proto3"
+	"\fStopSyscalls\x12\".proto.vm.v1.StopSyscallsVmRequest\x1a#.proto.vm.v1.StopSyscallsVmResponse\"\x00\x12h\n" +
+	"\x11ListTraceSessions\x12'.proto.vm.v1.ListTraceSessionsVmRequest\x1a(.proto.vm.v1.ListTraceSessionsVmResponse\"\x00\x12b\n" +
+	"\x0fGetSyscallStats\x12%.proto.vm.v1.GetSyscallStatsVmRequest\x1a&.proto.vm.v1.GetSyscallStatsVmResponse\"\x00\x12^\n" +
+	"\rWatchSyscalls\x12#.proto.vm.v1.WatchSyscallsVmRequest\x1a$.proto.vm.v1.WatchSyscallsVmResponse\"\x000\x01\x12J\n" +
+	"\aCleanup\x12\x1d.proto.vm.v1.CleanupVmRequest\x1a\x1e.proto.vm.v1.CleanupVmResponse\"\x00\x12D\n" +
+	"\x05Pause\x12\x1b.proto.vm.v1.PauseVmRequest\x1a\x1c.proto.vm.v1.PauseVmResponse\"\x00\x12G\n" +
+	"\x06Resume\x12\x1c.proto.vm.v1.ResumeVmRequest\x1a\x1d.proto.vm.v1.ResumeVmResponse\"\x00\x12_\n" +
+	"\x0eCreateSnapshot\x12$.proto.vm.v1.CreateSnapshotVmRequest\x1a%.proto.vm.v1.CreateSnapshotVmResponse\"\x00\x12n\n" +
+	"\x13RestoreFromSnapshot\x12).proto.vm.v1.RestoreFromSnapshotVmRequest\x1a*.proto.vm.v1.RestoreFromSnapshotVmResponse\"\x00\x12F\n" +
+	"\aListVms\x12\x1b.proto.vm.v1.ListVmsRequest\x1a\x1c.proto.vm.v1.ListVmsResponse\"\x00\x12@\n" +
+	"\x05GetVm\x12\x19.proto.vm.v1.GetVmRequest\x1a\x1a.proto.vm.v1.GetVmResponse\"\x00\x12I\n" +
+	"\bDeleteVm\x12\x1c.proto.vm.v1.DeleteVmRequest\x1a\x1d.proto.vm.v1.DeleteVmResponse\"\x00\x12U\n" +
+	"\fGetVmMetrics\x12 .proto.vm.v1.GetVmMetricsRequest\x1a!.proto.vm.v1.GetVmMetricsResponse\"\x00\x12E\n" +
+	"\x04Exec\x12\x1a.proto.vm.v1.ExecVmRequest\x1a\x1b.proto.vm.v1.ExecVmResponse\"\x00(\x010\x01\x12N\n" +
+	"\aPutFile\x12\x1d.proto.vm.v1.PutFileVmRequest\x1a\x1e.proto.vm.v1.PutFileVmResponse\"\x00(\x010\x01\x12L\n" +
+	"\aGetFile\x12\x1d.proto.vm.v1.GetFileVmRequest\x1a\x1e.proto.vm.v1.GetFileVmResponse\"\x000\x01\x12M\n" +
+	"\bListJobs\x12\x1e.proto.vm.v1.ListJobsVmRequest\x1a\x1f.proto.vm.v1.ListJobsVmResponse\"\x00\x12G\n" +
+	"\x06GetJob\x12\x1c.proto.vm.v1.GetJobVmRequest\x1a\x1d.proto.vm.v1.GetJobVmResponse\"\x00\x12P\n" +
+	"\tCancelJob\x12\x1f.proto.vm.v1.CancelJobVmRequest\x1a .proto.vm.v1.CancelJobVmResponse\"\x00B\rZ\vproto/vm/v1b\x06proto3"
 
 var (
 	file_proto_vm_proto_rawDescOnce sync.Once
@@ -670,41 +4404,175 @@ func file_proto_vm_proto_rawDescGZIP() []byte {
 	return file_proto_vm_proto_rawDescData
 }
 
-var file_proto_vm_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_vm_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_vm_proto_msgTypes = make([]protoimpl.MessageInfo, 65)
 var file_proto_vm_proto_goTypes = []any{
-	(*Vm)(nil),                          // 0: proto.vm.v1.Vm
-	(*CreateVmRequest)(nil),             // 1: proto.vm.v1.CreateVmRequest
-	(*CreateVmResponse)(nil),            // 2: proto.vm.v1.CreateVmResponse
-	(*SendServerCommandVmRequest)(nil),  // 3: proto.vm.v1.SendServerCommandVmRequest
-	(*SendServerCommandVmResponse)(nil), // 4: proto.vm.v1.SendServerCommandVmResponse
-	(*SendClientCommandVmRequest)(nil),  // 5: proto.vm.v1.SendClientCommandVmRequest
-	(*SendClientCommandVmResponse)(nil), // 6: proto.vm.v1.SendClientCommandVmResponse
-	(*TrackSyscallsVmRequest)(nil),      // 7: proto.vm.v1.TrackSyscallsVmRequest
-	(*TrackSyscallsVmResponse)(nil),     // 8: proto.vm.v1.TrackSyscallsVmResponse
-	(*StopSyscallsVmRequest)(nil),       // 9: proto.vm.v1.StopSyscallsVmRequest
-	(*StopSyscallsVmResponse)(nil),      // 10: proto.vm.v1.StopSyscallsVmResponse
-	(*CleanupVmRequest)(nil),            // 11: proto.vm.v1.CleanupVmRequest
-	(*CleanupVmResponse)(nil),           // 12: proto.vm.v1.CleanupVmResponse
+	(VmState)(0),                          // 0: proto.vm.v1.VmState
+	(OutputStream)(0),                     // 1: proto.vm.v1.OutputStream
+	(JobState)(0),                         // 2: proto.vm.v1.JobState
+	(SnapshotType)(0),                     // 3: proto.vm.v1.SnapshotType
+	(*Vm)(nil),                            // 4: proto.vm.v1.Vm
+	(*TokenBucket)(nil),                   // 5: proto.vm.v1.TokenBucket
+	(*RateLimiter)(nil),                   // 6: proto.vm.v1.RateLimiter
+	(*CreateVmRequest)(nil),               // 7: proto.vm.v1.CreateVmRequest
+	(*CreateVmResponse)(nil),              // 8: proto.vm.v1.CreateVmResponse
+	(*SendServerCommandVmRequest)(nil),    // 9: proto.vm.v1.SendServerCommandVmRequest
+	(*SendServerCommandVmResponse)(nil),   // 10: proto.vm.v1.SendServerCommandVmResponse
+	(*SendClientCommandVmRequest)(nil),    // 11: proto.vm.v1.SendClientCommandVmRequest
+	(*SendClientCommandVmResponse)(nil),   // 12: proto.vm.v1.SendClientCommandVmResponse
+	(*CommandExit)(nil),                   // 13: proto.vm.v1.CommandExit
+	(*ExecVmRequest)(nil),                 // 14: proto.vm.v1.ExecVmRequest
+	(*ExecVmStart)(nil),                   // 15: proto.vm.v1.ExecVmStart
+	(*TerminalSize)(nil),                  // 16: proto.vm.v1.TerminalSize
+	(*ExecVmResponse)(nil),                // 17: proto.vm.v1.ExecVmResponse
+	(*Job)(nil),                           // 18: proto.vm.v1.Job
+	(*ListJobsVmRequest)(nil),             // 19: proto.vm.v1.ListJobsVmRequest
+	(*ListJobsVmResponse)(nil),            // 20: proto.vm.v1.ListJobsVmResponse
+	(*GetJobVmRequest)(nil),               // 21: proto.vm.v1.GetJobVmRequest
+	(*GetJobVmResponse)(nil),              // 22: proto.vm.v1.GetJobVmResponse
+	(*CancelJobVmRequest)(nil),            // 23: proto.vm.v1.CancelJobVmRequest
+	(*CancelJobVmResponse)(nil),           // 24: proto.vm.v1.CancelJobVmResponse
+	(*PutFileVmRequest)(nil),              // 25: proto.vm.v1.PutFileVmRequest
+	(*PutFileVmStart)(nil),                // 26: proto.vm.v1.PutFileVmStart
+	(*PutFileVmResponse)(nil),             // 27: proto.vm.v1.PutFileVmResponse
+	(*FileInfo)(nil),                      // 28: proto.vm.v1.FileInfo
+	(*GetFileVmRequest)(nil),              // 29: proto.vm.v1.GetFileVmRequest
+	(*GetFileVmResponse)(nil),             // 30: proto.vm.v1.GetFileVmResponse
+	(*TrackSyscallsVmRequest)(nil),        // 31: proto.vm.v1.TrackSyscallsVmRequest
+	(*TrackSyscallsVmResponse)(nil),       // 32: proto.vm.v1.TrackSyscallsVmResponse
+	(*StopSyscallsVmRequest)(nil),         // 33: proto.vm.v1.StopSyscallsVmRequest
+	(*StopSyscallsVmResponse)(nil),        // 34: proto.vm.v1.StopSyscallsVmResponse
+	(*TraceTarget)(nil),                   // 35: proto.vm.v1.TraceTarget
+	(*TraceSession)(nil),                  // 36: proto.vm.v1.TraceSession
+	(*ListTraceSessionsVmRequest)(nil),    // 37: proto.vm.v1.ListTraceSessionsVmRequest
+	(*ListTraceSessionsVmResponse)(nil),   // 38: proto.vm.v1.ListTraceSessionsVmResponse
+	(*SyscallCount)(nil),                  // 39: proto.vm.v1.SyscallCount
+	(*SyscallLatency)(nil),                // 40: proto.vm.v1.SyscallLatency
+	(*LatencyBucket)(nil),                 // 41: proto.vm.v1.LatencyBucket
+	(*SyscallStats)(nil),                  // 42: proto.vm.v1.SyscallStats
+	(*GetSyscallStatsVmRequest)(nil),      // 43: proto.vm.v1.GetSyscallStatsVmRequest
+	(*GetSyscallStatsVmResponse)(nil),     // 44: proto.vm.v1.GetSyscallStatsVmResponse
+	(*WatchSyscallsVmRequest)(nil),        // 45: proto.vm.v1.WatchSyscallsVmRequest
+	(*WatchSyscallsVmResponse)(nil),       // 46: proto.vm.v1.WatchSyscallsVmResponse
+	(*CleanupVmRequest)(nil),              // 47: proto.vm.v1.CleanupVmRequest
+	(*CleanupVmResponse)(nil),             // 48: proto.vm.v1.CleanupVmResponse
+	(*PauseVmRequest)(nil),                // 49: proto.vm.v1.PauseVmRequest
+	(*PauseVmResponse)(nil),               // 50: proto.vm.v1.PauseVmResponse
+	(*ResumeVmRequest)(nil),               // 51: proto.vm.v1.ResumeVmRequest
+	(*ResumeVmResponse)(nil),              // 52: proto.vm.v1.ResumeVmResponse
+	(*Snapshot)(nil),                      // 53: proto.vm.v1.Snapshot
+	(*CreateSnapshotVmRequest)(nil),       // 54: proto.vm.v1.CreateSnapshotVmRequest
+	(*CreateSnapshotVmResponse)(nil),      // 55: proto.vm.v1.CreateSnapshotVmResponse
+	(*RestoreFromSnapshotVmRequest)(nil),  // 56: proto.vm.v1.RestoreFromSnapshotVmRequest
+	(*RestoreFromSnapshotVmResponse)(nil), // 57: proto.vm.v1.RestoreFromSnapshotVmResponse
+	(*ListVmsRequest)(nil),                // 58: proto.vm.v1.ListVmsRequest
+	(*ListVmsResponse)(nil),               // 59: proto.vm.v1.ListVmsResponse
+	(*GetVmRequest)(nil),                  // 60: proto.vm.v1.GetVmRequest
+	(*GetVmResponse)(nil),                 // 61: proto.vm.v1.GetVmResponse
+	(*DeleteVmRequest)(nil),               // 62: proto.vm.v1.DeleteVmRequest
+	(*DeleteVmResponse)(nil),              // 63: proto.vm.v1.DeleteVmResponse
+	(*GetVmMetricsRequest)(nil),           // 64: proto.vm.v1.GetVmMetricsRequest
+	(*MetricsSample)(nil),                 // 65: proto.vm.v1.MetricsSample
+	(*GetVmMetricsResponse)(nil),          // 66: proto.vm.v1.GetVmMetricsResponse
+	nil,                                   // 67: proto.vm.v1.MetricsSample.ValuesEntry
+	nil,                                   // 68: proto.vm.v1.GetVmMetricsResponse.DeltaEntry
 }
 var file_proto_vm_proto_depIdxs = []int32{
-	0,  // 0: proto.vm.v1.CreateVmResponse.vm:type_name -> proto.vm.v1.Vm
-	1,  // 1: proto.vm.v1.VmService.Create:input_type -> proto.vm.v1.CreateVmRequest
-	3,  // 2: proto.vm.v1.VmService.SendServerCommand:input_type -> proto.vm.v1.SendServerCommandVmRequest
-	5,  // 3: proto.vm.v1.VmService.SendClientCommand:input_type -> proto.vm.v1.SendClientCommandVmRequest
-	7,  // 4: proto.vm.v1.VmService.TrackSyscalls:input_type -> proto.vm.v1.TrackSyscallsVmRequest
-	9,  // 5: proto.vm.v1.VmService.StopSyscalls:input_type -> proto.vm.v1.StopSyscallsVmRequest
-	11, // 6: proto.vm.v1.VmService.Cleanup:input_type -> proto.vm.v1.CleanupVmRequest
-	2,  // 7: proto.vm.v1.VmService.Create:output_type -> proto.vm.v1.CreateVmResponse
-	4,  // 8: proto.vm.v1.VmService.SendServerCommand:output_type -> proto.vm.v1.SendServerCommandVmResponse
-	6,  // 9: proto.vm.v1.VmService.SendClientCommand:output_type -> proto.vm.v1.SendClientCommandVmResponse
-	8,  // 10: proto.vm.v1.VmService.TrackSyscalls:output_type -> proto.vm.v1.TrackSyscallsVmResponse
-	10, // 11: proto.vm.v1.VmService.StopSyscalls:output_type -> proto.vm.v1.StopSyscallsVmResponse
-	12, // 12: proto.vm.v1.VmService.Cleanup:output_type -> proto.vm.v1.CleanupVmResponse
-	7,  // [7:13] is the sub-list for method output_type
-	1,  // [1:7] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	0,  // 0: proto.vm.v1.Vm.state:type_name -> proto.vm.v1.VmState
+	6,  // 1: proto.vm.v1.Vm.networkRxRateLimiter:type_name -> proto.vm.v1.RateLimiter
+	6,  // 2: proto.vm.v1.Vm.networkTxRateLimiter:type_name -> proto.vm.v1.RateLimiter
+	6,  // 3: proto.vm.v1.Vm.driveRateLimiter:type_name -> proto.vm.v1.RateLimiter
+	5,  // 4: proto.vm.v1.RateLimiter.bandwidth:type_name -> proto.vm.v1.TokenBucket
+	5,  // 5: proto.vm.v1.RateLimiter.ops:type_name -> proto.vm.v1.TokenBucket
+	6,  // 6: proto.vm.v1.CreateVmRequest.networkRxRateLimiter:type_name -> proto.vm.v1.RateLimiter
+	6,  // 7: proto.vm.v1.CreateVmRequest.networkTxRateLimiter:type_name -> proto.vm.v1.RateLimiter
+	6,  // 8: proto.vm.v1.CreateVmRequest.driveRateLimiter:type_name -> proto.vm.v1.RateLimiter
+	4,  // 9: proto.vm.v1.CreateVmResponse.vm:type_name -> proto.vm.v1.Vm
+	1,  // 10: proto.vm.v1.SendClientCommandVmResponse.stream:type_name -> proto.vm.v1.OutputStream
+	13, // 11: proto.vm.v1.SendClientCommandVmResponse.exit:type_name -> proto.vm.v1.CommandExit
+	15, // 12: proto.vm.v1.ExecVmRequest.start:type_name -> proto.vm.v1.ExecVmStart
+	16, // 13: proto.vm.v1.ExecVmRequest.resize:type_name -> proto.vm.v1.TerminalSize
+	16, // 14: proto.vm.v1.ExecVmStart.size:type_name -> proto.vm.v1.TerminalSize
+	1,  // 15: proto.vm.v1.ExecVmResponse.stream:type_name -> proto.vm.v1.OutputStream
+	13, // 16: proto.vm.v1.ExecVmResponse.exit:type_name -> proto.vm.v1.CommandExit
+	2,  // 17: proto.vm.v1.Job.state:type_name -> proto.vm.v1.JobState
+	13, // 18: proto.vm.v1.Job.exit:type_name -> proto.vm.v1.CommandExit
+	18, // 19: proto.vm.v1.ListJobsVmResponse.jobs:type_name -> proto.vm.v1.Job
+	18, // 20: proto.vm.v1.GetJobVmResponse.job:type_name -> proto.vm.v1.Job
+	18, // 21: proto.vm.v1.CancelJobVmResponse.job:type_name -> proto.vm.v1.Job
+	26, // 22: proto.vm.v1.PutFileVmRequest.start:type_name -> proto.vm.v1.PutFileVmStart
+	28, // 23: proto.vm.v1.PutFileVmResponse.file:type_name -> proto.vm.v1.FileInfo
+	28, // 24: proto.vm.v1.GetFileVmResponse.file:type_name -> proto.vm.v1.FileInfo
+	36, // 25: proto.vm.v1.TrackSyscallsVmResponse.session:type_name -> proto.vm.v1.TraceSession
+	35, // 26: proto.vm.v1.TraceSession.targets:type_name -> proto.vm.v1.TraceTarget
+	36, // 27: proto.vm.v1.ListTraceSessionsVmResponse.sessions:type_name -> proto.vm.v1.TraceSession
+	41, // 28: proto.vm.v1.SyscallLatency.buckets:type_name -> proto.vm.v1.LatencyBucket
+	39, // 29: proto.vm.v1.SyscallStats.interval:type_name -> proto.vm.v1.SyscallCount
+	39, // 30: proto.vm.v1.SyscallStats.total:type_name -> proto.vm.v1.SyscallCount
+	40, // 31: proto.vm.v1.SyscallStats.intervalLatency:type_name -> proto.vm.v1.SyscallLatency
+	40, // 32: proto.vm.v1.SyscallStats.totalLatency:type_name -> proto.vm.v1.SyscallLatency
+	42, // 33: proto.vm.v1.GetSyscallStatsVmResponse.stats:type_name -> proto.vm.v1.SyscallStats
+	42, // 34: proto.vm.v1.WatchSyscallsVmResponse.stats:type_name -> proto.vm.v1.SyscallStats
+	3,  // 35: proto.vm.v1.Snapshot.type:type_name -> proto.vm.v1.SnapshotType
+	3,  // 36: proto.vm.v1.CreateSnapshotVmRequest.type:type_name -> proto.vm.v1.SnapshotType
+	53, // 37: proto.vm.v1.CreateSnapshotVmResponse.snapshot:type_name -> proto.vm.v1.Snapshot
+	4,  // 38: proto.vm.v1.RestoreFromSnapshotVmResponse.vm:type_name -> proto.vm.v1.Vm
+	4,  // 39: proto.vm.v1.ListVmsResponse.vms:type_name -> proto.vm.v1.Vm
+	4,  // 40: proto.vm.v1.GetVmResponse.vm:type_name -> proto.vm.v1.Vm
+	67, // 41: proto.vm.v1.MetricsSample.values:type_name -> proto.vm.v1.MetricsSample.ValuesEntry
+	68, // 42: proto.vm.v1.GetVmMetricsResponse.delta:type_name -> proto.vm.v1.GetVmMetricsResponse.DeltaEntry
+	65, // 43: proto.vm.v1.GetVmMetricsResponse.samples:type_name -> proto.vm.v1.MetricsSample
+	7,  // 44: proto.vm.v1.VmService.Create:input_type -> proto.vm.v1.CreateVmRequest
+	9,  // 45: proto.vm.v1.VmService.SendServerCommand:input_type -> proto.vm.v1.SendServerCommandVmRequest
+	11, // 46: proto.vm.v1.VmService.SendClientCommand:input_type -> proto.vm.v1.SendClientCommandVmRequest
+	31, // 47: proto.vm.v1.VmService.TrackSyscalls:input_type -> proto.vm.v1.TrackSyscallsVmRequest
+	33, // 48: proto.vm.v1.VmService.StopSyscalls:input_type -> proto.vm.v1.StopSyscallsVmRequest
+	37, // 49: proto.vm.v1.VmService.ListTraceSessions:input_type -> proto.vm.v1.ListTraceSessionsVmRequest
+	43, // 50: proto.vm.v1.VmService.GetSyscallStats:input_type -> proto.vm.v1.GetSyscallStatsVmRequest
+	45, // 51: proto.vm.v1.VmService.WatchSyscalls:input_type -> proto.vm.v1.WatchSyscallsVmRequest
+	47, // 52: proto.vm.v1.VmService.Cleanup:input_type -> proto.vm.v1.CleanupVmRequest
+	49, // 53: proto.vm.v1.VmService.Pause:input_type -> proto.vm.v1.PauseVmRequest
+	51, // 54: proto.vm.v1.VmService.Resume:input_type -> proto.vm.v1.ResumeVmRequest
+	54, // 55: proto.vm.v1.VmService.CreateSnapshot:input_type -> proto.vm.v1.CreateSnapshotVmRequest
+	56, // 56: proto.vm.v1.VmService.RestoreFromSnapshot:input_type -> proto.vm.v1.RestoreFromSnapshotVmRequest
+	58, // 57: proto.vm.v1.VmService.ListVms:input_type -> proto.vm.v1.ListVmsRequest
+	60, // 58: proto.vm.v1.VmService.GetVm:input_type -> proto.vm.v1.GetVmRequest
+	62, // 59: proto.vm.v1.VmService.DeleteVm:input_type -> proto.vm.v1.DeleteVmRequest
+	64, // 60: proto.vm.v1.VmService.GetVmMetrics:input_type -> proto.vm.v1.GetVmMetricsRequest
+	14, // 61: proto.vm.v1.VmService.Exec:input_type -> proto.vm.v1.ExecVmRequest
+	25, // 62: proto.vm.v1.VmService.PutFile:input_type -> proto.vm.v1.PutFileVmRequest
+	29, // 63: proto.vm.v1.VmService.GetFile:input_type -> proto.vm.v1.GetFileVmRequest
+	19, // 64: proto.vm.v1.VmService.ListJobs:input_type -> proto.vm.v1.ListJobsVmRequest
+	21, // 65: proto.vm.v1.VmService.GetJob:input_type -> proto.vm.v1.GetJobVmRequest
+	23, // 66: proto.vm.v1.VmService.CancelJob:input_type -> proto.vm.v1.CancelJobVmRequest
+	8,  // 67: proto.vm.v1.VmService.Create:output_type -> proto.vm.v1.CreateVmResponse
+	10, // 68: proto.vm.v1.VmService.SendServerCommand:output_type -> proto.vm.v1.SendServerCommandVmResponse
+	12, // 69: proto.vm.v1.VmService.SendClientCommand:output_type -> proto.vm.v1.SendClientCommandVmResponse
+	32, // 70: proto.vm.v1.VmService.TrackSyscalls:output_type -> proto.vm.v1.TrackSyscallsVmResponse
+	34, // 71: proto.vm.v1.VmService.StopSyscalls:output_type -> proto.vm.v1.StopSyscallsVmResponse
+	38, // 72: proto.vm.v1.VmService.ListTraceSessions:output_type -> proto.vm.v1.ListTraceSessionsVmResponse
+	44, // 73: proto.vm.v1.VmService.GetSyscallStats:output_type -> proto.vm.v1.GetSyscallStatsVmResponse
+	46, // 74: proto.vm.v1.VmService.WatchSyscalls:output_type -> proto.vm.v1.WatchSyscallsVmResponse
+	48, // 75: proto.vm.v1.VmService.Cleanup:output_type -> proto.vm.v1.CleanupVmResponse
+	50, // 76: proto.vm.v1.VmService.Pause:output_type -> proto.vm.v1.PauseVmResponse
+	52, // 77: proto.vm.v1.VmService.Resume:output_type -> proto.vm.v1.ResumeVmResponse
+	55, // 78: proto.vm.v1.VmService.CreateSnapshot:output_type -> proto.vm.v1.CreateSnapshotVmResponse
+	57, // 79: proto.vm.v1.VmService.RestoreFromSnapshot:output_type -> proto.vm.v1.RestoreFromSnapshotVmResponse
+	59, // 80: proto.vm.v1.VmService.ListVms:output_type -> proto.vm.v1.ListVmsResponse
+	61, // 81: proto.vm.v1.VmService.GetVm:output_type -> proto.vm.v1.GetVmResponse
+	63, // 82: proto.vm.v1.VmService.DeleteVm:output_type -> proto.vm.v1.DeleteVmResponse
+	66, // 83: proto.vm.v1.VmService.GetVmMetrics:output_type -> proto.vm.v1.GetVmMetricsResponse
+	17, // 84: proto.vm.v1.VmService.Exec:output_type -> proto.vm.v1.ExecVmResponse
+	27, // 85: proto.vm.v1.VmService.PutFile:output_type -> proto.vm.v1.PutFileVmResponse
+	30, // 86: proto.vm.v1.VmService.GetFile:output_type -> proto.vm.v1.GetFileVmResponse
+	20, // 87: proto.vm.v1.VmService.ListJobs:output_type -> proto.vm.v1.ListJobsVmResponse
+	22, // 88: proto.vm.v1.VmService.GetJob:output_type -> proto.vm.v1.GetJobVmResponse
+	24, // 89: proto.vm.v1.VmService.CancelJob:output_type -> proto.vm.v1.CancelJobVmResponse
+	67, // [67:90] is the sub-list for method output_type
+	44, // [44:67] is the sub-list for method input_type
+	44, // [44:44] is the sub-list for extension type_name
+	44, // [44:44] is the sub-list for extension extendee
+	0,  // [0:44] is the sub-list for field type_name
 }
 
 func init() { file_proto_vm_proto_init() }
@@ -717,13 +4585,14 @@ func file_proto_vm_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_vm_proto_rawDesc), len(file_proto_vm_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   13,
+			NumEnums:      4,
+			NumMessages:   65,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_vm_proto_goTypes,
 		DependencyIndexes: file_proto_vm_proto_depIdxs,
+		EnumInfos:         file_proto_vm_proto_enumTypes,
 		MessageInfos:      file_proto_vm_proto_msgTypes,
 	}.Build()
 	File_proto_vm_proto = out.File
@@ -19,12 +19,29 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	VmService_Create_FullMethodName            = "/proto.vm.v1.VmService/Create"
-	VmService_SendServerCommand_FullMethodName = "/proto.vm.v1.VmService/SendServerCommand"
-	VmService_SendClientCommand_FullMethodName = "/proto.vm.v1.VmService/SendClientCommand"
-	VmService_TrackSyscalls_FullMethodName     = "/proto.vm.v1.VmService/TrackSyscalls"
-	VmService_StopSyscalls_FullMethodName      = "/proto.vm.v1.VmService/StopSyscalls"
-	VmService_Cleanup_FullMethodName           = "/proto.vm.v1.VmService/Cleanup"
+	VmService_Create_FullMethodName              = "/proto.vm.v1.VmService/Create"
+	VmService_SendServerCommand_FullMethodName   = "/proto.vm.v1.VmService/SendServerCommand"
+	VmService_SendClientCommand_FullMethodName   = "/proto.vm.v1.VmService/SendClientCommand"
+	VmService_TrackSyscalls_FullMethodName       = "/proto.vm.v1.VmService/TrackSyscalls"
+	VmService_StopSyscalls_FullMethodName        = "/proto.vm.v1.VmService/StopSyscalls"
+	VmService_ListTraceSessions_FullMethodName   = "/proto.vm.v1.VmService/ListTraceSessions"
+	VmService_GetSyscallStats_FullMethodName     = "/proto.vm.v1.VmService/GetSyscallStats"
+	VmService_WatchSyscalls_FullMethodName       = "/proto.vm.v1.VmService/WatchSyscalls"
+	VmService_Cleanup_FullMethodName             = "/proto.vm.v1.VmService/Cleanup"
+	VmService_Pause_FullMethodName               = "/proto.vm.v1.VmService/Pause"
+	VmService_Resume_FullMethodName              = "/proto.vm.v1.VmService/Resume"
+	VmService_CreateSnapshot_FullMethodName      = "/proto.vm.v1.VmService/CreateSnapshot"
+	VmService_RestoreFromSnapshot_FullMethodName = "/proto.vm.v1.VmService/RestoreFromSnapshot"
+	VmService_ListVms_FullMethodName             = "/proto.vm.v1.VmService/ListVms"
+	VmService_GetVm_FullMethodName               = "/proto.vm.v1.VmService/GetVm"
+	VmService_DeleteVm_FullMethodName            = "/proto.vm.v1.VmService/DeleteVm"
+	VmService_GetVmMetrics_FullMethodName        = "/proto.vm.v1.VmService/GetVmMetrics"
+	VmService_Exec_FullMethodName                = "/proto.vm.v1.VmService/Exec"
+	VmService_PutFile_FullMethodName             = "/proto.vm.v1.VmService/PutFile"
+	VmService_GetFile_FullMethodName             = "/proto.vm.v1.VmService/GetFile"
+	VmService_ListJobs_FullMethodName            = "/proto.vm.v1.VmService/ListJobs"
+	VmService_GetJob_FullMethodName              = "/proto.vm.v1.VmService/GetJob"
+	VmService_CancelJob_FullMethodName           = "/proto.vm.v1.VmService/CancelJob"
 )
 
 // VmServiceClient is the client API for VmService service.
@@ -36,7 +53,24 @@ type VmServiceClient interface {
 	SendClientCommand(ctx context.Context, in *SendClientCommandVmRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SendClientCommandVmResponse], error)
 	TrackSyscalls(ctx context.Context, in *TrackSyscallsVmRequest, opts ...grpc.CallOption) (*TrackSyscallsVmResponse, error)
 	StopSyscalls(ctx context.Context, in *StopSyscallsVmRequest, opts ...grpc.CallOption) (*StopSyscallsVmResponse, error)
+	ListTraceSessions(ctx context.Context, in *ListTraceSessionsVmRequest, opts ...grpc.CallOption) (*ListTraceSessionsVmResponse, error)
+	GetSyscallStats(ctx context.Context, in *GetSyscallStatsVmRequest, opts ...grpc.CallOption) (*GetSyscallStatsVmResponse, error)
+	WatchSyscalls(ctx context.Context, in *WatchSyscallsVmRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchSyscallsVmResponse], error)
 	Cleanup(ctx context.Context, in *CleanupVmRequest, opts ...grpc.CallOption) (*CleanupVmResponse, error)
+	Pause(ctx context.Context, in *PauseVmRequest, opts ...grpc.CallOption) (*PauseVmResponse, error)
+	Resume(ctx context.Context, in *ResumeVmRequest, opts ...grpc.CallOption) (*ResumeVmResponse, error)
+	CreateSnapshot(ctx context.Context, in *CreateSnapshotVmRequest, opts ...grpc.CallOption) (*CreateSnapshotVmResponse, error)
+	RestoreFromSnapshot(ctx context.Context, in *RestoreFromSnapshotVmRequest, opts ...grpc.CallOption) (*RestoreFromSnapshotVmResponse, error)
+	ListVms(ctx context.Context, in *ListVmsRequest, opts ...grpc.CallOption) (*ListVmsResponse, error)
+	GetVm(ctx context.Context, in *GetVmRequest, opts ...grpc.CallOption) (*GetVmResponse, error)
+	DeleteVm(ctx context.Context, in *DeleteVmRequest, opts ...grpc.CallOption) (*DeleteVmResponse, error)
+	GetVmMetrics(ctx context.Context, in *GetVmMetricsRequest, opts ...grpc.CallOption) (*GetVmMetricsResponse, error)
+	Exec(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ExecVmRequest, ExecVmResponse], error)
+	PutFile(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PutFileVmRequest, PutFileVmResponse], error)
+	GetFile(ctx context.Context, in *GetFileVmRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetFileVmResponse], error)
+	ListJobs(ctx context.Context, in *ListJobsVmRequest, opts ...grpc.CallOption) (*ListJobsVmResponse, error)
+	GetJob(ctx context.Context, in *GetJobVmRequest, opts ...grpc.CallOption) (*GetJobVmResponse, error)
+	CancelJob(ctx context.Context, in *CancelJobVmRequest, opts ...grpc.CallOption) (*CancelJobVmResponse, error)
 }
 
 type vmServiceClient struct {
@@ -106,6 +140,45 @@ func (c *vmServiceClient) StopSyscalls(ctx context.Context, in *StopSyscallsVmRe
 	return out, nil
 }
 
+func (c *vmServiceClient) ListTraceSessions(ctx context.Context, in *ListTraceSessionsVmRequest, opts ...grpc.CallOption) (*ListTraceSessionsVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTraceSessionsVmResponse)
+	err := c.cc.Invoke(ctx, VmService_ListTraceSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) GetSyscallStats(ctx context.Context, in *GetSyscallStatsVmRequest, opts ...grpc.CallOption) (*GetSyscallStatsVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSyscallStatsVmResponse)
+	err := c.cc.Invoke(ctx, VmService_GetSyscallStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) WatchSyscalls(ctx context.Context, in *WatchSyscallsVmRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchSyscallsVmResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VmService_ServiceDesc.Streams[1], VmService_WatchSyscalls_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchSyscallsVmRequest, WatchSyscallsVmResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VmService_WatchSyscallsClient = grpc.ServerStreamingClient[WatchSyscallsVmResponse]
+
 func (c *vmServiceClient) Cleanup(ctx context.Context, in *CleanupVmRequest, opts ...grpc.CallOption) (*CleanupVmResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CleanupVmResponse)
@@ -116,6 +189,161 @@ func (c *vmServiceClient) Cleanup(ctx context.Context, in *CleanupVmRequest, opt
 	return out, nil
 }
 
+func (c *vmServiceClient) Pause(ctx context.Context, in *PauseVmRequest, opts ...grpc.CallOption) (*PauseVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseVmResponse)
+	err := c.cc.Invoke(ctx, VmService_Pause_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) Resume(ctx context.Context, in *ResumeVmRequest, opts ...grpc.CallOption) (*ResumeVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeVmResponse)
+	err := c.cc.Invoke(ctx, VmService_Resume_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) CreateSnapshot(ctx context.Context, in *CreateSnapshotVmRequest, opts ...grpc.CallOption) (*CreateSnapshotVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSnapshotVmResponse)
+	err := c.cc.Invoke(ctx, VmService_CreateSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) RestoreFromSnapshot(ctx context.Context, in *RestoreFromSnapshotVmRequest, opts ...grpc.CallOption) (*RestoreFromSnapshotVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreFromSnapshotVmResponse)
+	err := c.cc.Invoke(ctx, VmService_RestoreFromSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) ListVms(ctx context.Context, in *ListVmsRequest, opts ...grpc.CallOption) (*ListVmsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVmsResponse)
+	err := c.cc.Invoke(ctx, VmService_ListVms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) GetVm(ctx context.Context, in *GetVmRequest, opts ...grpc.CallOption) (*GetVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVmResponse)
+	err := c.cc.Invoke(ctx, VmService_GetVm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) DeleteVm(ctx context.Context, in *DeleteVmRequest, opts ...grpc.CallOption) (*DeleteVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteVmResponse)
+	err := c.cc.Invoke(ctx, VmService_DeleteVm_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) GetVmMetrics(ctx context.Context, in *GetVmMetricsRequest, opts ...grpc.CallOption) (*GetVmMetricsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetVmMetricsResponse)
+	err := c.cc.Invoke(ctx, VmService_GetVmMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) Exec(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ExecVmRequest, ExecVmResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VmService_ServiceDesc.Streams[2], VmService_Exec_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExecVmRequest, ExecVmResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VmService_ExecClient = grpc.BidiStreamingClient[ExecVmRequest, ExecVmResponse]
+
+func (c *vmServiceClient) PutFile(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PutFileVmRequest, PutFileVmResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VmService_ServiceDesc.Streams[3], VmService_PutFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PutFileVmRequest, PutFileVmResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VmService_PutFileClient = grpc.BidiStreamingClient[PutFileVmRequest, PutFileVmResponse]
+
+func (c *vmServiceClient) GetFile(ctx context.Context, in *GetFileVmRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetFileVmResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VmService_ServiceDesc.Streams[4], VmService_GetFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetFileVmRequest, GetFileVmResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VmService_GetFileClient = grpc.ServerStreamingClient[GetFileVmResponse]
+
+func (c *vmServiceClient) ListJobs(ctx context.Context, in *ListJobsVmRequest, opts ...grpc.CallOption) (*ListJobsVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobsVmResponse)
+	err := c.cc.Invoke(ctx, VmService_ListJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) GetJob(ctx context.Context, in *GetJobVmRequest, opts ...grpc.CallOption) (*GetJobVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobVmResponse)
+	err := c.cc.Invoke(ctx, VmService_GetJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vmServiceClient) CancelJob(ctx context.Context, in *CancelJobVmRequest, opts ...grpc.CallOption) (*CancelJobVmResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelJobVmResponse)
+	err := c.cc.Invoke(ctx, VmService_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // VmServiceServer is the server API for VmService service.
 // All implementations must embed UnimplementedVmServiceServer
 // for forward compatibility.
@@ -125,7 +353,24 @@ type VmServiceServer interface {
 	SendClientCommand(*SendClientCommandVmRequest, grpc.ServerStreamingServer[SendClientCommandVmResponse]) error
 	TrackSyscalls(context.Context, *TrackSyscallsVmRequest) (*TrackSyscallsVmResponse, error)
 	StopSyscalls(context.Context, *StopSyscallsVmRequest) (*StopSyscallsVmResponse, error)
+	ListTraceSessions(context.Context, *ListTraceSessionsVmRequest) (*ListTraceSessionsVmResponse, error)
+	GetSyscallStats(context.Context, *GetSyscallStatsVmRequest) (*GetSyscallStatsVmResponse, error)
+	WatchSyscalls(*WatchSyscallsVmRequest, grpc.ServerStreamingServer[WatchSyscallsVmResponse]) error
 	Cleanup(context.Context, *CleanupVmRequest) (*CleanupVmResponse, error)
+	Pause(context.Context, *PauseVmRequest) (*PauseVmResponse, error)
+	Resume(context.Context, *ResumeVmRequest) (*ResumeVmResponse, error)
+	CreateSnapshot(context.Context, *CreateSnapshotVmRequest) (*CreateSnapshotVmResponse, error)
+	RestoreFromSnapshot(context.Context, *RestoreFromSnapshotVmRequest) (*RestoreFromSnapshotVmResponse, error)
+	ListVms(context.Context, *ListVmsRequest) (*ListVmsResponse, error)
+	GetVm(context.Context, *GetVmRequest) (*GetVmResponse, error)
+	DeleteVm(context.Context, *DeleteVmRequest) (*DeleteVmResponse, error)
+	GetVmMetrics(context.Context, *GetVmMetricsRequest) (*GetVmMetricsResponse, error)
+	Exec(grpc.BidiStreamingServer[ExecVmRequest, ExecVmResponse]) error
+	PutFile(grpc.BidiStreamingServer[PutFileVmRequest, PutFileVmResponse]) error
+	GetFile(*GetFileVmRequest, grpc.ServerStreamingServer[GetFileVmResponse]) error
+	ListJobs(context.Context, *ListJobsVmRequest) (*ListJobsVmResponse, error)
+	GetJob(context.Context, *GetJobVmRequest) (*GetJobVmResponse, error)
+	CancelJob(context.Context, *CancelJobVmRequest) (*CancelJobVmResponse, error)
 	mustEmbedUnimplementedVmServiceServer()
 }
 
@@ -151,9 +396,60 @@ func (UnimplementedVmServiceServer) TrackSyscalls(context.Context, *TrackSyscall
 func (UnimplementedVmServiceServer) StopSyscalls(context.Context, *StopSyscallsVmRequest) (*StopSyscallsVmResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopSyscalls not implemented")
 }
+func (UnimplementedVmServiceServer) ListTraceSessions(context.Context, *ListTraceSessionsVmRequest) (*ListTraceSessionsVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTraceSessions not implemented")
+}
+func (UnimplementedVmServiceServer) GetSyscallStats(context.Context, *GetSyscallStatsVmRequest) (*GetSyscallStatsVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSyscallStats not implemented")
+}
+func (UnimplementedVmServiceServer) WatchSyscalls(*WatchSyscallsVmRequest, grpc.ServerStreamingServer[WatchSyscallsVmResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSyscalls not implemented")
+}
 func (UnimplementedVmServiceServer) Cleanup(context.Context, *CleanupVmRequest) (*CleanupVmResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Cleanup not implemented")
 }
+func (UnimplementedVmServiceServer) Pause(context.Context, *PauseVmRequest) (*PauseVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pause not implemented")
+}
+func (UnimplementedVmServiceServer) Resume(context.Context, *ResumeVmRequest) (*ResumeVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (UnimplementedVmServiceServer) CreateSnapshot(context.Context, *CreateSnapshotVmRequest) (*CreateSnapshotVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSnapshot not implemented")
+}
+func (UnimplementedVmServiceServer) RestoreFromSnapshot(context.Context, *RestoreFromSnapshotVmRequest) (*RestoreFromSnapshotVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFromSnapshot not implemented")
+}
+func (UnimplementedVmServiceServer) ListVms(context.Context, *ListVmsRequest) (*ListVmsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVms not implemented")
+}
+func (UnimplementedVmServiceServer) GetVm(context.Context, *GetVmRequest) (*GetVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVm not implemented")
+}
+func (UnimplementedVmServiceServer) DeleteVm(context.Context, *DeleteVmRequest) (*DeleteVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVm not implemented")
+}
+func (UnimplementedVmServiceServer) GetVmMetrics(context.Context, *GetVmMetricsRequest) (*GetVmMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVmMetrics not implemented")
+}
+func (UnimplementedVmServiceServer) Exec(grpc.BidiStreamingServer[ExecVmRequest, ExecVmResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Exec not implemented")
+}
+func (UnimplementedVmServiceServer) PutFile(grpc.BidiStreamingServer[PutFileVmRequest, PutFileVmResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method PutFile not implemented")
+}
+func (UnimplementedVmServiceServer) GetFile(*GetFileVmRequest, grpc.ServerStreamingServer[GetFileVmResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method GetFile not implemented")
+}
+func (UnimplementedVmServiceServer) ListJobs(context.Context, *ListJobsVmRequest) (*ListJobsVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedVmServiceServer) GetJob(context.Context, *GetJobVmRequest) (*GetJobVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedVmServiceServer) CancelJob(context.Context, *CancelJobVmRequest) (*CancelJobVmResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
 func (UnimplementedVmServiceServer) mustEmbedUnimplementedVmServiceServer() {}
 func (UnimplementedVmServiceServer) testEmbeddedByValue()                   {}
 
@@ -258,6 +554,53 @@ func _VmService_StopSyscalls_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VmService_ListTraceSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTraceSessionsVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).ListTraceSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_ListTraceSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).ListTraceSessions(ctx, req.(*ListTraceSessionsVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_GetSyscallStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSyscallStatsVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).GetSyscallStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_GetSyscallStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).GetSyscallStats(ctx, req.(*GetSyscallStatsVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_WatchSyscalls_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSyscallsVmRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VmServiceServer).WatchSyscalls(m, &grpc.GenericServerStream[WatchSyscallsVmRequest, WatchSyscallsVmResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VmService_WatchSyscallsServer = grpc.ServerStreamingServer[WatchSyscallsVmResponse]
+
 func _VmService_Cleanup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CleanupVmRequest)
 	if err := dec(in); err != nil {
@@ -276,6 +619,229 @@ func _VmService_Cleanup_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VmService_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_Pause_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).Pause(ctx, req.(*PauseVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_Resume_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).Resume(ctx, req.(*ResumeVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_CreateSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSnapshotVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).CreateSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_CreateSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).CreateSnapshot(ctx, req.(*CreateSnapshotVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_RestoreFromSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreFromSnapshotVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).RestoreFromSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_RestoreFromSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).RestoreFromSnapshot(ctx, req.(*RestoreFromSnapshotVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_ListVms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVmsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).ListVms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_ListVms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).ListVms(ctx, req.(*ListVmsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_GetVm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).GetVm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_GetVm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).GetVm(ctx, req.(*GetVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_DeleteVm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).DeleteVm(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_DeleteVm_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).DeleteVm(ctx, req.(*DeleteVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_GetVmMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVmMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).GetVmMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_GetVmMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).GetVmMetrics(ctx, req.(*GetVmMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_Exec_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VmServiceServer).Exec(&grpc.GenericServerStream[ExecVmRequest, ExecVmResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VmService_ExecServer = grpc.BidiStreamingServer[ExecVmRequest, ExecVmResponse]
+
+func _VmService_PutFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VmServiceServer).PutFile(&grpc.GenericServerStream[PutFileVmRequest, PutFileVmResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VmService_PutFileServer = grpc.BidiStreamingServer[PutFileVmRequest, PutFileVmResponse]
+
+func _VmService_GetFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetFileVmRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VmServiceServer).GetFile(m, &grpc.GenericServerStream[GetFileVmRequest, GetFileVmResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VmService_GetFileServer = grpc.ServerStreamingServer[GetFileVmResponse]
+
+func _VmService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).ListJobs(ctx, req.(*ListJobsVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).GetJob(ctx, req.(*GetJobVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VmService_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobVmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VmServiceServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VmService_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VmServiceServer).CancelJob(ctx, req.(*CancelJobVmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // VmService_ServiceDesc is the grpc.ServiceDesc for VmService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -299,10 +865,62 @@ var VmService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "StopSyscalls",
 			Handler:    _VmService_StopSyscalls_Handler,
 		},
+		{
+			MethodName: "ListTraceSessions",
+			Handler:    _VmService_ListTraceSessions_Handler,
+		},
+		{
+			MethodName: "GetSyscallStats",
+			Handler:    _VmService_GetSyscallStats_Handler,
+		},
 		{
 			MethodName: "Cleanup",
 			Handler:    _VmService_Cleanup_Handler,
 		},
+		{
+			MethodName: "Pause",
+			Handler:    _VmService_Pause_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _VmService_Resume_Handler,
+		},
+		{
+			MethodName: "CreateSnapshot",
+			Handler:    _VmService_CreateSnapshot_Handler,
+		},
+		{
+			MethodName: "RestoreFromSnapshot",
+			Handler:    _VmService_RestoreFromSnapshot_Handler,
+		},
+		{
+			MethodName: "ListVms",
+			Handler:    _VmService_ListVms_Handler,
+		},
+		{
+			MethodName: "GetVm",
+			Handler:    _VmService_GetVm_Handler,
+		},
+		{
+			MethodName: "DeleteVm",
+			Handler:    _VmService_DeleteVm_Handler,
+		},
+		{
+			MethodName: "GetVmMetrics",
+			Handler:    _VmService_GetVmMetrics_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _VmService_ListJobs_Handler,
+		},
+		{
+			MethodName: "GetJob",
+			Handler:    _VmService_GetJob_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _VmService_CancelJob_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -310,6 +928,28 @@ var VmService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _VmService_SendClientCommand_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchSyscalls",
+			Handler:       _VmService_WatchSyscalls_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Exec",
+			Handler:       _VmService_Exec_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "PutFile",
+			Handler:       _VmService_PutFile_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "GetFile",
+			Handler:       _VmService_GetFile_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/vm.proto",
 }